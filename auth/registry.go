@@ -6,7 +6,9 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/docker/cli/cli/config/configfile"
 	bkauth "github.com/moby/buildkit/session/auth"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -17,6 +19,14 @@ import (
 
 const defaultDockerDomain = "docker.io"
 
+// dockerHubConfigKey is the key Docker Hub credentials are stored under in
+// a docker config.json.
+const dockerHubConfigKey = "https://index.docker.io/v1/"
+
+// dockerConfigCacheTTL is how long credentials read from a docker config,
+// and so possibly from a credential helper, are reused before asking again.
+const dockerConfigCacheTTL = 5 * time.Minute
+
 // RegistryAuthProvider is a custom auth provider for image's registry
 // authentication from dynamic user provided secrets.
 // Adapted from: https://github.com/dagger/dagger/blob/v0.2.36/solver/registryauth.go
@@ -34,14 +44,39 @@ type RegistryAuthProvider struct {
 	// Mutex to handle concurrency.
 	m sync.RWMutex
 
+	// Docker config to fall back to, if any, and the credentials read from
+	// it so far.
+	dockerConfig      *configfile.ConfigFile
+	dockerCredentials map[string]dockerCredential
+	// Credential helpers are run one at a time, as some of them misbehave
+	// otherwise, see https://github.com/docker/cli/issues/1862
+	dockerConfigMu sync.Mutex
+	now            func() time.Time
+
 	bkauth.UnimplementedAuthServer
 }
 
+type dockerCredential struct {
+	resp    *bkauth.CredentialsResponse
+	expires time.Time
+}
+
 // NewRegistryAuthProvider initializes a new store.
 func NewRegistryAuthProvider() *RegistryAuthProvider {
 	return &RegistryAuthProvider{credentials: map[string]*bkauth.CredentialsResponse{}}
 }
 
+// NewDockerConfigAuthProvider initializes a new store that falls back to the
+// credentials of the given docker config, including the ones of its
+// credHelpers and credsStore. Those are only read when a registry asks for
+// them.
+func NewDockerConfigAuthProvider(cfg *configfile.ConfigFile) *RegistryAuthProvider {
+	r := NewRegistryAuthProvider()
+	r.dockerConfig = cfg
+	r.dockerCredentials = map[string]dockerCredential{}
+	return r
+}
+
 // AddCredential inserts a new credential for the corresponding address.
 // Returns an error if the address does not match the standard registry
 // address: {registry_domain}.{extension}.
@@ -170,15 +205,65 @@ func (r *RegistryAuthProvider) credential(domain string) *bkauth.CredentialsResp
 	return nil
 }
 
+// dockerConfigCredential returns the credential of the docker config for the
+// given domain, or nil if there's none.
+func (r *RegistryAuthProvider) dockerConfigCredential(domain string) (*bkauth.CredentialsResponse, error) {
+	if r.dockerConfig == nil {
+		return nil, nil
+	}
+
+	key := domain
+	switch domain {
+	case defaultDockerDomain, "registry-1.docker.io", "index.docker.io":
+		key = dockerHubConfigKey
+	}
+
+	r.dockerConfigMu.Lock()
+	defer r.dockerConfigMu.Unlock()
+
+	now := time.Now()
+	if r.now != nil {
+		now = r.now()
+	}
+	if cached, ok := r.dockerCredentials[key]; ok && now.Before(cached.expires) {
+		return cached.resp, nil
+	}
+
+	ac, err := r.dockerConfig.GetAuthConfig(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get docker config credential for %s: %w", domain, err)
+	}
+	var resp *bkauth.CredentialsResponse
+	switch {
+	case ac.IdentityToken != "":
+		resp = &bkauth.CredentialsResponse{Secret: ac.IdentityToken}
+	case ac.Password != "":
+		resp = &bkauth.CredentialsResponse{Username: ac.Username, Secret: ac.Password}
+	}
+	if resp != nil {
+		// misses aren't cached, so that a docker login during the session is
+		// picked up on the next request
+		r.dockerCredentials[key] = dockerCredential{resp: resp, expires: now.Add(dockerConfigCacheTTL)}
+	}
+	return resp, nil
+}
+
 // Credentials retrieves credentials of the requested address.
 // It searches in the memory map for the standardize address.
 //
 // If the address isn't registered in the memory map, it will search
-// on DockerAuthProvider.
+// in the docker config, if any.
 func (r *RegistryAuthProvider) Credentials(ctx context.Context, req *bkauth.CredentialsRequest) (*bkauth.CredentialsResponse, error) {
 	memoryCredential := r.credential(req.GetHost())
 	if memoryCredential != nil {
 		return memoryCredential, nil
 	}
+	dockerCredential, err := r.dockerConfigCredential(req.GetHost())
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if dockerCredential != nil {
+		return dockerCredential, nil
+	}
 	return nil, status.Errorf(codes.NotFound, "no credential found for %s", req.GetHost())
 }
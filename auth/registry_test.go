@@ -2,10 +2,17 @@ package auth
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/cli/cli/config"
 	"github.com/moby/buildkit/session/auth"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -130,3 +137,126 @@ func TestRegistryAuthProvider(t *testing.T) {
 		require.Equal(t, testRegistrySecret, credentialsRes.Secret)
 	})
 }
+
+// fakeCredentialHelper is a docker credential helper that logs the hosts it's
+// asked for, and returns a username and secret derived from its name.
+const fakeCredentialHelper = `#!/bin/sh
+read -r host
+echo "$host" >> "$HELPER_LOG"
+case "$host" in
+  *.missing) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+echo '{"ServerURL":"'"$host"'","Username":"'"${0##*-}"'","Secret":"'"${0##*-}"'-secret"}'
+`
+
+func TestDockerConfigAuthProvider(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	for _, name := range []string{"docker-credential-helper", "docker-credential-store"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(fakeCredentialHelper), 0o755))
+	}
+	helperLog := filepath.Join(dir, "helper.log")
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HELPER_LOG", helperLog)
+
+	cfg, err := config.LoadFromReader(strings.NewReader(`{
+		"auths": {
+			"https://index.docker.io/v1/": {"auth": "aHViOmh1Yi1zZWNyZXQ="},
+			"token.io": {"identitytoken": "id-token"}
+		},
+		"credHelpers": {"helped.io": "helper"},
+		"credsStore": "store"
+	}`))
+	require.NoError(t, err)
+	// the auths are only used when there's no credsStore
+	fileCfg, err := config.LoadFromReader(strings.NewReader(`{
+		"auths": {
+			"https://index.docker.io/v1/": {"auth": "aHViOmh1Yi1zZWNyZXQ="},
+			"token.io": {"identitytoken": "id-token"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	credentials := func(registry *RegistryAuthProvider, host string) (*auth.CredentialsResponse, error) {
+		return registry.Credentials(ctx, &auth.CredentialsRequest{Host: host})
+	}
+	helperCalls := func() []string {
+		out, err := os.ReadFile(helperLog)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return strings.Fields(string(out))
+	}
+
+	t.Run("auths", func(t *testing.T) {
+		registry := NewDockerConfigAuthProvider(fileCfg)
+
+		for _, host := range []string{"docker.io", "registry-1.docker.io"} {
+			res, err := credentials(registry, host)
+			require.NoError(t, err)
+			require.Equal(t, "hub", res.Username)
+			require.Equal(t, "hub-secret", res.Secret)
+		}
+
+		res, err := credentials(registry, "token.io")
+		require.NoError(t, err)
+		require.Empty(t, res.Username)
+		require.Equal(t, "id-token", res.Secret)
+
+		_, err = credentials(registry, "other.io")
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("in memory credentials first", func(t *testing.T) {
+		registry := NewDockerConfigAuthProvider(fileCfg)
+		require.NoError(t, registry.AddCredential("docker.io", testRegistryUser, testRegistrySecret))
+		res, err := credentials(registry, "registry-1.docker.io")
+		require.NoError(t, err)
+		require.Equal(t, testRegistryUser, res.Username)
+		require.Equal(t, testRegistrySecret, res.Secret)
+	})
+
+	t.Run("credential helpers", func(t *testing.T) {
+		registry := NewDockerConfigAuthProvider(cfg)
+		now := time.Now()
+		registry.now = func() time.Time { return now }
+
+		res, err := credentials(registry, "helped.io")
+		require.NoError(t, err)
+		require.Equal(t, "helper", res.Username)
+		require.Equal(t, "helper-secret", res.Secret)
+
+		res, err = credentials(registry, "stored.io")
+		require.NoError(t, err)
+		require.Equal(t, "store", res.Username)
+		require.Equal(t, "store-secret", res.Secret)
+
+		res, err = credentials(registry, "docker.io")
+		require.NoError(t, err)
+		require.Equal(t, "store", res.Username)
+
+		_, err = credentials(registry, "stored.missing")
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		// the helpers are only asked once for each host they have a credential
+		// for, but asked again for the others
+		for _, host := range []string{"helped.io", "stored.io", "docker.io", "stored.missing"} {
+			_, err = credentials(registry, host)
+			require.True(t, err == nil || status.Code(err) == codes.NotFound)
+		}
+		require.Equal(t, []string{"helped.io", "stored.io", dockerHubConfigKey, "stored.missing", "stored.missing"}, helperCalls())
+
+		// until the cache expires
+		now = now.Add(dockerConfigCacheTTL)
+		_, err = credentials(registry, "helped.io")
+		require.NoError(t, err)
+		require.Len(t, helperCalls(), 6)
+	})
+
+	t.Run("no docker config", func(t *testing.T) {
+		_, err := credentials(NewRegistryAuthProvider(), "docker.io")
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
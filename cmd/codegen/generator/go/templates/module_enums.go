@@ -52,7 +52,15 @@ func (ps *parseState) parseGoEnum(t *types.Basic, named *types.Named) (*parsedEn
 			value: value,
 		}
 		if doc := docForAstSpec(astSpec); doc != nil {
+			pragmas, rest := parsePragmaComment(doc.Text())
 			valueSpec.doc = doc.Text()
+			if v, ok := pragmas["deprecated"]; ok {
+				valueSpec.doc = rest
+				valueSpec.deprecated, err = parseDeprecatedPragma(v)
+				if err != nil {
+					return nil, fmt.Errorf("enum value %s: %w", objConst.Name(), err)
+				}
+			}
 		}
 		valueSpec.sourceMap = ps.sourceMap(astSpec)
 		spec.values = append(spec.values, valueSpec)
@@ -88,9 +96,10 @@ type parsedEnumType struct {
 }
 
 type parsedEnumValue struct {
-	value     string
-	doc       string
-	sourceMap *sourceMap
+	value      string
+	doc        string
+	sourceMap  *sourceMap
+	deprecated string
 }
 
 var _ NamedParsedType = &parsedEnumType{}
@@ -123,6 +132,9 @@ func (spec *parsedEnumType) TypeDefCode() (*Statement, error) {
 		if val.sourceMap != nil {
 			withEnumValueOpts = append(withEnumValueOpts, Id("SourceMap").Op(":").Add(val.sourceMap.TypeDefCode()))
 		}
+		if val.deprecated != "" {
+			withEnumValueOpts = append(withEnumValueOpts, Id("Deprecated").Op(":").Lit(val.deprecated))
+		}
 		if len(withEnumValueOpts) > 0 {
 			valueTypeDefCode = append(valueTypeDefCode,
 				Id("dagger").Dot("TypeDefWithEnumValueOpts").Values(withEnumValueOpts...),
@@ -6,8 +6,10 @@ import (
 	"go/ast"
 	"go/types"
 	"maps"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	. "github.com/dave/jennifer/jen" //nolint:stylecheck
 )
@@ -30,6 +32,22 @@ func (ps *parseState) parseGoFunc(parentType *types.Named, fn *types.Func) (*fun
 	spec.doc = funcDecl.Doc.Text()
 	spec.sourceMap = ps.sourceMap(funcDecl)
 
+	pragmas, doc := parsePragmaComment(spec.doc)
+	if v, ok := pragmas["cache"]; ok {
+		spec.doc = doc
+		spec.cachePolicy, spec.cacheTTL, err = parseCachePragma(v)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %w", fn.Name(), err)
+		}
+	}
+	if v, ok := pragmas["deprecated"]; ok {
+		spec.doc = doc
+		spec.deprecated, err = parseDeprecatedPragma(v)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %w", fn.Name(), err)
+		}
+	}
+
 	sig, ok := fn.Type().(*types.Signature)
 	if !ok {
 		return nil, fmt.Errorf("expected method to be a func, got %T", fn.Type())
@@ -61,14 +79,14 @@ func (ps *parseState) parseGoFunc(parentType *types.Named, fn *types.Func) (*fun
 			spec.returnsError = true
 			break
 		}
-		spec.returnSpec, err = ps.parseGoTypeReference(result, nil, false)
+		spec.returnSpec, spec.streaming, err = ps.parseGoReturnType(result)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse return type: %w", err)
 		}
 	case 2:
 		spec.returnsError = true
 		result := results.At(0).Type()
-		spec.returnSpec, err = ps.parseGoTypeReference(result, nil, false)
+		spec.returnSpec, spec.streaming, err = ps.parseGoReturnType(result)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse return type: %w", err)
 		}
@@ -79,11 +97,64 @@ func (ps *parseState) parseGoFunc(parentType *types.Named, fn *types.Func) (*fun
 	return spec, nil
 }
 
+// parseGoReturnType parses the type returned by a function, and whether the
+// function streams its result: a receive channel is streamed as a list of its
+// elements, and an io.Reader as a string.
+func (ps *parseState) parseGoReturnType(typ types.Type) (ParsedType, bool, error) {
+	switch {
+	case isStreamedChan(typ):
+		elem := dealias(typ).Underlying().(*types.Chan).Elem()
+		elemSpec, err := ps.parseGoTypeReference(elem, nil, false)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse channel element type: %w", err)
+		}
+		return &parsedSliceType{
+			goType:     types.NewSlice(elem),
+			underlying: elemSpec,
+		}, true, nil
+	case isIOReader(typ):
+		return &parsedPrimitiveType{goType: types.Typ[types.String]}, true, nil
+	default:
+		spec, err := ps.parseGoTypeReference(typ, nil, false)
+		return spec, false, err
+	}
+}
+
+// isStreamedResult returns true if the type is returned by streaming functions.
+func isStreamedResult(typ types.Type) bool {
+	return isStreamedChan(typ) || isIOReader(typ)
+}
+
+func isStreamedChan(typ types.Type) bool {
+	ch, ok := dealias(typ).Underlying().(*types.Chan)
+	return ok && ch.Dir() != types.SendOnly
+}
+
+func isIOReader(typ types.Type) bool {
+	named, ok := dealias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "io" && named.Obj().Name() == "Reader"
+}
+
 type funcTypeSpec struct {
 	name      string
 	doc       string
 	sourceMap *sourceMap
 
+	// cachePolicy is the name of the FunctionCachePolicy enum value set with
+	// the cache pragma, if any, and cacheTTL its time to live.
+	cachePolicy string
+	cacheTTL    string
+
+	// deprecated is the reason set with the deprecated pragma, if any.
+	deprecated string
+
+	// streaming is set if the function returns a channel or an io.Reader,
+	// whose items are sent to the caller as they arrive.
+	streaming bool
+
 	argSpecs []paramSpec
 
 	returnSpec   ParsedType // nil if void return
@@ -114,6 +185,21 @@ func (spec *funcTypeSpec) TypeDefCode() (*Statement, error) {
 	if spec.sourceMap != nil {
 		fnTypeDefCode = dotLine(fnTypeDefCode, "WithSourceMap").Call(spec.sourceMap.TypeDefCode())
 	}
+	if spec.cachePolicy != "" {
+		cacheArgsCode := []Code{Id("dagger").Dot(spec.cachePolicy)}
+		if spec.cacheTTL != "" {
+			cacheArgsCode = append(cacheArgsCode, Id("dagger").Dot("FunctionWithCachePolicyOpts").Values(
+				Id("TimeToLive").Op(":").Lit(spec.cacheTTL),
+			))
+		}
+		fnTypeDefCode = dotLine(fnTypeDefCode, "WithCachePolicy").Call(cacheArgsCode...)
+	}
+	if spec.deprecated != "" {
+		fnTypeDefCode = dotLine(fnTypeDefCode, "WithDeprecated").Call(Lit(spec.deprecated))
+	}
+	if spec.streaming {
+		fnTypeDefCode = dotLine(fnTypeDefCode, "WithStreaming").Call()
+	}
 
 	for _, argSpec := range spec.argSpecs {
 		if argSpec.isContext {
@@ -157,6 +243,12 @@ func (spec *funcTypeSpec) TypeDefCode() (*Statement, error) {
 			argOptsCode = append(argOptsCode, Id("Ignore").Op(":").Index().String().Values(ignores...))
 		}
 
+		if argSpec.deprecated != "" {
+			argOptsCode = append(argOptsCode, Id("Deprecated").Op(":").Lit(argSpec.deprecated))
+		}
+
+		argOptsCode = append(argOptsCode, argSpec.constraints.optsCode()...)
+
 		// arguments to WithArg (args to arg... ugh, at least the name of the variable is honest?)
 		argTypeDefArgCode := []Code{Lit(argSpec.name), argTypeDefCode}
 		if len(argOptsCode) > 0 {
@@ -168,6 +260,78 @@ func (spec *funcTypeSpec) TypeDefCode() (*Statement, error) {
 	return fnTypeDefCode, nil
 }
 
+// optsCode returns the fields of FunctionWithArgOpts setting the constraints.
+func (c argConstraints) optsCode() []Code {
+	var code []Code
+	if c.pattern != "" {
+		code = append(code, Id("Pattern").Op(":").Lit(c.pattern))
+	}
+	if c.min != nil {
+		code = append(code, Id("Min").Op(":").Add(floatOptCode(*c.min)))
+	}
+	if c.max != nil {
+		code = append(code, Id("Max").Op(":").Add(floatOptCode(*c.max)))
+	}
+	if c.minLength != nil && *c.minLength > 0 {
+		code = append(code, Id("MinLength").Op(":").Lit(*c.minLength))
+	}
+	if len(c.allowedValues) > 0 {
+		values := make([]Code, 0, len(c.allowedValues))
+		for _, v := range c.allowedValues {
+			values = append(values, Lit(v))
+		}
+		code = append(code, Id("AllowedValues").Op(":").Index().String().Values(values...))
+	}
+	return code
+}
+
+// floatOptCode returns the code for a float option. Zero values of options are
+// not sent to the API, so zero is written as the smallest non-zero float
+// instead, which is sent as 0 since floats are formatted with %f.
+func floatOptCode(f float64) Code {
+	if f == 0 {
+		return Qual("math", "SmallestNonzeroFloat64")
+	}
+	return Lit(f)
+}
+
+// parseCachePragma parses the value of a cache pragma: "never", "session",
+// or a time to live such as "10m". It returns the name of the matching
+// FunctionCachePolicy enum value, and the time to live if any.
+func parseCachePragma(v string) (policy string, ttl string, err error) {
+	if strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) {
+		v = v[1 : len(v)-1]
+	}
+	switch v {
+	case "never":
+		return "FunctionCachePolicyNever", "", nil
+	case "session":
+		return "FunctionCachePolicyPerSession", "", nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < time.Second {
+		return "", "", fmt.Errorf("cache pragma %q must be \"never\", \"session\" or a duration of at least 1s", v)
+	}
+	return "FunctionCachePolicyTtl", v, nil
+}
+
+// parseDeprecatedPragma parses the value of a deprecated pragma, the reason
+// for the deprecation, which may be quoted.
+func parseDeprecatedPragma(v string) (string, error) {
+	if strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) {
+		unquoted, err := strconv.Unquote(v)
+		if err != nil {
+			return "", fmt.Errorf("deprecated pragma %s must be a valid string: %w", v, err)
+		}
+		v = unquoted
+	}
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", fmt.Errorf("deprecated pragma must have a reason, e.g. +deprecated=\"use build2 instead\"")
+	}
+	return v, nil
+}
+
 func (spec *funcTypeSpec) GoType() types.Type {
 	return spec.goType
 }
@@ -315,6 +479,20 @@ func (ps *parseState) parseParamSpecVar(field *types.Var, astField *ast.Field, d
 		}
 	}
 
+	deprecated := ""
+	if v, ok := pragmas["deprecated"]; ok {
+		var err error
+		deprecated, err = parseDeprecatedPragma(v)
+		if err != nil {
+			return paramSpec{}, err
+		}
+	}
+
+	constraints, err := parseArgConstraintPragmas(pragmas)
+	if err != nil {
+		return paramSpec{}, err
+	}
+
 	// ignore ctx arg for parsing type reference
 	isContext := paramType.String() == contextTypename
 	var typeSpec ParsedType
@@ -348,6 +526,8 @@ func (ps *parseState) parseParamSpecVar(field *types.Var, astField *ast.Field, d
 		description:  comment,
 		defaultPath:  defaultPath,
 		ignore:       ignore,
+		deprecated:   deprecated,
+		constraints:  constraints,
 	}, nil
 }
 
@@ -383,4 +563,61 @@ type paramSpec struct {
 	// The ignore patterns are applied to the input directory, and
 	// matching entries are filtered out, in a cache-efficient manner.
 	ignore []string
+
+	// The reason the argument is deprecated, if it is.
+	deprecated string
+
+	// Constraints on the values of the argument, enforced by the engine.
+	constraints argConstraints
+}
+
+type argConstraints struct {
+	pattern       string
+	min           *float64
+	max           *float64
+	minLength     *int
+	allowedValues []string
+}
+
+// parseArgConstraintPragmas parses the pragmas constraining the values of an
+// argument: +pattern, +min, +max, +minLength and +allowedValues.
+func parseArgConstraintPragmas(pragmas map[string]string) (argConstraints, error) {
+	var c argConstraints
+	if v, ok := pragmas["pattern"]; ok {
+		// the pattern is taken literally, so backslashes don't need escaping
+		if strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) && len(v) >= 2 {
+			v = v[1 : len(v)-1]
+		}
+		if _, err := regexp.Compile(v); err != nil {
+			return c, fmt.Errorf("pattern pragma %q must be a valid regular expression: %w", v, err)
+		}
+		c.pattern = v
+	}
+	for _, bound := range []struct {
+		name string
+		dest **float64
+	}{{"min", &c.min}, {"max", &c.max}} {
+		v, ok := pragmas[bound.name]
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return c, fmt.Errorf("%s pragma %q must be a number: %w", bound.name, v, err)
+		}
+		*bound.dest = &f
+	}
+	if v, ok := pragmas["minLength"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return c, fmt.Errorf("minLength pragma %q must be a non-negative integer", v)
+		}
+		c.minLength = &n
+	}
+	if v, ok := pragmas["allowedValues"]; ok {
+		if err := json.Unmarshal([]byte(v), &c.allowedValues); err != nil {
+			return c, fmt.Errorf("allowedValues pragma '%s', must be a valid JSON array of strings: %w", v, err)
+		}
+	}
+	return c, nil
 }
@@ -135,6 +135,12 @@ func (ps *parseState) parseGoStruct(t *types.Struct, named *types.Named) (*parse
 				fieldSpec.isPrivate, _ = strconv.ParseBool(v)
 			}
 		}
+		if v, ok := pragmas["deprecated"]; ok {
+			fieldSpec.deprecated, err = parseDeprecatedPragma(v)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", fieldSpec.goName, err)
+			}
+		}
 
 		fieldSpec.doc = comment
 
@@ -213,6 +219,9 @@ func (spec *parsedObjectType) TypeDefCode() (*Statement, error) {
 		if field.sourceMap != nil {
 			withFieldOpts = append(withFieldOpts, Id("SourceMap").Op(":").Add(field.sourceMap.TypeDefCode()))
 		}
+		if field.deprecated != "" {
+			withFieldOpts = append(withFieldOpts, Id("Deprecated").Op(":").Lit(field.deprecated))
+		}
 		if len(withFieldOpts) > 0 {
 			withFieldArgsCode = append(withFieldArgsCode,
 				Id("dagger").Dot("TypeDefWithFieldOpts").Values(withFieldOpts...),
@@ -516,6 +525,8 @@ type fieldSpec struct {
 
 	// isPrivate is true if the field is marked with the +private pragma
 	isPrivate bool
+	// deprecated is the reason set with the +deprecated pragma, if any
+	deprecated string
 	// goName is the name of the field in the Go struct. It may be different than name if the user changed the name of the field via a json tag
 	goName string
 
@@ -79,6 +79,19 @@ func (ps *parseState) parseGoTypeReference(typ types.Type, named *types.Named, i
 			underlying: elemTypeSpec,
 		}, nil
 
+	case *types.Map:
+		if key, ok := t.Key().Underlying().(*types.Basic); !ok || key.Info()&types.IsString == 0 {
+			return nil, fmt.Errorf("map keys must be strings, got %s", t.Key())
+		}
+		valueTypeSpec, err := ps.parseGoTypeReference(t.Elem(), nil, isPtr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse map value type: %w", err)
+		}
+		return &parsedMapType{
+			goType:     t,
+			underlying: valueTypeSpec,
+		}, nil
+
 	case *types.Basic:
 		parsedType := &parsedPrimitiveType{goType: t, isPtr: isPtr}
 		if named != nil {
@@ -118,6 +131,9 @@ func (ps *parseState) parseGoTypeReference(typ types.Type, named *types.Named, i
 		if typeName == "" {
 			return nil, fmt.Errorf("struct types must be named")
 		}
+		if ps.isGoUnion(named) {
+			return ps.parseGoUnion(t, named, isPtr)
+		}
 		moduleName := ""
 		if !ps.isDaggerGenerated(named.Obj()) {
 			moduleName = ps.moduleName
@@ -241,6 +257,30 @@ func (spec *parsedSliceType) GoSubTypes() []types.Type {
 	return spec.underlying.GoSubTypes()
 }
 
+// parsedMapType is a parsed type that is a map of string keys to other types
+type parsedMapType struct {
+	goType     *types.Map
+	underlying ParsedType // the value TypeSpec
+}
+
+var _ ParsedType = &parsedMapType{}
+
+func (spec *parsedMapType) TypeDefCode() (*Statement, error) {
+	underlyingCode, err := spec.underlying.TypeDefCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate underlying type code: %w", err)
+	}
+	return Qual("dag", "TypeDef").Call().Dot("WithMapOf").Call(underlyingCode), nil
+}
+
+func (spec *parsedMapType) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedMapType) GoSubTypes() []types.Type {
+	return spec.underlying.GoSubTypes()
+}
+
 // parsedObjectTypeReference is a parsed object type that is referred to just by name rather
 // than with the full type definition
 type parsedObjectTypeReference struct {
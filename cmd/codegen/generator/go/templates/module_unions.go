@@ -0,0 +1,178 @@
+package templates
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+
+	. "github.com/dave/jennifer/jen" //nolint:stylecheck
+)
+
+// isGoUnion returns true if the named struct type is marked with a `+union` pragma.
+func (ps *parseState) isGoUnion(named *types.Named) bool {
+	if named == nil || ps.isDaggerGenerated(named.Obj()) {
+		return false
+	}
+	astSpec, err := ps.astSpecForObj(named.Obj())
+	if err != nil {
+		return false
+	}
+	doc := docForAstSpec(astSpec)
+	if doc == nil {
+		return false
+	}
+	pragmas, _ := parsePragmaComment(doc.Text())
+	_, ok := pragmas["union"]
+	return ok
+}
+
+// parseGoUnion parses a struct marked with a `+union` pragma. Each exported
+// field of the struct is a member of the union, and exactly one of them is
+// set at a time, so every field needs a type whose zero value is nil.
+func (ps *parseState) parseGoUnion(t *types.Struct, named *types.Named, isPtr bool) (*parsedUnionType, error) {
+	spec := &parsedUnionType{
+		name:   named.Obj().Name(),
+		isPtr:  isPtr,
+		goType: named,
+	}
+
+	astSpec, err := ps.astSpecForObj(named.Obj())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find decl for union %s: %w", spec.name, err)
+	}
+	if doc := docForAstSpec(astSpec); doc != nil {
+		_, spec.doc = parsePragmaComment(doc.Text())
+	}
+	spec.sourceMap = ps.sourceMap(astSpec)
+
+	astTypeSpec, ok := astSpec.(*ast.TypeSpec)
+	if !ok {
+		return nil, fmt.Errorf("expected type spec, got %T", astSpec)
+	}
+	astStructType, ok := astTypeSpec.Type.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("expected type spec to be a struct, got %T", astTypeSpec.Type)
+	}
+
+	astFields := unpackASTFields(astStructType.Fields)
+	for i := range t.NumFields() {
+		field := t.Field(i)
+		if !field.Exported() {
+			continue
+		}
+
+		memberSpec := &unionMemberSpec{name: field.Name()}
+		tag := reflect.StructTag(t.Tag(i))
+		if dt := tag.Get("json"); dt != "" {
+			dt, _, _ = strings.Cut(dt, ",")
+			if dt == "-" {
+				continue
+			}
+			memberSpec.name = dt
+		}
+
+		fieldType := field.Type()
+		switch x := fieldType.Underlying().(type) {
+		case *types.Pointer:
+			// the pointer only marks the member as unset, it doesn't make it optional
+			fieldType = x.Elem()
+		case *types.Slice, *types.Map:
+		default:
+			return nil, fmt.Errorf("union %s member %s must be a pointer, slice or map", spec.name, field.Name())
+		}
+		memberSpec.typeSpec, err = ps.parseGoTypeReference(fieldType, nil, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse union %s member %s: %w", spec.name, field.Name(), err)
+		}
+
+		_, docComment := parsePragmaComment(astFields[i].Doc.Text())
+		_, lineComment := parsePragmaComment(astFields[i].Comment.Text())
+		memberSpec.doc = strings.TrimSpace(docComment)
+		if memberSpec.doc == "" {
+			memberSpec.doc = strings.TrimSpace(lineComment)
+		}
+
+		spec.members = append(spec.members, memberSpec)
+	}
+	if len(spec.members) == 0 {
+		return nil, fmt.Errorf("union %s must have at least one exported field", spec.name)
+	}
+
+	return spec, nil
+}
+
+// parsedUnionType is a parsed struct marked with a `+union` pragma. Unions
+// aren't registered with the module on their own, so unlike objects, the
+// full definition is emitted everywhere the type is referenced.
+type parsedUnionType struct {
+	name      string
+	doc       string
+	sourceMap *sourceMap
+
+	members []*unionMemberSpec
+
+	isPtr  bool
+	goType *types.Named
+}
+
+type unionMemberSpec struct {
+	name     string
+	doc      string
+	typeSpec ParsedType
+}
+
+var _ ParsedType = &parsedUnionType{}
+
+func (spec *parsedUnionType) TypeDefCode() (*Statement, error) {
+	withUnionArgsCode := []Code{
+		Lit(spec.name),
+	}
+	withUnionOptsCode := []Code{}
+	if spec.doc != "" {
+		withUnionOptsCode = append(withUnionOptsCode, Id("Description").Op(":").Lit(strings.TrimSpace(spec.doc)))
+	}
+	if spec.sourceMap != nil {
+		withUnionOptsCode = append(withUnionOptsCode, Id("SourceMap").Op(":").Add(spec.sourceMap.TypeDefCode()))
+	}
+	if len(withUnionOptsCode) > 0 {
+		withUnionArgsCode = append(withUnionArgsCode, Id("dagger").Dot("TypeDefWithUnionOpts").Values(withUnionOptsCode...))
+	}
+	typeDefCode := Qual("dag", "TypeDef").Call().Dot("WithUnion").Call(withUnionArgsCode...)
+
+	for _, member := range spec.members {
+		memberTypeDefCode, err := member.typeSpec.TypeDefCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate type def code for union member %s: %w", member.name, err)
+		}
+		withMemberArgsCode := []Code{
+			Lit(member.name),
+			memberTypeDefCode,
+		}
+		if member.doc != "" {
+			withMemberArgsCode = append(withMemberArgsCode, Id("dagger").Dot("TypeDefWithUnionMemberOpts").Values(
+				Id("Description").Op(":").Lit(member.doc),
+			))
+		}
+		typeDefCode = dotLine(typeDefCode, "WithUnionMember").Call(withMemberArgsCode...)
+	}
+
+	if spec.isPtr {
+		typeDefCode = dotLine(typeDefCode, "WithOptional").Call(Lit(true))
+	}
+	return typeDefCode, nil
+}
+
+func (spec *parsedUnionType) GoType() types.Type {
+	return spec.goType
+}
+
+func (spec *parsedUnionType) GoSubTypes() []types.Type {
+	// the union itself isn't a sub type: it's defined inline, not registered
+	var subTypes []types.Type
+	for _, member := range spec.members {
+		subTypes = append(subTypes, member.typeSpec.GoSubTypes()...)
+	}
+	return subTypes
+}
@@ -384,6 +384,12 @@ func dispatch(ctx context.Context) (rerr error) {
 		}
 		return err
 	}
+	if streamed, ok := result.(streamedResult); ok {
+		result, err = streamed.returnItems(ctx, fnCall)
+		if err != nil {
+			return err
+		}
+	}
 	resultBytes, err := json.Marshal(result)
 	if err != nil {
 		return fmt.Errorf("marshal: %w", err)
@@ -393,6 +399,75 @@ func dispatch(ctx context.Context) (rerr error) {
 		return fmt.Errorf("store return value: %w", err)
 	}
 	return nil
+}
+
+// streamedResult is the result of a streaming function: a channel or an
+// io.Reader whose items are returned to the caller as they arrive.
+type streamedResult struct {
+	result any
+}
+
+func streamResult(result any, err error) (any, error) {
+	return streamedResult{result}, err
+}
+
+// returnItems returns each item of the result as soon as it's available, and
+// then the full result: the elements received from a channel as a slice, or
+// the lines read from an io.Reader as a string.
+func (streamed streamedResult) returnItems(ctx context.Context, fnCall *dagger.FunctionCall) (any, error) {
+	returnItem := func(item any) error {
+		itemBytes, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("marshal item: %w", err)
+		}
+		if ` + voidRet + ` := fnCall.ReturnItem(ctx, dagger.JSON(itemBytes)); err != nil {
+			return fmt.Errorf("return item: %w", err)
+		}
+		return nil
+	}
+
+	if streamed.result == nil {
+		// a nil io.Reader
+		return "", nil
+	}
+	if r, ok := streamed.result.(io.Reader); ok {
+		var full strings.Builder
+		lines := bufio.NewReader(r)
+		for {
+			line, err := lines.ReadString('\n')
+			if line != "" {
+				full.WriteString(line)
+				if err := returnItem(line); err != nil {
+					return nil, err
+				}
+			}
+			if err == io.EOF {
+				return full.String(), nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("read result: %w", err)
+			}
+		}
+	}
+
+	ch := reflect.ValueOf(streamed.result)
+	if ch.Kind() != reflect.Chan {
+		return streamed.result, nil
+	}
+	items := reflect.MakeSlice(reflect.SliceOf(ch.Type().Elem()), 0, 0)
+	if ch.IsNil() {
+		return items.Interface(), nil
+	}
+	for {
+		item, ok := ch.Recv()
+		if !ok {
+			return items.Interface(), nil
+		}
+		items = reflect.Append(items, item)
+		if err := returnItem(item.Interface()); err != nil {
+			return nil, err
+		}
+	}
 }`
 }
 
@@ -722,7 +797,11 @@ func (ps *parseState) fillObjectFunctionCase(
 			return fmt.Errorf("second return value must be error, have %s", results.At(1).Type().String())
 		}
 
-		statements = append(statements, Return(callStatement))
+		if isStreamedResult(results.At(0).Type()) {
+			statements = append(statements, Return(Id("streamResult").Call(callStatement)))
+		} else {
+			statements = append(statements, Return(callStatement))
+		}
 		cases[objName] = append(cases[objName], Case(Lit(caseName)).Block(statements...))
 
 		if err := ps.fillObjectFunctionCases(results.At(0).Type(), cases); err != nil {
@@ -740,7 +819,11 @@ func (ps *parseState) fillObjectFunctionCase(
 		} else {
 			// non-error return
 
-			statements = append(statements, Return(callStatement, Nil()))
+			if isStreamedResult(results.At(0).Type()) {
+				statements = append(statements, Return(Id("streamResult").Call(callStatement, Nil())))
+			} else {
+				statements = append(statements, Return(callStatement, Nil()))
+			}
 			cases[objName] = append(cases[objName], Case(Lit(caseName)).Block(statements...))
 
 			if err := ps.fillObjectFunctionCases(results.At(0).Type(), cases); err != nil {
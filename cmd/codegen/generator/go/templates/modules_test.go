@@ -1,6 +1,7 @@
 package templates
 
 import (
+	"go/types"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -107,3 +108,111 @@ func TestParsePragmaComment(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCachePragma(t *testing.T) {
+	for _, test := range []struct {
+		value  string
+		policy string
+		ttl    string
+	}{
+		{value: `"never"`, policy: "FunctionCachePolicyNever"},
+		{value: "session", policy: "FunctionCachePolicyPerSession"},
+		{value: `"10m"`, policy: "FunctionCachePolicyTtl", ttl: "10m"},
+		{value: "1h30m", policy: "FunctionCachePolicyTtl", ttl: "1h30m"},
+	} {
+		t.Run(test.value, func(t *testing.T) {
+			policy, ttl, err := parseCachePragma(test.value)
+			require.NoError(t, err)
+			require.Equal(t, test.policy, policy)
+			require.Equal(t, test.ttl, ttl)
+		})
+	}
+
+	for _, value := range []string{"", "forever", `"10"`, "500ms"} {
+		t.Run(value, func(t *testing.T) {
+			_, _, err := parseCachePragma(value)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseDeprecatedPragma(t *testing.T) {
+	for _, test := range []struct {
+		value  string
+		reason string
+	}{
+		{value: `"use build2"`, reason: "use build2"},
+		{value: "use build2", reason: "use build2"},
+		{value: `"use \"build2\" instead"`, reason: `use "build2" instead`},
+	} {
+		t.Run(test.value, func(t *testing.T) {
+			reason, err := parseDeprecatedPragma(test.value)
+			require.NoError(t, err)
+			require.Equal(t, test.reason, reason)
+		})
+	}
+
+	for _, value := range []string{"", `""`, `"  "`} {
+		t.Run(value, func(t *testing.T) {
+			_, err := parseDeprecatedPragma(value)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseArgConstraintPragmas(t *testing.T) {
+	pragmas, _ := parsePragmaComment(`+pattern="^v\d+$"
++min=0
++max=64
++minLength=1
++allowedValues=["amd64","arm64"]
+`)
+	c, err := parseArgConstraintPragmas(pragmas)
+	require.NoError(t, err)
+	require.Equal(t, `^v\d+$`, c.pattern)
+	require.NotNil(t, c.min)
+	require.Equal(t, 0.0, *c.min)
+	require.NotNil(t, c.max)
+	require.Equal(t, 64.0, *c.max)
+	require.NotNil(t, c.minLength)
+	require.Equal(t, 1, *c.minLength)
+	require.Equal(t, []string{"amd64", "arm64"}, c.allowedValues)
+
+	for _, comment := range []string{
+		`+pattern="("`,
+		`+min=one`,
+		`+minLength=-1`,
+		`+allowedValues=amd64`,
+	} {
+		t.Run(comment, func(t *testing.T) {
+			pragmas, _ := parsePragmaComment(comment)
+			_, err := parseArgConstraintPragmas(pragmas)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestIsStreamedResult(t *testing.T) {
+	ioPkg := types.NewPackage("io", "io")
+	reader := types.NewNamed(types.NewTypeName(0, ioPkg, "Reader", nil), types.NewInterfaceType(nil, nil), nil)
+	writer := types.NewNamed(types.NewTypeName(0, ioPkg, "Writer", nil), types.NewInterfaceType(nil, nil), nil)
+	str := types.Typ[types.String]
+
+	for _, test := range []struct {
+		name     string
+		typ      types.Type
+		streamed bool
+	}{
+		{name: "receive channel", typ: types.NewChan(types.RecvOnly, str), streamed: true},
+		{name: "channel", typ: types.NewChan(types.SendRecv, str), streamed: true},
+		{name: "send channel", typ: types.NewChan(types.SendOnly, str), streamed: false},
+		{name: "reader", typ: reader, streamed: true},
+		{name: "writer", typ: writer, streamed: false},
+		{name: "slice", typ: types.NewSlice(str), streamed: false},
+		{name: "string", typ: str, streamed: false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.streamed, isStreamedResult(test.typ))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package templates
 
 import (
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -39,6 +40,7 @@ func (funcs typescriptTemplateFuncs) FuncMap() template.FuncMap {
 		"FormatInputType":           commonFunc.FormatInputType,
 		"FormatOutputType":          commonFunc.FormatOutputType,
 		"FormatEnum":                funcs.formatEnum,
+		"FormatJSONType":            funcs.formatJSONType,
 		"FormatName":                funcs.formatName,
 		"QueryToClient":             funcs.queryToClient,
 		"GetOptionalArgs":           funcs.getOptionalArgs,
@@ -62,6 +64,7 @@ func (funcs typescriptTemplateFuncs) FuncMap() template.FuncMap {
 		"ToUpperCase":               commonFunc.ToUpperCase,
 		"ToSingleType":              funcs.toSingleType,
 		"GetEnumValues":             funcs.getEnumValues,
+		"GetJSONValues":             funcs.getJSONValues,
 		"CheckVersionCompatibility": commonFunc.CheckVersionCompatibility,
 		"ModuleRelPath":             funcs.moduleRelPath,
 		"FormatProtected":           funcs.formatProtected,
@@ -269,6 +272,50 @@ func (funcs typescriptTemplateFuncs) getEnumValues(values introspection.InputVal
 	return enums
 }
 
+// getJSONValues returns the values holding a map or a union, which are sent
+// as JSON.
+func (funcs typescriptTemplateFuncs) getJSONValues(values introspection.InputValues) introspection.InputValues {
+	jsons := introspection.InputValues{}
+
+	for _, v := range values {
+		if v.Directives.JSONType() != nil {
+			jsons = append(jsons, v)
+		}
+	}
+
+	return jsons
+}
+
+// formatJSONType formats the type of a map or a union, given by the jsonType
+// directive, into a TS equivalent.
+func (funcs typescriptTemplateFuncs) formatJSONType(t *introspection.JSONTypeRef) string {
+	switch t.Kind {
+	case "STRING_KIND":
+		return "string"
+	case "INTEGER_KIND", "FLOAT_KIND":
+		return "number"
+	case "BOOLEAN_KIND":
+		return "boolean"
+	case "VOID_KIND":
+		return "void"
+	case "LIST_KIND":
+		if t.Of.Kind == "UNION_KIND" {
+			return "(" + funcs.formatJSONType(t.Of) + ")[]"
+		}
+		return funcs.formatJSONType(t.Of) + "[]"
+	case "MAP_KIND":
+		return "Record<string, " + funcs.formatJSONType(t.Of) + ">"
+	case "UNION_KIND":
+		members := make([]string, 0, len(t.Members))
+		for _, member := range t.Members {
+			members = append(members, fmt.Sprintf("{ %s: %s }", member.Name, funcs.formatJSONType(member.Type)))
+		}
+		return strings.Join(members, " | ")
+	default:
+		return funcs.formatName(t.Name)
+	}
+}
+
 func (funcs typescriptTemplateFuncs) getRequiredArgs(values introspection.InputValues) introspection.InputValues {
 	required, _ := funcs.splitRequiredOptionalArgs(values)
 	return required
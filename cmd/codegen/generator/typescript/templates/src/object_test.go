@@ -26,6 +26,19 @@ func TestObject(t *testing.T) {
 	require.Equal(t, want, b.String())
 }
 
+func TestObjectJSONTypes(t *testing.T) {
+	tmpl := templateHelper(t)
+
+	object := objectInit(t, jsonTypesJSON)
+
+	var b bytes.Buffer
+	err := tmpl.ExecuteTemplate(&b, "object", object)
+
+	want := updateAndGetFixtures(t, "testdata/object_test_json_types_want.ts", b.String())
+	require.NoError(t, err)
+	require.Equal(t, want, b.String())
+}
+
 func objectInit(t *testing.T, jsonString string) *introspection.Type {
 	t.Helper()
 	var object introspection.Type
@@ -134,3 +147,115 @@ var containerExecArgsJSON = `
         "possibleTypes": null
       }
 `
+
+var jsonTypesJSON = `
+      {
+        "kind": "OBJECT",
+        "name": "Labeler",
+        "description": "",
+        "fields": [
+          {
+            "name": "labels",
+            "description": "",
+            "args": [],
+            "type": {
+              "kind": "NON_NULL",
+              "name": null,
+              "ofType": {
+                "kind": "SCALAR",
+                "name": "JSON",
+                "ofType": null
+              }
+            },
+            "directives": [
+              {
+                "name": "jsonType",
+                "args": [
+                  {
+                    "name": "typeDef",
+                    "value": "{\"kind\":\"MAP_KIND\",\"of\":{\"kind\":\"STRING_KIND\"}}"
+                  }
+                ]
+              }
+            ],
+            "isDeprecated": false,
+            "deprecationReason": null
+          },
+          {
+            "name": "withSource",
+            "description": "",
+            "args": [
+              {
+                "name": "source",
+                "description": "",
+                "type": {
+                  "kind": "NON_NULL",
+                  "name": null,
+                  "ofType": {
+                    "kind": "SCALAR",
+                    "name": "JSON",
+                    "ofType": null
+                  }
+                },
+                "directives": [
+                  {
+                    "name": "jsonType",
+                    "args": [
+                      {
+                        "name": "typeDef",
+                        "value": "{\"kind\":\"UNION_KIND\",\"name\":\"Source\",\"members\":[{\"name\":\"directory\",\"type\":{\"kind\":\"OBJECT_KIND\",\"name\":\"Directory\"}},{\"name\":\"gitUrl\",\"type\":{\"kind\":\"STRING_KIND\"}}]}"
+                      }
+                    ]
+                  }
+                ],
+                "defaultValue": null
+              },
+              {
+                "name": "labels",
+                "description": "",
+                "type": {
+                  "kind": "LIST",
+                  "name": null,
+                  "ofType": {
+                    "kind": "NON_NULL",
+                    "name": null,
+                    "ofType": {
+                      "kind": "SCALAR",
+                      "name": "JSON",
+                      "ofType": null
+                    }
+                  }
+                },
+                "directives": [
+                  {
+                    "name": "jsonType",
+                    "args": [
+                      {
+                        "name": "typeDef",
+                        "value": "{\"kind\":\"LIST_KIND\",\"optional\":true,\"of\":{\"kind\":\"MAP_KIND\",\"of\":{\"kind\":\"STRING_KIND\"}}}"
+                      }
+                    ]
+                  }
+                ],
+                "defaultValue": null
+              }
+            ],
+            "type": {
+              "kind": "NON_NULL",
+              "name": null,
+              "ofType": {
+                "kind": "OBJECT",
+                "name": "Labeler",
+                "ofType": null
+              }
+            },
+            "isDeprecated": false,
+            "deprecationReason": null
+          }
+        ],
+        "inputFields": null,
+        "interfaces": [],
+        "enumValues": null,
+        "possibleTypes": null
+      }
+`
@@ -0,0 +1,52 @@
+
+export class Labeler extends BaseClient {
+  private readonly _labels?: JSON = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+   constructor(
+    ctx?: Context,
+     _labels?: JSON,
+   ) {
+     super(ctx)
+
+     this._labels = _labels
+   }
+  labels = async (): Promise<Record<string, string>> => {
+    if (this._labels) {
+      return loadJSONValue(this._ctx, {"kind":"MAP_KIND","of":{"kind":"STRING_KIND"}}, this._labels)
+    }
+
+    const ctx = this._ctx.select(
+      "labels",
+    )
+
+    const response: Awaited<JSON> = await ctx.execute()
+
+    
+    return loadJSONValue(ctx, {"kind":"MAP_KIND","of":{"kind":"STRING_KIND"}}, response)
+  }
+  withSource = (source: { directory: Directory } | { gitUrl: string }, opts?: LabelerWithSourceOpts): Labeler => {
+	const metadata = {
+	    source: { json_type: {"kind":"UNION_KIND","name":"Source","members":[{"name":"directory","type":{"kind":"OBJECT_KIND","name":"Directory"}},{"name":"gitUrl","type":{"kind":"STRING_KIND"}}]} },
+	    labels: { json_type: {"kind":"LIST_KIND","optional":true,"of":{"kind":"MAP_KIND","of":{"kind":"STRING_KIND"}}} },
+	}
+
+
+    const ctx = this._ctx.select(
+      "withSource",
+      { source, ...opts, __metadata: metadata },
+    )
+    return new Labeler(ctx)
+  }
+
+  /**
+   * Call the provided function with current Labeler.
+   *
+   * This is useful for reusability and readability by not breaking the calling chain.
+   */
+  with = (arg: (param: Labeler) => Labeler) => {
+    return arg(this)
+  }
+}
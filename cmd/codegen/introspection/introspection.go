@@ -2,6 +2,7 @@ package introspection
 
 import (
 	_ "embed"
+	"encoding/json"
 	"fmt"
 )
 
@@ -257,11 +258,13 @@ func (i InputValues) HasOptionals() bool {
 }
 
 type InputValue struct {
-	Name         string     `json:"name"`
-	Description  string     `json:"description"`
-	DefaultValue *string    `json:"defaultValue"`
-	TypeRef      *TypeRef   `json:"type"`
-	Directives   Directives `json:"directives"`
+	Name              string     `json:"name"`
+	Description       string     `json:"description"`
+	DefaultValue      *string    `json:"defaultValue"`
+	TypeRef           *TypeRef   `json:"type"`
+	IsDeprecated      bool       `json:"isDeprecated"`
+	DeprecationReason string     `json:"deprecationReason"`
+	Directives        Directives `json:"directives"`
 }
 
 func (v InputValue) IsOptional() bool {
@@ -311,6 +314,50 @@ func (t *Directives) SourceMap() *SourceMap {
 	}
 }
 
+// JSONTypeRef is the definition of the type of a JSON field or argument
+// holding a map or a union, as given by the jsonType directive.
+type JSONTypeRef struct {
+	// Kind is the TypeDefKind of the type (e.g. "MAP_KIND").
+	Kind     string `json:"kind"`
+	Optional bool   `json:"optional,omitempty"`
+	// Name is the name of an object, interface, enum, scalar or union.
+	Name string `json:"name,omitempty"`
+	// Of is the type of the elements of a list, or of the values of a map.
+	Of      *JSONTypeRef          `json:"of,omitempty"`
+	Members []*JSONUnionMemberRef `json:"members,omitempty"`
+
+	raw string
+}
+
+// JSONUnionMemberRef is a member of a union in a JSONTypeRef.
+type JSONUnionMemberRef struct {
+	Name string       `json:"name"`
+	Type *JSONTypeRef `json:"type"`
+}
+
+// String returns the JSON encoding of the type, as given by the directive.
+func (ref *JSONTypeRef) String() string {
+	return ref.raw
+}
+
+// JSONType returns the type given by the jsonType directive, if any.
+func (t *Directives) JSONType() *JSONTypeRef {
+	d := t.Directive("jsonType")
+	if d == nil {
+		return nil
+	}
+	arg := d.Arg("typeDef")
+	if arg == nil || arg.Value == nil {
+		return nil
+	}
+	var ref JSONTypeRef
+	if err := json.Unmarshal([]byte(*arg.Value), &ref); err != nil {
+		return nil
+	}
+	ref.raw = *arg.Value
+	return &ref
+}
+
 type Directive struct {
 	Name string          `json:"name"`
 	Args []*DirectiveArg `json:"args"`
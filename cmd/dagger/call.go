@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
 	"strings"
 
@@ -100,20 +101,43 @@ available functions.
 func functionListRun(o functionProvider, writer io.Writer) error {
 	fns, skipped := GetSupportedFunctions(o)
 
+	// only show the cache and deprecation columns if a function needs them
+	showCache := slices.ContainsFunc(fns, func(fn *modFunction) bool {
+		return fn.Cache() != ""
+	})
+	showDeprecated := slices.ContainsFunc(fns, func(fn *modFunction) bool {
+		return fn.Deprecated != nil
+	})
+
 	tw := tabwriter.NewWriter(writer, 0, 0, 3, ' ', tabwriter.DiscardEmptyColumns)
-	fmt.Fprintf(tw, "%s\t%s\n",
-		termenv.String("Name").Bold(),
-		termenv.String("Description").Bold(),
-	)
+	header := []string{termenv.String("Name").Bold().String()}
+	if showCache {
+		header = append(header, termenv.String("Cache").Bold().String())
+	}
+	if showDeprecated {
+		header = append(header, termenv.String("Deprecated").Bold().String())
+	}
+	header = append(header, termenv.String("Description").Bold().String())
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
 	// List functions on the final object
 	sort.Slice(fns, func(i, j int) bool {
 		return fns[i].Name < fns[j].Name
 	})
 	for _, fn := range fns {
-		fmt.Fprintf(tw, "%s\t%s\n",
-			fn.CmdName(),
-			fn.Short(),
-		)
+		row := []string{fn.CmdName()}
+		if showCache {
+			cache := fn.Cache()
+			if cache == "" {
+				cache = "-"
+			}
+			row = append(row, cache)
+		}
+		if showDeprecated {
+			row = append(row, fn.DeprecationNote())
+		}
+		row = append(row, fn.Short())
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
 	}
 	if len(skipped) > 0 {
 		msg := fmt.Sprintf("Skipped %d function(s) with unsupported types: %s", len(skipped), strings.Join(skipped, ", "))
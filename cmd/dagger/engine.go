@@ -74,6 +74,7 @@ func withEngine(
 		}
 
 		params.DisableHostRW = disableHostRW
+		params.HostRegistryAuth = hostRegistryAuth
 
 		params.EngineCallback = Frontend.ConnectedToEngine
 		params.CloudURLCallback = Frontend.SetCloudURL
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -20,6 +21,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"dagger.io/dagger"
+	"dagger.io/dagger/querybuilder"
 )
 
 type UnsupportedFlagError struct {
@@ -255,6 +257,185 @@ func (v *enumValue) Set(s string) error {
 	return fmt.Errorf("value should be one of %s", v.Type())
 }
 
+// newElementFlag returns a standalone flag for parsing a single value of the
+// given type, such as a value in a map or a member of a union.
+func newElementFlag(typeDef *modTypeDef) (*pflag.Flag, error) {
+	arg := &modFunctionArg{Name: "value", TypeDef: typeDef}
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	if err := arg.AddFlag(flags); err != nil {
+		return nil, err
+	}
+	return arg.GetFlag(flags)
+}
+
+// elementFlagValue returns the final value of a flag created with
+// newElementFlag, for encoding as JSON.
+func elementFlagValue(ctx context.Context, flag *pflag.Flag, dag *dagger.Client, modSrc *dagger.ModuleSource, typeDef *modTypeDef) (any, error) {
+	var v any = flag.Value
+	switch val := flag.Value.(type) {
+	case DaggerValue:
+		obj, err := val.Get(ctx, dag, modSrc, &modFunctionArg{Name: "value", TypeDef: typeDef})
+		if err != nil {
+			return nil, err
+		}
+		v = obj
+	case pflag.SliceValue:
+		v = val.GetSlice()
+	}
+	// objects are passed by ID
+	if obj, ok := v.(querybuilder.GraphQLMarshaller); ok {
+		return obj.XXX_GraphQLID(ctx)
+	}
+	return v, nil
+}
+
+// parseKeyValues parses comma-separated key=value pairs, as used for map and
+// union flags.
+func parseKeyValues(s string) ([][2]string, error) {
+	ss, err := readAsCSV(s)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	pairs := make([][2]string, 0, len(ss))
+	for _, s := range ss {
+		k, v, ok := strings.Cut(strings.TrimSpace(s), "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", s)
+		}
+		pairs = append(pairs, [2]string{k, v})
+	}
+	return pairs, nil
+}
+
+// mapValue is a pflag.Value that builds a map from key=value pairs, e.g.
+// --labels foo=bar,baz=qux. The flag can be repeated to add more entries.
+type mapValue struct {
+	valueType *modTypeDef
+	keys      []string
+	values    map[string]*pflag.Flag
+}
+
+var _ DaggerValue = &mapValue{}
+
+func (v *mapValue) newValue() (*pflag.Flag, error) {
+	return newElementFlag(v.valueType)
+}
+
+func (v *mapValue) Type() string {
+	return "key=" + v.valueType.String()
+}
+
+func (v *mapValue) String() string {
+	ss := make([]string, 0, len(v.keys))
+	for _, k := range v.keys {
+		ss = append(ss, k+"="+v.values[k].Value.String())
+	}
+	out, _ := writeAsCSV(ss)
+	return out
+}
+
+func (v *mapValue) Set(s string) error {
+	pairs, err := parseKeyValues(s)
+	if err != nil {
+		return err
+	}
+	if v.values == nil {
+		v.values = map[string]*pflag.Flag{}
+	}
+	for _, kv := range pairs {
+		k, s := kv[0], kv[1]
+		flag, err := v.newValue()
+		if err != nil {
+			return err
+		}
+		if err := flag.Value.Set(s); err != nil {
+			return fmt.Errorf("invalid value for key %q: %w", k, err)
+		}
+		if _, ok := v.values[k]; !ok {
+			v.keys = append(v.keys, k)
+		}
+		v.values[k] = flag
+	}
+	return nil
+}
+
+func (v *mapValue) Get(ctx context.Context, dag *dagger.Client, modSrc *dagger.ModuleSource, _ *modFunctionArg) (any, error) {
+	out := make(map[string]any, len(v.values))
+	for k, flag := range v.values {
+		val, err := elementFlagValue(ctx, flag, dag, modSrc, v.valueType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get value for key %q: %w", k, err)
+		}
+		out[k] = val
+	}
+	bs, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return dagger.JSON(bs), nil
+}
+
+// unionValue is a pflag.Value that sets one member of a union from a
+// member=value pair, e.g. --source directory=./src or --source git-url=https://...
+type unionValue struct {
+	typedef *modUnion
+	member  *modUnionMember
+	value   *pflag.Flag
+}
+
+var _ DaggerValue = &unionValue{}
+
+func (v *unionValue) Type() string {
+	names := make([]string, 0, len(v.typedef.Members))
+	for _, member := range v.typedef.Members {
+		names = append(names, cliName(member.Name))
+	}
+	return strings.Join(names, "|") + "=value"
+}
+
+func (v *unionValue) String() string {
+	if v.member == nil {
+		return ""
+	}
+	return cliName(v.member.Name) + "=" + v.value.Value.String()
+}
+
+func (v *unionValue) Set(s string) error {
+	name, s, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("expected member=value, with member one of: %s", strings.TrimSuffix(v.Type(), "=value"))
+	}
+	member := v.typedef.Member(name)
+	if member == nil {
+		return fmt.Errorf("unknown member %q, expected one of: %s", name, strings.TrimSuffix(v.Type(), "=value"))
+	}
+	flag, err := newElementFlag(member.TypeDef)
+	if err != nil {
+		return err
+	}
+	if err := flag.Value.Set(s); err != nil {
+		return fmt.Errorf("invalid value for member %q: %w", name, err)
+	}
+	v.member = member
+	v.value = flag
+	return nil
+}
+
+func (v *unionValue) Get(ctx context.Context, dag *dagger.Client, modSrc *dagger.ModuleSource, _ *modFunctionArg) (any, error) {
+	if v.member == nil {
+		return nil, fmt.Errorf("no union member set")
+	}
+	val, err := elementFlagValue(ctx, v.value, dag, modSrc, v.member.TypeDef)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(map[string]any{v.member.Name: val})
+	if err != nil {
+		return nil, err
+	}
+	return dagger.JSON(bs), nil
+}
+
 // containerValue is a pflag.Value that builds a dagger.Container from a
 // base image name.
 type containerValue struct {
@@ -945,6 +1126,30 @@ func (r *modFunctionArg) AddFlag(flags *pflag.FlagSet) error {
 				Type: "list of lists",
 			}
 		}
+
+	case dagger.TypeDefKindMapKind:
+		val := &mapValue{valueType: r.TypeDef.AsMap.ValueTypeDef}
+		if _, err := val.newValue(); err != nil {
+			return &UnsupportedFlagError{
+				Name: name,
+				Type: "map of " + r.TypeDef.AsMap.ValueTypeDef.String(),
+			}
+		}
+		flags.Var(val, name, usage)
+		return nil
+
+	case dagger.TypeDefKindUnionKind:
+		val := &unionValue{typedef: r.TypeDef.AsUnion}
+		for _, member := range val.typedef.Members {
+			if _, err := newElementFlag(member.TypeDef); err != nil {
+				return &UnsupportedFlagError{
+					Name: name,
+					Type: fmt.Sprintf("union with %q member", member.TypeDef.String()),
+				}
+			}
+		}
+		flags.Var(val, name, usage)
+		return nil
 	}
 
 	return &UnsupportedFlagError{Name: name}
@@ -422,6 +422,17 @@ func (fc *FuncCommand) addFlagsForFunction(cmd *cobra.Command, fn *modFunction)
 			"help:group",
 			[]string{"Arguments"},
 		)
+		if arg.Deprecated != nil {
+			flag := cmd.Flags().Lookup(arg.FlagName())
+			flag.Usage = strings.TrimSpace(fmt.Sprintf("%s (deprecated: %s)", flag.Usage, deprecationMessage(*arg.Deprecated)))
+		}
+		if constraints := arg.Constraints(); constraints != "" {
+			flag := cmd.Flags().Lookup(arg.FlagName())
+			flag.Usage = strings.TrimSpace(fmt.Sprintf("%s (%s)", flag.Usage, constraints))
+		}
+		if len(arg.AllowedValues) > 0 {
+			cmd.RegisterFlagCompletionFunc(arg.FlagName(), cobra.FixedCompletions(arg.AllowedValues, cobra.ShellCompDirectiveNoFileComp))
+		}
 		hasArgs = true
 	}
 
@@ -548,6 +559,12 @@ func (fc *FuncCommand) RunE(ctx context.Context, fn *modFunction) func(*cobra.Co
 			return handleResponse(fn.ReturnType, nil, o, e)
 		}
 
+		// Print the items of a streamed result as they arrive, unless the
+		// output needs the full result.
+		if fn.Streaming && outputPath == "" && !jsonOutput {
+			return streamResponse(ctx, q, fn.ReturnType, o)
+		}
+
 		var response any
 
 		if err := makeRequest(ctx, q, &response); err != nil {
@@ -618,6 +635,39 @@ func makeRequest(ctx context.Context, q *querybuilder.Selection, response any) e
 	return nil
 }
 
+// streamResponse subscribes to the result of a streaming function, printing
+// each element of a list on its own line, or each chunk of a string, as soon
+// as it's received.
+func streamResponse(ctx context.Context, q *querybuilder.Selection, returnType *modTypeDef, o io.Writer) error {
+	query, _ := q.Build(ctx)
+
+	slog.Debug("subscribing to query", "query", query)
+
+	var item any
+	var endsWithNewline bool
+	err := q.Bind(&item).Subscribe(ctx, func() error {
+		buf := new(bytes.Buffer)
+		if returnType.AsList != nil {
+			if err := printResponse(buf, item, returnType.AsList.ElementTypeDef); err != nil {
+				return err
+			}
+			fmt.Fprintln(buf)
+		} else if err := printPlainResult(buf, item); err != nil {
+			return err
+		}
+		if buf.Len() == 0 {
+			return nil
+		}
+		endsWithNewline = bytes.HasSuffix(buf.Bytes(), []byte("\n"))
+		_, err := buf.WriteTo(o)
+		return err
+	})
+	if stdoutIsTTY && !endsWithNewline {
+		fmt.Fprintln(o)
+	}
+	return err
+}
+
 func handleResponse(returnType *modTypeDef, response any, o, e io.Writer) error {
 	if returnType.Kind == dagger.TypeDefKindVoidKind {
 		return nil
@@ -705,6 +755,13 @@ func printEncodedID(w io.Writer, encodedID string) error {
 }
 
 func printResponse(w io.Writer, response any, typeDef *modTypeDef) error {
+	if typeDef != nil && (typeDef.AsMap != nil || typeDef.AsUnion != nil) {
+		// maps and unions are returned as a JSON-encoded string
+		if s, ok := response.(string); ok && json.Valid([]byte(s)) {
+			response = json.RawMessage(s)
+		}
+	}
+
 	if jsonOutput {
 		// disable HTML escaping to improve readability
 		encoder := json.NewEncoder(w)
@@ -750,6 +807,8 @@ func printPlainResult(w io.Writer, r any) error {
 		return nil
 	case string:
 		fmt.Fprint(w, t)
+	case json.RawMessage:
+		fmt.Fprint(w, string(t))
 	default:
 		fmt.Fprintf(w, "%+v", t)
 	}
@@ -59,6 +59,7 @@ var (
 	interactiveCommandParsed []string
 	web                      bool
 	noExit                   bool
+	hostRegistryAuth, _      = strconv.ParseBool(os.Getenv("DAGGER_HOST_REGISTRY_AUTH"))
 
 	dotOutputFilePath string
 	dotFocusField     string
@@ -233,6 +234,7 @@ func installGlobalFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&interactiveCommand, "interactive-command", "/bin/sh", "Change the default command for interactive mode")
 	flags.BoolVarP(&web, "web", "w", false, "Open trace URL in a web browser")
 	flags.BoolVarP(&noExit, "no-exit", "E", false, "Leave the TUI running after completion")
+	flags.BoolVar(&hostRegistryAuth, "host-registry-auth", hostRegistryAuth, "Use registry credentials from the host's Docker config, running credential helpers on demand")
 
 	flags.StringVar(&dotOutputFilePath, "dot-output", "", "If set, write the calls made during execution to a dot file at the given path before exiting")
 	flags.StringVar(&dotFocusField, "dot-focus-field", "", "In dot output, filter out vertices that aren't this field or descendents of this field")
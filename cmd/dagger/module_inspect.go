@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"dagger.io/dagger"
 	"dagger.io/dagger/telemetry"
@@ -548,6 +550,14 @@ func (m *moduleDef) LoadTypeDef(typeDef *modTypeDef) {
 		if typeDef.AsList != nil {
 			m.LoadTypeDef(typeDef.AsList.ElementTypeDef)
 		}
+		if typeDef.AsMap != nil {
+			m.LoadTypeDef(typeDef.AsMap.ValueTypeDef)
+		}
+		if typeDef.AsUnion != nil {
+			for _, member := range typeDef.AsUnion.Members {
+				m.LoadTypeDef(member.TypeDef)
+			}
+		}
 	})
 }
 
@@ -570,6 +580,8 @@ type modTypeDef struct {
 	AsList      *modList
 	AsScalar    *modScalar
 	AsEnum      *modEnum
+	AsMap       *modMap
+	AsUnion     *modUnion
 
 	// once protects concurrent update from LoadTypeDef
 	once sync.Once
@@ -599,6 +611,10 @@ func (t *modTypeDef) String() string {
 		return t.AsInterface.Name
 	case dagger.TypeDefKindListKind:
 		return "[]" + t.AsList.ElementTypeDef.String()
+	case dagger.TypeDefKindMapKind:
+		return "map[string]" + t.AsMap.ValueTypeDef.String()
+	case dagger.TypeDefKindUnionKind:
+		return t.AsUnion.Name
 	default:
 		// this should never happen because all values for kind are covered,
 		// unless a new one is added and this code isn't updated
@@ -626,6 +642,10 @@ func (t *modTypeDef) KindDisplay() string {
 		return "Interface"
 	case dagger.TypeDefKindListKind:
 		return "List of " + strings.ToLower(t.AsList.ElementTypeDef.KindDisplay()) + "s"
+	case dagger.TypeDefKindMapKind:
+		return "Map of " + strings.ToLower(t.AsMap.ValueTypeDef.KindDisplay()) + "s"
+	case dagger.TypeDefKindUnionKind:
+		return "Union"
 	default:
 		return ""
 	}
@@ -652,6 +672,10 @@ func (t *modTypeDef) Description() string {
 		return t.AsInterface.Description
 	case dagger.TypeDefKindListKind:
 		return t.AsList.ElementTypeDef.Description()
+	case dagger.TypeDefKindMapKind:
+		return t.AsMap.ValueTypeDef.Description()
+	case dagger.TypeDefKindUnionKind:
+		return t.AsUnion.Description
 	default:
 		// this should never happen because all values for kind are covered,
 		// unless a new one is added and this code isn't updated
@@ -838,6 +862,7 @@ func (e *modEnum) ValueNames() []string {
 type modEnumValue struct {
 	Name        string
 	Description string
+	Deprecated  *string
 }
 
 type modInput struct {
@@ -851,11 +876,42 @@ type modList struct {
 	ElementTypeDef *modTypeDef
 }
 
+// modMap is a representation of dagger.MapTypeDef.
+type modMap struct {
+	ValueTypeDef *modTypeDef
+}
+
+// modUnion is a representation of dagger.UnionTypeDef.
+type modUnion struct {
+	Name        string
+	Description string
+	Members     []*modUnionMember
+}
+
+// modUnionMember is a representation of dagger.UnionMemberTypeDef.
+type modUnionMember struct {
+	Name        string
+	Description string
+	TypeDef     *modTypeDef
+}
+
+// Member returns the member with the given name, matching it as a flag
+// name too (e.g. git-url for gitUrl).
+func (u *modUnion) Member(name string) *modUnionMember {
+	for _, member := range u.Members {
+		if strings.EqualFold(member.Name, name) || cliName(member.Name) == name {
+			return member
+		}
+	}
+	return nil
+}
+
 // modField is a representation of dagger.FieldTypeDef.
 type modField struct {
 	Name        string
 	Description string
 	TypeDef     *modTypeDef
+	Deprecated  *string
 }
 
 func (f *modField) AsFunction() *modFunction {
@@ -863,17 +919,22 @@ func (f *modField) AsFunction() *modFunction {
 		Name:        f.Name,
 		Description: f.Description,
 		ReturnType:  f.TypeDef,
+		Deprecated:  f.Deprecated,
 	}
 }
 
 // modFunction is a representation of dagger.Function.
 type modFunction struct {
-	Name        string
-	Description string
-	ReturnType  *modTypeDef
-	Args        []*modFunctionArg
-	cmdName     string
-	once        sync.Once
+	Name            string
+	Description     string
+	CachePolicy     dagger.FunctionCachePolicy
+	CacheTTLSeconds int
+	Deprecated      *string
+	Streaming       bool
+	ReturnType      *modTypeDef
+	Args            []*modFunctionArg
+	cmdName         string
+	once            sync.Once
 }
 
 func (f *modFunction) CmdName() string {
@@ -891,6 +952,30 @@ func (f *modFunction) Short() string {
 	return s
 }
 
+// Cache returns a short description of the function's cache policy, or an
+// empty string for the default policy.
+func (f *modFunction) Cache() string {
+	switch f.CachePolicy {
+	case dagger.FunctionCachePolicyNever:
+		return "never"
+	case dagger.FunctionCachePolicyPerSession:
+		return "per session"
+	case dagger.FunctionCachePolicyTtl:
+		return "ttl " + (time.Duration(f.CacheTTLSeconds) * time.Second).String()
+	default:
+		return ""
+	}
+}
+
+// DeprecationNote returns the first line of the reason the function is
+// deprecated, or "-" if it isn't deprecated.
+func (f *modFunction) DeprecationNote() string {
+	if f.Deprecated == nil {
+		return "-"
+	}
+	return strings.SplitN(deprecationMessage(*f.Deprecated), "\n", 2)[0]
+}
+
 // GetArg returns the argument definition corresponding to the given name.
 func (f *modFunction) GetArg(name string) (*modFunctionArg, error) {
 	for _, a := range f.Args {
@@ -956,6 +1041,15 @@ func (f *modFunction) ReturnsCoreObject() bool {
 	return false
 }
 
+// deprecationMessage returns the message to show for a deprecated function
+// or argument, falling back to GraphQL's default reason.
+func deprecationMessage(reason string) string {
+	if reason = strings.TrimSpace(reason); reason != "" {
+		return reason
+	}
+	return "no longer supported"
+}
+
 // modFunctionArg is a representation of dagger.FunctionArg.
 type modFunctionArg struct {
 	Name         string
@@ -964,8 +1058,16 @@ type modFunctionArg struct {
 	DefaultValue dagger.JSON
 	DefaultPath  string
 	Ignore       []string
-	flagName     string
-	once         sync.Once
+	Deprecated   *string
+
+	Pattern       string
+	Min           *float64
+	Max           *float64
+	MinLength     *int
+	AllowedValues []string
+
+	flagName string
+	once     sync.Once
 }
 
 // FlagName returns the name of the argument using CLI naming conventions.
@@ -1011,9 +1113,40 @@ func (r *modFunctionArg) Long() string {
 		fmt.Fprintf(sb, "(possible values: %s)", names)
 	}
 
+	if constraints := r.Constraints(); constraints != "" {
+		if multiline {
+			sb.WriteString("\n\n")
+		} else if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+		fmt.Fprintf(sb, "(%s)", constraints)
+	}
+
 	return sb.String()
 }
 
+// Constraints returns a short description of the validation constraints
+// declared on the argument, if any.
+func (r *modFunctionArg) Constraints() string {
+	var parts []string
+	if r.Pattern != "" {
+		parts = append(parts, fmt.Sprintf("pattern: %s", r.Pattern))
+	}
+	if r.Min != nil {
+		parts = append(parts, fmt.Sprintf("min: %s", strconv.FormatFloat(*r.Min, 'f', -1, 64)))
+	}
+	if r.Max != nil {
+		parts = append(parts, fmt.Sprintf("max: %s", strconv.FormatFloat(*r.Max, 'f', -1, 64)))
+	}
+	if r.MinLength != nil {
+		parts = append(parts, fmt.Sprintf("min length: %d", *r.MinLength))
+	}
+	if len(r.AllowedValues) > 0 {
+		parts = append(parts, fmt.Sprintf("allowed values: %s", strings.Join(r.AllowedValues, ", ")))
+	}
+	return strings.Join(parts, ", ")
+}
+
 func (r *modFunctionArg) IsRequired() bool {
 	return !r.TypeDef.Optional && r.DefaultValue == ""
 }
@@ -207,6 +207,8 @@ type CompletionContext struct {
 	// ModFunc indicates the completions should be performed on the arguments
 	// for a function call.
 	ModFunction *modFunction
+	// ModFunctionArgs are the words already written after the function name.
+	ModFunctionArgs []string
 
 	root bool
 }
@@ -217,6 +219,12 @@ func (ctx *CompletionContext) completions(prefix string) []string {
 	case ctx.ModFunction != nil:
 		// TODO: also complete required args sometimes (depending on type)
 
+		// complete values for args that only accept some values
+		if values := ctx.allowedValues(prefix); values != nil {
+			results = append(results, values...)
+			break
+		}
+
 		// complete optional args
 		if strings.HasPrefix(prefix, "-") {
 			for _, arg := range ctx.ModFunction.OptionalArgs() {
@@ -270,6 +278,35 @@ func (ctx *CompletionContext) completions(prefix string) []string {
 	return results
 }
 
+// allowedValues returns the values to complete for the flag being written
+// at prefix, either as `--flag <value>` or `--flag=<value>`, if that flag's
+// argument declares its allowed values.
+func (ctx *CompletionContext) allowedValues(prefix string) []string {
+	args := ctx.ModFunctionArgs
+	if prefix != "" && len(args) > 0 && args[len(args)-1] == prefix {
+		args = args[:len(args)-1]
+	}
+
+	var flagName, valuePrefix string
+	if name, _, ok := strings.Cut(prefix, "="); ok && strings.HasPrefix(name, "--") {
+		flagName, valuePrefix = name, name+"="
+	} else if len(args) > 0 && strings.HasPrefix(args[len(args)-1], "--") && !strings.Contains(args[len(args)-1], "=") {
+		flagName = args[len(args)-1]
+	} else {
+		return nil
+	}
+
+	arg, err := ctx.ModFunction.GetArg(strings.TrimPrefix(flagName, "--"))
+	if err != nil || len(arg.AllowedValues) == 0 {
+		return nil
+	}
+	results := make([]string, 0, len(arg.AllowedValues))
+	for _, value := range arg.AllowedValues {
+		results = append(results, valuePrefix+value)
+	}
+	return results
+}
+
 func (ctx *CompletionContext) lookupField(field string, args []string) *CompletionContext {
 	if cmd := ctx.builtinCmd(field); cmd != nil {
 		return cmd.Complete(ctx, args)
@@ -283,8 +320,9 @@ func (ctx *CompletionContext) lookupField(field string, args []string) *Completi
 			return nil
 		}
 		return &CompletionContext{
-			Completer:   ctx.Completer,
-			ModFunction: next,
+			Completer:       ctx.Completer,
+			ModFunction:     next,
+			ModFunctionArgs: args,
 		}
 	}
 
@@ -301,8 +339,9 @@ func (ctx *CompletionContext) lookupField(field string, args []string) *Completi
 	case shellCoreCmdName:
 		if fn := def.GetCoreFunction(field); fn != nil {
 			return &CompletionContext{
-				Completer:   ctx.Completer,
-				ModFunction: fn,
+				Completer:       ctx.Completer,
+				ModFunction:     fn,
+				ModFunctionArgs: args,
 			}
 		}
 	}
@@ -320,8 +359,9 @@ func (ctx *CompletionContext) lookupField(field string, args []string) *Completi
 			return nil
 		}
 		return &CompletionContext{
-			Completer:   ctx.Completer,
-			ModFunction: next,
+			Completer:       ctx.Completer,
+			ModFunction:     next,
+			ModFunctionArgs: args,
 		}
 	}
 
@@ -340,8 +380,9 @@ func (ctx *CompletionContext) lookupField(field string, args []string) *Completi
 	// TODO: loading other modules isn't supported yet
 	if ctx.Completer.IsDefaultModule(field) {
 		return &CompletionContext{
-			Completer:   ctx.Completer,
-			ModFunction: def.MainObject.AsObject.Constructor,
+			Completer:       ctx.Completer,
+			ModFunction:     def.MainObject.AsObject.Constructor,
+			ModFunctionArgs: args,
 		}
 	}
 
@@ -293,6 +293,9 @@ func shellPreprocessArgs(ctx context.Context, fn *modFunction, args []string) ([
 			default:
 				flags.StringSlice(name, nil, "")
 			}
+		case dagger.TypeDefKindMapKind:
+			// Map entries are parsed as CSV later, so keep each occurrence whole.
+			flags.StringArray(name, nil, "")
 		case dagger.TypeDefKindBooleanKind:
 			flags.Bool(name, false, "")
 		default:
@@ -0,0 +1,554 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	continuityfs "github.com/containerd/continuity/fs"
+	"github.com/klauspost/compress/zstd"
+	"github.com/moby/buildkit/client/llb"
+	bksession "github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/solver"
+	"github.com/opencontainers/go-digest"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine/buildkit"
+)
+
+type ArchiveFormat string
+
+var ArchiveFormats = dagql.NewEnum[ArchiveFormat]()
+
+var (
+	ArchiveFormatTar     = ArchiveFormats.Register("TAR", `An uncompressed tar archive`)
+	ArchiveFormatTarGzip = ArchiveFormats.Register("TAR_GZ", `A gzip-compressed tar archive`)
+	ArchiveFormatTarZstd = ArchiveFormats.Register("TAR_ZSTD", `A zstd-compressed tar archive`)
+	ArchiveFormatZip     = ArchiveFormats.Register("ZIP", `A zip archive`)
+)
+
+func (format ArchiveFormat) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ArchiveFormat",
+		NonNull:   true,
+	}
+}
+
+func (format ArchiveFormat) TypeDescription() string {
+	return "File format of an archive."
+}
+
+func (format ArchiveFormat) Decoder() dagql.InputDecoder {
+	return ArchiveFormats
+}
+
+func (format ArchiveFormat) ToLiteral() call.Literal {
+	return ArchiveFormats.Literal(format)
+}
+
+// Extension returns the conventional file extension for the format.
+func (format ArchiveFormat) Extension() string {
+	switch format {
+	case ArchiveFormatTarGzip:
+		return ".tar.gz"
+	case ArchiveFormatTarZstd:
+		return ".tar.zst"
+	case ArchiveFormatZip:
+		return ".zip"
+	default:
+		return ".tar"
+	}
+}
+
+// reproducibleModTime is the modification time given to every entry of a
+// reproducible archive. Zip can't represent times before 1980, so it's used
+// for all formats for consistency.
+var reproducibleModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewArchiveDagOp returns a state containing a single file, filename, which is
+// an archive of the contents of dir in the input state.
+func NewArchiveDagOp(ctx context.Context, input llb.State, dir string, filename string, format ArchiveFormat, reproducible bool) (llb.State, error) {
+	dagOp := ArchiveDagOp{
+		Dir:          dir,
+		Filename:     filename,
+		Format:       format,
+		Reproducible: reproducible,
+	}
+	return buildkit.NewCustomLLB(ctx, dagOp, []llb.State{input},
+		llb.WithCustomNamef("%s %s", dagOp.Name(), filename),
+		buildkit.WithPassthrough())
+}
+
+type ArchiveDagOp struct {
+	Dir          string
+	Filename     string
+	Format       ArchiveFormat
+	Reproducible bool
+}
+
+func (op ArchiveDagOp) Name() string {
+	return "dagop.archive"
+}
+
+func (op ArchiveDagOp) Backend() buildkit.CustomOpBackend {
+	return &op
+}
+
+func (op ArchiveDagOp) CacheKey(ctx context.Context) (key digest.Digest, err error) {
+	return digest.FromString(fmt.Sprintf("%s\x00%s\x00%s\x00%t", op.Dir, op.Filename, op.Format, op.Reproducible)), nil
+}
+
+func (op ArchiveDagOp) Exec(ctx context.Context, g bksession.Group, inputs []solver.Result, opt buildkit.OpOpts) (outputs []solver.Result, retErr error) {
+	return withOutputMount(ctx, g, nil, opt, op.Name(), func(outDir string) error {
+		f, err := os.Create(filepath.Join(outDir, op.Filename))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w := bufio.NewWriter(f)
+		err = withInputMount(ctx, g, inputs, func(inDir string) error {
+			root := ""
+			if inDir != "" {
+				root, err = continuityfs.RootPath(inDir, op.Dir)
+				if err != nil {
+					return err
+				}
+			}
+			return writeArchive(w, root, op.Format, op.Reproducible)
+		})
+		if err != nil {
+			return fmt.Errorf("write %s: %w", op.Filename, err)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// NewExtractDagOp returns a state containing the contents of the archive at
+// filePath in the input state.
+func NewExtractDagOp(ctx context.Context, input llb.State, filePath string) (llb.State, error) {
+	dagOp := ExtractDagOp{Path: filePath}
+	return buildkit.NewCustomLLB(ctx, dagOp, []llb.State{input},
+		llb.WithCustomNamef("%s %s", dagOp.Name(), filePath),
+		buildkit.WithPassthrough())
+}
+
+type ExtractDagOp struct {
+	Path string
+}
+
+func (op ExtractDagOp) Name() string {
+	return "dagop.extract"
+}
+
+func (op ExtractDagOp) Backend() buildkit.CustomOpBackend {
+	return &op
+}
+
+func (op ExtractDagOp) CacheKey(ctx context.Context) (key digest.Digest, err error) {
+	return digest.FromString(op.Path), nil
+}
+
+func (op ExtractDagOp) Exec(ctx context.Context, g bksession.Group, inputs []solver.Result, opt buildkit.OpOpts) (outputs []solver.Result, retErr error) {
+	return withOutputMount(ctx, g, nil, opt, op.Name(), func(outDir string) error {
+		return withInputMount(ctx, g, inputs, func(inDir string) error {
+			if inDir == "" {
+				return fmt.Errorf("archive %s not found", op.Path)
+			}
+			archivePath, err := continuityfs.RootPath(inDir, op.Path)
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(archivePath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := extractArchive(f, outDir); err != nil {
+				return fmt.Errorf("extract %s: %w", op.Path, err)
+			}
+			return nil
+		})
+	})
+}
+
+// writeArchive writes the contents of root to w in the given format. An empty
+// root writes an empty archive.
+//
+// If reproducible is set, modification times and ownership are normalized so
+// that the same contents always produce the same archive.
+func writeArchive(w io.Writer, root string, format ArchiveFormat, reproducible bool) error {
+	switch format {
+	case ArchiveFormatZip:
+		zw := zip.NewWriter(w)
+		if err := walkArchive(root, reproducible, func(hdr *tar.Header, src string) error {
+			return writeZipEntry(zw, hdr, src)
+		}); err != nil {
+			return err
+		}
+		return zw.Close()
+	case ArchiveFormatTarGzip:
+		// NB: the gzip header's name and mtime are left empty
+		gw := gzip.NewWriter(w)
+		if err := writeTar(gw, root, reproducible); err != nil {
+			return err
+		}
+		return gw.Close()
+	case ArchiveFormatTarZstd:
+		// a single encoder goroutine keeps the output deterministic
+		zw, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		if err := writeTar(zw, root, reproducible); err != nil {
+			return err
+		}
+		return zw.Close()
+	case ArchiveFormatTar:
+		return writeTar(w, root, reproducible)
+	default:
+		return fmt.Errorf("unsupported archive format %q", format)
+	}
+}
+
+func writeTar(w io.Writer, root string, reproducible bool) error {
+	tw := tar.NewWriter(w)
+	if err := walkArchive(root, reproducible, func(hdr *tar.Header, src string) error {
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil
+		}
+		return copyFileTo(tw, src)
+	}); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+func writeZipEntry(zw *zip.Writer, hdr *tar.Header, src string) error {
+	fh, err := zip.FileInfoHeader(hdr.FileInfo())
+	if err != nil {
+		return err
+	}
+	fh.Name = hdr.Name
+	fh.Modified = hdr.ModTime
+	if hdr.Typeflag == tar.TypeReg {
+		fh.Method = zip.Deflate
+	} else {
+		fh.Method = zip.Store
+	}
+	ew, err := zw.CreateHeader(fh)
+	if err != nil {
+		return err
+	}
+	switch hdr.Typeflag {
+	case tar.TypeReg:
+		return copyFileTo(ew, src)
+	case tar.TypeSymlink:
+		// zip stores the symlink's target as its content
+		_, err := io.WriteString(ew, hdr.Linkname)
+		return err
+	default:
+		return nil
+	}
+}
+
+func copyFileTo(w io.Writer, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// walkArchive calls fn with a header for each regular file, directory and
+// symlink under root, in lexical order. Other file types are skipped.
+func walkArchive(root string, reproducible bool, fn func(hdr *tar.Header, src string) error) error {
+	if root == "" {
+		return nil
+	}
+	return filepath.WalkDir(root, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, src)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		switch {
+		case info.Mode().IsRegular(), info.IsDir():
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err = os.Readlink(src)
+			if err != nil {
+				return err
+			}
+		default:
+			// devices, sockets and pipes don't belong in a release archive
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Format = tar.FormatPAX
+		hdr.AccessTime = time.Time{}
+		hdr.ChangeTime = time.Time{}
+		if reproducible {
+			hdr.ModTime = reproducibleModTime
+			hdr.Uid = 0
+			hdr.Gid = 0
+			hdr.Uname = ""
+			hdr.Gname = ""
+		} else {
+			hdr.ModTime = hdr.ModTime.Truncate(time.Second)
+		}
+		return fn(hdr, src)
+	})
+}
+
+// extractArchive unpacks the archive read from f into dest, detecting its
+// format from its contents. Entries are never written outside of dest.
+func extractArchive(f *os.File, dest string) error {
+	magic := make([]byte, 4)
+	n, err := io.ReadFull(f, magic)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	magic = magic[:n]
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")), bytes.HasPrefix(magic, []byte("PK\x05\x06")):
+		st, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(f, st.Size())
+		if err != nil {
+			return err
+		}
+		return extractZip(zr, dest)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		return extractTar(gr, dest)
+	case bytes.HasPrefix(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		return extractTar(zr, dest)
+	default:
+		if err := extractTar(f, dest); err != nil {
+			return fmt.Errorf("not a valid tar or zip archive: %w", err)
+		}
+		return nil
+	}
+}
+
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	x := newExtractor(dest)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := x.extract(hdr, tr); err != nil {
+			return fmt.Errorf("%s: %w", hdr.Name, err)
+		}
+	}
+	return x.finish()
+}
+
+func extractZip(zr *zip.Reader, dest string) error {
+	x := newExtractor(dest)
+	for _, zf := range zr.File {
+		hdr, err := tar.FileInfoHeader(zf.FileInfo(), "")
+		if err != nil {
+			return fmt.Errorf("%s: %w", zf.Name, err)
+		}
+		hdr.Name = zf.Name
+		hdr.ModTime = zf.Modified
+		if err := func() error {
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			if hdr.Typeflag == tar.TypeSymlink {
+				target, err := io.ReadAll(io.LimitReader(rc, 4096))
+				if err != nil {
+					return err
+				}
+				hdr.Linkname = string(target)
+			}
+			return x.extract(hdr, rc)
+		}(); err != nil {
+			return fmt.Errorf("%s: %w", zf.Name, err)
+		}
+	}
+	return x.finish()
+}
+
+type archiveExtractor struct {
+	dest string
+	// directory times are applied last, since extracting their contents
+	// modifies them
+	dirTimes map[string]time.Time
+}
+
+func newExtractor(dest string) *archiveExtractor {
+	return &archiveExtractor{
+		dest:     dest,
+		dirTimes: map[string]time.Time{},
+	}
+}
+
+// resolve returns the path for an entry within dest, resolving any symlinks
+// in its parent directories within dest.
+func (x *archiveExtractor) resolve(name string) (string, error) {
+	name = path.Clean("/" + name)
+	if name == "/" {
+		return x.dest, nil
+	}
+	parent, err := continuityfs.RootPath(x.dest, path.Dir(name))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(parent, path.Base(name)), nil
+}
+
+func (x *archiveExtractor) extract(hdr *tar.Header, r io.Reader) error {
+	target, err := x.resolve(hdr.Name)
+	if err != nil {
+		return err
+	}
+	if target == x.dest {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	mode := hdr.FileInfo().Mode()
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			return err
+		}
+		x.dirTimes[target] = hdr.ModTime
+	case tar.TypeReg:
+		if err := removeNonDir(target); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		if err := removeNonDir(target); err != nil {
+			return err
+		}
+		return errors.Join(
+			os.Symlink(hdr.Linkname, target),
+			os.Lchown(target, hdr.Uid, hdr.Gid),
+		)
+	case tar.TypeLink:
+		source, err := x.resolve(hdr.Linkname)
+		if err != nil {
+			return err
+		}
+		if err := removeNonDir(target); err != nil {
+			return err
+		}
+		return os.Link(source, target)
+	default:
+		// devices, fifos and the like are skipped
+		return nil
+	}
+
+	if err := os.Lchown(target, hdr.Uid, hdr.Gid); err != nil {
+		return err
+	}
+	if err := os.Chmod(target, mode.Perm()|mode&(fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky)); err != nil {
+		return err
+	}
+	if hdr.Typeflag == tar.TypeReg {
+		return os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+	}
+	return nil
+}
+
+func (x *archiveExtractor) finish() error {
+	for dir, mtime := range x.dirTimes {
+		if err := os.Chtimes(dir, mtime, mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeNonDir(target string) error {
+	st, err := os.Lstat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if st.IsDir() {
+		return fmt.Errorf("a directory already exists at %s", target)
+	}
+	return os.Remove(target)
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/fs"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"dagger.io/dagger/telemetry"
 	"github.com/containerd/containerd/content"
@@ -66,6 +68,9 @@ type Container struct {
 	// List of GPU devices that will be exposed to the container
 	EnabledGPUs []string `json:"enabledGPUs,omitempty"`
 
+	// Default resource limits applied to the container's execs
+	ResourceLimits ContainerResourceLimits `json:"resourceLimits,omitempty"`
+
 	// Mount points configured for the container.
 	Mounts ContainerMounts `json:"mounts,omitempty"`
 
@@ -90,6 +95,9 @@ type Container struct {
 	// Ports to expose from the container.
 	Ports []Port `json:"ports,omitempty"`
 
+	// Custom health check to run when the container is started as a service.
+	Healthcheck *ContainerHealthcheck `json:"healthcheck,omitempty"`
+
 	// Services to start before running the container.
 	Services ServiceBindings `json:"services,omitempty"`
 
@@ -165,6 +173,9 @@ func (container *Container) Clone() *Container {
 	cp.Secrets = cloneSlice(cp.Secrets)
 	cp.Sockets = cloneSlice(cp.Sockets)
 	cp.Ports = cloneSlice(cp.Ports)
+	if cp.Healthcheck != nil {
+		cp.Healthcheck = cp.Healthcheck.Clone()
+	}
 	cp.Services = cloneSlice(cp.Services)
 	cp.SystemEnvNames = cloneSlice(cp.SystemEnvNames)
 	return &cp
@@ -182,6 +193,15 @@ func (owner Ownership) Opt() llb.ChownOption {
 	return llb.WithUIDGID(owner.UID, owner.GID)
 }
 
+// OwnerMapping changes the ownership of files owned by given IDs: a file whose
+// UID is FromUID gets the UID of To, and a file whose GID is FromGID gets the
+// GID of To.
+type OwnerMapping struct {
+	FromUID int       `json:"fromUID"`
+	FromGID int       `json:"fromGID"`
+	To      Ownership `json:"to"`
+}
+
 // ContainerSecret configures a secret to expose, either as an environment
 // variable or mounted to a file path.
 type ContainerSecret struct {
@@ -562,7 +582,7 @@ func (container *Container) WithRootFS(ctx context.Context, dir *Directory) (*Co
 	return container, nil
 }
 
-func (container *Container) WithDirectory(ctx context.Context, subdir string, src *Directory, filter CopyFilter, owner string) (*Container, error) {
+func (container *Container) WithDirectory(ctx context.Context, subdir string, src *Directory, filter CopyFilter, owner string, ownerMap []string) (*Container, error) {
 	container = container.Clone()
 
 	return container.writeToPath(ctx, subdir, func(dir *Directory) (*Directory, error) {
@@ -570,12 +590,22 @@ func (container *Container) WithDirectory(ctx context.Context, subdir string, sr
 		if err != nil {
 			return nil, err
 		}
+		mappings, err := container.ownerMappings(ctx, ownerMap, owner)
+		if err != nil {
+			return nil, err
+		}
+		if len(mappings) > 0 {
+			src, err = src.withOwnerMappings(ctx, mappings)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		return dir.WithDirectory(ctx, ".", src, filter, ownership)
 	})
 }
 
-func (container *Container) WithFile(ctx context.Context, destPath string, src *File, permissions *int, owner string) (*Container, error) {
+func (container *Container) WithFile(ctx context.Context, destPath string, src *File, permissions *int, owner string, ownerMap []string) (*Container, error) {
 	container = container.Clone()
 
 	dir, file := filepath.Split(filepath.Clean(destPath))
@@ -584,6 +614,16 @@ func (container *Container) WithFile(ctx context.Context, destPath string, src *
 		if err != nil {
 			return nil, err
 		}
+		mappings, err := container.ownerMappings(ctx, ownerMap, owner)
+		if err != nil {
+			return nil, err
+		}
+		if len(mappings) > 0 {
+			src, err = src.withOwnerMappings(ctx, mappings)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		return dir.WithFile(ctx, file, src, permissions, ownership)
 	})
@@ -604,7 +644,7 @@ func (container *Container) WithoutPaths(ctx context.Context, destPaths ...strin
 	return container, nil
 }
 
-func (container *Container) WithFiles(ctx context.Context, destDir string, src []*File, permissions *int, owner string) (*Container, error) {
+func (container *Container) WithFiles(ctx context.Context, destDir string, src []*File, permissions *int, owner string, ownerMap []string) (*Container, error) {
 	container = container.Clone()
 
 	dir, file := filepath.Split(filepath.Clean(destDir))
@@ -613,6 +653,20 @@ func (container *Container) WithFiles(ctx context.Context, destDir string, src [
 		if err != nil {
 			return nil, err
 		}
+		mappings, err := container.ownerMappings(ctx, ownerMap, owner)
+		if err != nil {
+			return nil, err
+		}
+		if len(mappings) > 0 {
+			mapped := make([]*File, len(src))
+			for i, f := range src {
+				mapped[i], err = f.withOwnerMappings(ctx, mappings)
+				if err != nil {
+					return nil, err
+				}
+			}
+			src = mapped
+		}
 
 		return dir.WithFiles(ctx, file, src, permissions, ownership)
 	})
@@ -632,6 +686,15 @@ func (container *Container) WithNewFile(ctx context.Context, dest string, conten
 	})
 }
 
+func (container *Container) WithSymlink(ctx context.Context, target, linkName string) (*Container, error) {
+	container = container.Clone()
+
+	dir, link := filepath.Split(filepath.Clean(linkName))
+	return container.writeToPath(ctx, dir, func(dir *Directory) (*Directory, error) {
+		return dir.WithSymlink(ctx, target, link)
+	})
+}
+
 func (container *Container) WithMountedDirectory(ctx context.Context, target string, dir *Directory, owner string, readonly bool) (*Container, error) {
 	container = container.Clone()
 
@@ -1111,6 +1174,142 @@ func (container *Container) WithGPU(ctx context.Context, gpuOpts ContainerGPUOpt
 	return container, nil
 }
 
+// ContainerResourceLimits bounds the resources available to a container's
+// execs. Zero values mean unlimited.
+type ContainerResourceLimits struct {
+	// Number of CPUs, enforced as a CFS quota (e.g. 0.5 or 2)
+	CPUQuota float64 `json:"cpuQuota,omitempty"`
+
+	// Maximum memory in bytes
+	MemoryLimit int64 `json:"memoryLimit,omitempty"`
+
+	// Maximum number of processes
+	PidsLimit int64 `json:"pidsLimit,omitempty"`
+}
+
+func (limits ContainerResourceLimits) IsZero() bool {
+	return limits == ContainerResourceLimits{}
+}
+
+// Merge returns the limits with any non-zero values from other taking
+// precedence.
+func (limits ContainerResourceLimits) Merge(other ContainerResourceLimits) ContainerResourceLimits {
+	if other.CPUQuota != 0 {
+		limits.CPUQuota = other.CPUQuota
+	}
+	if other.MemoryLimit != 0 {
+		limits.MemoryLimit = other.MemoryLimit
+	}
+	if other.PidsLimit != 0 {
+		limits.PidsLimit = other.PidsLimit
+	}
+	return limits
+}
+
+func (limits ContainerResourceLimits) Validate() error {
+	if limits.CPUQuota < 0 {
+		return fmt.Errorf("invalid cpu quota %v: must be positive", limits.CPUQuota)
+	}
+	if limits.CPUQuota > 0 && limits.CPUQuota < 0.01 {
+		return fmt.Errorf("invalid cpu quota %v: must be at least 0.01", limits.CPUQuota)
+	}
+	if limits.MemoryLimit < 0 {
+		return fmt.Errorf("invalid memory limit %d: must be positive", limits.MemoryLimit)
+	}
+	if limits.PidsLimit < 0 {
+		return fmt.Errorf("invalid pids limit %d: must be positive", limits.PidsLimit)
+	}
+	return nil
+}
+
+func (limits ContainerResourceLimits) String() string {
+	return fmt.Sprintf("cpu=%s,memory=%d,pids=%d",
+		strconv.FormatFloat(limits.CPUQuota, 'f', -1, 64),
+		limits.MemoryLimit,
+		limits.PidsLimit,
+	)
+}
+
+func (container *Container) WithResourceLimits(ctx context.Context, limits ContainerResourceLimits) (*Container, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	container = container.Clone()
+	container.ResourceLimits = container.ResourceLimits.Merge(limits)
+	return container, nil
+}
+
+// ContainerHealthcheck configures how a service is determined to be ready,
+// in addition to its exposed ports accepting connections.
+type ContainerHealthcheck struct {
+	// Command to run inside the service's container. The service is healthy
+	// once it exits successfully.
+	Args []string `json:"args,omitempty"`
+
+	// HTTP path to request from the service. The service is healthy once it
+	// responds with HTTPStatus.
+	HTTPPath   string `json:"httpPath,omitempty"`
+	HTTPPort   int    `json:"httpPort,omitempty"`
+	HTTPStatus int    `json:"httpStatus,omitempty"`
+
+	// Time to wait between attempts.
+	Interval time.Duration `json:"interval"`
+
+	// Number of consecutive failures after which the service is considered
+	// unhealthy.
+	Retries int `json:"retries"`
+
+	// Time to give the service to initialize before failures count towards
+	// Retries.
+	StartPeriod time.Duration `json:"startPeriod,omitempty"`
+}
+
+func (hc *ContainerHealthcheck) Clone() *ContainerHealthcheck {
+	cp := *hc
+	cp.Args = cloneSlice(cp.Args)
+	return &cp
+}
+
+func (hc *ContainerHealthcheck) Validate() error {
+	if len(hc.Args) == 0 && hc.HTTPPath == "" {
+		return errors.New("health check must have a command or an HTTP path")
+	}
+	if hc.HTTPPath != "" && !strings.HasPrefix(hc.HTTPPath, "/") {
+		return fmt.Errorf("invalid health check path %q: must be absolute", hc.HTTPPath)
+	}
+	if hc.HTTPPort < 0 || hc.HTTPPort > 65535 {
+		return fmt.Errorf("invalid health check port %d", hc.HTTPPort)
+	}
+	if hc.HTTPPath != "" && (hc.HTTPStatus < 100 || hc.HTTPStatus > 599) {
+		return fmt.Errorf("invalid health check status %d", hc.HTTPStatus)
+	}
+	if hc.Interval <= 0 {
+		return fmt.Errorf("invalid health check interval %s: must be positive", hc.Interval)
+	}
+	if hc.Retries <= 0 {
+		return fmt.Errorf("invalid health check retries %d: must be positive", hc.Retries)
+	}
+	if hc.StartPeriod < 0 {
+		return fmt.Errorf("invalid health check start period %s: must not be negative", hc.StartPeriod)
+	}
+	return nil
+}
+
+func (container *Container) WithHealthcheck(ctx context.Context, hc ContainerHealthcheck) (*Container, error) {
+	if err := hc.Validate(); err != nil {
+		return nil, err
+	}
+	container = container.Clone()
+	container.Healthcheck = &hc
+	return container, nil
+}
+
+func (container *Container) WithoutHealthcheck(ctx context.Context) (*Container, error) {
+	container = container.Clone()
+	container.Healthcheck = nil
+	return container, nil
+}
+
 func (container Container) Evaluate(ctx context.Context) (*buildkit.Result, error) {
 	if container.FS == nil {
 		return nil, nil
@@ -1182,6 +1381,9 @@ func (container *Container) Publish(
 	platformVariants []*Container,
 	forcedCompression ImageLayerCompression,
 	mediaTypes ImageMediaTypes,
+	signer *ImageSigner,
+	attestations []ImageAttestation,
+	id *call.ID,
 ) (string, error) {
 	if mediaTypes == "" {
 		// Modern registry implementations support oci types and docker daemons
@@ -1205,10 +1407,12 @@ func (container *Container) Publish(
 	services := ServiceBindings{}
 
 	variants := append([]*Container{container}, platformVariants...)
+	exported := make([]*Container, 0, len(variants))
 	for _, variant := range variants {
 		if variant.FS == nil {
 			continue
 		}
+		exported = append(exported, variant)
 		st, err := variant.FSState()
 		if err != nil {
 			return "", err
@@ -1274,6 +1478,12 @@ func (container *Container) Publish(
 		return "", err
 	}
 
+	if signer != nil || len(attestations) > 0 {
+		if err := container.publishReferrers(ctx, bk, ref, resp, exported, signer, attestations, id); err != nil {
+			return "", err
+		}
+	}
+
 	imageDigest, found := resp[exptypes.ExporterImageDigestKey]
 	if found {
 		dig, err := digest.Parse(imageDigest)
@@ -1292,6 +1502,51 @@ func (container *Container) Publish(
 	return ref, nil
 }
 
+// publishReferrers pushes the signature and attestations of an image just
+// published, as OCI referrers.
+func (container *Container) publishReferrers(
+	ctx context.Context,
+	bk *buildkit.Client,
+	ref string,
+	resp map[string]string,
+	variants []*Container,
+	signer *ImageSigner,
+	attestations []ImageAttestation,
+	id *call.ID,
+) error {
+	descData, found := resp[exptypes.ExporterImageDescriptorKey]
+	if !found {
+		return errors.New("published image has no descriptor")
+	}
+	descJSON, err := base64.StdEncoding.DecodeString(descData)
+	if err != nil {
+		return fmt.Errorf("decode published image descriptor: %w", err)
+	}
+	var desc specs.Descriptor
+	if err := json.Unmarshal(descJSON, &desc); err != nil {
+		return fmt.Errorf("decode published image descriptor: %w", err)
+	}
+
+	var referrers []buildkit.ImageReferrer
+	if signer != nil {
+		sig, err := signer.SignatureReferrer(ref, desc.Digest)
+		if err != nil {
+			return err
+		}
+		referrers = append(referrers, sig)
+	}
+	attestationReferrers, err := container.attestationReferrers(ctx, ref, desc.Digest, id, variants, attestations, signer)
+	if err != nil {
+		return err
+	}
+	referrers = append(referrers, attestationReferrers...)
+
+	if err := bk.PushImageReferrers(ctx, ref, desc, referrers); err != nil {
+		return fmt.Errorf("push signature and attestations: %w", err)
+	}
+	return nil
+}
+
 func (container *Container) Export(
 	ctx context.Context,
 	dest string,
@@ -1656,6 +1911,54 @@ func (container *Container) ownership(ctx context.Context, owner string) (*Owner
 	return resolveUIDGID(ctx, fsSt, bk, container.Platform, owner)
 }
 
+// ownerMappings parses "FROM:TO" owner map entries, where FROM is a numeric
+// "UID" or "UID/GID", the GID defaulting to the UID, and TO is a user name or
+// ID resolved like an owner.
+func (container *Container) ownerMappings(ctx context.Context, ownerMap []string, owner string) ([]OwnerMapping, error) {
+	if len(ownerMap) == 0 {
+		return nil, nil
+	}
+	if owner != "" {
+		return nil, fmt.Errorf("cannot set both owner and owner map")
+	}
+
+	mappings := make([]OwnerMapping, 0, len(ownerMap))
+	seenUIDs := map[int]bool{}
+	seenGIDs := map[int]bool{}
+	for _, entry := range ownerMap {
+		from, to, ok := strings.Cut(entry, ":")
+		if !ok || to == "" {
+			return nil, fmt.Errorf("invalid owner map entry %q: expected FROM:TO", entry)
+		}
+		fromUser, fromGroup, hasGroup := strings.Cut(from, "/")
+		fromUID, err := parseUID(fromUser)
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner map entry %q: %w", entry, err)
+		}
+		fromGID := fromUID
+		if hasGroup {
+			fromGID, err = parseUID(fromGroup)
+			if err != nil {
+				return nil, fmt.Errorf("invalid owner map entry %q: %w", entry, err)
+			}
+		}
+		if seenUIDs[fromUID] {
+			return nil, fmt.Errorf("invalid owner map entry %q: user %d is already mapped", entry, fromUID)
+		}
+		if seenGIDs[fromGID] {
+			return nil, fmt.Errorf("invalid owner map entry %q: group %d is already mapped", entry, fromGID)
+		}
+		seenUIDs[fromUID] = true
+		seenGIDs[fromGID] = true
+		ownership, err := container.ownership(ctx, to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner map entry %q: %w", entry, err)
+		}
+		mappings = append(mappings, OwnerMapping{FromUID: fromUID, FromGID: fromGID, To: *ownership})
+	}
+	return mappings, nil
+}
+
 func (container *Container) command(opts ContainerExecOpts) ([]string, error) {
 	cfg := container.Config
 	args := opts.Args
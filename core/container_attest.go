@@ -0,0 +1,719 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	continuityfs "github.com/containerd/continuity/fs"
+	"github.com/google/go-containerregistry/pkg/name"
+	intoto "github.com/in-toto/in-toto-golang/in_toto"
+	slsacommon "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/common"
+	slsa02 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	bkgw "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/util/purl"
+	"github.com/opencontainers/go-digest"
+	"github.com/package-url/packageurl-go"
+	spdxcommon "github.com/spdx/tools-golang/spdx/v2/common"
+	spdx "github.com/spdx/tools-golang/spdx/v2/v2_3"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/dagql/call/callpbv1"
+	"github.com/dagger/dagger/engine"
+	"github.com/dagger/dagger/engine/buildkit"
+)
+
+type ImageAttestation string
+
+var ImageAttestations = dagql.NewEnum[ImageAttestation]()
+
+var (
+	ImageAttestationSLSAProvenance = ImageAttestations.Register("SLSA_PROVENANCE",
+		"SLSA v0.2 provenance, listing the calls that built the image and its base images and sources.")
+	ImageAttestationSPDXSBOM = ImageAttestations.Register("SPDX_SBOM",
+		"SPDX 2.3 SBOM, listing the Alpine and Debian packages installed in the image, and its base images and sources.")
+)
+
+func (proto ImageAttestation) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ImageAttestation",
+		NonNull:   true,
+	}
+}
+
+func (proto ImageAttestation) TypeDescription() string {
+	return "An attestation to publish along with an image."
+}
+
+func (proto ImageAttestation) Decoder() dagql.InputDecoder {
+	return ImageAttestations
+}
+
+func (proto ImageAttestation) ToLiteral() call.Literal {
+	return ImageAttestations.Literal(proto)
+}
+
+const (
+	// inTotoMediaType is the media type of an unsigned in-toto statement.
+	inTotoMediaType = "application/vnd.in-toto+json"
+	// inTotoPredicateTypeAnnotation is the annotation of an attestation
+	// holding the type of its predicate.
+	inTotoPredicateTypeAnnotation = "in-toto.io/predicate-type"
+
+	// provenanceBuildType is the build type of the provenance of published
+	// images, whose build config is the DAG of calls that built them.
+	provenanceBuildType = "https://dagger.io/provenance/call-dag/v1"
+)
+
+// attestationReferrers returns the given attestations of the image published
+// at ref with the given digest, signed by signer if it's not nil.
+func (container *Container) attestationReferrers(
+	ctx context.Context,
+	ref string,
+	dgst digest.Digest,
+	id *call.ID,
+	variants []*Container,
+	attestations []ImageAttestation,
+	signer *ImageSigner,
+) ([]buildkit.ImageReferrer, error) {
+	if len(attestations) == 0 {
+		return nil, nil
+	}
+
+	parsed, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("parse ref %q: %w", ref, err)
+	}
+	subject := intoto.Subject{
+		Name:   parsed.Context().Name(),
+		Digest: slsacommon.DigestSet{dgst.Algorithm().String(): dgst.Encoded()},
+	}
+	dag, err := id.ToProto()
+	if err != nil {
+		return nil, fmt.Errorf("encode call DAG: %w", err)
+	}
+
+	var referrers []buildkit.ImageReferrer
+	seen := map[ImageAttestation]bool{}
+	for _, attestation := range attestations {
+		if seen[attestation] {
+			return nil, fmt.Errorf("duplicate attestation %s", attestation)
+		}
+		seen[attestation] = true
+		var predicateType string
+		var predicate any
+		switch attestation {
+		case ImageAttestationSLSAProvenance:
+			predicateType = slsa02.PredicateSLSAProvenance
+			predicate = callDAGProvenance(dag, time.Now())
+		case ImageAttestationSPDXSBOM:
+			var pkgs []imagePackage
+			for _, variant := range variants {
+				variantPkgs, err := variant.installedPackages(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("list packages of %s image: %w", variant.Platform.Format(), err)
+				}
+				pkgs = append(pkgs, variantPkgs...)
+			}
+			predicateType = intoto.PredicateSPDX
+			predicate = imageSBOM(subject.Name, dgst, pkgs, callDAGMaterials(dag), time.Now())
+		default:
+			return nil, fmt.Errorf("unsupported attestation %q", attestation)
+		}
+
+		statement, err := json.Marshal(intoto.Statement{
+			StatementHeader: intoto.StatementHeader{
+				Type:          intoto.StatementInTotoV01,
+				PredicateType: predicateType,
+				Subject:       []intoto.Subject{subject},
+			},
+			Predicate: predicate,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("encode %s attestation: %w", attestation, err)
+		}
+
+		mediaType := inTotoMediaType
+		data := statement
+		if signer != nil {
+			mediaType = dsseEnvelopeMediaType
+			data, err = signer.Envelope(inTotoMediaType, statement)
+			if err != nil {
+				return nil, fmt.Errorf("sign %s attestation: %w", attestation, err)
+			}
+		}
+		annotations := map[string]string{inTotoPredicateTypeAnnotation: predicateType}
+		referrers = append(referrers, buildkit.ImageReferrer{
+			ArtifactType: mediaType,
+			Layers: []buildkit.ImageReferrerLayer{{
+				MediaType:   mediaType,
+				Data:        data,
+				Annotations: annotations,
+			}},
+			Annotations: annotations,
+		})
+	}
+	return referrers, nil
+}
+
+// provenanceBuildConfig is the build config of the provenance of a published
+// image: the calls that built it, each after the calls it depends on.
+type provenanceBuildConfig struct {
+	RootDigest string           `json:"rootDigest"`
+	Calls      []provenanceCall `json:"calls"`
+}
+
+type provenanceCall struct {
+	Digest   string         `json:"digest"`
+	Receiver string         `json:"receiver,omitempty"`
+	Field    string         `json:"field"`
+	Type     string         `json:"type"`
+	Args     map[string]any `json:"args,omitempty"`
+	Nth      int64          `json:"nth,omitempty"`
+	Module   string         `json:"module,omitempty"`
+	View     string         `json:"view,omitempty"`
+}
+
+// callDAGProvenance returns the SLSA provenance of an image built by the
+// given DAG of calls.
+func callDAGProvenance(dag *callpbv1.DAG, finished time.Time) slsa02.ProvenancePredicate {
+	config := provenanceBuildConfig{RootDigest: dag.RootDigest}
+	for _, c := range sortedCalls(dag) {
+		pc := provenanceCall{
+			Digest:   c.Digest,
+			Receiver: c.ReceiverDigest,
+			Field:    c.Field,
+			Type:     callpbv1TypeString(c.Type),
+			Nth:      c.Nth,
+			View:     c.View,
+		}
+		if c.Module != nil {
+			pc.Module = c.Module.Ref
+		}
+		if len(c.Args) > 0 {
+			pc.Args = map[string]any{}
+			for _, arg := range c.Args {
+				pc.Args[arg.Name] = provenanceLiteral(arg.Value)
+			}
+		}
+		config.Calls = append(config.Calls, pc)
+	}
+
+	return slsa02.ProvenancePredicate{
+		Builder: slsacommon.ProvenanceBuilder{
+			ID: "https://dagger.io/engine@" + engine.Version,
+		},
+		BuildType:   provenanceBuildType,
+		BuildConfig: config,
+		Metadata: &slsa02.ProvenanceMetadata{
+			BuildFinishedOn: &finished,
+			Completeness: slsa02.ProvenanceComplete{
+				// every call is listed, but values from the host (e.g.
+				// directories and secrets) are only referred to
+				Parameters: true,
+			},
+		},
+		Materials: callDAGMaterials(dag),
+	}
+}
+
+// sortedCalls returns the calls of the DAG, each after the calls it depends
+// on.
+func sortedCalls(dag *callpbv1.DAG) []*callpbv1.Call {
+	var sorted []*callpbv1.Call
+	visited := map[string]bool{}
+	var visit func(dgst string)
+	var visitLiteral func(lit *callpbv1.Literal)
+	visit = func(dgst string) {
+		if dgst == "" || visited[dgst] {
+			return
+		}
+		visited[dgst] = true
+		c, ok := dag.CallsByDigest[dgst]
+		if !ok {
+			return
+		}
+		visit(c.ReceiverDigest)
+		if c.Module != nil {
+			visit(c.Module.CallDigest)
+		}
+		for _, arg := range c.Args {
+			visitLiteral(arg.Value)
+		}
+		sorted = append(sorted, c)
+	}
+	visitLiteral = func(lit *callpbv1.Literal) {
+		switch v := lit.GetValue().(type) {
+		case *callpbv1.Literal_CallDigest:
+			visit(v.CallDigest)
+		case *callpbv1.Literal_List:
+			for _, elem := range v.List.GetValues() {
+				visitLiteral(elem)
+			}
+		case *callpbv1.Literal_Object:
+			for _, field := range v.Object.GetValues() {
+				visitLiteral(field.GetValue())
+			}
+		}
+	}
+	visit(dag.RootDigest)
+	return sorted
+}
+
+// provenanceLiteral returns the JSON value of a call argument. Objects
+// returned by other calls are referred to by their call digest.
+func provenanceLiteral(lit *callpbv1.Literal) any {
+	switch v := lit.GetValue().(type) {
+	case *callpbv1.Literal_CallDigest:
+		return map[string]string{"call": v.CallDigest}
+	case *callpbv1.Literal_Bool:
+		return v.Bool
+	case *callpbv1.Literal_Enum:
+		return v.Enum
+	case *callpbv1.Literal_Int:
+		return v.Int
+	case *callpbv1.Literal_Float:
+		return v.Float
+	case *callpbv1.Literal_String_:
+		return v.String_
+	case *callpbv1.Literal_List:
+		list := []any{}
+		for _, elem := range v.List.GetValues() {
+			list = append(list, provenanceLiteral(elem))
+		}
+		return list
+	case *callpbv1.Literal_Object:
+		obj := map[string]any{}
+		for _, field := range v.Object.GetValues() {
+			obj[field.GetName()] = provenanceLiteral(field.GetValue())
+		}
+		return obj
+	default:
+		return nil
+	}
+}
+
+func callpbv1TypeString(t *callpbv1.Type) string {
+	if t == nil {
+		return ""
+	}
+	var s string
+	if t.Elem != nil {
+		s = "[" + callpbv1TypeString(t.Elem) + "]"
+	} else {
+		s = t.NamedType
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// callDAGMaterials returns the images, git repositories, HTTP files and
+// modules that the given DAG of calls pulls from.
+func callDAGMaterials(dag *callpbv1.DAG) []slsacommon.ProvenanceMaterial {
+	materials := map[string]slsacommon.ProvenanceMaterial{}
+	add := func(m slsacommon.ProvenanceMaterial) {
+		if m.URI != "" {
+			materials[m.URI] = m
+		}
+	}
+	stringArg := func(c *callpbv1.Call, name string) string {
+		for _, arg := range c.Args {
+			if arg.Name == name {
+				return arg.GetValue().GetString_()
+			}
+		}
+		return ""
+	}
+
+	for _, c := range dag.CallsByDigest {
+		if c.Module != nil && c.Module.Ref != "" {
+			m := slsacommon.ProvenanceMaterial{URI: c.Module.Ref}
+			if c.Module.Pin != "" {
+				m.Digest = slsacommon.DigestSet{"sha1": c.Module.Pin}
+			}
+			add(m)
+		}
+
+		receiverField := ""
+		if receiver, ok := dag.CallsByDigest[c.ReceiverDigest]; ok {
+			receiverField = receiver.Field
+		}
+		switch {
+		case c.Field == "from" && c.GetType().GetNamedType() == "Container":
+			// from always selects itself again with a canonical address
+			address := stringArg(c, "address")
+			dgst, ok := strings.CutPrefix(address[strings.LastIndex(address, "@")+1:], "sha256:")
+			if !ok {
+				continue
+			}
+			uri, err := purl.RefToPURL(packageurl.TypeDocker, address, nil)
+			if err != nil {
+				continue
+			}
+			add(slsacommon.ProvenanceMaterial{URI: uri, Digest: slsacommon.DigestSet{"sha256": dgst}})
+		case c.Field == "http" && c.ReceiverDigest == "":
+			add(slsacommon.ProvenanceMaterial{URI: stringArg(c, "url")})
+		case receiverField == "git" && c.GetType().GetNamedType() == "GitRef":
+			receiver := dag.CallsByDigest[c.ReceiverDigest]
+			url := stringArg(receiver, "url")
+			switch c.Field {
+			case "commit":
+				add(slsacommon.ProvenanceMaterial{
+					URI:    url,
+					Digest: slsacommon.DigestSet{"sha1": stringArg(c, "id")},
+				})
+			case "branch", "tag", "ref":
+				add(slsacommon.ProvenanceMaterial{URI: url + "#" + stringArg(c, "name")})
+			case "head":
+				add(slsacommon.ProvenanceMaterial{URI: url})
+			}
+		}
+	}
+
+	uris := make([]string, 0, len(materials))
+	for uri := range materials {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	sorted := make([]slsacommon.ProvenanceMaterial, 0, len(uris))
+	for _, uri := range uris {
+		sorted = append(sorted, materials[uri])
+	}
+	return sorted
+}
+
+// imagePackage is a package installed in an image.
+type imagePackage struct {
+	PURL    string
+	Name    string
+	Version string
+	License string
+}
+
+// installedPackages returns the packages installed in the container by apk
+// or dpkg.
+func (container *Container) installedPackages(ctx context.Context) ([]imagePackage, error) {
+	const (
+		osReleasePath = "/etc/os-release"
+		apkDBPath     = "/lib/apk/db/installed"
+		dpkgDBPath    = "/var/lib/dpkg/status"
+	)
+	files, err := container.readRootFSFiles(ctx, osReleasePath, apkDBPath, dpkgDBPath)
+	if err != nil {
+		return nil, err
+	}
+	distro := osReleaseID(files[osReleasePath])
+	var pkgs []imagePackage
+	if db, ok := files[apkDBPath]; ok {
+		if distro == "" {
+			distro = "alpine"
+		}
+		pkgs = append(pkgs, parseAPKInstalled(db, distro)...)
+	}
+	if db, ok := files[dpkgDBPath]; ok {
+		if distro == "" {
+			distro = "debian"
+		}
+		pkgs = append(pkgs, parseDPKGStatus(db, distro)...)
+	}
+	return pkgs, nil
+}
+
+// readRootFSFiles returns the contents of the files at the given paths in
+// the root filesystem of the container, leaving out the ones that don't
+// exist.
+func (container *Container) readRootFSFiles(ctx context.Context, paths ...string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	if container.FS == nil {
+		return files, nil
+	}
+
+	svcs, err := container.Query.Services(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %w", err)
+	}
+	bk, err := container.Query.Buildkit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+
+	detach, _, err := svcs.StartBindings(ctx, container.Services)
+	if err != nil {
+		return nil, err
+	}
+	defer detach()
+
+	res, err := bk.Solve(ctx, bkgw.SolveRequest{
+		Definition: container.FS,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return nil, err
+	}
+	// empty root filesystem, i.e. llb.Scratch()
+	if ref == nil {
+		return files, nil
+	}
+
+	err = ref.Mount(ctx, func(root string) error {
+		for _, p := range paths {
+			fp, err := continuityfs.RootPath(root, p)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(fp)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return err
+			}
+			files[p] = data
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// osReleaseID returns the ID of the distribution in the given
+// /etc/os-release file.
+func osReleaseID(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "ID="); ok {
+			return strings.Trim(id, `"'`)
+		}
+	}
+	return ""
+}
+
+// parseAPKInstalled parses the packages of an apk database.
+func parseAPKInstalled(db []byte, distro string) []imagePackage {
+	var pkgs []imagePackage
+	var name, version, arch, license string
+	flush := func() {
+		if name != "" && version != "" {
+			qualifiers := packageurl.Qualifiers{}
+			if arch != "" {
+				qualifiers = packageurl.QualifiersFromMap(map[string]string{"arch": arch})
+			}
+			pkgs = append(pkgs, imagePackage{
+				PURL:    packageurl.NewPackageURL("apk", distro, name, version, qualifiers, "").ToString(),
+				Name:    name,
+				Version: version,
+				License: license,
+			})
+		}
+		name, version, arch, license = "", "", "", ""
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(db))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "P":
+			name = value
+		case "V":
+			version = value
+		case "A":
+			arch = value
+		case "L":
+			license = value
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// parseDPKGStatus parses the installed packages of a dpkg status database.
+func parseDPKGStatus(db []byte, distro string) []imagePackage {
+	var pkgs []imagePackage
+	var name, version, arch, status string
+	flush := func() {
+		if name != "" && version != "" && strings.HasSuffix(status, " installed") {
+			qualifiers := packageurl.Qualifiers{}
+			if arch != "" {
+				qualifiers = packageurl.QualifiersFromMap(map[string]string{"arch": arch})
+			}
+			pkgs = append(pkgs, imagePackage{
+				PURL:    packageurl.NewPackageURL(packageurl.TypeDebian, distro, name, version, qualifiers, "").ToString(),
+				Name:    name,
+				Version: version,
+			})
+		}
+		name, version, arch, status = "", "", "", ""
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(db))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			// continuation of a multi-line field
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			name = value
+		case "Version":
+			version = value
+		case "Architecture":
+			arch = value
+		case "Status":
+			status = value
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// imageSBOM returns the SPDX document of the image published to the
+// repository with the given name and digest.
+func imageSBOM(
+	repo string,
+	dgst digest.Digest,
+	pkgs []imagePackage,
+	materials []slsacommon.ProvenanceMaterial,
+	created time.Time,
+) *spdx.Document {
+	const rootID = spdxcommon.ElementID("image")
+	doc := &spdx.Document{
+		SPDXVersion:       spdx.Version,
+		DataLicense:       spdx.DataLicense,
+		SPDXIdentifier:    spdxcommon.ElementID("DOCUMENT"),
+		DocumentName:      repo + "@" + dgst.String(),
+		DocumentNamespace: "https://dagger.io/spdx/" + repo + "/" + dgst.String(),
+		CreationInfo: &spdx.CreationInfo{
+			Creators: []spdxcommon.Creator{{CreatorType: "Tool", Creator: "dagger-" + engine.Version}},
+			Created:  created.UTC().Format(time.RFC3339),
+		},
+	}
+
+	rootPURL, err := purl.RefToPURL("oci", repo+"@"+dgst.String(), nil)
+	if err != nil {
+		rootPURL = ""
+	}
+	root := &spdx.Package{
+		PackageName:             repo,
+		PackageSPDXIdentifier:   rootID,
+		PackageVersion:          dgst.String(),
+		PackageDownloadLocation: "NOASSERTION",
+		PrimaryPackagePurpose:   "CONTAINER",
+		PackageChecksums: []spdxcommon.Checksum{{
+			Algorithm: spdxcommon.SHA256,
+			Value:     dgst.Encoded(),
+		}},
+	}
+	if rootPURL != "" {
+		root.PackageExternalReferences = []*spdx.PackageExternalReference{{
+			Category: spdxcommon.CategoryPackageManager,
+			RefType:  spdxcommon.TypePackageManagerPURL,
+			Locator:  rootPURL,
+		}}
+	}
+	doc.Packages = append(doc.Packages, root)
+	doc.Relationships = append(doc.Relationships, &spdx.Relationship{
+		RefA:         spdxcommon.MakeDocElementID("", "DOCUMENT"),
+		RefB:         spdxcommon.MakeDocElementID("", string(rootID)),
+		Relationship: spdxcommon.TypeRelationshipDescribe,
+	})
+
+	seen := map[string]bool{}
+	addPackage := func(pkg *spdx.Package, relationship string) {
+		pkg.PackageSPDXIdentifier = spdxcommon.ElementID(fmt.Sprintf("package-%d", len(doc.Packages)))
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, &spdx.Relationship{
+			RefA:         spdxcommon.MakeDocElementID("", string(rootID)),
+			RefB:         spdxcommon.MakeDocElementID("", string(pkg.PackageSPDXIdentifier)),
+			Relationship: relationship,
+		})
+	}
+
+	for _, pkg := range pkgs {
+		if seen[pkg.PURL] {
+			continue
+		}
+		seen[pkg.PURL] = true
+		spdxPkg := &spdx.Package{
+			PackageName:             pkg.Name,
+			PackageVersion:          pkg.Version,
+			PackageDownloadLocation: "NOASSERTION",
+			PackageExternalReferences: []*spdx.PackageExternalReference{{
+				Category: spdxcommon.CategoryPackageManager,
+				RefType:  spdxcommon.TypePackageManagerPURL,
+				Locator:  pkg.PURL,
+			}},
+		}
+		if pkg.License != "" {
+			spdxPkg.PackageLicenseDeclared = pkg.License
+		}
+		addPackage(spdxPkg, spdxcommon.TypeRelationshipContains)
+	}
+
+	for _, m := range materials {
+		if seen[m.URI] {
+			continue
+		}
+		seen[m.URI] = true
+		pkg := &spdx.Package{
+			PackageName:             m.URI,
+			PackageDownloadLocation: "NOASSERTION",
+		}
+		relationship := spdxcommon.TypeRelationshipGeneratedFrom
+		if strings.HasPrefix(m.URI, "pkg:") {
+			pkg.PackageExternalReferences = []*spdx.PackageExternalReference{{
+				Category: spdxcommon.CategoryPackageManager,
+				RefType:  spdxcommon.TypePackageManagerPURL,
+				Locator:  m.URI,
+			}}
+			if strings.HasPrefix(m.URI, "pkg:docker/") {
+				relationship = spdxcommon.TypeRelationshipDescendantOf
+			}
+		} else {
+			pkg.PackageDownloadLocation = m.URI
+		}
+		for alg, value := range m.Digest {
+			switch alg {
+			case "sha256":
+				pkg.PackageChecksums = append(pkg.PackageChecksums, spdxcommon.Checksum{Algorithm: spdxcommon.SHA256, Value: value})
+			case "sha1":
+				pkg.PackageChecksums = append(pkg.PackageChecksums, spdxcommon.Checksum{Algorithm: spdxcommon.SHA1, Value: value})
+			}
+		}
+		addPackage(pkg, relationship)
+	}
+	return doc
+}
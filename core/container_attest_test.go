@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPKInstalled(t *testing.T) {
+	db := []byte(`C:Q1abc=
+P:musl
+V:1.2.4-r2
+A:x86_64
+L:MIT
+
+P:busybox
+V:1.36.1-r5
+A:x86_64
+L:GPL-2.0-only
+`)
+	require.Equal(t, []imagePackage{
+		{PURL: "pkg:apk/alpine/musl@1.2.4-r2?arch=x86_64", Name: "musl", Version: "1.2.4-r2", License: "MIT"},
+		{PURL: "pkg:apk/alpine/busybox@1.36.1-r5?arch=x86_64", Name: "busybox", Version: "1.36.1-r5", License: "GPL-2.0-only"},
+	}, parseAPKInstalled(db, "alpine"))
+}
+
+func TestParseDPKGStatus(t *testing.T) {
+	db := []byte(`Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.2.15-2+b2
+Description: GNU Bourne Again SHell
+ Bash is an sh-compatible command language interpreter.
+
+Package: removed
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0
+`)
+	require.Equal(t, []imagePackage{
+		{PURL: "pkg:deb/debian/bash@5.2.15-2+b2?arch=amd64", Name: "bash", Version: "5.2.15-2+b2"},
+	}, parseDPKGStatus(db, "debian"))
+}
+
+func TestOSReleaseID(t *testing.T) {
+	require.Equal(t, "debian", osReleaseID([]byte("PRETTY_NAME=\"Debian GNU/Linux 12\"\nID=debian\n")))
+	require.Equal(t, "alpine", osReleaseID([]byte("ID=\"alpine\"\n")))
+	require.Equal(t, "", osReleaseID(nil))
+}
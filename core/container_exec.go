@@ -8,6 +8,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine"
@@ -55,6 +56,21 @@ type ContainerExecOpts struct {
 	// Skip the init process injected into containers by default so that the
 	// user's process is PID 1
 	NoInit bool `default:"false"`
+
+	// Number of CPUs the command may use, overriding the container's default
+	CPUQuota float64 `name:"cpuQuota" default:"0"`
+
+	// Maximum memory in bytes the command may use, overriding the container's
+	// default
+	MemoryLimit int `default:"0"`
+
+	// Maximum number of processes the command may create, overriding the
+	// container's default
+	PidsLimit int `default:"0"`
+
+	// Number of seconds after which the command is killed if it's still
+	// running
+	Timeout int `default:"0"`
 }
 
 func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts) (*Container, error) { //nolint:gocyclo
@@ -106,6 +122,30 @@ func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts
 		runOpts = append(runOpts, llb.AddEnv(buildkit.DaggerNoInitEnv, "true"))
 	}
 
+	limits := container.ResourceLimits.Merge(ContainerResourceLimits{
+		CPUQuota:    opts.CPUQuota,
+		MemoryLimit: int64(opts.MemoryLimit),
+		PidsLimit:   int64(opts.PidsLimit),
+	})
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	if !limits.IsZero() {
+		execMD.CPUQuota = limits.CPUQuota
+		execMD.MemoryLimit = limits.MemoryLimit
+		execMD.PidsLimit = limits.PidsLimit
+		// include the limits in the cache key so that an exec that was killed
+		// for exceeding a limit isn't confused with one run under different limits
+		runOpts = append(runOpts, llb.AddEnv(buildkit.DaggerResourceLimitsEnv, limits.String()))
+	}
+
+	if opts.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout %d: must not be negative", opts.Timeout)
+	}
+	// NB: the timeout is deliberately left out of the cache key; an exec that
+	// finishes in time produces the same result regardless of its timeout
+	execMD.Timeout = time.Duration(opts.Timeout) * time.Second
+
 	mod, err := container.Query.CurrentModule(ctx)
 	if err == nil {
 		// allow the exec to reach services scoped to the module that
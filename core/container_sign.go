@@ -0,0 +1,229 @@
+package core
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/dagger/dagger/engine/buildkit"
+)
+
+const (
+	// cosignSignatureArtifactType is the artifact type of the signatures
+	// cosign pushes as OCI referrers.
+	cosignSignatureArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	// cosignSimpleSigningMediaType is the media type of the payload cosign
+	// signs.
+	cosignSimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// cosignSignatureAnnotation is the annotation of the payload layer holding
+	// its signature.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// dsseEnvelopeMediaType is the media type of a signed in-toto statement.
+	dsseEnvelopeMediaType = "application/vnd.dsse.envelope.v1+json"
+)
+
+// ImageSigner signs published images and their attestations with a private
+// key, the way cosign does, so they can be verified with
+// `cosign verify --experimental-oci11 --key`.
+type ImageSigner struct {
+	key crypto.Signer
+}
+
+// NewImageSigner returns a signer for the given PEM encoded private key.
+//
+// Keys generated with `cosign generate-key-pair` are supported, given their
+// password, as well as unencrypted PKCS#8, EC and RSA private keys.
+func NewImageSigner(pemKey, password []byte) (*ImageSigner, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "ENCRYPTED SIGSTORE PRIVATE KEY", "ENCRYPTED COSIGN PRIVATE KEY":
+		var der []byte
+		der, err = decryptCosignKey(block.Bytes, password)
+		if err != nil {
+			return nil, err
+		}
+		key, err = x509.ParsePKCS8PrivateKey(der)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse signing key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		return &ImageSigner{key: key}, nil
+	case *rsa.PrivateKey:
+		return &ImageSigner{key: key}, nil
+	case ed25519.PrivateKey:
+		return &ImageSigner{key: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing key algorithm %T", key)
+	}
+}
+
+// cosignEncryptedKey is the format of the private keys encrypted by cosign.
+type cosignEncryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// decryptCosignKey decrypts a private key encrypted by cosign, returning its
+// PKCS#8 DER encoding.
+func decryptCosignKey(data, password []byte) ([]byte, error) {
+	var enc cosignEncryptedKey
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("parse encrypted signing key: %w", err)
+	}
+	if enc.KDF.Name != "scrypt" {
+		return nil, fmt.Errorf("unsupported signing key KDF %q", enc.KDF.Name)
+	}
+	if enc.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("unsupported signing key cipher %q", enc.Cipher.Name)
+	}
+	var nonce [24]byte
+	if len(enc.Cipher.Nonce) != len(nonce) {
+		return nil, errors.New("invalid signing key nonce")
+	}
+	copy(nonce[:], enc.Cipher.Nonce)
+
+	derived, err := scrypt.Key(password, enc.KDF.Salt, enc.KDF.Params.N, enc.KDF.Params.R, enc.KDF.Params.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("derive signing key password: %w", err)
+	}
+	var secretKey [32]byte
+	copy(secretKey[:], derived)
+
+	der, ok := secretbox.Open(nil, enc.Ciphertext, &nonce, &secretKey)
+	if !ok {
+		return nil, errors.New("decrypt signing key: wrong password")
+	}
+	return der, nil
+}
+
+// Sign signs the given payload: its SHA-256 digest for ECDSA and RSA (with
+// PKCS #1 v1.5) keys, or the payload itself for Ed25519 keys.
+func (s *ImageSigner) Sign(payload []byte) ([]byte, error) {
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		return s.key.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+	sum := sha256.Sum256(payload)
+	return s.key.Sign(rand.Reader, sum[:], crypto.SHA256)
+}
+
+// cosignSimpleSigning is the payload cosign signs to sign an image.
+type cosignSimpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]any `json:"optional"`
+}
+
+// SignatureReferrer returns the cosign signature of the image with the given
+// digest in the repository of ref.
+func (s *ImageSigner) SignatureReferrer(ref string, dgst digest.Digest) (buildkit.ImageReferrer, error) {
+	parsed, err := name.ParseReference(ref)
+	if err != nil {
+		return buildkit.ImageReferrer{}, fmt.Errorf("parse ref %q: %w", ref, err)
+	}
+
+	var payload cosignSimpleSigning
+	payload.Critical.Identity.DockerReference = parsed.Context().Name()
+	payload.Critical.Image.DockerManifestDigest = dgst.String()
+	payload.Critical.Type = "cosign container image signature"
+	payloadData, err := json.Marshal(payload)
+	if err != nil {
+		return buildkit.ImageReferrer{}, err
+	}
+
+	sig, err := s.Sign(payloadData)
+	if err != nil {
+		return buildkit.ImageReferrer{}, fmt.Errorf("sign image: %w", err)
+	}
+	return buildkit.ImageReferrer{
+		ArtifactType: cosignSignatureArtifactType,
+		Layers: []buildkit.ImageReferrerLayer{{
+			MediaType: cosignSimpleSigningMediaType,
+			Data:      payloadData,
+			Annotations: map[string]string{
+				cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+			},
+		}},
+	}, nil
+}
+
+// dsseEnvelope is a DSSE envelope, as produced by `cosign attest`.
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     []byte          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+type dsseSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   []byte `json:"sig"`
+}
+
+// Envelope returns the given payload signed in a DSSE envelope.
+func (s *ImageSigner) Envelope(payloadType string, payload []byte) ([]byte, error) {
+	sig, err := s.Sign(dssePAE(payloadType, payload))
+	if err != nil {
+		return nil, fmt.Errorf("sign envelope: %w", err)
+	}
+	return json.Marshal(dsseEnvelope{
+		PayloadType: payloadType,
+		Payload:     payload,
+		Signatures:  []dsseSignature{{Sig: sig}},
+	})
+}
+
+// dssePAE returns the pre-authentication encoding of a DSSE payload, which is
+// what gets signed.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+func TestImageSignerECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	signer, err := NewImageSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil)
+	require.NoError(t, err)
+
+	dgst := digest.FromString("image")
+	referrer, err := signer.SignatureReferrer("localhost:5000/foo:latest", dgst)
+	require.NoError(t, err)
+	require.Equal(t, cosignSignatureArtifactType, referrer.ArtifactType)
+	require.Len(t, referrer.Layers, 1)
+
+	layer := referrer.Layers[0]
+	require.Equal(t, cosignSimpleSigningMediaType, layer.MediaType)
+	var payload cosignSimpleSigning
+	require.NoError(t, json.Unmarshal(layer.Data, &payload))
+	require.Equal(t, "localhost:5000/foo", payload.Critical.Identity.DockerReference)
+	require.Equal(t, dgst.String(), payload.Critical.Image.DockerManifestDigest)
+	require.Equal(t, "cosign container image signature", payload.Critical.Type)
+
+	sig, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+	require.NoError(t, err)
+	sum := sha256.Sum256(layer.Data)
+	require.True(t, ecdsa.VerifyASN1(&key.PublicKey, sum[:], sig))
+}
+
+func TestImageSignerEncryptedCosignKey(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	// encrypt the key the way `cosign generate-key-pair` does
+	var enc cosignEncryptedKey
+	enc.KDF.Name = "scrypt"
+	enc.KDF.Params.N = 1024
+	enc.KDF.Params.R = 8
+	enc.KDF.Params.P = 1
+	enc.KDF.Salt = []byte("0123456789abcdef0123456789abcdef")
+	enc.Cipher.Name = "nacl/secretbox"
+	enc.Cipher.Nonce = []byte("0123456789abcdef01234567")
+	derived, err := scrypt.Key([]byte("hunter2"), enc.KDF.Salt, enc.KDF.Params.N, enc.KDF.Params.R, enc.KDF.Params.P, 32)
+	require.NoError(t, err)
+	var secretKey [32]byte
+	copy(secretKey[:], derived)
+	var nonce [24]byte
+	copy(nonce[:], enc.Cipher.Nonce)
+	enc.Ciphertext = secretbox.Seal(nil, der, &nonce, &secretKey)
+	data, err := json.Marshal(enc)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: data})
+
+	_, err = NewImageSigner(pemKey, []byte("wrong"))
+	require.ErrorContains(t, err, "wrong password")
+
+	signer, err := NewImageSigner(pemKey, []byte("hunter2"))
+	require.NoError(t, err)
+
+	envelope, err := signer.Envelope(inTotoMediaType, []byte(`{"hello":"world"}`))
+	require.NoError(t, err)
+	var env dsseEnvelope
+	require.NoError(t, json.Unmarshal(envelope, &env))
+	require.Equal(t, inTotoMediaType, env.PayloadType)
+	require.Equal(t, `{"hello":"world"}`, string(env.Payload))
+	require.Len(t, env.Signatures, 1)
+	require.True(t, ed25519.Verify(pub, dssePAE(env.PayloadType, env.Payload), env.Signatures[0].Sig))
+}
+
+func TestImageSignerInvalidKey(t *testing.T) {
+	_, err := NewImageSigner([]byte("not a key"), nil)
+	require.ErrorContains(t, err, "not PEM encoded")
+
+	_, err = NewImageSigner(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("x")}), nil)
+	require.ErrorContains(t, err, "unsupported signing key type")
+}
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/containerd/continuity/fs"
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/call"
 	"github.com/dagger/dagger/engine/buildkit"
+	"github.com/dagger/dagger/engine/sources/gitdns"
 	bkcache "github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/client/llb"
@@ -19,11 +22,19 @@ import (
 	"github.com/moby/buildkit/worker"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
+	fscopy "github.com/tonistiigi/fsutil/copy"
 )
 
 func init() {
 	buildkit.RegisterCustomOp(DirectoryDagOp{})
 	buildkit.RegisterCustomOp(RawDagOp{})
+	buildkit.RegisterCustomOp(SymlinkDagOp{})
+	buildkit.RegisterCustomOp(ArchiveDagOp{})
+	buildkit.RegisterCustomOp(ExtractDagOp{})
+	buildkit.RegisterCustomOp(FilterDagOp{})
+	buildkit.RegisterCustomOp(OwnerMapDagOp{})
+	buildkit.RegisterCustomOp(GitCheckoutDagOp{})
+	buildkit.RegisterCustomOp(GitCommitDagOp{})
 }
 
 // NewDirectoryDagOp takes a target ID for a Directory, and returns a Directory
@@ -207,6 +218,409 @@ func (op RawDagOp) Exec(ctx context.Context, g bksession.Group, inputs []solver.
 	return []solver.Result{worker.NewWorkerRefResult(snap, opt.Worker)}, nil
 }
 
+// NewSymlinkDagOp returns the given state with a symlink at linkName pointing
+// to target, created directly on the filesystem rather than by running a
+// command in a container.
+func NewSymlinkDagOp(ctx context.Context, input llb.State, target, linkName string) (llb.State, error) {
+	dagOp := SymlinkDagOp{Target: target, LinkName: linkName}
+	return buildkit.NewCustomLLB(ctx, dagOp, []llb.State{input},
+		llb.WithCustomNamef("%s %s -> %s", dagOp.Name(), linkName, target),
+		buildkit.WithPassthrough())
+}
+
+type SymlinkDagOp struct {
+	Target   string
+	LinkName string
+}
+
+func (op SymlinkDagOp) Name() string {
+	return "dagop.symlink"
+}
+
+func (op SymlinkDagOp) Backend() buildkit.CustomOpBackend {
+	return &op
+}
+
+func (op SymlinkDagOp) CacheKey(ctx context.Context) (key digest.Digest, err error) {
+	return digest.FromString(op.Target + "\x00" + op.LinkName), nil
+}
+
+func (op SymlinkDagOp) Exec(ctx context.Context, g bksession.Group, inputs []solver.Result, opt buildkit.OpOpts) (outputs []solver.Result, retErr error) {
+	var parent bkcache.ImmutableRef
+	if len(inputs) > 0 && inputs[0] != nil {
+		workerRef, ok := inputs[0].Sys().(*worker.WorkerRef)
+		if !ok {
+			return nil, fmt.Errorf("invalid input ref type: %T", inputs[0].Sys())
+		}
+		parent = workerRef.ImmutableRef
+	}
+
+	return withOutputMount(ctx, g, parent, opt, op.Name(), func(dir string) error {
+		// resolve the parent directory within the mount, but not the link
+		// itself, which may already exist as a symlink that should be replaced
+		linkDir, linkBase := filepath.Split(filepath.Clean("/" + op.LinkName))
+		parentPath, err := fs.RootPath(dir, linkDir)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(parentPath, 0o755); err != nil {
+			return err
+		}
+		linkPath := filepath.Join(parentPath, linkBase)
+		if st, err := os.Lstat(linkPath); err == nil {
+			if st.IsDir() {
+				return fmt.Errorf("cannot create symlink %s: a directory already exists at that path", op.LinkName)
+			}
+			if err := os.Remove(linkPath); err != nil {
+				return err
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		return os.Symlink(op.Target, linkPath)
+	})
+}
+
+// NewFilterDagOp returns a state containing only the contents of dir in the
+// input state that pass the given filters.
+func NewFilterDagOp(ctx context.Context, input llb.State, dir string, copyFilter CopyFilter, ignoreFilter IgnoreFilter) (llb.State, error) {
+	dagOp := FilterDagOp{Dir: dir, CopyFilter: copyFilter, IgnoreFilter: ignoreFilter}
+	return buildkit.NewCustomLLB(ctx, dagOp, []llb.State{input},
+		llb.WithCustomNamef("%s %s", dagOp.Name(), dir),
+		buildkit.WithPassthrough())
+}
+
+type FilterDagOp struct {
+	Dir string
+	CopyFilter
+	IgnoreFilter
+}
+
+func (op FilterDagOp) Name() string {
+	return "dagop.filter"
+}
+
+func (op FilterDagOp) Backend() buildkit.CustomOpBackend {
+	return &op
+}
+
+func (op FilterDagOp) CacheKey(ctx context.Context) (key digest.Digest, err error) {
+	dt, err := json.Marshal(op)
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+func (op FilterDagOp) Exec(ctx context.Context, g bksession.Group, inputs []solver.Result, opt buildkit.OpOpts) (outputs []solver.Result, retErr error) {
+	return withOutputMount(ctx, g, nil, opt, op.Name(), func(outDir string) error {
+		return withInputMount(ctx, g, inputs, func(inDir string) error {
+			if inDir == "" {
+				return nil
+			}
+			root, err := fs.RootPath(inDir, op.Dir)
+			if err != nil {
+				return err
+			}
+			return copyFiltered(ctx, root, outDir, op.CopyFilter, op.IgnoreFilter)
+		})
+	})
+}
+
+// NewOwnerMapDagOp returns a state containing a copy of the file or directory
+// at srcPath in the input state, with ownership changed according to the given
+// mappings. A directory's contents are copied to the root, and a file is
+// copied to the root under its base name.
+func NewOwnerMapDagOp(ctx context.Context, input llb.State, srcPath string, mappings []OwnerMapping) (llb.State, error) {
+	dagOp := OwnerMapDagOp{Path: srcPath, Mappings: mappings}
+	return buildkit.NewCustomLLB(ctx, dagOp, []llb.State{input},
+		llb.WithCustomNamef("%s %s", dagOp.Name(), srcPath),
+		buildkit.WithPassthrough())
+}
+
+type OwnerMapDagOp struct {
+	Path     string
+	Mappings []OwnerMapping
+}
+
+func (op OwnerMapDagOp) Name() string {
+	return "dagop.ownermap"
+}
+
+func (op OwnerMapDagOp) Backend() buildkit.CustomOpBackend {
+	return &op
+}
+
+func (op OwnerMapDagOp) CacheKey(ctx context.Context) (key digest.Digest, err error) {
+	dt, err := json.Marshal(op)
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+func (op OwnerMapDagOp) Exec(ctx context.Context, g bksession.Group, inputs []solver.Result, opt buildkit.OpOpts) (outputs []solver.Result, retErr error) {
+	return withOutputMount(ctx, g, nil, opt, op.Name(), func(outDir string) error {
+		return withInputMount(ctx, g, inputs, func(inDir string) error {
+			if inDir == "" {
+				return nil
+			}
+			srcPath, err := fs.RootPath(inDir, op.Path)
+			if err != nil {
+				return err
+			}
+			st, err := os.Stat(srcPath)
+			if err != nil {
+				return err
+			}
+			destPath := "/"
+			if !st.IsDir() {
+				destPath = filepath.Base(srcPath)
+			}
+			return fscopy.Copy(ctx, inDir, op.Path, outDir, destPath, func(ci *fscopy.CopyInfo) {
+				ci.CopyDirContents = true
+				ci.Chown = func(old *fscopy.User) (*fscopy.User, error) {
+					if old == nil {
+						old = &fscopy.User{}
+					}
+					return &fscopy.User{
+						UID: mapUID(op.Mappings, old.UID),
+						GID: mapGID(op.Mappings, old.GID),
+					}, nil
+				}
+			})
+		})
+	})
+}
+
+// mapUID returns the UID mapped from uid, or uid itself if no mapping applies.
+func mapUID(mappings []OwnerMapping, uid int) int {
+	for _, m := range mappings {
+		if m.FromUID == uid {
+			return m.To.UID
+		}
+	}
+	return uid
+}
+
+// mapGID returns the GID mapped from gid, or gid itself if no mapping applies.
+func mapGID(mappings []OwnerMapping, gid int) int {
+	for _, m := range mappings {
+		if m.FromGID == gid {
+			return m.To.GID
+		}
+	}
+	return gid
+}
+
+// NewGitCheckoutDagOp returns a state containing the tree of the given commit
+// in the git directory at gitDirPath in the input state, limited to
+// sparsePaths if set. If keepGitDir is set, the git directory is included too.
+func NewGitCheckoutDagOp(ctx context.Context, input llb.State, gitDirPath string, commit string, keepGitDir bool, sparsePaths []string) (llb.State, error) {
+	dagOp := GitCheckoutDagOp{
+		Path:        gitDirPath,
+		Commit:      commit,
+		KeepGitDir:  keepGitDir,
+		SparsePaths: sparsePaths,
+	}
+	return buildkit.NewCustomLLB(ctx, dagOp, []llb.State{input},
+		llb.WithCustomNamef("%s %s", dagOp.Name(), commit),
+		buildkit.WithPassthrough())
+}
+
+type GitCheckoutDagOp struct {
+	Path        string
+	Commit      string
+	KeepGitDir  bool
+	SparsePaths []string
+}
+
+func (op GitCheckoutDagOp) Name() string {
+	return "dagop.gitcheckout"
+}
+
+func (op GitCheckoutDagOp) Backend() buildkit.CustomOpBackend {
+	return &op
+}
+
+func (op GitCheckoutDagOp) CacheKey(ctx context.Context) (key digest.Digest, err error) {
+	dt, err := json.Marshal(op)
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+func (op GitCheckoutDagOp) Exec(ctx context.Context, g bksession.Group, inputs []solver.Result, opt buildkit.OpOpts) (outputs []solver.Result, retErr error) {
+	return withOutputMount(ctx, g, nil, opt, op.Name(), func(outDir string) error {
+		return withInputMount(ctx, g, inputs, func(inDir string) error {
+			if inDir == "" {
+				return fmt.Errorf("no git directory to check out from")
+			}
+			gitDir, err := fs.RootPath(inDir, op.Path)
+			if err != nil {
+				return err
+			}
+			return gitdns.Checkout(ctx, gitDir, op.Commit, outDir, op.KeepGitDir, op.SparsePaths)
+		})
+	})
+}
+
+// NewGitCommitDagOp returns a state containing a copy of the git directory at
+// gitDirPath in gitDir, with a new commit on top of parent recording the
+// contents of the directory at treePath in tree, and HEAD pointing to it.
+func NewGitCommitDagOp(ctx context.Context, gitDir llb.State, gitDirPath string, tree llb.State, treePath string, parent string, message string, authorName string, authorEmail string) (llb.State, error) {
+	dagOp := GitCommitDagOp{
+		GitDirPath:  gitDirPath,
+		TreePath:    treePath,
+		Parent:      parent,
+		Message:     message,
+		AuthorName:  authorName,
+		AuthorEmail: authorEmail,
+	}
+	return buildkit.NewCustomLLB(ctx, dagOp, []llb.State{gitDir, tree},
+		llb.WithCustomNamef("%s %s", dagOp.Name(), parent),
+		buildkit.WithPassthrough())
+}
+
+type GitCommitDagOp struct {
+	GitDirPath  string
+	TreePath    string
+	Parent      string
+	Message     string
+	AuthorName  string
+	AuthorEmail string
+}
+
+func (op GitCommitDagOp) Name() string {
+	return "dagop.gitcommit"
+}
+
+func (op GitCommitDagOp) Backend() buildkit.CustomOpBackend {
+	return &op
+}
+
+func (op GitCommitDagOp) CacheKey(ctx context.Context) (key digest.Digest, err error) {
+	dt, err := json.Marshal(op)
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+func (op GitCommitDagOp) Exec(ctx context.Context, g bksession.Group, inputs []solver.Result, opt buildkit.OpOpts) (outputs []solver.Result, retErr error) {
+	return withOutputMount(ctx, g, nil, opt, op.Name(), func(outDir string) error {
+		return withInputMount(ctx, g, inputs, func(inDir string) error {
+			if inDir == "" {
+				return fmt.Errorf("no git directory to commit to")
+			}
+			srcGitDir, err := fs.RootPath(inDir, op.GitDirPath)
+			if err != nil {
+				return err
+			}
+			parent, err := gitdns.ResolveCommit(ctx, srcGitDir, op.Parent)
+			if err != nil {
+				return err
+			}
+			if err := fscopy.Copy(ctx, srcGitDir, "/", outDir, "/", func(ci *fscopy.CopyInfo) {
+				ci.CopyDirContents = true
+			}); err != nil {
+				return fmt.Errorf("failed to copy git directory: %w", err)
+			}
+			return withInputMount(ctx, g, inputs[1:], func(treeDir string) error {
+				var workTree string
+				if treeDir == "" {
+					// scratch, so commit an empty tree
+					workTree, err = os.MkdirTemp("", "git-empty")
+					if err != nil {
+						return err
+					}
+					defer os.RemoveAll(workTree)
+				} else {
+					workTree, err = fs.RootPath(treeDir, op.TreePath)
+					if err != nil {
+						return err
+					}
+				}
+				_, err = gitdns.CommitWorkTree(ctx, outDir, workTree, parent, op.Message, op.AuthorName, op.AuthorEmail, time.Now())
+				return err
+			})
+		})
+	})
+}
+
+// withInputMount mounts the first input read-only and calls fn with its
+// path, or with an empty path if there is no input (i.e. scratch).
+func withInputMount(ctx context.Context, g bksession.Group, inputs []solver.Result, fn func(string) error) error {
+	if len(inputs) == 0 || inputs[0] == nil {
+		return fn("")
+	}
+	workerRef, ok := inputs[0].Sys().(*worker.WorkerRef)
+	if !ok {
+		return fmt.Errorf("invalid input ref type: %T", inputs[0].Sys())
+	}
+	if workerRef.ImmutableRef == nil {
+		return fn("")
+	}
+	mount, err := workerRef.ImmutableRef.Mount(ctx, true, g)
+	if err != nil {
+		return err
+	}
+	lm := snapshot.LocalMounter(mount)
+	dir, err := lm.Mount()
+	if err != nil {
+		return err
+	}
+	defer lm.Unmount()
+	return fn(dir)
+}
+
+// withOutputMount creates a new ref on top of parent, calls fn with its
+// mounted path, and commits it.
+func withOutputMount(ctx context.Context, g bksession.Group, parent bkcache.ImmutableRef, opt buildkit.OpOpts, desc string, fn func(string) error) (outputs []solver.Result, retErr error) {
+	ref, err := opt.Cache.New(ctx, parent, g,
+		bkcache.WithRecordType(client.UsageRecordTypeRegular),
+		bkcache.WithDescription(desc))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create new mutable")
+	}
+	defer func() {
+		if retErr != nil && ref != nil {
+			ref.Release(context.WithoutCancel(ctx))
+		}
+	}()
+
+	mount, err := ref.Mount(ctx, false, g)
+	if err != nil {
+		return nil, err
+	}
+	lm := snapshot.LocalMounter(mount)
+	dir, err := lm.Mount()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if lm != nil {
+			lm.Unmount()
+		}
+	}()
+
+	if err := fn(dir); err != nil {
+		return nil, err
+	}
+
+	lm.Unmount()
+	lm = nil
+
+	snap, err := ref.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ref = nil
+
+	return []solver.Result{worker.NewWorkerRefResult(snap, opt.Worker)}, nil
+}
+
 type dagOpContextKey string
 
 func withDagOpContext(ctx context.Context, op buildkit.CustomOp) context.Context {
@@ -1,24 +1,34 @@
 package core
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
+	"maps"
+	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	continuityfs "github.com/containerd/continuity/fs"
 	"github.com/moby/buildkit/client/llb"
 	bkgw "github.com/moby/buildkit/frontend/gateway/client"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/patternmatcher"
 	"github.com/pkg/errors"
+	"github.com/tonistiigi/fsutil"
 	fstypes "github.com/tonistiigi/fsutil/types"
 	"github.com/vektah/gqlparser/v2/ast"
 
 	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/engine/buildkit"
+	"github.com/dagger/dagger/engine/ignore"
 )
 
 // Directory is a content-addressed directory.
@@ -347,6 +357,180 @@ func (dir *Directory) Glob(ctx context.Context, pattern string) ([]string, error
 	return paths, nil
 }
 
+// SearchResult is a single match of a Directory.search pattern.
+type SearchResult struct {
+	FilePath    string `field:"true" doc:"The path of the matching file, relative to the searched directory."`
+	LineNumber  int    `field:"true" doc:"The 1-based line number of the match."`
+	Column      int    `field:"true" doc:"The 1-based byte offset of the match within its line."`
+	MatchedText string `field:"true" doc:"The text that matched the pattern."`
+}
+
+func (SearchResult) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "SearchResult",
+		NonNull:   true,
+	}
+}
+
+func (SearchResult) TypeDescription() string {
+	return "A match of a pattern within a file."
+}
+
+type SearchOpts struct {
+	// Treat the pattern as a literal string rather than a regular expression.
+	Literal bool
+	// Paths to search, relative to the directory. Searches everything if empty.
+	Paths []string
+	// Only search files whose path matches one of these glob patterns.
+	Globs []string
+	// Match case-insensitively.
+	CaseInsensitive bool
+	// Stop after this many results. Unlimited if zero.
+	Limit int
+}
+
+// searchBinarySniffLen is how much of a file is checked for NUL bytes to
+// decide whether it's binary and should be skipped, like grep does.
+const searchBinarySniffLen = 8000
+
+func (dir *Directory) Search(ctx context.Context, pattern string, opts SearchOpts) ([]SearchResult, error) {
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d: must not be negative", opts.Limit)
+	}
+	if opts.Literal {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.CaseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	var pm *patternmatcher.PatternMatcher
+	if len(opts.Globs) > 0 {
+		pm, err = patternmatcher.New(opts.Globs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid globs: %w", err)
+		}
+	}
+
+	searchPaths := opts.Paths
+	if len(searchPaths) == 0 {
+		searchPaths = []string{"."}
+	}
+
+	results := []SearchResult{}
+	errLimit := errors.New("limit reached")
+	// an empty directory, i.e. llb.Scratch(), has no results
+	_, err = dir.mountRoot(ctx, func(root string) error {
+		dirRoot, err := continuityfs.RootPath(root, dir.Dir)
+		if err != nil {
+			return err
+		}
+		// collect the files first, since paths may overlap and results are
+		// ordered by file path
+		files := map[string]string{}
+		for _, searchPath := range searchPaths {
+			searchRoot, err := continuityfs.RootPath(dirRoot, searchPath)
+			if err != nil {
+				return err
+			}
+			err = filepath.WalkDir(searchRoot, func(fp string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				if !d.Type().IsRegular() {
+					return nil
+				}
+				rel, err := filepath.Rel(dirRoot, fp)
+				if err != nil {
+					return err
+				}
+				if pm != nil {
+					//nolint:staticcheck // see Glob
+					match, err := pm.MatchesUsingParentResult(rel, false)
+					if err != nil {
+						return err
+					}
+					if !match {
+						return nil
+					}
+				}
+				files[filepath.ToSlash(rel)] = fp
+				return nil
+			})
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return fmt.Errorf("path %q not found", searchPath)
+				}
+				return err
+			}
+		}
+		for _, rel := range slices.Sorted(maps.Keys(files)) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			err := searchFile(files[rel], rel, re, func(result SearchResult) error {
+				results = append(results, result)
+				if opts.Limit > 0 && len(results) >= opts.Limit {
+					return errLimit
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLimit) {
+		return nil, err
+	}
+	return results, nil
+}
+
+// searchFile calls fn for every match of re in the file at fp, skipping it
+// entirely if it looks binary.
+func searchFile(fp string, rel string, re *regexp.Regexp, fn func(SearchResult) error) error {
+	f, err := os.Open(fp)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	if head, _ := r.Peek(searchBinarySniffLen); bytes.IndexByte(head, 0) != -1 {
+		return nil
+	}
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			line = bytes.TrimSuffix(line, []byte("\n"))
+			line = bytes.TrimSuffix(line, []byte("\r"))
+			for _, loc := range re.FindAllIndex(line, -1) {
+				if err := fn(SearchResult{
+					FilePath:    rel,
+					LineNumber:  lineNumber,
+					Column:      loc[0] + 1,
+					MatchedText: string(line[loc[0]:loc[1]]),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
 func (dir *Directory) WithNewFile(ctx context.Context, dest string, content []byte, permissions fs.FileMode, ownership *Ownership) (*Directory, error) {
 	dir = dir.Clone()
 
@@ -387,6 +571,54 @@ func (dir *Directory) WithNewFile(ctx context.Context, dest string, content []by
 	return dir, nil
 }
 
+func (dir *Directory) WithSymlink(ctx context.Context, target, linkName string) (*Directory, error) {
+	dir = dir.Clone()
+
+	err := validateFileName(linkName)
+	if err != nil {
+		return nil, err
+	}
+	if target == "" {
+		return nil, fmt.Errorf("symlink target must not be empty")
+	}
+
+	// be sure to create the link under the working directory
+	linkName = path.Join(dir.Dir, linkName)
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	st, err = NewSymlinkDagOp(ctx, st, target, linkName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dir.SetState(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	return dir, nil
+}
+
+// AsArchive packs the directory's contents into a single archive file.
+func (dir *Directory) AsArchive(ctx context.Context, format ArchiveFormat, reproducible bool) (*File, error) {
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	filename := "archive" + format.Extension()
+	st, err = NewArchiveDagOp(ctx, st, dir.Dir, filename, format, reproducible)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileSt(ctx, dir.Query, st, filename, dir.Platform, dir.Services)
+}
+
 func (dir *Directory) Directory(ctx context.Context, subdir string) (*Directory, error) {
 	dir = dir.Clone()
 
@@ -455,6 +687,102 @@ type CopyFilter struct {
 	Include []string `default:"[]"`
 }
 
+// IgnoreFilter excludes paths named by ignore files found within a directory,
+// following the semantics of .gitignore.
+type IgnoreFilter struct {
+	Gitignore   bool     `default:"false"`
+	IgnoreFiles []string `default:"[]"`
+}
+
+func (filter IgnoreFilter) Matcher(root string) *ignore.Matcher {
+	return ignore.NewMatcher(root, filter.Gitignore, filter.IgnoreFiles)
+}
+
+// withOwnerMappings returns a copy of the directory with the ownership of its
+// contents changed according to the given mappings.
+func (dir *Directory) withOwnerMappings(ctx context.Context, mappings []OwnerMapping) (*Directory, error) {
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	st, err = NewOwnerMapDagOp(ctx, st, dir.Dir, mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDirectorySt(ctx, dir.Query, st, "/", dir.Platform, dir.Services)
+}
+
+// Filter returns a new directory containing only the contents of this one
+// that pass the given filters.
+func (dir *Directory) Filter(ctx context.Context, copyFilter CopyFilter, ignoreFilter IgnoreFilter) (*Directory, error) {
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	st, err = NewFilterDagOp(ctx, st, dir.Dir, copyFilter, ignoreFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDirectorySt(ctx, dir.Query, st, "/", dir.Platform, dir.Services)
+}
+
+// copyFiltered copies the tree at src to dest, preserving metadata and
+// skipping anything that doesn't pass the filters.
+func copyFiltered(ctx context.Context, src, dest string, copyFilter CopyFilter, ignoreFilter IgnoreFilter) error {
+	matcher := ignoreFilter.Matcher(src)
+	filterOpt := &fsutil.FilterOpt{
+		IncludePatterns: copyFilter.Include,
+		ExcludePatterns: copyFilter.Exclude,
+	}
+	if matcher.Enabled() {
+		filterOpt.Map = matcher.MapFunc()
+	}
+
+	srcFS, err := fsutil.NewFS(src)
+	if err != nil {
+		return err
+	}
+	srcFS, err = fsutil.NewFilterFS(srcFS, filterOpt)
+	if err != nil {
+		return err
+	}
+
+	dw, err := fsutil.NewDiskWriter(ctx, dest, fsutil.DiskWriterOpt{
+		SyncDataCb: func(ctx context.Context, p string, w io.WriteCloser) error {
+			r, err := srcFS.Open(p)
+			if err != nil {
+				return err
+			}
+			defer r.Close()
+			_, err = io.Copy(w, r)
+			return err
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := srcFS.Walk(ctx, "/", func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return dw.HandleChange(fsutil.ChangeKindAdd, p, info, nil)
+	}); err != nil {
+		return err
+	}
+	if err := dw.Wait(ctx); err != nil {
+		return err
+	}
+	return matcher.Err()
+}
+
 func (dir *Directory) WithDirectory(ctx context.Context, destDir string, src *Directory, filter CopyFilter, owner *Ownership) (*Directory, error) {
 	dir = dir.Clone()
 
@@ -788,3 +1116,58 @@ func validateFileName(file string) error {
 	}
 	return nil
 }
+
+// mount solves the directory, mounts it read-only and calls fn with the path
+// of the directory within it.
+func (dir *Directory) mount(ctx context.Context, fn func(dirPath string) error) error {
+	ok, err := dir.mountRoot(ctx, func(root string) error {
+		dirPath, err := continuityfs.RootPath(root, dir.Dir)
+		if err != nil {
+			return err
+		}
+		return fn(dirPath)
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s: %w", dir.Dir, os.ErrNotExist)
+	}
+	return nil
+}
+
+// mountRoot solves the directory, mounts its whole filesystem read-only and
+// calls fn with the path of its root. If the filesystem is empty, i.e.
+// llb.Scratch(), there's nothing to mount: fn isn't called and false is
+// returned.
+func (dir *Directory) mountRoot(ctx context.Context, fn func(root string) error) (bool, error) {
+	svcs, err := dir.Query.Services(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get services: %w", err)
+	}
+	bk, err := dir.Query.Buildkit(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+
+	detach, _, err := svcs.StartBindings(ctx, dir.Services)
+	if err != nil {
+		return false, err
+	}
+	defer detach()
+
+	res, err := bk.Solve(ctx, bkgw.SolveRequest{
+		Definition: dir.LLB,
+	})
+	if err != nil {
+		return false, err
+	}
+	ref, err := res.SingleRef()
+	if err != nil {
+		return false, err
+	}
+	if ref == nil {
+		return false, nil
+	}
+	return true, ref.Mount(ctx, fn)
+}
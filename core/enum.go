@@ -136,6 +136,9 @@ func (e *ModuleEnum) PossibleValues() ast.EnumValueList {
 		if val.SourceMap != nil {
 			def.Directives = append(def.Directives, val.SourceMap.TypeDirective())
 		}
+		if reason := deprecationReason(val.Deprecated); reason != "" {
+			def.Directives = append(def.Directives, dagql.DeprecatedDirective(reason))
+		}
 		values = append(values, def)
 	}
 
@@ -301,6 +301,38 @@ func (file *File) WithTimestamps(ctx context.Context, unix int) (*File, error) {
 	return file, nil
 }
 
+// Extract unpacks the file, which must be a tar (optionally gzip or zstd
+// compressed) or zip archive, into a new directory.
+func (file *File) Extract(ctx context.Context) (*Directory, error) {
+	st, err := file.State()
+	if err != nil {
+		return nil, err
+	}
+
+	st, err = NewExtractDagOp(ctx, st, file.File)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDirectorySt(ctx, file.Query, st, "/", file.Platform, file.Services)
+}
+
+// withOwnerMappings returns a copy of the file with its ownership changed
+// according to the given mappings.
+func (file *File) withOwnerMappings(ctx context.Context, mappings []OwnerMapping) (*File, error) {
+	st, err := file.State()
+	if err != nil {
+		return nil, err
+	}
+
+	st, err = NewOwnerMapDagOp(ctx, st, file.File, mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileSt(ctx, file.Query, st, path.Join("/", path.Base(file.File)), file.Platform, file.Services)
+}
+
 func (file *File) Open(ctx context.Context) (io.ReadCloser, error) {
 	bk, err := file.Query.Buildkit(ctx)
 	if err != nil {
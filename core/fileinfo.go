@@ -0,0 +1,210 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+
+	continuityfs "github.com/containerd/continuity/fs"
+	"github.com/tonistiigi/fsutil"
+	fstypes "github.com/tonistiigi/fsutil/types"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
+)
+
+type FileType string
+
+var FileTypes = dagql.NewEnum[FileType]()
+
+var (
+	FileTypeFile      = FileTypes.Register("FILE", `A regular file`)
+	FileTypeDirectory = FileTypes.Register("DIRECTORY", `A directory`)
+	FileTypeSymlink   = FileTypes.Register("SYMLINK", `A symbolic link`)
+	FileTypeOther     = FileTypes.Register("OTHER", `Anything else, such as a device or a named pipe`)
+)
+
+func (typ FileType) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "FileType",
+		NonNull:   true,
+	}
+}
+
+func (typ FileType) TypeDescription() string {
+	return "The type of a filesystem entry."
+}
+
+func (typ FileType) Decoder() dagql.InputDecoder {
+	return FileTypes
+}
+
+func (typ FileType) ToLiteral() call.Literal {
+	return FileTypes.Literal(typ)
+}
+
+func fileTypeOf(mode os.FileMode) FileType {
+	switch {
+	case mode.IsRegular():
+		return FileTypeFile
+	case mode.IsDir():
+		return FileTypeDirectory
+	case mode&os.ModeSymlink != 0:
+		return FileTypeSymlink
+	default:
+		return FileTypeOther
+	}
+}
+
+// FileInfo describes a filesystem entry, as returned by Directory.stat.
+type FileInfo struct {
+	Name          string   `field:"true" doc:"The base name of the entry."`
+	FileType      FileType `field:"true" doc:"The type of the entry."`
+	Size          int      `field:"true" doc:"The size of the entry in bytes."`
+	Mode          int      `field:"true" doc:"The permission bits of the entry (e.g., 0644)."`
+	UID           int      `field:"true" doc:"The user ID owning the entry."`
+	GID           int      `field:"true" doc:"The group ID owning the entry."`
+	ModifiedTime  int      `field:"true" doc:"The last modification time of the entry, in seconds since the Unix epoch."`
+	SymlinkTarget string   `field:"true" doc:"The target of the entry if it is a symbolic link, or an empty string."`
+}
+
+func (FileInfo) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "FileInfo",
+		NonNull:   true,
+	}
+}
+
+func (FileInfo) TypeDescription() string {
+	return "Information about a file, directory or other filesystem entry."
+}
+
+func newFileInfo(st *fstypes.Stat) *FileInfo {
+	mode := os.FileMode(st.Mode)
+	return &FileInfo{
+		Name:          path.Base(st.Path),
+		FileType:      fileTypeOf(mode),
+		Size:          int(st.Size_),
+		Mode:          unixPerms(mode),
+		UID:           int(st.Uid),
+		GID:           int(st.Gid),
+		ModifiedTime:  int(st.ModTime / 1e9),
+		SymlinkTarget: st.Linkname,
+	}
+}
+
+// unixPerms converts the permission bits of a Go file mode to their Unix
+// representation, including the setuid, setgid and sticky bits.
+func unixPerms(mode os.FileMode) int {
+	perms := int(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		perms |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		perms |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		perms |= 0o1000
+	}
+	return perms
+}
+
+// StatPath returns information about the entry at the given path, relative to
+// the directory. Unless follow is set, a symlink at the path is described
+// itself rather than its target; symlinks in parent directories are always
+// followed, within the directory's root.
+//
+// An error wrapping os.ErrNotExist is returned if there's nothing at the path.
+func (dir *Directory) StatPath(ctx context.Context, p string, follow bool) (*FileInfo, error) {
+	fullPath := path.Join("/", dir.Dir, p)
+
+	var info *FileInfo
+	ok, err := dir.mountRoot(ctx, func(root string) error {
+		var fp string
+		var err error
+		switch {
+		case follow:
+			fp, err = continuityfs.RootPath(root, fullPath)
+		case fullPath == "/":
+			fp = root
+		default:
+			fp, err = continuityfs.RootPath(root, path.Dir(fullPath))
+			fp = filepath.Join(fp, path.Base(fullPath))
+		}
+		if err != nil {
+			return err
+		}
+		st, err := fsutil.Stat(fp)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+				return fmt.Errorf("%s: %w", p, os.ErrNotExist)
+			}
+			return err
+		}
+		st.Path = fullPath
+		info = newFileInfo(st)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// empty directory, i.e. llb.Scratch()
+		if fullPath == "/" {
+			// fake out a reasonable response
+			return &FileInfo{
+				Name:     "/",
+				FileType: FileTypeDirectory,
+			}, nil
+		}
+		return nil, fmt.Errorf("%s: %w", p, os.ErrNotExist)
+	}
+	return info, nil
+}
+
+// Exists returns whether there's an entry at the given path, relative to the
+// directory. If expectedType is set, the entry must also be of that type;
+// symlinks are followed unless the expected type is FileTypeSymlink.
+func (dir *Directory) Exists(ctx context.Context, p string, expectedType FileType) (bool, error) {
+	info, err := dir.StatPath(ctx, p, expectedType != "" && expectedType != FileTypeSymlink)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return expectedType == "" || info.FileType == expectedType, nil
+}
+
+// StatPath returns information about the entry at the given path in the
+// container, which may be within a mount.
+func (container *Container) StatPath(ctx context.Context, p string, follow bool) (*FileInfo, error) {
+	dir, _, err := locatePath(container, p, NewDirectory)
+	if err != nil {
+		return nil, err
+	}
+	info, err := dir.StatPath(ctx, ".", follow)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", p, os.ErrNotExist)
+		}
+		return nil, err
+	}
+	info.Name = path.Base(absPath(container.Config.WorkingDir, p))
+	return info, nil
+}
+
+// Exists returns whether there's an entry at the given path in the container,
+// which may be within a mount. See Directory.Exists.
+func (container *Container) Exists(ctx context.Context, p string, expectedType FileType) (bool, error) {
+	dir, _, err := locatePath(container, p, NewDirectory)
+	if err != nil {
+		return false, err
+	}
+	return dir.Exists(ctx, ".", expectedType)
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// FunctionCallStreams routes the items returned incrementally by streaming
+// function calls to the callers waiting for them.
+//
+// Calls are identified by the ID of the nested client they run in, which is
+// also the client the SDK uses to return items.
+type FunctionCallStreams struct {
+	mu    sync.Mutex
+	sinks map[string]func(context.Context, JSON) error
+}
+
+func NewFunctionCallStreams() *FunctionCallStreams {
+	return &FunctionCallStreams{
+		sinks: map[string]func(context.Context, JSON) error{},
+	}
+}
+
+// Register sets the sink receiving the items of the function call running
+// in the given client, until the returned func is called.
+func (streams *FunctionCallStreams) Register(clientID string, sink func(context.Context, JSON) error) func() {
+	streams.mu.Lock()
+	defer streams.mu.Unlock()
+	streams.sinks[clientID] = sink
+	return func() {
+		streams.mu.Lock()
+		defer streams.mu.Unlock()
+		delete(streams.sinks, clientID)
+	}
+}
+
+// Send delivers an item of the function call running in the given client. If
+// nobody is waiting for the items of the call, e.g. because the call isn't
+// part of a subscription, the item is dropped: the caller gets it as part of
+// the returned value instead.
+func (streams *FunctionCallStreams) Send(ctx context.Context, clientID string, item JSON) error {
+	streams.mu.Lock()
+	sink, ok := streams.sinks[clientID]
+	streams.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return sink(ctx, item)
+}
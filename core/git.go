@@ -3,6 +3,8 @@ package core
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
 
 	"github.com/moby/buildkit/client/llb"
 	"github.com/pkg/errors"
@@ -19,6 +21,9 @@ type GitRepository struct {
 
 	DiscardGitDir bool `json:"discardGitDir"`
 
+	// CheckoutOpts are the defaults for the trees of the repository's refs.
+	CheckoutOpts GitCheckoutOpts `json:"checkoutOpts"`
+
 	SSHKnownHosts string  `json:"sshKnownHosts"`
 	SSHAuthSocket *Socket `json:"sshAuthSocket"`
 
@@ -27,6 +32,12 @@ type GitRepository struct {
 
 	AuthToken  *Secret `json:"authToken"`
 	AuthHeader *Secret `json:"authHeader"`
+
+	// LocalGitDir is the .git directory of a repository on the client, synced
+	// over its session, if this is a local repository rather than a remote.
+	LocalGitDir *Directory `json:"localGitDir"`
+	// LocalPath is the path of the local repository on the client.
+	LocalPath string `json:"localPath"`
 }
 
 func (*GitRepository) Type() *ast.Type {
@@ -47,6 +58,44 @@ type GitRef struct {
 	Repo *GitRepository `json:"repository"`
 }
 
+// GitCommit is a commit in a git repository.
+type GitCommit struct {
+	SHA            string   `field:"true" doc:"The SHA of the commit."`
+	Message        string   `field:"true" doc:"The full commit message."`
+	AuthorName     string   `field:"true" doc:"The name of the author of the commit."`
+	AuthorEmail    string   `field:"true" doc:"The email address of the author of the commit."`
+	AuthorTime     int      `field:"true" doc:"The time the commit was authored, in seconds since the Unix epoch."`
+	CommitterName  string   `field:"true" doc:"The name of the committer of the commit."`
+	CommitterEmail string   `field:"true" doc:"The email address of the committer of the commit."`
+	CommitTime     int      `field:"true" doc:"The time the commit was committed, in seconds since the Unix epoch."`
+	Parents        []string `field:"true" doc:"The SHAs of the parents of the commit."`
+}
+
+func (GitCommit) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "GitCommit",
+		NonNull:   true,
+	}
+}
+
+func (GitCommit) TypeDescription() string {
+	return "A commit in a git repository."
+}
+
+func newGitCommit(c gitdns.Commit) GitCommit {
+	return GitCommit{
+		SHA:            c.SHA,
+		Message:        c.Message,
+		AuthorName:     c.AuthorName,
+		AuthorEmail:    c.AuthorEmail,
+		AuthorTime:     int(c.AuthorTime),
+		CommitterName:  c.CommitterName,
+		CommitterEmail: c.CommitterEmail,
+		CommitTime:     int(c.CommitTime),
+		Parents:        c.Parents,
+	}
+}
+
 func (*GitRef) Type() *ast.Type {
 	return &ast.Type{
 		NamedType: "GitRef",
@@ -58,20 +107,72 @@ func (*GitRef) TypeDescription() string {
 	return "A git ref (tag, branch, or commit)."
 }
 
-func (ref *GitRef) Tree(ctx context.Context, discardGitDir bool) (*Directory, error) {
-	st, err := ref.getState(ctx, ref.Repo.DiscardGitDir || discardGitDir)
+// GitCheckoutOpts controls how much of a repository is fetched and checked
+// out for a tree.
+type GitCheckoutOpts struct {
+	// Depth is the number of commits of history to fetch, or -1 for all of
+	// it. Zero means the default of 1.
+	Depth int
+	// SparsePaths restricts the checkout to these paths, if set.
+	SparsePaths []string
+	// Filter is a partial clone filter spec, such as "blob:none".
+	Filter string
+	// DiscardSubmodules skips checking out submodules.
+	DiscardSubmodules bool
+	// NoCheckout only keeps the .git directory, without a working tree.
+	NoCheckout bool
+	// Tags fetches all tags along with the ref.
+	Tags bool
+}
+
+func (ref *GitRef) Tree(ctx context.Context, discardGitDir bool, checkoutOpts GitCheckoutOpts) (*Directory, error) {
+	if ref.Repo.LocalGitDir != nil {
+		return ref.localTree(ctx, ref.Repo.DiscardGitDir || discardGitDir, checkoutOpts)
+	}
+	st, err := ref.getState(ctx, ref.Repo.DiscardGitDir || discardGitDir, checkoutOpts)
 	if err != nil {
 		return nil, err
 	}
 	return NewDirectorySt(ctx, ref.Query, st, "", ref.Repo.Platform, ref.Repo.Services)
 }
 
+// localTree checks out the ref from a local repository. Only sparse paths
+// apply; the whole history is always kept, and submodules aren't checked out.
+func (ref *GitRef) localTree(ctx context.Context, discardGitDir bool, checkoutOpts GitCheckoutOpts) (*Directory, error) {
+	commit, err := ref.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	st, err := ref.Repo.LocalGitDir.State()
+	if err != nil {
+		return nil, err
+	}
+	st, err = NewGitCheckoutDagOp(ctx, st, ref.Repo.LocalGitDir.Dir, commit, !discardGitDir, checkoutOpts.SparsePaths)
+	if err != nil {
+		return nil, err
+	}
+	return NewDirectorySt(ctx, ref.Query, st, "", ref.Repo.Platform, nil)
+}
+
 func (ref *GitRef) Commit(ctx context.Context) (string, error) {
+	if ref.Repo.LocalGitDir != nil {
+		var commit string
+		err := ref.Repo.LocalGitDir.mount(ctx, func(gitDir string) error {
+			var err error
+			commit, err = gitdns.ResolveCommit(ctx, gitDir, ref.Ref)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+		return commit, nil
+	}
+
 	bk, err := ref.Query.Buildkit(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get buildkit client: %w", err)
 	}
-	st, err := ref.getState(ctx, true)
+	st, err := ref.getState(ctx, true, GitCheckoutOpts{})
 	if err != nil {
 		return "", err
 	}
@@ -85,7 +186,281 @@ func (ref *GitRef) Commit(ctx context.Context) (string, error) {
 	return p.Sources.Git[0].Commit, nil
 }
 
-func (ref *GitRef) getState(ctx context.Context, discardGitDir bool) (llb.State, error) {
+// Log returns the commits reachable from the ref, newest first. If limit is
+// positive, at most that many are returned.
+func (ref *GitRef) Log(ctx context.Context, limit int) ([]GitCommit, error) {
+	// only fetch as much history as is returned
+	depth := -1
+	if limit > 0 {
+		depth = limit
+	}
+	var commits []GitCommit
+	err := ref.mountGitDir(ctx, GitCheckoutOpts{Depth: depth, Filter: "blob:none"}, func(gitDir, rev string) error {
+		log, err := gitdns.Log(ctx, gitDir, rev, limit)
+		if err != nil {
+			return err
+		}
+		commits = make([]GitCommit, 0, len(log))
+		for _, c := range log {
+			commits = append(commits, newGitCommit(c))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// Describe returns the most recent tag reachable from the ref, like git
+// describe --tags.
+func (ref *GitRef) Describe(ctx context.Context) (string, error) {
+	var desc string
+	err := ref.mountGitDir(ctx, GitCheckoutOpts{Depth: -1, Filter: "blob:none", Tags: true}, func(gitDir, rev string) error {
+		var err error
+		desc, err = gitdns.Describe(ctx, gitDir, rev)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return desc, nil
+}
+
+// WithChanges returns a ref to a new commit on top of this one, recording the
+// contents of dir. The commit only exists in a copy of the repository's .git
+// directory, until it's pushed.
+func (ref *GitRef) WithChanges(ctx context.Context, dir *Directory, message, authorName, authorEmail string) (*GitRef, error) {
+	var gitDir llb.State
+	var gitDirPath, parent string
+	services := slices.Clone(ref.Repo.Services)
+	if ref.Repo.LocalGitDir != nil {
+		var err error
+		gitDir, err = ref.Repo.LocalGitDir.State()
+		if err != nil {
+			return nil, err
+		}
+		gitDirPath = ref.Repo.LocalGitDir.Dir
+		parent = ref.Ref
+	} else {
+		// blobs are needed to push the commit later, so no filter here
+		var err error
+		gitDir, err = ref.getState(ctx, false, GitCheckoutOpts{NoCheckout: true, DiscardSubmodules: true})
+		if err != nil {
+			return nil, err
+		}
+		gitDirPath = ".git"
+		parent = "HEAD"
+	}
+	tree, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+	services.Merge(dir.Services)
+
+	st, err := NewGitCommitDagOp(ctx, gitDir, gitDirPath, tree, dir.Dir, parent, message, authorName, authorEmail)
+	if err != nil {
+		return nil, err
+	}
+	newGitDir, err := NewDirectorySt(ctx, ref.Query, st, "", ref.Repo.Platform, services)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := *ref.Repo
+	repo.LocalGitDir = newGitDir
+	return &GitRef{
+		Query: ref.Query,
+		Ref:   "HEAD",
+		Repo:  &repo,
+	}, nil
+}
+
+// Diff returns the paths of the files that differ between two refs of the
+// repository.
+func (repo *GitRepository) Diff(ctx context.Context, fromRef, toRef string) ([]string, error) {
+	var paths []string
+	if repo.LocalGitDir != nil {
+		err := repo.LocalGitDir.mount(ctx, func(gitDir string) error {
+			fromSHA, err := gitdns.ResolveCommit(ctx, gitDir, fromRef)
+			if err != nil {
+				return err
+			}
+			toSHA, err := gitdns.ResolveCommit(ctx, gitDir, toRef)
+			if err != nil {
+				return err
+			}
+			paths, err = gitdns.DiffNames(ctx, gitDir, nil, fromSHA, toSHA)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return paths, nil
+	}
+
+	from := &GitRef{Query: repo.Query, Ref: fromRef, Repo: repo}
+	to := &GitRef{Query: repo.Query, Ref: toRef, Repo: repo}
+
+	fromSHA, err := from.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	toSHA, err := to.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = from.mountGitDir(ctx, GitCheckoutOpts{Filter: "blob:none"}, func(fromGitDir, _ string) error {
+		return to.mountGitDir(ctx, GitCheckoutOpts{Filter: "blob:none"}, func(toGitDir, _ string) error {
+			var err error
+			paths, err = gitdns.DiffNames(ctx, toGitDir, []string{fromGitDir}, fromSHA, toSHA)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// LocalTags returns the tags of a local repository matching any of the given
+// patterns, or all of them if there are none.
+func (repo *GitRepository) LocalTags(ctx context.Context, patterns []string) ([]string, error) {
+	var tags []string
+	err := repo.LocalGitDir.mount(ctx, func(gitDir string) error {
+		var err error
+		tags, err = gitdns.ListTags(ctx, gitDir, patterns)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// UncommittedChanges returns the paths in the working tree of a local
+// repository that differ from its index or HEAD, including untracked files.
+// The working tree is passed separately, since it's only synced on demand.
+func (repo *GitRepository) UncommittedChanges(ctx context.Context, workTree *Directory) ([]string, error) {
+	var paths []string
+	err := repo.LocalGitDir.mount(ctx, func(gitDir string) error {
+		return workTree.mount(ctx, func(workTreeDir string) error {
+			var err error
+			paths, err = gitdns.Status(ctx, gitDir, workTreeDir)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// Push pushes the commit of ref to remoteRef in the repository, using its
+// credentials, and returns the commit SHA. The ref may come from another
+// repository, such as one returned by WithChanges.
+func (repo *GitRepository) Push(ctx context.Context, ref *GitRef, remoteRef string, force bool) (string, error) {
+	if repo.URL == "" {
+		return "", errors.Errorf("push is only available for remote repositories")
+	}
+	commit, err := ref.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	opts := gitdns.PushOpts{
+		KnownHosts: repo.SSHKnownHosts,
+		Namespace:  clientMetadata.SessionID,
+		DNS:        repo.Query.DNSConfig(),
+	}
+	if repo.AuthToken != nil || repo.AuthHeader != nil {
+		secretStore, err := repo.Query.Secrets(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get secret store: %w", err)
+		}
+		if repo.AuthToken != nil {
+			token, err := secretStore.GetSecretPlaintext(ctx, repo.AuthToken.IDDigest)
+			if err != nil {
+				return "", fmt.Errorf("failed to get auth token: %w", err)
+			}
+			opts.AuthToken = string(token)
+		}
+		if repo.AuthHeader != nil {
+			header, err := secretStore.GetSecretPlaintext(ctx, repo.AuthHeader.IDDigest)
+			if err != nil {
+				return "", fmt.Errorf("failed to get auth header: %w", err)
+			}
+			opts.AuthHeader = string(header)
+		}
+	}
+	if repo.SSHAuthSocket != nil {
+		socketStore, err := repo.Query.Sockets(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get socket store: %w", err)
+		}
+		sockPath, cleanup, err := socketStore.MountSocket(ctx, repo.SSHAuthSocket.IDDigest)
+		if err != nil {
+			return "", fmt.Errorf("failed to mount SSH socket: %w", err)
+		}
+		defer cleanup()
+		opts.SSHAuthSock = sockPath
+	}
+
+	svcs, err := repo.Query.Services(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get services: %w", err)
+	}
+	detach, _, err := svcs.StartBindings(ctx, repo.Services)
+	if err != nil {
+		return "", err
+	}
+	defer detach()
+
+	// the remote may not have any of the history, so fetch all of it
+	err = ref.mountGitDir(ctx, GitCheckoutOpts{Depth: -1}, func(gitDir, _ string) error {
+		return gitdns.Push(ctx, gitDir, repo.URL, commit, remoteRef, force, opts)
+	})
+	if err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+// mountGitDir calls fn with a read-only .git directory containing the ref,
+// and the revision to use for it there.
+//
+// For a remote repository, the .git directory is fetched with opts, without a
+// working tree. For a local repository, it's the one synced from the client.
+func (ref *GitRef) mountGitDir(ctx context.Context, opts GitCheckoutOpts, fn func(gitDir, rev string) error) error {
+	if ref.Repo.LocalGitDir != nil {
+		return ref.Repo.LocalGitDir.mount(ctx, func(gitDir string) error {
+			commit, err := gitdns.ResolveCommit(ctx, gitDir, ref.Ref)
+			if err != nil {
+				return err
+			}
+			return fn(gitDir, commit)
+		})
+	}
+
+	opts.NoCheckout = true
+	st, err := ref.getState(ctx, false, opts)
+	if err != nil {
+		return err
+	}
+	dir, err := NewDirectorySt(ctx, ref.Query, st, "", ref.Repo.Platform, ref.Repo.Services)
+	if err != nil {
+		return err
+	}
+	return dir.mount(ctx, func(root string) error {
+		return fn(filepath.Join(root, ".git"), "HEAD")
+	})
+}
+
+func (ref *GitRef) getState(ctx context.Context, discardGitDir bool, checkoutOpts GitCheckoutOpts) (llb.State, error) {
 	opts := []llb.GitOption{}
 
 	if !discardGitDir {
@@ -103,6 +478,27 @@ func (ref *GitRef) getState(ctx context.Context, discardGitDir bool) (llb.State,
 	if ref.Repo.AuthHeader != nil {
 		opts = append(opts, llb.AuthHeaderSecret(ref.Repo.AuthHeader.LLBID()))
 	}
+	switch {
+	case checkoutOpts.Depth < 0:
+		opts = append(opts, gitdns.Depth(0))
+	case checkoutOpts.Depth > 1:
+		opts = append(opts, gitdns.Depth(checkoutOpts.Depth))
+	}
+	if len(checkoutOpts.SparsePaths) > 0 {
+		opts = append(opts, gitdns.SparsePaths(checkoutOpts.SparsePaths))
+	}
+	if checkoutOpts.Filter != "" {
+		opts = append(opts, gitdns.Filter(checkoutOpts.Filter))
+	}
+	if checkoutOpts.DiscardSubmodules {
+		opts = append(opts, gitdns.NoSubmodules())
+	}
+	if checkoutOpts.NoCheckout {
+		opts = append(opts, gitdns.NoCheckout())
+	}
+	if checkoutOpts.Tags {
+		opts = append(opts, gitdns.Tags())
+	}
 
 	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
 	if err != nil {
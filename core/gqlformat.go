@@ -21,6 +21,19 @@ func formatGqlDescription(desc string) string {
 	return "\n" + strings.TrimSpace(desc) + "\n"
 }
 
+// deprecationReason returns the reason to use for the @deprecated directive of
+// a deprecated type member, or "" if it isn't deprecated. GraphQL's default
+// reason is used if none was given.
+func deprecationReason(deprecated *string) string {
+	if deprecated == nil {
+		return ""
+	}
+	if reason := strings.TrimSpace(*deprecated); reason != "" {
+		return reason
+	}
+	return "No longer supported"
+}
+
 func gqlObjectName(name string) string {
 	// gql object name is capitalized camel case
 	return strcase.ToCamel(name)
@@ -2,12 +2,17 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	bkgw "github.com/moby/buildkit/frontend/gateway/client"
 
 	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/engine/buildkit"
@@ -86,3 +91,190 @@ func (d *portHealthChecker) Check(ctx context.Context) (rerr error) {
 
 	return nil
 }
+
+// healthcheckProbeTimeout bounds how long a single custom health check attempt
+// may take before it's considered failed.
+const healthcheckProbeTimeout = 30 * time.Second
+
+// customHealthChecker runs a user-configured health check against a service,
+// either by running a command inside its container or by probing it over
+// HTTP.
+type customHealthChecker struct {
+	bk    *buildkit.Client
+	ctr   *buildkit.Container
+	host  string
+	ports []Port
+	hc    *ContainerHealthcheck
+
+	// execReq is the request for the service's own process, used as a
+	// template for running the health check command.
+	execReq bkgw.StartRequest
+}
+
+func newCustomHealth(
+	bk *buildkit.Client,
+	ctr *buildkit.Container,
+	host string,
+	ports []Port,
+	hc *ContainerHealthcheck,
+	execReq bkgw.StartRequest,
+) *customHealthChecker {
+	return &customHealthChecker{
+		bk:      bk,
+		ctr:     ctr,
+		host:    host,
+		ports:   ports,
+		hc:      hc,
+		execReq: execReq,
+	}
+}
+
+func (d *customHealthChecker) Check(ctx context.Context) (rerr error) {
+	ctx, span := Tracer(ctx).Start(ctx, "healthcheck "+d.describe())
+	defer telemetry.End(span, func() error { return rerr })
+
+	slog := slog.SpanLogger(ctx, InstrumentationLibrary).With("host", d.host)
+
+	started := time.Now()
+	failures := 0
+	for {
+		err := d.probe(ctx)
+		if err == nil {
+			slog.Info("service is healthy", "elapsed", time.Since(started))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+
+		if time.Since(started) < d.hc.StartPeriod {
+			// failures during the start period don't count
+			slog.Warn("service not ready", "error", err, "elapsed", time.Since(started))
+		} else {
+			failures++
+			slog.Warn("health check failed", "error", err, "failures", failures)
+			if failures >= d.hc.Retries {
+				return fmt.Errorf("service unhealthy after %d consecutive failures: %w", failures, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-time.After(d.hc.Interval):
+		}
+	}
+}
+
+func (d *customHealthChecker) describe() string {
+	if len(d.hc.Args) > 0 {
+		return strings.Join(d.hc.Args, " ")
+	}
+	return fmt.Sprintf("GET %s", d.hc.HTTPPath)
+}
+
+func (d *customHealthChecker) probe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeoutCause(ctx, healthcheckProbeTimeout,
+		fmt.Errorf("health check %w after %s", buildkit.ErrExecTimedOut, healthcheckProbeTimeout))
+	defer cancel()
+	if len(d.hc.Args) > 0 {
+		return d.probeExec(ctx)
+	}
+	return d.probeHTTP(ctx)
+}
+
+func (d *customHealthChecker) probeExec(ctx context.Context) error {
+	stdio := telemetry.SpanStdio(ctx, InstrumentationLibrary)
+	defer stdio.Close()
+
+	req := d.execReq
+	req.Args = d.hc.Args
+	req.Tty = false
+	req.Stdin = nil
+	req.Stdout = nopWriteCloser{stdio.Stdout}
+	req.Stderr = nopWriteCloser{stdio.Stderr}
+
+	proc, err := d.ctr.Start(ctx, req)
+	if err != nil {
+		return fmt.Errorf("start health check: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- proc.Wait()
+	}()
+
+	select {
+	case err := <-exited:
+		return err
+	case <-ctx.Done():
+		if err := proc.Signal(context.WithoutCancel(ctx), syscall.SIGKILL); err != nil {
+			return errors.Join(context.Cause(ctx), fmt.Errorf("kill health check: %w", err))
+		}
+		<-exited
+		return context.Cause(ctx)
+	}
+}
+
+func (d *customHealthChecker) probeHTTP(ctx context.Context) error {
+	port := d.hc.HTTPPort
+	if port == 0 {
+		for _, p := range d.ports {
+			if p.Protocol == NetworkProtocolTCP {
+				port = p.Port
+				break
+			}
+		}
+		if port == 0 {
+			return errors.New("no port specified for HTTP health check and no TCP ports exposed")
+		}
+	}
+	addr := net.JoinHostPort(d.host, fmt.Sprintf("%d", port))
+
+	// dial from within the container's network namespace, then make the
+	// request over the established connection
+	conn, err := buildkit.RunInNetNS(ctx, d.bk, d.ctr, func() (net.Conn, error) {
+		dialer := net.Dialer{Timeout: time.Second}
+		return dialer.DialContext(ctx, "tcp", addr)
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(context.Context, string, string) (net.Conn, error) {
+				return conn, nil
+			},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+d.hc.HTTPPath, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != d.hc.HTTPStatus {
+		return fmt.Errorf("GET %s: expected status %d, got %d", d.hc.HTTPPath, d.hc.HTTPStatus, resp.StatusCode)
+	}
+	return nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -3,13 +3,19 @@ package core
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
 	_ "embed"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
@@ -2828,6 +2834,123 @@ func (ContainerSuite) TestPublish(ctx context.Context, t *testctx.T) {
 	require.Equal(t, "im-a-default-arg\n", output)
 }
 
+func (ContainerSuite) TestPublishSignedWithAttestations(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	testRef := registryRef("container-publish-signed")
+	pushedRef, err := c.Container().From(alpineImage).
+		WithExec([]string{"touch", "/foo"}).
+		Publish(ctx, testRef, dagger.ContainerPublishOpts{
+			Sign: c.SetSecret("cosign-key", string(pemKey)),
+			Attestations: []dagger.ImageAttestation{
+				dagger.ImageAttestationSlsaProvenance,
+				dagger.ImageAttestationSpdxSbom,
+			},
+		})
+	require.NoError(t, err)
+
+	parsedRef, err := name.ParseReference(pushedRef, name.Insecure)
+	require.NoError(t, err)
+	dgst := parsedRef.(name.Digest).DigestStr()
+	tagRef, err := name.ParseReference(
+		parsedRef.Context().Name()+":"+strings.Replace(dgst, ":", "-", 1),
+		name.Insecure,
+	)
+	require.NoError(t, err)
+
+	// registry:2 doesn't implement the referrers API, so they're listed in
+	// the referrers tag schema index
+	indexDesc, err := remote.Get(tagRef, remote.WithTransport(http.DefaultTransport))
+	require.NoError(t, err)
+	var index ocispecs.Index
+	require.NoError(t, json.Unmarshal(indexDesc.Manifest, &index))
+
+	manifests := map[string]ocispecs.Descriptor{}
+	for _, desc := range index.Manifests {
+		manifests[desc.ArtifactType] = desc
+	}
+	require.Contains(t, manifests, "application/vnd.dev.cosign.artifact.sig.v1+json")
+	require.Contains(t, manifests, "application/vnd.dsse.envelope.v1+json")
+	require.Len(t, index.Manifests, 3)
+
+	fetchLayer := func(desc ocispecs.Descriptor) (ocispecs.Descriptor, []byte) {
+		manifestRef := parsedRef.Context().Digest(desc.Digest.String())
+		img, err := remote.Image(manifestRef, remote.WithTransport(http.DefaultTransport))
+		require.NoError(t, err)
+		manifest, err := img.Manifest()
+		require.NoError(t, err)
+		require.NotNil(t, manifest.Subject)
+		require.Equal(t, dgst, manifest.Subject.Digest.String())
+		layers, err := img.Layers()
+		require.NoError(t, err)
+		require.Len(t, layers, 1)
+		rc, err := layers[0].Compressed()
+		require.NoError(t, err)
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		return ocispecs.Descriptor{
+			MediaType:   string(manifest.Layers[0].MediaType),
+			Annotations: manifest.Layers[0].Annotations,
+		}, data
+	}
+
+	t.Run("signature", func(ctx context.Context, t *testctx.T) {
+		layer, payload := fetchLayer(manifests["application/vnd.dev.cosign.artifact.sig.v1+json"])
+		require.Equal(t, "application/vnd.dev.cosign.simplesigning.v1+json", layer.MediaType)
+		require.Contains(t, string(payload), dgst)
+		sig, err := base64.StdEncoding.DecodeString(layer.Annotations["dev.cosignproject.cosign/signature"])
+		require.NoError(t, err)
+		sum := sha256.Sum256(payload)
+		require.True(t, ecdsa.VerifyASN1(&key.PublicKey, sum[:], sig))
+	})
+
+	t.Run("attestations", func(ctx context.Context, t *testctx.T) {
+		predicates := map[string]string{}
+		for _, desc := range index.Manifests {
+			if desc.ArtifactType != "application/vnd.dsse.envelope.v1+json" {
+				continue
+			}
+			_, data := fetchLayer(desc)
+			var envelope struct {
+				PayloadType string `json:"payloadType"`
+				Payload     []byte `json:"payload"`
+				Signatures  []struct {
+					Sig []byte `json:"sig"`
+				} `json:"signatures"`
+			}
+			require.NoError(t, json.Unmarshal(data, &envelope))
+			require.Len(t, envelope.Signatures, 1)
+			pae := fmt.Sprintf("DSSEv1 %d %s %d %s",
+				len(envelope.PayloadType), envelope.PayloadType,
+				len(envelope.Payload), envelope.Payload)
+			sum := sha256.Sum256([]byte(pae))
+			require.True(t, ecdsa.VerifyASN1(&key.PublicKey, sum[:], envelope.Signatures[0].Sig))
+
+			var statement struct {
+				PredicateType string `json:"predicateType"`
+				Subject       []struct {
+					Digest map[string]string `json:"digest"`
+				} `json:"subject"`
+			}
+			require.NoError(t, json.Unmarshal(envelope.Payload, &statement))
+			require.Len(t, statement.Subject, 1)
+			require.Equal(t, strings.TrimPrefix(dgst, "sha256:"), statement.Subject[0].Digest["sha256"])
+			predicates[statement.PredicateType] = string(envelope.Payload)
+		}
+		require.Contains(t, predicates, "https://slsa.dev/provenance/v0.2")
+		require.Contains(t, predicates["https://slsa.dev/provenance/v0.2"], `"touch"`)
+		require.Contains(t, predicates, "https://spdx.dev/Document")
+		require.Contains(t, predicates["https://spdx.dev/Document"], "pkg:apk/alpine/busybox@")
+	})
+}
+
 func (ContainerSuite) TestAnnotations(ctx context.Context, t *testctx.T) {
 	build := func(c *dagger.Client, platform dagger.Platform) *dagger.Container {
 		return c.Container(dagger.ContainerOpts{Platform: platform}).
@@ -3696,6 +3819,195 @@ func (ContainerSuite) TestInsecureRootCapabilitesWithService(ctx context.Context
 	require.Equal(t, fmt.Sprintf("%s-from-outside\n%s-from-inside\n", randID, randID), out)
 }
 
+func (ContainerSuite) TestExecResourceLimits(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	t.Run("cpu quota", func(ctx context.Context, t *testctx.T) {
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"cat", "/sys/fs/cgroup/cpu.max"}, dagger.ContainerWithExecOpts{
+				CPUQuota: 0.5,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "50000 100000", strings.TrimSpace(out))
+	})
+
+	t.Run("memory limit", func(ctx context.Context, t *testctx.T) {
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"cat", "/sys/fs/cgroup/memory.max"}, dagger.ContainerWithExecOpts{
+				MemoryLimit: 64 << 20,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprint(64<<20), strings.TrimSpace(out))
+	})
+
+	t.Run("pids limit", func(ctx context.Context, t *testctx.T) {
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"cat", "/sys/fs/cgroup/pids.max"}, dagger.ContainerWithExecOpts{
+				PidsLimit: 32,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "32", strings.TrimSpace(out))
+	})
+
+	t.Run("container defaults", func(ctx context.Context, t *testctx.T) {
+		ctr := c.Container().From(alpineImage).
+			WithResourceLimits(dagger.ContainerWithResourceLimitsOpts{
+				MemoryLimit: 64 << 20,
+				PidsLimit:   32,
+			})
+
+		out, err := ctr.
+			WithExec([]string{"cat", "/sys/fs/cgroup/memory.max", "/sys/fs/cgroup/pids.max"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("%d\n32\n", 64<<20), out)
+
+		out, err = ctr.
+			WithExec([]string{"cat", "/sys/fs/cgroup/pids.max"}, dagger.ContainerWithExecOpts{
+				PidsLimit: 16,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "16", strings.TrimSpace(out))
+	})
+
+	t.Run("invalid limits", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().From(alpineImage).
+			WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+				MemoryLimit: -1,
+			}).
+			Sync(ctx)
+		require.ErrorContains(t, err, "invalid memory limit")
+	})
+
+	t.Run("oom", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().From(alpineImage).
+			WithExec([]string{"sh", "-c", "tail /dev/zero"}, dagger.ContainerWithExecOpts{
+				MemoryLimit: 16 << 20,
+			}).
+			Sync(ctx)
+
+		var oomErr *dagger.OOMError
+		require.ErrorAs(t, err, &oomErr)
+		require.Equal(t, 137, oomErr.ExitCode)
+
+		// OOM errors are still exec errors
+		var execErr *dagger.ExecError
+		require.ErrorAs(t, err, &execErr)
+		require.Contains(t, execErr.Error(), "exceeding its memory limit")
+	})
+}
+
+func (ContainerSuite) TestExecTimeout(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	t.Run("finishes in time", func(ctx context.Context, t *testctx.T) {
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"echo", "hi"}, dagger.ContainerWithExecOpts{
+				Timeout: 60,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hi\n", out)
+	})
+
+	t.Run("times out", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().From(alpineImage).
+			WithEnvVariable("CACHEBUSTER", identity.NewID()).
+			WithExec([]string{"sh", "-c", "echo started; echo oops >&2; sleep 300"}, dagger.ContainerWithExecOpts{
+				Timeout: 2,
+			}).
+			Sync(ctx)
+
+		var timeoutErr *dagger.TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.Contains(t, timeoutErr.Error(), "timed out after 2s")
+		require.Equal(t, "started", timeoutErr.Stdout)
+		require.Equal(t, "oops", timeoutErr.Stderr)
+
+		// timeout errors are still exec errors
+		var execErr *dagger.ExecError
+		require.ErrorAs(t, err, &execErr)
+	})
+
+	t.Run("negative timeout", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().From(alpineImage).
+			WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+				Timeout: -1,
+			}).
+			Sync(ctx)
+		requireErrOut(t, err, "must not be negative")
+	})
+}
+
+func (ContainerSuite) TestWithSymlink(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	out, err := c.Container().From(alpineImage).
+		WithWorkdir("/work").
+		WithEnvVariable("DIR", "/opt/bin").
+		WithSymlink("/bin/busybox", "$DIR/hello", dagger.ContainerWithSymlinkOpts{Expand: true}).
+		WithSymlink("../etc/os-release", "os-release").
+		WithExec([]string{"sh", "-c", "readlink /opt/bin/hello; readlink /work/os-release"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "/bin/busybox\n../etc/os-release\n", out)
+
+	t.Run("scratch", func(ctx context.Context, t *testctx.T) {
+		entries, err := c.Container().
+			WithSymlink("/nowhere", "/link").
+			Rootfs().
+			Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"link"}, entries)
+	})
+}
+
+func (ContainerSuite) TestExistsStat(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	ctr := c.Container().From(alpineImage).
+		WithMountedDirectory("/mnt", c.Directory().WithNewFile("mounted.txt", "hi")).
+		WithWorkdir("/mnt")
+
+	t.Run("exists in rootfs", func(ctx context.Context, t *testctx.T) {
+		exists, err := ctr.Exists(ctx, "/etc/alpine-release", dagger.ContainerExistsOpts{ExpectedType: dagger.FileTypeFile})
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		exists, err = ctr.Exists(ctx, "/etc", dagger.ContainerExistsOpts{ExpectedType: dagger.FileTypeFile})
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("exists in mount", func(ctx context.Context, t *testctx.T) {
+		exists, err := ctr.Exists(ctx, "mounted.txt")
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		exists, err = ctr.Exists(ctx, "/mnt/nope")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("stat", func(ctx context.Context, t *testctx.T) {
+		info := ctr.Stat("/mnt/mounted.txt")
+		name, err := info.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "mounted.txt", name)
+		size, err := info.Size(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, size)
+
+		typ, err := ctr.Stat("/mnt").FileType(ctx)
+		require.NoError(t, err)
+		require.Equal(t, dagger.FileTypeDirectory, typ)
+	})
+}
+
 func (ContainerSuite) TestWithMountedFileOwner(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 
@@ -3872,6 +4184,95 @@ func (ContainerSuite) TestWithDirectoryOwner(ctx context.Context, t *testctx.T)
 	})
 }
 
+func (ContainerSuite) TestWithDirectoryOwnerMap(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	src := c.Container().From(alpineImage).
+		WithExec([]string{"sh", "-c", "mkdir -p /src/sub && echo hi > /src/sub/a && echo hi > /src/b && " +
+			"chown -R 1000:1000 /src && chown 1001:1000 /src/b"}).
+		Directory("/src")
+
+	ctr := c.Container().From(alpineImage).
+		WithExec([]string{"adduser", "-D", "-u", "1234", "app"})
+
+	owners := func(ctx context.Context, t *testctx.T, ctr *dagger.Container, paths ...string) string {
+		out, err := ctr.WithExec(append([]string{"stat", "-c", "%n %u:%g"}, paths...)).Stdout(ctx)
+		require.NoError(t, err)
+		return out
+	}
+
+	t.Run("preserves ownership by default", func(ctx context.Context, t *testctx.T) {
+		require.Equal(t,
+			"/dst/sub/a 1000:1000\n/dst/b 1001:1000\n",
+			owners(ctx, t, ctr.WithDirectory("/dst", src), "/dst/sub/a", "/dst/b"))
+	})
+
+	t.Run("maps IDs", func(ctx context.Context, t *testctx.T) {
+		require.Equal(t,
+			"/dst/sub 0:0\n/dst/sub/a 0:0\n/dst/b 1001:0\n",
+			owners(ctx, t, ctr.WithDirectory("/dst", src, dagger.ContainerWithDirectoryOpts{
+				OwnerMap: []string{"1000:0"},
+			}), "/dst/sub", "/dst/sub/a", "/dst/b"))
+	})
+
+	t.Run("maps to names", func(ctx context.Context, t *testctx.T) {
+		require.Equal(t,
+			"/dst/sub/a 1234:1234\n/dst/b 1001:1234\n",
+			owners(ctx, t, ctr.WithDirectory("/dst", src, dagger.ContainerWithDirectoryOpts{
+				OwnerMap: []string{"1000:app"},
+			}), "/dst/sub/a", "/dst/b"))
+	})
+
+	t.Run("maps user and group separately", func(ctx context.Context, t *testctx.T) {
+		src := c.Container().From(alpineImage).
+			WithExec([]string{"sh", "-c", "mkdir -p /src && echo hi > /src/a && echo hi > /src/b && " +
+				"chown 1002:100 /src/a && chown 1003:100 /src/b"}).
+			Directory("/src")
+
+		require.Equal(t,
+			"/dst/a 1234:1234\n/dst/b 1003:1234\n",
+			owners(ctx, t, ctr.WithDirectory("/dst", src, dagger.ContainerWithDirectoryOpts{
+				OwnerMap: []string{"1002/100:app"},
+			}), "/dst/a", "/dst/b"))
+		require.Equal(t,
+			"/dst/a 1234:100\n/dst/b 1003:100\n",
+			owners(ctx, t, ctr.WithDirectory("/dst", src, dagger.ContainerWithDirectoryOpts{
+				OwnerMap: []string{"1002:app"},
+			}), "/dst/a", "/dst/b"))
+	})
+
+	t.Run("files", func(ctx context.Context, t *testctx.T) {
+		require.Equal(t,
+			"/dst/a 0:0\n",
+			owners(ctx, t, ctr.WithFile("/dst/a", src.File("sub/a"), dagger.ContainerWithFileOpts{
+				OwnerMap: []string{"1000:0"},
+			}), "/dst/a"))
+		require.Equal(t,
+			"/dst/a 1234:1234\n/dst/b 1234:1234\n",
+			owners(ctx, t, ctr.WithFiles("/dst", []*dagger.File{src.File("sub/a"), src.File("b")}, dagger.ContainerWithFilesOpts{
+				OwnerMap: []string{"1000:app", "1001:app"},
+			}), "/dst/a", "/dst/b"))
+	})
+
+	t.Run("invalid", func(ctx context.Context, t *testctx.T) {
+		_, err := ctr.WithDirectory("/dst", src, dagger.ContainerWithDirectoryOpts{
+			Owner:    "app",
+			OwnerMap: []string{"1000:0"},
+		}).Sync(ctx)
+		requireErrOut(t, err, "cannot set both owner and owner map")
+
+		_, err = ctr.WithDirectory("/dst", src, dagger.ContainerWithDirectoryOpts{
+			OwnerMap: []string{"app"},
+		}).Sync(ctx)
+		requireErrOut(t, err, "expected FROM:TO")
+
+		_, err = ctr.WithDirectory("/dst", src, dagger.ContainerWithDirectoryOpts{
+			OwnerMap: []string{"1000/100:0", "1001/100:app"},
+		}).Sync(ctx)
+		requireErrOut(t, err, "group 100 is already mapped")
+	})
+}
+
 func (ContainerSuite) TestWithNewFileOwner(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 
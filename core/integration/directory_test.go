@@ -74,6 +74,333 @@ func (DirectorySuite) TestWithNewFile(ctx context.Context, t *testctx.T) {
 	require.Equal(t, []string{"some-file"}, res.Directory.WithNewFile.Entries)
 }
 
+func (DirectorySuite) TestWithSymlink(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	dir := c.Directory().
+		WithNewFile("lib/libfoo.so.1", "foo").
+		WithSymlink("libfoo.so.1", "lib/libfoo.so").
+		WithSymlink("/does/not/exist", "dangling")
+
+	entries, err := dir.Directory("lib").Entries(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"libfoo.so", "libfoo.so.1"}, entries)
+
+	out, err := c.Container().From(alpineImage).
+		WithMountedDirectory("/mnt", dir).
+		WithExec([]string{"sh", "-c", "readlink /mnt/lib/libfoo.so; cat /mnt/lib/libfoo.so; readlink /mnt/dangling"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "libfoo.so.1\nfoo/does/not/exist\n", out)
+
+	t.Run("replaces existing file", func(ctx context.Context, t *testctx.T) {
+		out, err := c.Container().From(alpineImage).
+			WithMountedDirectory("/mnt", dir.WithSymlink("lib/libfoo.so.1", "dangling")).
+			WithExec([]string{"readlink", "/mnt/dangling"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "lib/libfoo.so.1\n", out)
+	})
+
+	t.Run("refuses to replace directory", func(ctx context.Context, t *testctx.T) {
+		_, err := dir.WithSymlink("foo", "lib").Sync(ctx)
+		requireErrOut(t, err, "a directory already exists")
+	})
+}
+
+func (DirectorySuite) TestSearch(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	dir := c.Directory().
+		WithNewFile("main.go", "package main\n\n// TODO: fix\nfunc main() {} // todo\n").
+		WithNewFile("lib/lib.go", "package lib\n// TODO: a TODO\n").
+		WithNewFile("README.md", "TODO: docs\n").
+		WithNewFile("bin/tool", "TODO\x00binary")
+
+	type match struct {
+		FilePath    string
+		LineNumber  int
+		Column      int
+		MatchedText string
+	}
+	search := func(ctx context.Context, t *testctx.T, dir *dagger.Directory, pattern string, opts ...dagger.DirectorySearchOpts) []match {
+		results, err := dir.Search(ctx, pattern, opts...)
+		require.NoError(t, err)
+		matches := make([]match, len(results))
+		for i, res := range results {
+			matches[i].FilePath, err = res.FilePath(ctx)
+			require.NoError(t, err)
+			matches[i].LineNumber, err = res.LineNumber(ctx)
+			require.NoError(t, err)
+			matches[i].Column, err = res.Column(ctx)
+			require.NoError(t, err)
+			matches[i].MatchedText, err = res.MatchedText(ctx)
+			require.NoError(t, err)
+		}
+		return matches
+	}
+
+	t.Run("regexp", func(ctx context.Context, t *testctx.T) {
+		require.Equal(t, []match{
+			{"README.md", 1, 1, "TODO"},
+			{"lib/lib.go", 2, 4, "TODO"},
+			{"lib/lib.go", 2, 12, "TODO"},
+			{"main.go", 3, 4, "TODO"},
+		}, search(ctx, t, dir, "TO+DO"))
+	})
+
+	t.Run("literal", func(ctx context.Context, t *testctx.T) {
+		require.Equal(t, []match{
+			{"main.go", 4, 1, "func main() {}"},
+		}, search(ctx, t, dir, "func main() {}", dagger.DirectorySearchOpts{Literal: true}))
+	})
+
+	t.Run("case insensitive", func(ctx context.Context, t *testctx.T) {
+		matches := search(ctx, t, dir, "todo", dagger.DirectorySearchOpts{
+			CaseInsensitive: true,
+			Paths:           []string{"main.go"},
+		})
+		require.Equal(t, []match{
+			{"main.go", 3, 4, "TODO"},
+			{"main.go", 4, 19, "todo"},
+		}, matches)
+	})
+
+	t.Run("globs", func(ctx context.Context, t *testctx.T) {
+		matches := search(ctx, t, dir, "TODO", dagger.DirectorySearchOpts{
+			Globs: []string{"**/*.md"},
+		})
+		require.Equal(t, []match{{"README.md", 1, 1, "TODO"}}, matches)
+	})
+
+	t.Run("paths", func(ctx context.Context, t *testctx.T) {
+		matches := search(ctx, t, dir, "TODO", dagger.DirectorySearchOpts{
+			Paths: []string{"lib"},
+		})
+		require.Len(t, matches, 2)
+
+		_, err := dir.Search(ctx, "TODO", dagger.DirectorySearchOpts{
+			Paths: []string{"nope"},
+		})
+		requireErrOut(t, err, `path "nope" not found`)
+	})
+
+	t.Run("overlapping paths", func(ctx context.Context, t *testctx.T) {
+		// each file is searched once, in path order, whatever the order of
+		// the paths
+		require.Equal(t, []match{
+			{"README.md", 1, 1, "TODO"},
+			{"lib/lib.go", 2, 4, "TODO"},
+			{"lib/lib.go", 2, 12, "TODO"},
+			{"main.go", 3, 4, "TODO"},
+		}, search(ctx, t, dir, "TODO", dagger.DirectorySearchOpts{
+			Paths: []string{"main.go", "lib/lib.go", ".", "lib"},
+		}))
+	})
+
+	t.Run("limit", func(ctx context.Context, t *testctx.T) {
+		matches := search(ctx, t, dir, "TODO", dagger.DirectorySearchOpts{Limit: 2})
+		require.Len(t, matches, 2)
+	})
+
+	t.Run("subdirectory", func(ctx context.Context, t *testctx.T) {
+		require.Equal(t, []match{
+			{"lib.go", 2, 4, "TODO"},
+			{"lib.go", 2, 12, "TODO"},
+		}, search(ctx, t, dir.Directory("lib"), "TODO"))
+	})
+
+	t.Run("scratch", func(ctx context.Context, t *testctx.T) {
+		require.Empty(t, search(ctx, t, c.Directory(), "TODO"))
+	})
+
+	t.Run("invalid pattern", func(ctx context.Context, t *testctx.T) {
+		_, err := dir.Search(ctx, "(")
+		requireErrOut(t, err, "invalid pattern")
+	})
+}
+
+func (DirectorySuite) TestExistsStat(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	dir := c.Directory().
+		WithNewFile("a.txt", "hello", dagger.DirectoryWithNewFileOpts{Permissions: 0o640}).
+		WithNewDirectory("sub").
+		WithSymlink("a.txt", "link").
+		WithSymlink("sub", "sub-link").
+		WithSymlink("missing", "dangling").
+		WithTimestamps(1234567890)
+
+	t.Run("exists", func(ctx context.Context, t *testctx.T) {
+		for _, tc := range []struct {
+			path         string
+			expectedType dagger.FileType
+			exists       bool
+		}{
+			{"a.txt", "", true},
+			{"a.txt", dagger.FileTypeFile, true},
+			{"a.txt", dagger.FileTypeDirectory, false},
+			{"sub", dagger.FileTypeDirectory, true},
+			{"sub-link", dagger.FileTypeDirectory, true},
+			{"sub-link", dagger.FileTypeSymlink, true},
+			{"link", dagger.FileTypeFile, true},
+			{"dangling", "", true},
+			{"dangling", dagger.FileTypeFile, false},
+			{"nope", "", false},
+			{"a.txt/nope", "", false},
+		} {
+			exists, err := dir.Exists(ctx, tc.path, dagger.DirectoryExistsOpts{ExpectedType: tc.expectedType})
+			require.NoError(t, err)
+			require.Equal(t, tc.exists, exists, "%s (%s)", tc.path, tc.expectedType)
+		}
+	})
+
+	t.Run("exists in subdirectory", func(ctx context.Context, t *testctx.T) {
+		exists, err := dir.Directory("sub").Exists(ctx, "a.txt")
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
+
+	t.Run("stat file", func(ctx context.Context, t *testctx.T) {
+		info := dir.Stat("a.txt")
+		name, err := info.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "a.txt", name)
+		typ, err := info.FileType(ctx)
+		require.NoError(t, err)
+		require.Equal(t, dagger.FileTypeFile, typ)
+		size, err := info.Size(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 5, size)
+		mode, err := info.Mode(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0o640, mode)
+		mtime, err := info.ModifiedTime(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1234567890, mtime)
+		uid, err := info.UID(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, uid)
+	})
+
+	t.Run("stat symlink", func(ctx context.Context, t *testctx.T) {
+		info := dir.Stat("link")
+		typ, err := info.FileType(ctx)
+		require.NoError(t, err)
+		require.Equal(t, dagger.FileTypeSymlink, typ)
+		target, err := info.SymlinkTarget(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "a.txt", target)
+	})
+
+	t.Run("stat missing", func(ctx context.Context, t *testctx.T) {
+		_, err := dir.Stat("nope").Name(ctx)
+		requireErrOut(t, err, "nope: file does not exist")
+	})
+}
+
+func (DirectorySuite) TestFilter(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	dir := c.Directory().
+		WithNewFile(".gitignore", "*.log\n/build/\n!keep.log\n").
+		WithNewFile(".dockerignore", "*.md\n").
+		WithNewFile("a.txt", "a").
+		WithNewFile("a.log", "a").
+		WithNewFile("keep.log", "keep").
+		WithNewFile("README.md", "readme").
+		WithNewFile("build/out", "out").
+		WithNewFile("sub/.gitignore", "local.txt\n!debug.log\n").
+		WithNewFile("sub/local.txt", "local").
+		WithNewFile("sub/debug.log", "debug").
+		WithNewFile("sub/build/out", "out")
+
+	glob := func(ctx context.Context, t *testctx.T, dir *dagger.Directory) []string {
+		entries, err := dir.Glob(ctx, "**/*")
+		require.NoError(t, err)
+		return entries
+	}
+
+	t.Run("gitignore", func(ctx context.Context, t *testctx.T) {
+		require.ElementsMatch(t, []string{
+			".dockerignore",
+			".gitignore",
+			"README.md",
+			"a.txt",
+			"keep.log",
+			"sub",
+			"sub/.gitignore",
+			"sub/build",
+			"sub/build/out",
+			"sub/debug.log",
+		}, glob(ctx, t, dir.Filter(dagger.DirectoryFilterOpts{Gitignore: true})))
+	})
+
+	t.Run("ignore files", func(ctx context.Context, t *testctx.T) {
+		entries, err := dir.Filter(dagger.DirectoryFilterOpts{
+			IgnoreFiles: []string{".dockerignore"},
+		}).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{".dockerignore", ".gitignore", "a.log", "a.txt", "build", "keep.log", "sub"}, entries)
+	})
+
+	t.Run("combined with patterns", func(ctx context.Context, t *testctx.T) {
+		entries, err := dir.Filter(dagger.DirectoryFilterOpts{
+			Gitignore:   true,
+			IgnoreFiles: []string{".dockerignore"},
+			Exclude:     []string{".*ignore"},
+		}).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a.txt", "keep.log", "sub"}, entries)
+	})
+
+	t.Run("subdirectory", func(ctx context.Context, t *testctx.T) {
+		entries, err := dir.Directory("sub").Filter(dagger.DirectoryFilterOpts{Gitignore: true}).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{".gitignore", "build", "debug.log"}, entries)
+	})
+}
+
+func (DirectorySuite) TestAsArchive(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	dir := c.Directory().
+		WithNewFile("README.md", "hello").
+		WithNewFile("bin/run.sh", "#!/bin/sh\necho hi\n", dagger.DirectoryWithNewFileOpts{Permissions: 0o755}).
+		WithNewDirectory("empty").
+		WithSymlink("bin/run.sh", "run")
+
+	for _, format := range []dagger.ArchiveFormat{
+		dagger.ArchiveFormatTar,
+		dagger.ArchiveFormatTarGz,
+		dagger.ArchiveFormatTarZstd,
+		dagger.ArchiveFormatZip,
+	} {
+		t.Run(string(format), func(ctx context.Context, t *testctx.T) {
+			archive := dir.AsArchive(dagger.DirectoryAsArchiveOpts{Format: format})
+
+			out, err := c.Container().From(alpineImage).
+				WithMountedDirectory("/mnt", archive.Extract()).
+				WithExec([]string{"sh", "-c", "cat /mnt/README.md; stat -c %a /mnt/bin/run.sh; readlink /mnt/run; ls -d /mnt/empty"}).
+				Stdout(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "hello755\nbin/run.sh\n/mnt/empty\n", out)
+		})
+	}
+
+	t.Run("reproducible", func(ctx context.Context, t *testctx.T) {
+		// the same contents with different timestamps must archive identically
+		a := c.Directory().WithNewFile("a.txt", "a").WithTimestamps(1)
+		b := c.Directory().WithNewFile("a.txt", "a").WithTimestamps(1000000)
+
+		digestA, err := a.AsArchive().Digest(ctx)
+		require.NoError(t, err)
+		digestB, err := b.AsArchive().Digest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, digestA, digestB)
+	})
+}
+
 func (DirectorySuite) TestEntries(ctx context.Context, t *testctx.T) {
 	var res struct {
 		Directory struct {
@@ -443,6 +443,24 @@ func (FileSuite) TestDigest(ctx context.Context, t *testctx.T) {
 	})
 }
 
+func (FileSuite) TestExtract(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	archive := c.Container().From(alpineImage).
+		WithExec([]string{"sh", "-c", "mkdir -p /src/sub && echo hi > /src/sub/hi.txt && tar -czf /out.tgz -C /src ."}).
+		File("/out.tgz")
+
+	contents, err := archive.Extract().File("sub/hi.txt").Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hi\n", contents)
+
+	t.Run("not an archive", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Directory().WithNewFile("foo", "not an archive").
+			File("foo").Extract().Sync(ctx)
+		requireErrOut(t, err, "not a valid tar or zip archive")
+	})
+}
+
 func (FileSuite) TestSync(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/moby/buildkit/identity"
 	"github.com/stretchr/testify/require"
@@ -166,6 +167,283 @@ func (GitSuite) TestKeepGitDir(ctx context.Context, t *testctx.T) {
 	})
 }
 
+func (GitSuite) TestTreeCheckoutOptions(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	const commit = "c80ac2c13df7d573a069938e01ca13f7a81f0345"
+	repo := c.Git("https://github.com/dagger/dagger", dagger.GitOpts{KeepGitDir: true})
+
+	gitOut := func(dir *dagger.Directory, args ...string) *dagger.Container {
+		return c.Container().
+			From(alpineImage).
+			WithExec([]string{"apk", "add", "git"}).
+			WithMountedDirectory("/src", dir).
+			WithWorkdir("/src").
+			WithExec(append([]string{"git", "-c", "safe.directory=*"}, args...))
+	}
+
+	t.Run("depth", func(ctx context.Context, t *testctx.T) {
+		out, err := gitOut(repo.Commit(commit).Tree(dagger.GitRefTreeOpts{Depth: 3}), "rev-list", "--count", "HEAD").Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "3", strings.TrimSpace(out))
+
+		out, err = gitOut(repo.Commit(commit).Tree(), "rev-list", "--count", "HEAD").Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "1", strings.TrimSpace(out))
+	})
+
+	t.Run("repository defaults", func(ctx context.Context, t *testctx.T) {
+		repo := c.Git("https://github.com/dagger/dagger", dagger.GitOpts{KeepGitDir: true, Depth: 3})
+
+		out, err := gitOut(repo.Commit(commit).Tree(), "rev-list", "--count", "HEAD").Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "3", strings.TrimSpace(out))
+
+		out, err = gitOut(repo.Commit(commit).Tree(dagger.GitRefTreeOpts{Depth: 2}), "rev-list", "--count", "HEAD").Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "2", strings.TrimSpace(out))
+	})
+
+	t.Run("sparse paths", func(ctx context.Context, t *testctx.T) {
+		dir := repo.Commit(commit).Tree(dagger.GitRefTreeOpts{
+			DiscardGitDir: true,
+			SparsePaths:   []string{"core/schema", "README.md"},
+		})
+		ent, err := dir.Entries(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"README.md", "core"}, ent)
+		ent, err = dir.Entries(ctx, dagger.DirectoryEntriesOpts{Path: "core"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"schema"}, ent)
+
+		dir = repo.Commit(commit).Tree(dagger.GitRefTreeOpts{
+			SparsePaths: []string{"core/schema"},
+		})
+		ent, err = dir.Entries(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{".git", "core"}, ent)
+	})
+
+	t.Run("filter", func(ctx context.Context, t *testctx.T) {
+		dir := repo.Commit(commit).Tree(dagger.GitRefTreeOpts{Filter: "blob:none"})
+		readme, err := dir.File("README.md").Contents(ctx)
+		require.NoError(t, err)
+		require.Contains(t, readme, "Dagger")
+
+		out, err := gitOut(dir, "config", "remote.origin.promisor").Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "true", strings.TrimSpace(out))
+	})
+
+	t.Run("invalid options", func(ctx context.Context, t *testctx.T) {
+		_, err := repo.Commit(commit).Tree(dagger.GitRefTreeOpts{Depth: -2}).Sync(ctx)
+		requireErrOut(t, err, "invalid depth")
+
+		_, err = repo.Commit(commit).Tree(dagger.GitRefTreeOpts{Filter: "--upload-pack=x"}).Sync(ctx)
+		requireErrOut(t, err, "invalid filter")
+
+		_, err = repo.Commit(commit).Tree(dagger.GitRefTreeOpts{SparsePaths: []string{"../etc"}}).Sync(ctx)
+		requireErrOut(t, err, "invalid sparse path")
+
+		_, err = c.Git("https://github.com/dagger/dagger", dagger.GitOpts{Filter: "tree"}).Commit(commit).Tree().Sync(ctx)
+		requireErrOut(t, err, "invalid filter")
+	})
+}
+
+func (GitSuite) TestHistory(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	const gitPort = 9418
+	srv := c.Container().
+		From(alpineImage).
+		WithExec([]string{"apk", "add", "git", "git-daemon"}).
+		WithEnvVariable("GIT_AUTHOR_NAME", "Test Author").
+		WithEnvVariable("GIT_AUTHOR_EMAIL", "author@localhost").
+		WithEnvVariable("GIT_COMMITTER_NAME", "Test Committer").
+		WithEnvVariable("GIT_COMMITTER_EMAIL", "committer@localhost").
+		WithWorkdir("/root/repo").
+		WithExec([]string{"sh", "-c", `set -e
+git init -b main
+echo 1 > a && echo 1 > b
+git add . && GIT_AUTHOR_DATE=@1000000000 GIT_COMMITTER_DATE=@1000000001 git commit -m "first"
+git tag -a v1.0.0 -m "release"
+echo 2 > a && mkdir c && echo 2 > c/d
+git add . && git commit -m "second" -m "with a body"
+git rm -q b && git commit -m "third"
+git clone --bare . /root/srv/repo.git
+`}).
+		WithExposedPort(gitPort).
+		WithDefaultArgs([]string{"git", "daemon", "--verbose", "--export-all", "--base-path=/root/srv"}).
+		AsService()
+	host, err := srv.Hostname(ctx)
+	require.NoError(t, err)
+	repo := c.Git(fmt.Sprintf("git://%s/repo.git", host), dagger.GitOpts{ExperimentalServiceHost: srv})
+
+	t.Run("log", func(ctx context.Context, t *testctx.T) {
+		log, err := repo.Branch("main").Log(ctx)
+		require.NoError(t, err)
+		require.Len(t, log, 3)
+
+		var messages []string
+		for _, commit := range log {
+			msg, err := commit.Message(ctx)
+			require.NoError(t, err)
+			messages = append(messages, msg)
+		}
+		require.Equal(t, []string{"third", "second\n\nwith a body", "first"}, messages)
+
+		first := log[2]
+		sha, err := first.Sha(ctx)
+		require.NoError(t, err)
+		commit, err := repo.Tag("v1.0.0").Commit(ctx)
+		require.NoError(t, err)
+		require.Equal(t, commit, sha)
+		parents, err := first.Parents(ctx)
+		require.NoError(t, err)
+		require.Empty(t, parents)
+		name, err := first.AuthorName(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "Test Author", name)
+		email, err := first.CommitterEmail(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "committer@localhost", email)
+		authorTime, err := first.AuthorTime(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1000000000, authorTime)
+		commitTime, err := first.CommitTime(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1000000001, commitTime)
+
+		parents, err = log[0].Parents(ctx)
+		require.NoError(t, err)
+		secondSHA, err := log[1].Sha(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{secondSHA}, parents)
+
+		log, err = repo.Branch("main").Log(ctx, dagger.GitRefLogOpts{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, log, 1)
+	})
+
+	t.Run("describe", func(ctx context.Context, t *testctx.T) {
+		desc, err := repo.Tag("v1.0.0").Describe(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "v1.0.0", desc)
+
+		desc, err = repo.Branch("main").Describe(ctx)
+		require.NoError(t, err)
+		require.Regexp(t, `^v1\.0\.0-2-g[0-9a-f]+$`, desc)
+	})
+
+	t.Run("diff", func(ctx context.Context, t *testctx.T) {
+		paths, err := repo.Diff(ctx, "v1.0.0", "main")
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c/d"}, paths)
+
+		paths, err = repo.Diff(ctx, "main", "main")
+		require.NoError(t, err)
+		require.Empty(t, paths)
+	})
+}
+
+func (GitSuite) TestWithChangesPush(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	const gitPort = 9418
+	srv := c.Container().
+		From(alpineImage).
+		WithExec([]string{"apk", "add", "git", "git-daemon"}).
+		WithEnvVariable("GIT_AUTHOR_NAME", "Test Author").
+		WithEnvVariable("GIT_AUTHOR_EMAIL", "author@localhost").
+		WithEnvVariable("GIT_COMMITTER_NAME", "Test Committer").
+		WithEnvVariable("GIT_COMMITTER_EMAIL", "committer@localhost").
+		WithWorkdir("/root/repo").
+		WithExec([]string{"sh", "-c", `set -e
+git init -b main
+echo 1 > a
+git add . && git commit -m "first"
+git clone --bare . /root/srv/repo.git
+`}).
+		WithExposedPort(gitPort).
+		WithDefaultArgs([]string{"git", "daemon", "--verbose", "--export-all", "--enable=receive-pack", "--base-path=/root/srv"}).
+		AsService()
+	host, err := srv.Hostname(ctx)
+	require.NoError(t, err)
+	repo := c.Git(fmt.Sprintf("git://%s/repo.git", host), dagger.GitOpts{ExperimentalServiceHost: srv})
+
+	main := repo.Branch("main")
+	mainSHA, err := main.Commit(ctx)
+	require.NoError(t, err)
+
+	changes := main.Tree(dagger.GitRefTreeOpts{DiscardGitDir: true}).
+		WithNewFile("b", "2").
+		WithoutFile("a")
+	ref := main.WithChanges(changes, "second", "Jane Doe <jane@example.com>")
+
+	t.Run("commit", func(ctx context.Context, t *testctx.T) {
+		log, err := ref.Log(ctx)
+		require.NoError(t, err)
+		require.Len(t, log, 2)
+		msg, err := log[0].Message(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "second", msg)
+		name, err := log[0].AuthorName(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "Jane Doe", name)
+		email, err := log[0].CommitterEmail(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "jane@example.com", email)
+		parents, err := log[0].Parents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{mainSHA}, parents)
+		// dated when it was made, not like its parent
+		commitTime, err := log[0].CommitTime(ctx)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now(), time.Unix(int64(commitTime), 0), 10*time.Minute)
+
+		entries, err := ref.Tree().Entries(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{".git", "b"}, entries)
+	})
+
+	t.Run("push", func(ctx context.Context, t *testctx.T) {
+		sha, err := repo.Push(ctx, ref, "feature")
+		require.NoError(t, err)
+		commit, err := ref.Commit(ctx)
+		require.NoError(t, err)
+		require.Equal(t, commit, sha)
+
+		pushed := repo.Branch("feature")
+		commit, err = pushed.Commit(ctx)
+		require.NoError(t, err)
+		require.Equal(t, sha, commit)
+		contents, err := pushed.Tree().File("b").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "2", contents)
+	})
+
+	t.Run("force", func(ctx context.Context, t *testctx.T) {
+		other := main.WithChanges(main.Tree(dagger.GitRefTreeOpts{DiscardGitDir: true}).WithNewFile("c", "3"),
+			"other", "Jane Doe <jane@example.com>")
+		_, err := repo.Push(ctx, other, "main")
+		require.NoError(t, err)
+
+		_, err = repo.Push(ctx, ref, "main")
+		requireErrOut(t, err, "non-fast-forward")
+
+		sha, err := repo.Push(ctx, ref, "refs/heads/main", dagger.GitRepositoryPushOpts{Force: true})
+		require.NoError(t, err)
+		commit, err := ref.Commit(ctx)
+		require.NoError(t, err)
+		require.Equal(t, commit, sha)
+	})
+
+	t.Run("invalid author", func(ctx context.Context, t *testctx.T) {
+		_, err := main.WithChanges(changes, "second", "Jane Doe").Commit(ctx)
+		requireErrOut(t, err, "invalid author")
+	})
+}
+
 func (GitSuite) TestSSHAuthSock(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 
@@ -298,6 +298,53 @@ func (HostSuite) TestDirectoryExcludeInclude(ctx context.Context, t *testctx.T)
 	})
 }
 
+func (HostSuite) TestDirectoryIgnoreFiles(ctx context.Context, t *testctx.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\nbuild/\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("*.md\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("1"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.log"), []byte("2"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("3"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "build"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "build", "out"), []byte("4"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "subdir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "subdir", ".gitignore"), []byte("local.txt\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "subdir", "local.txt"), []byte("5"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "subdir", "b.txt"), []byte("6"), 0o600))
+
+	c := connect(ctx, t)
+
+	t.Run("gitignore", func(ctx context.Context, t *testctx.T) {
+		entries, err := c.Host().Directory(dir, dagger.HostDirectoryOpts{
+			Gitignore: true,
+		}).Glob(ctx, "**/*")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			".dockerignore",
+			".gitignore",
+			"README.md",
+			"a.txt",
+			"subdir",
+			"subdir/.gitignore",
+			"subdir/b.txt",
+		}, entries)
+	})
+
+	t.Run("ignore files", func(ctx context.Context, t *testctx.T) {
+		entries, err := c.Host().Directory(dir, dagger.HostDirectoryOpts{
+			IgnoreFiles: []string{".dockerignore"},
+		}).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{".dockerignore", ".gitignore", "a.log", "a.txt", "build", "subdir"}, entries)
+	})
+
+	t.Run("unfiltered sync is unaffected", func(ctx context.Context, t *testctx.T) {
+		entries, err := c.Host().Directory(dir).Entries(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{".dockerignore", ".gitignore", "README.md", "a.log", "a.txt", "build", "subdir"}, entries)
+	})
+}
+
 func (HostSuite) TestFile(ctx context.Context, t *testctx.T) {
 	dir := t.TempDir()
 	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("1"), 0o600))
@@ -320,3 +367,168 @@ func (HostSuite) TestFile(ctx context.Context, t *testctx.T) {
 		require.Equal(t, "hello world", content)
 	})
 }
+
+func (HostSuite) TestGitRepository(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	dir := t.TempDir()
+	_, err := c.Container().
+		From(alpineImage).
+		WithExec([]string{"apk", "add", "git"}).
+		WithEnvVariable("GIT_AUTHOR_NAME", "Test User").
+		WithEnvVariable("GIT_AUTHOR_EMAIL", "root@localhost").
+		WithEnvVariable("GIT_COMMITTER_NAME", "Test User").
+		WithEnvVariable("GIT_COMMITTER_EMAIL", "root@localhost").
+		WithWorkdir("/repo").
+		WithExec([]string{"sh", "-c", `set -e
+git init -b main
+echo "*.log" > .gitignore
+echo 1 > a.txt
+git add . && git commit -m "first"
+git tag v1.0.0
+echo 2 > a.txt && echo 2 > b.txt
+git add . && git commit -m "second"
+git checkout -b feature
+echo 3 > c.txt
+git add . && git commit -m "unpushed"
+`}).
+		Directory("/repo").
+		Export(ctx, dir)
+	require.NoError(t, err)
+
+	repo := c.Host().GitRepository(dir)
+
+	t.Run("head", func(ctx context.Context, t *testctx.T) {
+		content, err := repo.Head().Tree().File("c.txt").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "3\n", content)
+
+		log, err := repo.Head().Log(ctx)
+		require.NoError(t, err)
+		require.Len(t, log, 3)
+		msg, err := log[0].Message(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "unpushed", msg)
+	})
+
+	t.Run("refs", func(ctx context.Context, t *testctx.T) {
+		tree := repo.Tag("v1.0.0").Tree(dagger.GitRefTreeOpts{DiscardGitDir: true})
+		entries, err := tree.Entries(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{".gitignore", "a.txt"}, entries)
+		content, err := tree.File("a.txt").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "1\n", content)
+
+		mainCommit, err := repo.Branch("main").Commit(ctx)
+		require.NoError(t, err)
+		out, err := c.Container().
+			From(alpineImage).
+			WithExec([]string{"apk", "add", "git"}).
+			WithMountedDirectory("/src", repo.Branch("main").Tree()).
+			WithWorkdir("/src").
+			WithExec([]string{"sh", "-c", "git rev-parse HEAD && git status --porcelain"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, mainCommit+"\n", out)
+
+		tags, err := repo.Tags(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"v1.0.0"}, tags)
+
+		desc, err := repo.Head().Describe(ctx)
+		require.NoError(t, err)
+		require.Regexp(t, `^v1\.0\.0-2-g[0-9a-f]+$`, desc)
+
+		paths, err := repo.Diff(ctx, "v1.0.0", "feature")
+		require.NoError(t, err)
+		require.Equal(t, []string{"a.txt", "b.txt", "c.txt"}, paths)
+
+		_, err = repo.Branch("nope").Commit(ctx)
+		requireErrOut(t, err, `unknown ref "nope"`)
+	})
+
+	t.Run("uncommitted changes", func(ctx context.Context, t *testctx.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("ignored\n"), 0o600))
+
+		paths, err := repo.UncommittedChanges(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"a.txt", "new.txt"}, paths)
+	})
+
+	t.Run("not a repository", func(ctx context.Context, t *testctx.T) {
+		notRepo := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(notRepo, ".git"), 0o755))
+		_, err := c.Host().GitRepository(notRepo).Head().Commit(ctx)
+		requireErrOut(t, err, "is not a git repository")
+	})
+
+	t.Run("remote", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Git("https://github.com/dagger/dagger").UncommittedChanges(ctx)
+		requireErrOut(t, err, "only available for local repositories")
+	})
+}
+
+func (HostSuite) TestGitRepositoryGitFile(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// a linked worktree and a repository with a separate git directory, like
+	// a submodule, both have a .git file instead of a directory; their paths
+	// are made relative so that they still resolve once exported
+	dir := t.TempDir()
+	_, err := c.Container().
+		From(alpineImage).
+		WithExec([]string{"apk", "add", "git"}).
+		WithEnvVariable("GIT_AUTHOR_NAME", "Test User").
+		WithEnvVariable("GIT_AUTHOR_EMAIL", "root@localhost").
+		WithEnvVariable("GIT_COMMITTER_NAME", "Test User").
+		WithEnvVariable("GIT_COMMITTER_EMAIL", "root@localhost").
+		WithWorkdir("/work").
+		WithExec([]string{"sh", "-c", `set -e
+git init -b main repo
+cd repo
+echo 1 > a.txt
+git add . && git commit -m "first"
+git worktree add -b feature ../worktree
+cd ../worktree
+echo 2 > b.txt
+git add . && git commit -m "second"
+echo "gitdir: ../repo/.git/worktrees/worktree" > .git
+cd ..
+git init -b main --separate-git-dir /work/separate.git separate
+cd separate
+echo 3 > c.txt
+git add . && git commit -m "separate"
+echo "gitdir: ../separate.git" > .git
+`}).
+		Directory("/work").
+		Export(ctx, dir)
+	require.NoError(t, err)
+
+	t.Run("worktree", func(ctx context.Context, t *testctx.T) {
+		repo := c.Host().GitRepository(filepath.Join(dir, "worktree"))
+		content, err := repo.Head().Tree().File("b.txt").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "2\n", content)
+
+		log, err := repo.Branch("main").Log(ctx)
+		require.NoError(t, err)
+		require.Len(t, log, 1)
+	})
+
+	t.Run("separate git directory", func(ctx context.Context, t *testctx.T) {
+		repo := c.Host().GitRepository(filepath.Join(dir, "separate"))
+		content, err := repo.Head().Tree().File("c.txt").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "3\n", content)
+	})
+
+	t.Run("invalid git file", func(ctx context.Context, t *testctx.T) {
+		notRepo := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(notRepo, ".git"), []byte("nope\n"), 0o600))
+		_, err := c.Host().GitRepository(notRepo).Head().Commit(ctx)
+		requireErrOut(t, err, "is not a git repository")
+	})
+}
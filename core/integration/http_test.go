@@ -6,6 +6,7 @@ import (
 
 	"github.com/dagger/testctx"
 	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/require"
 
 	"dagger.io/dagger"
@@ -65,3 +66,111 @@ func (HTTPSuite) TestHTTPServiceStableDigest(ctx context.Context, t *testctx.T)
 	c2 := connect(ctx, t)
 	require.Equal(t, hostname(c1), hostname(c2))
 }
+
+func (HTTPSuite) TestHTTPChecksum(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	content := identity.NewID()
+	svc, url := httpService(ctx, t, c, content)
+
+	t.Run("matching", func(ctx context.Context, t *testctx.T) {
+		contents, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                digest.FromString(content).String(),
+		}).Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, content, contents)
+	})
+
+	t.Run("mismatch", func(ctx context.Context, t *testctx.T) {
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                digest.FromString("nope").String(),
+		}).Contents(ctx)
+		requireErrOut(t, err, "checksum mismatch")
+	})
+
+	t.Run("invalid", func(ctx context.Context, t *testctx.T) {
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Checksum:                "md5:abc",
+		}).Contents(ctx)
+		requireErrOut(t, err, "invalid checksum")
+	})
+}
+
+func (HTTPSuite) TestHTTPHeaders(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// echoes back the headers we care about, or 401 without auth
+	script := `import http.server
+
+class Handler(http.server.BaseHTTPRequestHandler):
+    def do_GET(self):
+        if self.headers.get("Authorization") != "Bearer hunter2":
+            self.send_response(401)
+            self.end_headers()
+            return
+        body = self.headers.get("X-Custom", "").encode()
+        self.send_response(200)
+        self.end_headers()
+        self.wfile.write(body)
+
+http.server.HTTPServer(("", 8000), Handler).serve_forever()
+`
+	svc := c.Container().
+		From("python").
+		WithNewFile("/srv.py", script).
+		WithExposedPort(8000).
+		WithDefaultArgs([]string{"python", "/srv.py"}).
+		AsService()
+	url, err := svc.Endpoint(ctx, dagger.ServiceEndpointOpts{Scheme: "http"})
+	require.NoError(t, err)
+	url += "/" + identity.NewID()
+
+	token := c.SetSecret("token", "Bearer hunter2")
+
+	file := c.HTTP(url, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		AuthHeader:              token,
+		Headers:                 []dagger.HTTPHeader{{Name: "X-Custom", Value: "hello"}},
+		Name:                    "greeting.txt",
+		Permissions:             0o755,
+	})
+
+	contents, err := file.Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello", contents)
+
+	name, err := file.Name(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "greeting.txt", name)
+
+	out, err := c.Container().From(alpineImage).
+		WithMountedFile("/mnt/greeting.txt", file).
+		WithExec([]string{"stat", "-c", "%a", "/mnt/greeting.txt"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "755\n", out)
+
+	t.Run("secret not in ID", func(ctx context.Context, t *testctx.T) {
+		id, err := file.ID(ctx)
+		require.NoError(t, err)
+		require.NotContains(t, string(id), "hunter2")
+	})
+
+	t.Run("unauthorized", func(ctx context.Context, t *testctx.T) {
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+		}).Contents(ctx)
+		requireErrOut(t, err, "invalid response status 401")
+	})
+
+	t.Run("invalid name", func(ctx context.Context, t *testctx.T) {
+		_, err := c.HTTP(url, dagger.HTTPOpts{
+			ExperimentalServiceHost: svc,
+			Name:                    "a/b",
+		}).Sync(ctx)
+		requireErrOut(t, err, "must be a file name")
+	})
+}
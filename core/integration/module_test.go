@@ -1706,6 +1706,584 @@ export class Test {
 	}
 }
 
+func (ModuleSuite) TestFunctionCachePolicy(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	ctr := modInit(t, c, "go", `package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type Test struct{}
+
+func (m *Test) Cached() string {
+	return random()
+}
+
+// +cache="never"
+func (m *Test) Never() string {
+	return random()
+}
+
+// Returns a random string, cached for an hour
+// +cache="1h"
+func (m *Test) TTL() string {
+	return random()
+}
+
+func random() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+`)
+
+	query := func(q string) gjson.Result {
+		out, err := ctr.
+			WithEnvVariable("CACHEBUSTER", identity.NewID()).
+			With(daggerQuery(q)).
+			Stdout(ctx)
+		require.NoError(t, err)
+		return gjson.Parse(out)
+	}
+
+	t.Run("never", func(ctx context.Context, t *testctx.T) {
+		res := query(`{test{a:never b:never}}`)
+		require.NotEmpty(t, res.Get("test.a").String())
+		require.NotEqual(t, res.Get("test.a").String(), res.Get("test.b").String())
+	})
+
+	t.Run("default", func(ctx context.Context, t *testctx.T) {
+		res := query(`{test{a:cached b:cached}}`)
+		require.Equal(t, res.Get("test.a").String(), res.Get("test.b").String())
+		require.NotEqual(t, res.Get("test.a").String(), query(`{test{cached}}`).Get("test.cached").String())
+	})
+
+	t.Run("ttl", func(ctx context.Context, t *testctx.T) {
+		first := query(`{test{ttl}}`).Get("test.ttl").String()
+		require.NotEmpty(t, first)
+		require.Equal(t, first, query(`{test{ttl}}`).Get("test.ttl").String())
+	})
+
+	t.Run("typedefs", func(ctx context.Context, t *testctx.T) {
+		res := query(`{host{directory(path:"."){asModule{objects{asObject{functions{name cachePolicy cacheTTLSeconds description}}}}}}}`)
+		fns := res.Get("host.directory.asModule.objects.0.asObject.functions").Array()
+		require.Len(t, fns, 3)
+		policies := map[string]string{}
+		for _, fn := range fns {
+			policies[fn.Get("name").String()] = fn.Get("cachePolicy").String()
+			if fn.Get("name").String() == "ttl" {
+				require.EqualValues(t, 3600, fn.Get("cacheTTLSeconds").Int())
+				require.Equal(t, "Returns a random string, cached for an hour", fn.Get("description").String())
+			}
+		}
+		require.Equal(t, map[string]string{
+			"cached": "DEFAULT",
+			"never":  "NEVER",
+			"ttl":    "TTL",
+		}, policies)
+	})
+
+	t.Run("functions", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerFunctions()).Stdout(ctx)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Regexp(t, `^Name\s+Cache\s+Description$`, lines[0])
+		require.Regexp(t, `^cached\s+-\s+-$`, lines[1])
+		require.Regexp(t, `^never\s+never\s+-$`, lines[2])
+		require.Regexp(t, `^ttl\s+ttl 1h0m0s\s+Returns a random string, cached for an hour$`, lines[3])
+	})
+}
+
+func (ModuleSuite) TestFunctionDeprecation(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	ctr := modInit(t, c, "go", `package main
+
+type Test struct {
+	// +deprecated="Use Name instead."
+	Label string
+}
+
+type Mode string
+
+const (
+	Fast Mode = "FAST"
+	// +deprecated="Use FAST instead."
+	Slow Mode = "SLOW"
+)
+
+// Builds something
+//
+// +deprecated="Use Build2 instead."
+func (m *Test) Build() string {
+	return "build"
+}
+
+func (m *Test) Build2(
+	// +optional
+	// +deprecated="The tag is computed automatically."
+	tag string,
+	// +default="FAST"
+	mode Mode,
+) string {
+	return "build2:" + tag + ":" + string(mode)
+}
+`)
+
+	t.Run("typedefs", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerQuery(`{host{directory(path:"."){asModule{objects{asObject{name functions{name deprecated args{name deprecated}} fields{name deprecated}}} enums{asEnum{values{name deprecated}}}}}}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		mod := gjson.Get(out, "host.directory.asModule")
+
+		obj := mod.Get(`objects.#(asObject.name="Test").asObject`)
+		require.Equal(t, "Use Build2 instead.", obj.Get(`functions.#(name="build").deprecated`).String())
+		build2 := obj.Get(`functions.#(name="build2")`)
+		require.Equal(t, gjson.Null, build2.Get("deprecated").Type)
+		require.Equal(t, "The tag is computed automatically.", build2.Get(`args.#(name="tag").deprecated`).String())
+		require.Equal(t, gjson.Null, build2.Get(`args.#(name="mode").deprecated`).Type)
+		require.Equal(t, "Use Name instead.", obj.Get(`fields.#(name="label").deprecated`).String())
+
+		values := mod.Get("enums.0.asEnum.values")
+		require.Equal(t, gjson.Null, values.Get(`#(name="FAST").deprecated`).Type)
+		require.Equal(t, "Use FAST instead.", values.Get(`#(name="SLOW").deprecated`).String())
+	})
+
+	t.Run("schema", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerQuery(`{__type(name:"Test"){fields(includeDeprecated:true){name isDeprecated deprecationReason}}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		fields := gjson.Get(out, "__type.fields")
+		require.True(t, fields.Get(`#(name="build").isDeprecated`).Bool())
+		require.Equal(t, "Use Build2 instead.", fields.Get(`#(name="build").deprecationReason`).String())
+		require.False(t, fields.Get(`#(name="build2").isDeprecated`).Bool())
+	})
+
+	t.Run("still callable", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerQuery(`{test{build build2(tag:"v1",mode:SLOW)}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"test":{"build":"build","build2":"build2:v1:SLOW"}}`, out)
+	})
+
+	t.Run("functions", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerFunctions()).Stdout(ctx)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Regexp(t, `^Name\s+Deprecated\s+Description$`, lines[0])
+		require.Regexp(t, `^build\s+Use Build2 instead.\s+Builds something$`, lines[1])
+		require.Regexp(t, `^build2\s+-\s+-$`, lines[2])
+		require.Regexp(t, `^label\s+Use Name instead.\s+-$`, lines[3])
+	})
+
+	t.Run("call help", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerCall("build2", "--help")).Stdout(ctx)
+		require.NoError(t, err)
+		require.Contains(t, out, "(deprecated: The tag is computed automatically.)")
+	})
+
+	t.Run("required argument", func(ctx context.Context, t *testctx.T) {
+		_, err := modInit(t, c, "go", `package main
+
+type Test struct{}
+
+func (m *Test) Build(
+	// +deprecated="The tag is computed automatically."
+	tag string,
+) string {
+	return "build:" + tag
+}
+`).
+			With(daggerFunctions()).
+			Sync(ctx)
+		requireErrOut(t, err, `argument "tag" is required, so it can't be deprecated`)
+	})
+}
+
+func (ModuleSuite) TestMapAndUnionTypes(ctx context.Context, t *testctx.T) {
+	type testCase struct {
+		sdk    string
+		source string
+	}
+	for _, tc := range []testCase{
+		{
+			sdk: "go",
+			source: `package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"dagger/test/internal/dagger"
+)
+
+// Where to read files from
+//
+// +union
+type Source struct {
+	// A directory
+	Directory *dagger.Directory
+	GitURL    *string
+}
+
+type Test struct{}
+
+func (m *Test) Labels(labels map[string]string) string {
+	var kvs []string
+	for k, v := range labels {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+	return strings.Join(kvs, ",")
+}
+
+func (m *Test) Counts(words []string) map[string]int {
+	counts := map[string]int{}
+	for _, w := range words {
+		counts[w]++
+	}
+	return counts
+}
+
+func (m *Test) Read(ctx context.Context, source Source) (string, error) {
+	if source.Directory != nil {
+		return source.Directory.File("foo.txt").Contents(ctx)
+	}
+	return "git:" + *source.GitURL, nil
+}
+`,
+		},
+		{
+			sdk: "typescript",
+			source: `import { Directory, object, func } from "@dagger.io/dagger"
+
+/**
+ * Where to read files from
+ */
+type Source =
+  | {
+      /**
+       * A directory
+       */
+      directory: Directory
+    }
+  | { gitUrl: string }
+
+@object()
+export class Test {
+  @func()
+  labels(labels: Record<string, string>): string {
+    return Object.entries(labels)
+      .map(([k, v]) => k + "=" + v)
+      .sort()
+      .join(",")
+  }
+
+  @func()
+  counts(words: string[]): Record<string, number> {
+    const counts: Record<string, number> = {}
+    for (const w of words) {
+      counts[w] = (counts[w] ?? 0) + 1
+    }
+    return counts
+  }
+
+  @func()
+  async read(source: Source): Promise<string> {
+    if ("directory" in source) {
+      return source.directory.file("foo.txt").contents()
+    }
+    return "git:" + source.gitUrl
+  }
+}
+`,
+		},
+	} {
+		t.Run(tc.sdk, func(ctx context.Context, t *testctx.T) {
+			c := connect(ctx, t)
+
+			ctr := modInit(t, c, tc.sdk, tc.source).
+				WithNewFile("/work/src/foo.txt", "hello")
+
+			t.Run("typedefs", func(ctx context.Context, t *testctx.T) {
+				out, err := ctr.With(daggerQuery(`{host{directory(path:"."){asModule{objects{asObject{name functions{name returnType{kind asMap{valueTypeDef{kind}}} args{name typeDef{kind asMap{valueTypeDef{kind}} asUnion{name description members{name description typeDef{kind asObject{name}}}}}}}}}}}}}`)).Stdout(ctx)
+				require.NoError(t, err)
+				obj := gjson.Get(out, `host.directory.asModule.objects.#(asObject.name="Test").asObject`)
+
+				labels := obj.Get(`functions.#(name="labels").args.0.typeDef`)
+				require.Equal(t, "MAP_KIND", labels.Get("kind").String())
+				require.Equal(t, "STRING_KIND", labels.Get("asMap.valueTypeDef.kind").String())
+
+				counts := obj.Get(`functions.#(name="counts").returnType`)
+				require.Equal(t, "MAP_KIND", counts.Get("kind").String())
+				require.Equal(t, "INTEGER_KIND", counts.Get("asMap.valueTypeDef.kind").String())
+
+				source := obj.Get(`functions.#(name="read").args.0.typeDef`)
+				require.Equal(t, "UNION_KIND", source.Get("kind").String())
+				require.Equal(t, "Source", source.Get("asUnion.name").String())
+				require.Equal(t, "Where to read files from", source.Get("asUnion.description").String())
+				members := source.Get("asUnion.members")
+				require.Equal(t, "Directory", members.Get(`#(name="directory").typeDef.asObject.name`).String())
+				require.Equal(t, "A directory", members.Get(`#(name="directory").description`).String())
+				require.Equal(t, "STRING_KIND", members.Get(`#(name="gitUrl").typeDef.kind`).String())
+			})
+
+			t.Run("query", func(ctx context.Context, t *testctx.T) {
+				out, err := ctr.With(daggerQuery(`{test{labels(labels:{b:"2",a:"1"}) counts(words:["a","b","a"]) read(source:{gitUrl:"https://example.com/repo"})}}`)).Stdout(ctx)
+				require.NoError(t, err)
+				require.Equal(t, "a=1,b=2", gjson.Get(out, "test.labels").String())
+				require.JSONEq(t, `{"a":2,"b":1}`, gjson.Get(out, "test.counts").String())
+				require.Equal(t, "git:https://example.com/repo", gjson.Get(out, "test.read").String())
+			})
+
+			t.Run("invalid union", func(ctx context.Context, t *testctx.T) {
+				_, err := ctr.With(daggerQuery(`{test{read(source:{gitUrl:"a",unknown:"b"})}}`)).Sync(ctx)
+				requireErrOut(t, err, `union "Source" has no member "unknown"`)
+			})
+
+			t.Run("call map flag", func(ctx context.Context, t *testctx.T) {
+				out, err := ctr.With(daggerCall("labels", "--labels", "b=2,a=1", "--labels", "c=3")).Stdout(ctx)
+				require.NoError(t, err)
+				require.Equal(t, "a=1,b=2,c=3", strings.TrimSpace(out))
+			})
+
+			t.Run("call map result", func(ctx context.Context, t *testctx.T) {
+				out, err := ctr.With(daggerCall("counts", "--words", "a,b,a")).Stdout(ctx)
+				require.NoError(t, err)
+				require.JSONEq(t, `{"a":2,"b":1}`, out)
+			})
+
+			t.Run("call union flag", func(ctx context.Context, t *testctx.T) {
+				out, err := ctr.With(daggerCall("read", "--source", "directory=./src")).Stdout(ctx)
+				require.NoError(t, err)
+				require.Equal(t, "hello", strings.TrimSpace(out))
+
+				out, err = ctr.With(daggerCall("read", "--source", "git-url=https://example.com/repo")).Stdout(ctx)
+				require.NoError(t, err)
+				require.Equal(t, "git:https://example.com/repo", strings.TrimSpace(out))
+			})
+		})
+	}
+}
+
+func (ModuleSuite) TestFunctionArgConstraints(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	ctr := modInit(t, c, "go", `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Test struct{}
+
+func (m *Test) Release(
+	// +pattern="^v\d+\.\d+\.\d+$"
+	version string,
+	// +min=1
+	// +max=64
+	// +default=1
+	replicas int,
+	// +minLength=1
+	// +allowedValues=["linux/amd64","linux/arm64"]
+	platforms []string,
+) string {
+	return fmt.Sprintf("%s:%d:%s", version, replicas, strings.Join(platforms, ","))
+}
+`)
+
+	t.Run("typedefs", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerQuery(`{host{directory(path:"."){asModule{objects{asObject{name functions{name args{name pattern min max minLength allowedValues}}}}}}}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		args := gjson.Get(out, `host.directory.asModule.objects.#(asObject.name="Test").asObject.functions.#(name="release").args`)
+
+		version := args.Get(`#(name="version")`)
+		require.Equal(t, `^v\d+\.\d+\.\d+$`, version.Get("pattern").String())
+		require.Equal(t, gjson.Null, version.Get("min").Type)
+
+		replicas := args.Get(`#(name="replicas")`)
+		require.Equal(t, 1.0, replicas.Get("min").Float())
+		require.Equal(t, 64.0, replicas.Get("max").Float())
+
+		platforms := args.Get(`#(name="platforms")`)
+		require.Equal(t, int64(1), platforms.Get("minLength").Int())
+		require.JSONEq(t, `["linux/amd64","linux/arm64"]`, platforms.Get("allowedValues").Raw)
+	})
+
+	t.Run("valid", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerQuery(`{test{release(version:"v1.2.3",replicas:64,platforms:["linux/arm64"])}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"test":{"release":"v1.2.3:64:linux/arm64"}}`, out)
+	})
+
+	for _, tc := range []struct {
+		name  string
+		query string
+		err   string
+	}{
+		{
+			name:  "pattern",
+			query: `{test{release(version:"1.2",platforms:["linux/amd64"])}}`,
+			err:   `invalid value for arg "version": value "1.2" does not match pattern`,
+		},
+		{
+			name:  "min",
+			query: `{test{release(version:"v1.2.3",replicas:0,platforms:["linux/amd64"])}}`,
+			err:   `invalid value for arg "replicas": value 0 is less than the minimum 1`,
+		},
+		{
+			name:  "max",
+			query: `{test{release(version:"v1.2.3",replicas:65,platforms:["linux/amd64"])}}`,
+			err:   `invalid value for arg "replicas": value 65 is greater than the maximum 64`,
+		},
+		{
+			name:  "min length",
+			query: `{test{release(version:"v1.2.3",platforms:[])}}`,
+			err:   `invalid value for arg "platforms": length 0 is less than the minimum length 1`,
+		},
+		{
+			name:  "allowed values",
+			query: `{test{release(version:"v1.2.3",platforms:["linux/amd64","windows/amd64"])}}`,
+			err:   `invalid value for arg "platforms": value "windows/amd64" is not one of the allowed values: linux/amd64, linux/arm64`,
+		},
+	} {
+		t.Run(tc.name, func(ctx context.Context, t *testctx.T) {
+			_, err := ctr.With(daggerQuery(tc.query)).Sync(ctx)
+			requireErrOut(t, err, tc.err)
+		})
+	}
+
+	t.Run("call help", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerCall("release", "--help")).Stdout(ctx)
+		require.NoError(t, err)
+		require.Contains(t, out, `(pattern: ^v\d+\.\d+\.\d+$)`)
+		require.Contains(t, out, "(min: 1, max: 64)")
+		require.Contains(t, out, "(min length: 1, allowed values: linux/amd64, linux/arm64)")
+	})
+
+	t.Run("call invalid", func(ctx context.Context, t *testctx.T) {
+		_, err := ctr.With(daggerCall("release", "--version", "v1.2.3", "--platforms", "darwin/arm64")).Sync(ctx)
+		requireErrOut(t, err, `value "darwin/arm64" is not one of the allowed values`)
+	})
+
+	t.Run("invalid default", func(ctx context.Context, t *testctx.T) {
+		_, err := modInit(t, c, "go", `package main
+
+type Test struct{}
+
+func (m *Test) Scale(
+	// +min=1
+	// +default=0
+	replicas int,
+) int {
+	return replicas
+}
+`).With(daggerFunctions()).Sync(ctx)
+		requireErrOut(t, err, "value 0 is less than the minimum 1")
+	})
+}
+
+func (ModuleSuite) TestStreamingFunctions(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	ctr := modInit(t, c, "go", `package main
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+type Test struct{}
+
+func (m *Test) Count(ctx context.Context, to int) (<-chan int, error) {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 1; i <= to; i++ {
+			ch <- i
+		}
+	}()
+	return ch, nil
+}
+
+func (m *Test) Lines() io.Reader {
+	return strings.NewReader("one\ntwo\nthree")
+}
+
+func (m *Test) Words() []string {
+	return []string{"one", "two"}
+}
+`)
+
+	t.Run("typedefs", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerQuery(`{host{directory(path:"."){asModule{objects{asObject{name functions{name streaming returnType{kind}}}}}}}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		fns := gjson.Get(out, `host.directory.asModule.objects.#(asObject.name="Test").asObject.functions`)
+
+		count := fns.Get(`#(name="count")`)
+		require.True(t, count.Get("streaming").Bool())
+		require.Equal(t, "LIST_KIND", count.Get("returnType.kind").String())
+
+		lines := fns.Get(`#(name="lines")`)
+		require.True(t, lines.Get("streaming").Bool())
+		require.Equal(t, "STRING_KIND", lines.Get("returnType.kind").String())
+
+		require.False(t, fns.Get(`#(name="words").streaming`).Bool())
+	})
+
+	t.Run("schema", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerQuery(`{__schema{subscriptionType{name fields{name}}}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		subscription := gjson.Get(out, "__schema.subscriptionType")
+		require.Equal(t, "Subscription", subscription.Get("name").String())
+		require.True(t, subscription.Get(`fields.#(name="test")`).Exists())
+		require.False(t, subscription.Get(`fields.#(name="container")`).Exists())
+	})
+
+	t.Run("query", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerQuery(`{test{count(to:3) lines}}`)).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"test":{"count":[1,2,3],"lines":"one\ntwo\nthree"}}`, out)
+	})
+
+	t.Run("call list", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerCall("count", "--to", "3")).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "1\n2\n3\n", out)
+	})
+
+	t.Run("call string", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerCall("lines")).Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "one\ntwo\nthree", out)
+	})
+
+	t.Run("call json", func(ctx context.Context, t *testctx.T) {
+		out, err := ctr.With(daggerCall("count", "--to", "2", "--json")).Stdout(ctx)
+		require.NoError(t, err)
+		require.JSONEq(t, `[1,2]`, out)
+	})
+
+	t.Run("objects cannot be streamed", func(ctx context.Context, t *testctx.T) {
+		_, err := modInit(t, c, "go", `package main
+
+import (
+	"dagger/test/internal/dagger"
+)
+
+type Test struct{}
+
+func (m *Test) Dirs() <-chan *dagger.Directory {
+	return nil
+}
+`).With(daggerFunctions()).Sync(ctx)
+		requireErrOut(t, err, `function "dirs" cannot stream a list of OBJECT_KIND`)
+	})
+}
+
 func (ModuleSuite) TestNamespacing(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 
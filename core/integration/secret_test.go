@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"dagger.io/dagger"
 	"github.com/dagger/dagger/dagql/call"
 	"github.com/dagger/dagger/internal/testutil"
 	"github.com/dagger/testctx"
@@ -184,5 +185,62 @@ func (SecretSuite) TestBigScrubbed(ctx context.Context, t *testctx.T) {
 	require.Equal(t, "***", stdout)
 }
 
+func (SecretSuite) TestDerived(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	user := c.SetSecret("user", "admin")
+	token := c.SetSecret("token", "hunter2-hunter2")
+	config := c.SetSecret("config", `{"auths":{"ghcr.io":{"auth":"YWRtaW46aHVudGVyMg=="}},"items":[1,{"n":2}]}`)
+
+	for _, tc := range []struct {
+		name     string
+		secret   *dagger.Secret
+		expected string
+	}{
+		{"base64", token.Base64(), "aHVudGVyMi1odW50ZXIy"},
+		{"jsonPath string", config.JSONPath(`$.auths["ghcr.io"].auth`), "YWRtaW46aHVudGVyMg=="},
+		{"jsonPath object", config.JSONPath("items[1]"), `{"n":2}`},
+		{"template", token.Template("{{.user}}:{{.secret}}", dagger.SecretTemplateOpts{
+			Vars: []dagger.SecretVariable{{Name: "user", Secret: user}},
+		}), "admin:hunter2-hunter2"},
+		{"template funcs", token.Template(`{"auth":{{json (base64 .secret)}}}`), `{"auth":"aHVudGVyMi1odW50ZXIy"}`},
+		{"chained", config.JSONPath(`auths["ghcr.io"]`).JSONPath("auth"), "YWRtaW46aHVudGVyMg=="},
+	} {
+		t.Run(tc.name, func(ctx context.Context, t *testctx.T) {
+			ctr := c.Container().From(alpineImage).
+				WithSecretVariable("SECRET", tc.secret).
+				WithEnvVariable("EXPECTED", tc.expected).
+				WithExec([]string{"sh", "-c", `test "$SECRET" = "$EXPECTED" && echo -n "$SECRET"`})
+			stdout, err := ctr.Stdout(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "***", stdout)
+
+			// the plaintext of the secrets it's derived from isn't in its ID
+			id, err := ctr.ID(ctx)
+			require.NoError(t, err)
+			var idp call.ID
+			require.NoError(t, idp.Decode(string(id)))
+			require.NotContains(t, idp.Display(), "hunter2")
+
+			plaintext, err := tc.secret.Plaintext(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, plaintext)
+		})
+	}
+
+	t.Run("invalid", func(ctx context.Context, t *testctx.T) {
+		_, err := config.JSONPath("auths[").Plaintext(ctx)
+		requireErrOut(t, err, "invalid json path")
+		_, err = config.JSONPath("nope").Plaintext(ctx)
+		requireErrOut(t, err, `json path $["nope"]`)
+		_, err = token.JSONPath("auths").Plaintext(ctx)
+		requireErrOut(t, err, "secret is not valid JSON")
+		_, err = token.Template("{{.nope}}").Plaintext(ctx)
+		requireErrOut(t, err, "render secret template")
+		_, err = token.Template("{{").Plaintext(ctx)
+		requireErrOut(t, err, "invalid template")
+	})
+}
+
 //go:embed testdata/secretkey.txt
 var secretKeyBytes []byte
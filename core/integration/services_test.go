@@ -1714,6 +1714,118 @@ func (ServiceSuite) TestStartStopKill(ctx context.Context, t *testctx.T) {
 	require.Empty(t, out)
 }
 
+func (ServiceSuite) TestStartTimeout(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// never listens on the exposed port, so the health check never passes
+	unhealthy := func() *dagger.Service {
+		return c.Container().
+			From(alpineImage).
+			WithEnvVariable("BUST", identity.NewID()).
+			WithExposedPort(8080).
+			WithExec([]string{"sh", "-c", "echo booting; echo not yet >&2; sleep 300"}).
+			AsService()
+	}
+
+	t.Run("start", func(ctx context.Context, t *testctx.T) {
+		_, err := unhealthy().Start(ctx, dagger.ServiceStartOpts{Timeout: 5})
+
+		var timeoutErr *dagger.TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.Contains(t, timeoutErr.Error(), "timed out after 5s")
+		require.Equal(t, "booting", timeoutErr.Stdout)
+		require.Equal(t, "not yet", timeoutErr.Stderr)
+	})
+
+	t.Run("up", func(ctx context.Context, t *testctx.T) {
+		err := unhealthy().Up(ctx, dagger.ServiceUpOpts{Random: true, Timeout: 5})
+
+		var timeoutErr *dagger.TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.Equal(t, "booting", timeoutErr.Stdout)
+	})
+
+	t.Run("healthy in time", func(ctx context.Context, t *testctx.T) {
+		srv, _ := httpService(ctx, t, c, "hello")
+		_, err := srv.Start(ctx, dagger.ServiceStartOpts{Timeout: 60})
+		require.NoError(t, err)
+	})
+}
+
+func (ServiceSuite) TestCustomHealthcheck(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// accepts connections right away, but only serves content after a delay
+	slowService := func() *dagger.Container {
+		return c.Container().
+			From(alpineImage).
+			WithEnvVariable("BUST", identity.NewID()).
+			WithExposedPort(8000).
+			WithDefaultArgs([]string{"sh", "-c", "mkdir -p /srv; (sleep 3; echo ready > /srv/index.html) & httpd -f -p 8000 -h /srv"})
+	}
+
+	fetch := func(ctx context.Context, srv *dagger.Service) (string, error) {
+		return c.Container().
+			From(alpineImage).
+			WithServiceBinding("www", srv).
+			WithEnvVariable("BUST", identity.NewID()).
+			WithExec([]string{"wget", "-O-", "http://www:8000/index.html"}).
+			Stdout(ctx)
+	}
+
+	t.Run("command", func(ctx context.Context, t *testctx.T) {
+		srv := slowService().
+			WithHealthcheck([]string{"test", "-f", "/srv/index.html"}).
+			AsService()
+
+		_, err := srv.Start(ctx)
+		require.NoError(t, err)
+
+		out, err := fetch(ctx, srv)
+		require.NoError(t, err)
+		require.Equal(t, "ready\n", out)
+	})
+
+	t.Run("http", func(ctx context.Context, t *testctx.T) {
+		srv := slowService().
+			WithHTTPHealthcheck("/index.html").
+			AsService()
+
+		_, err := srv.Start(ctx)
+		require.NoError(t, err)
+
+		out, err := fetch(ctx, srv)
+		require.NoError(t, err)
+		require.Equal(t, "ready\n", out)
+	})
+
+	t.Run("unhealthy", func(ctx context.Context, t *testctx.T) {
+		_, err := slowService().
+			WithHealthcheck([]string{"false"}, dagger.ContainerWithHealthcheckOpts{
+				Retries: 2,
+			}).
+			AsService().
+			Start(ctx)
+		requireErrOut(t, err, "unhealthy after 2 consecutive failures")
+	})
+
+	t.Run("removed", func(ctx context.Context, t *testctx.T) {
+		_, err := slowService().
+			WithHealthcheck([]string{"false"}).
+			WithoutHealthcheck().
+			AsService().
+			Start(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid path", func(ctx context.Context, t *testctx.T) {
+		_, err := slowService().
+			WithHTTPHealthcheck("index.html").
+			Sync(ctx)
+		requireErrOut(t, err, "must be absolute")
+	})
+}
+
 // TestNoCrossTalk shows that services spawned in one client cannot be
 // reached by another client.
 func (ServiceSuite) TestNoCrossTalk(ctx context.Context, t *testctx.T) {
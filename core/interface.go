@@ -212,14 +212,19 @@ func (iface *InterfaceType) Install(ctx context.Context, dag *dagql.Server) erro
 		}
 
 		fieldDef := dagql.FieldSpec{
-			Name:        fnName,
-			Description: formatGqlDescription(fnTypeDef.Description),
-			Type:        fnTypeDef.ReturnType.ToTyped(),
-			Module:      iface.mod.IDModule(),
+			Name:             fnName,
+			Description:      formatGqlDescription(fnTypeDef.Description),
+			Type:             fnTypeDef.ReturnType.ToTyped(),
+			Module:           iface.mod.IDModule(),
+			DeprecatedReason: deprecationReason(fnTypeDef.Deprecated),
+			Streaming:        fnTypeDef.Streaming,
 		}
 		if fnTypeDef.SourceMap != nil {
 			fieldDef.Directives = append(fieldDef.Directives, fnTypeDef.SourceMap.TypeDirective())
 		}
+		if directive := fnTypeDef.ReturnType.JSONTypeDirective(); directive != nil {
+			fieldDef.Directives = append(fieldDef.Directives, directive)
+		}
 
 		for _, argMetadata := range fnTypeDef.Args {
 			// check whether this is a pre-existing object from a dependency module
@@ -244,13 +249,17 @@ func (iface *InterfaceType) Install(ctx context.Context, dag *dagql.Server) erro
 			}
 
 			inputSpec := dagql.InputSpec{
-				Name:        gqlArgName(argMetadata.Name),
-				Description: formatGqlDescription(argMetadata.Description),
-				Type:        argMetadata.TypeDef.ToInput(),
+				Name:             gqlArgName(argMetadata.Name),
+				Description:      formatGqlDescription(argMetadata.Description),
+				Type:             argMetadata.TypeDef.ToInput(),
+				DeprecatedReason: deprecationReason(argMetadata.Deprecated),
 			}
 			if argMetadata.SourceMap != nil {
 				inputSpec.Directives = append(inputSpec.Directives, argMetadata.SourceMap.TypeDirective())
 			}
+			if directive := argMetadata.TypeDef.JSONTypeDirective(); directive != nil {
+				inputSpec.Directives = append(inputSpec.Directives, directive)
+			}
 			fieldDef.Args = append(fieldDef.Args, inputSpec)
 		}
 
@@ -82,6 +82,14 @@ func (JSON) DecodeInput(val any) (res dagql.Input, err error) {
 		return JSON(x), nil
 	case json.RawMessage:
 		return JSON(x), nil
+	case map[string]any, []any:
+		// object and list literals, e.g. map and union values written inline
+		// in a query
+		bs, err := json.Marshal(x)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %T to JSON: %w", val, err)
+		}
+		return JSON(bs), nil
 	default:
 		return nil, fmt.Errorf("cannot convert %T to JSON", val)
 	}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	bkgw "github.com/moby/buildkit/frontend/gateway/client"
 	"github.com/moby/buildkit/identity"
@@ -129,6 +131,47 @@ func (fn *ModuleFunction) recordCall(ctx context.Context) {
 	analytics.Ctx(ctx).Capture(ctx, "module_call", props)
 }
 
+// warnDeprecated emits a warning span if the function, or any argument
+// explicitly set by the caller, is deprecated.
+func (fn *ModuleFunction) warnDeprecated(ctx context.Context) {
+	name := fn.metadata.Name
+	if fn.objDef != nil {
+		if name == "" {
+			name = fn.objDef.Name
+		} else {
+			name = fn.objDef.Name + "." + name
+		}
+	}
+	if name == "" {
+		return
+	}
+
+	var warnings []string
+	if fn.metadata.Deprecated != nil {
+		warnings = append(warnings, deprecationWarning(fmt.Sprintf("function %s", name), *fn.metadata.Deprecated))
+	}
+	if id := dagql.CurrentID(ctx); id != nil {
+		for _, idArg := range id.Args() {
+			arg, ok := fn.metadata.LookupArg(idArg.Name())
+			if !ok || arg.Deprecated == nil {
+				continue
+			}
+			warnings = append(warnings, deprecationWarning(fmt.Sprintf("argument %s of function %s", arg.Name, name), *arg.Deprecated))
+		}
+	}
+	for _, warning := range warnings {
+		_, span := Tracer(ctx).Start(ctx, warning)
+		span.End()
+	}
+}
+
+func deprecationWarning(subject, reason string) string {
+	if reason = strings.TrimSpace(reason); reason == "" {
+		return fmt.Sprintf("warning: %s is deprecated", subject)
+	}
+	return fmt.Sprintf("warning: %s is deprecated: %s", subject, reason)
+}
+
 // setCallInputs sets the call inputs for the function call.
 //
 // It first load the argument set by the user.
@@ -157,6 +200,12 @@ func (fn *ModuleFunction) setCallInputs(ctx context.Context, opts *CallOpts) ([]
 			return nil, fmt.Errorf("failed to marshal arg %q: %w", input.Name, err)
 		}
 
+		// enforce the arg's constraints here so invalid values fail fast,
+		// without starting the module runtime
+		if err := arg.metadata.CheckConstraints(encoded); err != nil {
+			return nil, fmt.Errorf("invalid value for arg %q: %w", input.Name, err)
+		}
+
 		callInputs[i] = &FunctionCallArgValue{
 			Name:  name,
 			Value: encoded,
@@ -213,6 +262,42 @@ func (fn *ModuleFunction) setCallInputs(ctx context.Context, opts *CallOpts) ([]
 	return callInputs, nil
 }
 
+// CacheKey returns the cache key of a call to the function, given the
+// original digest of the call, per the function's cache policy.
+//
+// The cache key is also the digest of the call's ID, which scopes the cache
+// of the function's exec (see ExecutionMetadata.CacheByCall).
+func (fn *ModuleFunction) CacheKey(ctx context.Context, origDgst digest.Digest) (digest.Digest, error) {
+	switch fn.metadata.CachePolicy {
+	case FunctionCachePolicyNever:
+		// never match a previous call
+		return HashFrom(origDgst.String(), identity.NewID()), nil
+	case FunctionCachePolicyTTL:
+		// match the previous calls made in the same time window
+		if fn.metadata.CacheTTLSeconds <= 0 {
+			return "", fmt.Errorf("function %q has a TTL cache policy without a time to live", fn.metadata.Name)
+		}
+		window := time.Now().Unix() / int64(fn.metadata.CacheTTLSeconds)
+		return HashFrom(origDgst.String(), strconv.FormatInt(window, 10)), nil
+	default:
+		return origDgst, nil
+	}
+}
+
+// cacheAcrossSessions returns whether the exec of a call to the function may
+// be cached across sessions, per the function's cache policy. byDefault is
+// used for the default policy.
+func (fn *ModuleFunction) cacheAcrossSessions(byDefault bool) bool {
+	switch fn.metadata.CachePolicy {
+	case FunctionCachePolicyTTL:
+		return true
+	case FunctionCachePolicyNever, FunctionCachePolicyPerSession:
+		return false
+	default:
+		return byDefault
+	}
+}
+
 func (fn *ModuleFunction) Call(ctx context.Context, opts *CallOpts) (t dagql.Typed, rerr error) { //nolint: gocyclo
 	mod := fn.mod
 
@@ -226,6 +311,8 @@ func (fn *ModuleFunction) Call(ctx context.Context, opts *CallOpts) (t dagql.Typ
 	// Calls without function name are internal and excluded.
 	fn.recordCall(ctx)
 
+	fn.warnDeprecated(ctx)
+
 	callInputs, err := fn.setCallInputs(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to set call inputs: %w", err)
@@ -248,7 +335,7 @@ func (fn *ModuleFunction) Call(ctx context.Context, opts *CallOpts) (t dagql.Typ
 		ClientID:        identity.NewID(),
 		CallID:          dagql.CurrentID(ctx),
 		ExecID:          identity.NewID(),
-		CachePerSession: !opts.Cache,
+		CachePerSession: !fn.cacheAcrossSessions(opts.Cache),
 		Internal:        true,
 		ModuleName:      mod.NameField,
 		CacheByCall:     !opts.SkipCallDigestCacheKey,
@@ -319,6 +406,19 @@ func (fn *ModuleFunction) Call(ctx context.Context, opts *CallOpts) (t dagql.Typ
 		return nil, fmt.Errorf("failed to get buildkit client: %w", err)
 	}
 
+	if fn.metadata.Streaming && dagql.IsStreaming(ctx) {
+		// forward the items returned by the function to the subscriber as
+		// they arrive
+		streams, err := fn.root.FunctionCallStreams(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get function call streams: %w", err)
+		}
+		release := streams.Register(execMD.ClientID, func(_ context.Context, item JSON) error {
+			return dagql.SendStreamItem(ctx, json.RawMessage(item))
+		})
+		defer release()
+	}
+
 	_, err = ctr.Evaluate(ctx)
 	if err != nil {
 		id, ok, extractErr := extractError(ctx, bk, err)
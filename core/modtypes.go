@@ -1,7 +1,9 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/dagger/dagger/dagql"
@@ -210,3 +212,222 @@ func (t *NullableType) TypeDef() *TypeDef {
 	cp.Optional = true
 	return cp
 }
+
+// MapType is a map of string keys to values of a single type. GraphQL has no
+// map type, so maps are represented in the schema as a JSON object.
+type MapType struct {
+	Value      *TypeDef
+	Underlying ModType
+}
+
+func (t *MapType) ConvertFromSDKResult(ctx context.Context, value any) (dagql.Typed, error) {
+	if value == nil {
+		// return an empty map, _not_ nil
+		return JSON("{}"), nil
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("MapType.ConvertFromSDKResult: expected map[string]any, got %T", value)
+	}
+	result, err := t.convertValues(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSONValue(result)
+}
+
+func (t *MapType) ConvertToSDKInput(ctx context.Context, value dagql.Typed) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var m map[string]any
+	if err := unmarshalJSONValue(value, &m); err != nil {
+		return nil, fmt.Errorf("%T.ConvertToSDKInput: %w", t, err)
+	}
+	return t.convertValues(ctx, m)
+}
+
+func (t *MapType) CollectCoreIDs(ctx context.Context, value dagql.Typed, ids map[digest.Digest]*resource.ID) error {
+	if value == nil {
+		return nil
+	}
+	var m map[string]any
+	if err := unmarshalJSONValue(value, &m); err != nil {
+		return fmt.Errorf("%T.CollectCoreIDs: %w", t, err)
+	}
+	for k, v := range m {
+		typed, err := t.Underlying.ConvertFromSDKResult(ctx, v)
+		if err != nil {
+			return fmt.Errorf("map key %q: %w", k, err)
+		}
+		if err := t.Underlying.CollectCoreIDs(ctx, typed, ids); err != nil {
+			return fmt.Errorf("map key %q: %w", k, err)
+		}
+	}
+	return nil
+}
+
+func (t *MapType) convertValues(ctx context.Context, m map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		converted, err := convertJSONValue(ctx, t.Underlying, v)
+		if err != nil {
+			return nil, fmt.Errorf("map key %q: %w", k, err)
+		}
+		result[k] = converted
+	}
+	return result, nil
+}
+
+func (t *MapType) SourceMod() Mod {
+	return t.Underlying.SourceMod()
+}
+
+func (t *MapType) TypeDef() *TypeDef {
+	return &TypeDef{
+		Kind: TypeDefKindMap,
+		AsMap: dagql.NonNull(&MapTypeDef{
+			ValueTypeDef: t.Value.Clone(),
+		}),
+	}
+}
+
+// UnionType is a tagged union of values of different types. Values are
+// represented in the schema as a JSON object with a single key naming the
+// member that is set.
+type UnionType struct {
+	Def *UnionTypeDef
+
+	// Members holds the type of each of the union's members, keyed by the
+	// member's standardized name
+	Members map[string]ModType
+}
+
+func (t *UnionType) ConvertFromSDKResult(ctx context.Context, value any) (dagql.Typed, error) {
+	if value == nil {
+		slog.Warn("UnionType.ConvertFromSDKResult: got nil value")
+		return nil, nil
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("UnionType.ConvertFromSDKResult: expected map[string]any, got %T", value)
+	}
+	member, v, err := t.member(m)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := convertJSONValue(ctx, t.Members[member.Name], v)
+	if err != nil {
+		return nil, fmt.Errorf("union %q member %q: %w", t.Def.Name, member.Name, err)
+	}
+	return marshalJSONValue(map[string]any{member.Name: converted})
+}
+
+func (t *UnionType) ConvertToSDKInput(ctx context.Context, value dagql.Typed) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var m map[string]any
+	if err := unmarshalJSONValue(value, &m); err != nil {
+		return nil, fmt.Errorf("%T.ConvertToSDKInput: %w", t, err)
+	}
+	member, v, err := t.member(m)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := convertJSONValue(ctx, t.Members[member.Name], v)
+	if err != nil {
+		return nil, fmt.Errorf("union %q member %q: %w", t.Def.Name, member.Name, err)
+	}
+	// SDKs get the member under the name they originally gave it
+	return map[string]any{member.OriginalName: converted}, nil
+}
+
+func (t *UnionType) CollectCoreIDs(ctx context.Context, value dagql.Typed, ids map[digest.Digest]*resource.ID) error {
+	if value == nil {
+		return nil
+	}
+	var m map[string]any
+	if err := unmarshalJSONValue(value, &m); err != nil {
+		return fmt.Errorf("%T.CollectCoreIDs: %w", t, err)
+	}
+	member, v, err := t.member(m)
+	if err != nil {
+		return err
+	}
+	memberType := t.Members[member.Name]
+	typed, err := memberType.ConvertFromSDKResult(ctx, v)
+	if err != nil {
+		return fmt.Errorf("union %q member %q: %w", t.Def.Name, member.Name, err)
+	}
+	return memberType.CollectCoreIDs(ctx, typed, ids)
+}
+
+// member returns the single member set in the given union value, ignoring
+// members explicitly set to null.
+func (t *UnionType) member(m map[string]any) (*UnionMemberTypeDef, any, error) {
+	var found *UnionMemberTypeDef
+	var value any
+	for k, v := range m {
+		if v == nil {
+			continue
+		}
+		member, ok := t.Def.MemberByName(k)
+		if !ok {
+			return nil, nil, fmt.Errorf("union %q has no member %q", t.Def.Name, k)
+		}
+		if found != nil {
+			return nil, nil, fmt.Errorf("union %q must have exactly one member set, got %q and %q", t.Def.Name, found.Name, member.Name)
+		}
+		found, value = member, v
+	}
+	if found == nil {
+		return nil, nil, fmt.Errorf("union %q must have exactly one member set, got none", t.Def.Name)
+	}
+	return found, value, nil
+}
+
+func (t *UnionType) SourceMod() Mod {
+	for _, member := range t.Def.Members {
+		if mod := t.Members[member.Name].SourceMod(); mod != nil && mod.Name() != ModuleName {
+			return mod
+		}
+	}
+	return nil
+}
+
+func (t *UnionType) TypeDef() *TypeDef {
+	return &TypeDef{
+		Kind:    TypeDefKindUnion,
+		AsUnion: dagql.NonNull(t.Def.Clone()),
+	}
+}
+
+// convertJSONValue validates a JSON-decoded value of a map or union against
+// its type, returning it in the form passed to SDKs (e.g. with objects loaded
+// and re-encoded as IDs).
+func convertJSONValue(ctx context.Context, modType ModType, value any) (any, error) {
+	typed, err := modType.ConvertFromSDKResult(ctx, value)
+	if err != nil {
+		return nil, err
+	}
+	return modType.ConvertToSDKInput(ctx, typed)
+}
+
+func marshalJSONValue(value any) (JSON, error) {
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return JSON(bs), nil
+}
+
+func unmarshalJSONValue(value dagql.Typed, dest any) error {
+	raw, ok := value.(JSON)
+	if !ok {
+		return fmt.Errorf("expected JSON, got %T", value)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return dec.Decode(dest)
+}
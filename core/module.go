@@ -216,6 +216,10 @@ func (mod *Module) ModTypeFor(ctx context.Context, typeDef *TypeDef, checkDirect
 		modType, ok = mod.modTypeForPrimitive(typeDef)
 	case TypeDefKindList:
 		modType, ok, err = mod.modTypeForList(ctx, typeDef, checkDirectDeps)
+	case TypeDefKindMap:
+		modType, ok, err = mod.modTypeForMap(ctx, typeDef, checkDirectDeps)
+	case TypeDefKindUnion:
+		modType, ok, err = mod.modTypeForUnion(ctx, typeDef, checkDirectDeps)
 	case TypeDefKindObject:
 		modType, ok, err = mod.modTypeFromDeps(ctx, typeDef, checkDirectDeps)
 		if ok || err != nil {
@@ -295,6 +299,41 @@ func (mod *Module) modTypeForList(ctx context.Context, typedef *TypeDef, checkDi
 	}, true, nil
 }
 
+func (mod *Module) modTypeForMap(ctx context.Context, typedef *TypeDef, checkDirectDeps bool) (ModType, bool, error) {
+	underlyingType, ok, err := mod.ModTypeFor(ctx, typedef.AsMap.Value.ValueTypeDef, checkDirectDeps)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get underlying type: %w", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	return &MapType{
+		Value:      typedef.AsMap.Value.ValueTypeDef,
+		Underlying: underlyingType,
+	}, true, nil
+}
+
+func (mod *Module) modTypeForUnion(ctx context.Context, typedef *TypeDef, checkDirectDeps bool) (ModType, bool, error) {
+	union := typedef.AsUnion.Value
+	members := make(map[string]ModType, len(union.Members))
+	for _, member := range union.Members {
+		memberType, ok, err := mod.ModTypeFor(ctx, member.TypeDef, checkDirectDeps)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get type of member %q: %w", member.Name, err)
+		}
+		if !ok {
+			return nil, false, nil
+		}
+		members[member.Name] = memberType
+	}
+
+	return &UnionType{
+		Def:     union,
+		Members: members,
+	}, true, nil
+}
+
 func (mod *Module) modTypeForObject(typeDef *TypeDef) (ModType, bool) {
 	for _, obj := range mod.ObjectDefs {
 		if obj.AsObject.Value.Name == typeDef.AsObject.Value.Name {
@@ -342,6 +381,27 @@ func (mod *Module) validateTypeDef(ctx context.Context, typeDef *TypeDef) error
 	switch typeDef.Kind {
 	case TypeDefKindList:
 		return mod.validateTypeDef(ctx, typeDef.AsList.Value.ElementTypeDef)
+	case TypeDefKindMap:
+		valueType := typeDef.AsMap.Value.ValueTypeDef
+		if err := mod.validateJSONValueTypeDef(ctx, valueType, "map value"); err != nil {
+			return err
+		}
+		return mod.validateTypeDef(ctx, valueType)
+	case TypeDefKindUnion:
+		union := typeDef.AsUnion.Value
+		if len(union.Members) == 0 {
+			return fmt.Errorf("union %q must have at least one member", union.OriginalName)
+		}
+		for _, member := range union.Members {
+			subject := fmt.Sprintf("union %q member %q", union.OriginalName, member.OriginalName)
+			if err := mod.validateJSONValueTypeDef(ctx, member.TypeDef, subject); err != nil {
+				return err
+			}
+			if err := mod.validateTypeDef(ctx, member.TypeDef); err != nil {
+				return err
+			}
+		}
+		return nil
 	case TypeDefKindObject:
 		return mod.validateObjectTypeDef(ctx, typeDef)
 	case TypeDefKindInterface:
@@ -350,6 +410,31 @@ func (mod *Module) validateTypeDef(ctx context.Context, typeDef *TypeDef) error
 	return nil
 }
 
+// Maps and unions are passed around as JSON, with objects encoded as their ID.
+// Objects and interfaces defined by a module are passed to its SDK as their
+// fields rather than an ID, so they can't be used inside maps and unions.
+func (mod *Module) validateJSONValueTypeDef(ctx context.Context, typeDef *TypeDef, subject string) error {
+	var name string
+	switch typeDef.Kind {
+	case TypeDefKindList:
+		return mod.validateJSONValueTypeDef(ctx, typeDef.AsList.Value.ElementTypeDef, subject)
+	case TypeDefKindObject:
+		name = typeDef.AsObject.Value.OriginalName
+	case TypeDefKindInterface:
+		name = typeDef.AsInterface.Value.OriginalName
+	default:
+		return nil
+	}
+	_, ok, err := mod.Deps.ModTypeFor(ctx, typeDef)
+	if err != nil {
+		return fmt.Errorf("failed to get mod type for type def: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%s cannot be of type %q defined by the module: only core types can be used in maps and unions", subject, name)
+	}
+	return nil
+}
+
 func (mod *Module) validateObjectTypeDef(ctx context.Context, typeDef *TypeDef) error {
 	// check whether this is a pre-existing object from core or another module
 	modType, ok, err := mod.Deps.ModTypeFor(ctx, typeDef)
@@ -476,6 +561,18 @@ func (mod *Module) namespaceTypeDef(ctx context.Context, modPath string, typeDef
 		if err := mod.namespaceTypeDef(ctx, modPath, typeDef.AsList.Value.ElementTypeDef); err != nil {
 			return err
 		}
+	case TypeDefKindMap:
+		if err := mod.namespaceTypeDef(ctx, modPath, typeDef.AsMap.Value.ValueTypeDef); err != nil {
+			return err
+		}
+	case TypeDefKindUnion:
+		union := typeDef.AsUnion.Value
+		union.SourceMap = mod.namespaceSourceMap(modPath, union.SourceMap)
+		for _, member := range union.Members {
+			if err := mod.namespaceTypeDef(ctx, modPath, member.TypeDef); err != nil {
+				return err
+			}
+		}
 	case TypeDefKindObject:
 		obj := typeDef.AsObject.Value
 
@@ -367,7 +367,9 @@ func (obj *ModuleObject) installConstructor(ctx context.Context, dag *dagql.Serv
 				Server:       dag,
 			})
 		},
-		nil,
+		func(ctx context.Context, _ dagql.Object, _ map[string]dagql.Input, origDgst digest.Digest) (digest.Digest, error) {
+			return fn.CacheKey(ctx, origDgst)
+		},
 	)
 
 	return nil
@@ -394,14 +396,18 @@ func (obj *ModuleObject) functions(ctx context.Context, dag *dagql.Server) (fiel
 
 func objField(mod *Module, field *FieldTypeDef) dagql.Field[*ModuleObject] {
 	spec := dagql.FieldSpec{
-		Name:        field.Name,
-		Description: field.Description,
-		Type:        field.TypeDef.ToTyped(),
-		Module:      mod.IDModule(),
+		Name:             field.Name,
+		Description:      field.Description,
+		Type:             field.TypeDef.ToTyped(),
+		Module:           mod.IDModule(),
+		DeprecatedReason: deprecationReason(field.Deprecated),
 	}
 	if field.SourceMap != nil {
 		spec.Directives = append(spec.Directives, field.SourceMap.TypeDirective())
 	}
+	if directive := field.TypeDef.JSONTypeDirective(); directive != nil {
+		spec.Directives = append(spec.Directives, directive)
+	}
 	return dagql.Field[*ModuleObject]{
 		Spec: spec,
 		Func: func(ctx context.Context, obj dagql.Instance[*ModuleObject], _ map[string]dagql.Input) (dagql.Typed, error) {
@@ -476,6 +482,9 @@ func objFun(ctx context.Context, mod *Module, objDef *ObjectTypeDef, fun *Functi
 			})
 			return modFun.Call(ctx, opts)
 		},
+		CacheKeyFunc: func(ctx context.Context, _ dagql.Instance[*ModuleObject], _ map[string]dagql.Input, origDgst digest.Digest) (digest.Digest, error) {
+			return modFun.CacheKey(ctx, origDgst)
+		},
 	}, nil
 }
 
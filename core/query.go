@@ -7,6 +7,7 @@ import (
 
 	"github.com/containerd/containerd/content"
 	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/util/leaseutil"
 	"github.com/vektah/gqlparser/v2/ast"
 	"go.opentelemetry.io/otel/trace"
@@ -74,6 +75,9 @@ type Server interface {
 	// The services for the current client's session
 	Services(context.Context) (*Services, error)
 
+	// The streams of function call results for the current client's session
+	FunctionCallStreams(context.Context) (*FunctionCallStreams, error)
+
 	// The default platform for the engine as a whole
 	Platform() Platform
 
@@ -83,6 +87,9 @@ type Server interface {
 	// The lease manager for the engine as a whole
 	LeaseManager() *leaseutil.Manager
 
+	// The DNS config for the engine as a whole
+	DNSConfig() *oci.DNSConfig
+
 	// Return all the cache entries in the local cache. No support for filtering yet.
 	EngineLocalCacheEntries(context.Context) (*EngineCacheEntrySet, error)
 
@@ -101,6 +101,23 @@ func (s *containerSchema) Install() {
 			ArgDoc("expand",
 				`Replace "${VAR}" or "$VAR" in the value of path according to the current `+
 					`environment variables defined in the container (e.g. "/$VAR/foo.txt").`),
+		dagql.Func("exists", s.exists).
+			Doc(`Returns whether a file, directory or other entry exists at the given path.`,
+				`Mounts are included.`).
+			ArgDoc("path", `The path to check (e.g., "./README.md").`).
+			ArgDoc("expectedType",
+				`If set, the entry must also be of this type.`,
+				`Symlinks are followed, unless the expected type is SYMLINK.`).
+			ArgDoc("expand",
+				`Replace "${VAR}" or "$VAR" in the value of path according to the current `+
+					`environment variables defined in the container (e.g. "/$VAR/foo.txt").`),
+		dagql.Func("stat", s.stat).
+			Doc(`Returns information about the entry at the given path.`,
+				`Mounts are included. A symlink at the path is described itself, rather than its target.`).
+			ArgDoc("path", `The path of the entry (e.g., "./README.md").`).
+			ArgDoc("expand",
+				`Replace "${VAR}" or "$VAR" in the value of path according to the current `+
+					`environment variables defined in the container (e.g. "/$VAR/foo.txt").`),
 
 		dagql.Func("user", s.user).
 			Doc("Retrieves the user to be set for all commands."),
@@ -301,6 +318,13 @@ func (s *containerSchema) Install() {
 				`A user:group to set for the file.`,
 				`The user and group can either be an ID (1000:1000) or a name (foo:bar).`,
 				`If the group is omitted, it defaults to the same as the user.`).
+			ArgDoc("ownerMap",
+				`Changes of ownership to apply to the copied file, as FROM:TO pairs (e.g., ["1000:0"]).`,
+				`FROM is a user ID recorded in the source, optionally followed by a group ID as UID/GID
+				(e.g., "1000/100"), the group ID defaulting to the user ID. Files owned by the user get the
+				user of TO, and files owned by the group get the group of TO. TO is a user ID or name, with
+				the group defaulting to the user's. Cannot be used together with owner; if neither is set,
+				the ownership recorded in the source is preserved.`).
 			ArgDoc("expand",
 				`Replace "${VAR}" or "$VAR" in the value of path according to the current `+
 					`environment variables defined in the container (e.g. "/$VAR/foo.txt").`),
@@ -328,6 +352,13 @@ func (s *containerSchema) Install() {
 				`A user:group to set for the files.`,
 				`The user and group can either be an ID (1000:1000) or a name (foo:bar).`,
 				`If the group is omitted, it defaults to the same as the user.`).
+			ArgDoc("ownerMap",
+				`Changes of ownership to apply to the copied files, as FROM:TO pairs (e.g., ["1000:0"]).`,
+				`FROM is a user ID recorded in the source, optionally followed by a group ID as UID/GID
+				(e.g., "1000/100"), the group ID defaulting to the user ID. Files owned by the user get the
+				user of TO, and files owned by the group get the group of TO. TO is a user ID or name, with
+				the group defaulting to the user's. Cannot be used together with owner; if neither is set,
+				the ownership recorded in the source is preserved.`).
 			ArgDoc("expand",
 				`Replace "${VAR}" or "$VAR" in the value of path according to the current `+
 					`environment variables defined in the container (e.g. "/$VAR/foo.txt").`),
@@ -356,6 +387,14 @@ func (s *containerSchema) Install() {
 				`The user and group can either be an ID (1000:1000) or a name (foo:bar).`,
 				`If the group is omitted, it defaults to the same as the user.`),
 
+		dagql.Func("withSymlink", s.withSymlink).
+			Doc(`Retrieves this container plus a symbolic link at the given path.`).
+			ArgDoc("target", `Location the symbolic link points to (e.g., "/usr/bin/python3").`).
+			ArgDoc("linkName", `Location of the symbolic link (e.g., "/usr/bin/python").`).
+			ArgDoc("expand",
+				`Replace "${VAR}" or "$VAR" in the value of linkName according to the current `+
+					`environment variables defined in the container (e.g. "/$VAR/foo").`),
+
 		dagql.Func("withDirectory", s.withDirectory).
 			Doc(`Retrieves this container plus a directory written at the given path.`).
 			ArgDoc("path", `Location of the written directory (e.g., "/tmp/directory").`).
@@ -366,6 +405,13 @@ func (s *containerSchema) Install() {
 				`A user:group to set for the directory and its contents.`,
 				`The user and group can either be an ID (1000:1000) or a name (foo:bar).`,
 				`If the group is omitted, it defaults to the same as the user.`).
+			ArgDoc("ownerMap",
+				`Changes of ownership to apply to the copied contents, as FROM:TO pairs (e.g., ["1000:0"]).`,
+				`FROM is a user ID recorded in the source, optionally followed by a group ID as UID/GID
+				(e.g., "1000/100"), the group ID defaulting to the user ID. Files owned by the user get the
+				user of TO, and files owned by the group get the group of TO. TO is a user ID or name, with
+				the group defaulting to the user's. Cannot be used together with owner; if neither is set,
+				the ownership recorded in the source is preserved.`).
 			ArgDoc("expand",
 				`Replace "${VAR}" or "$VAR" in the value of path according to the current `+
 					`environment variables defined in the container (e.g. "/$VAR/foo").`),
@@ -414,7 +460,29 @@ func (s *containerSchema) Install() {
 				`If set, skip the automatic init process injected into containers by default.`,
 				`This should only be used if the user requires that their exec process be the
 				pid 1 process in the container. Otherwise it may result in unexpected behavior.`,
-			),
+			).
+			ArgDoc("cpuQuota",
+				`Number of CPUs the command may use (e.g., 0.5 or 2).`,
+				`Overrides the default set with withResourceLimits. Zero means unlimited.`).
+			ArgDoc("memoryLimit",
+				`Maximum memory in bytes the command may use.`,
+				`Overrides the default set with withResourceLimits. Zero means unlimited.`,
+				`If the command exceeds the limit it is killed and the call fails with an
+				exec error reporting that it ran out of memory.`).
+			ArgDoc("pidsLimit",
+				`Maximum number of processes the command may create.`,
+				`Overrides the default set with withResourceLimits. Zero means unlimited.`).
+			ArgDoc("timeout",
+				`Number of seconds after which the command is killed if it's still running. Zero means no timeout.`,
+				`When the timeout expires the call fails with an exec error reporting that it
+				timed out, including the output captured so far.`),
+
+		dagql.Func("withResourceLimits", s.withResourceLimits).
+			Doc(`Retrieves this container with default resource limits for all subsequent withExec calls.`,
+				`Limits that are not set (or set to zero) keep their previous value.`).
+			ArgDoc("cpuQuota", `Number of CPUs the container's commands may use (e.g., 0.5 or 2).`).
+			ArgDoc("memoryLimit", `Maximum memory in bytes the container's commands may use.`).
+			ArgDoc("pidsLimit", `Maximum number of processes the container's commands may create.`),
 
 		dagql.Func("withExec", s.withExec).
 			View(BeforeVersion("v0.13.0")).
@@ -510,7 +578,7 @@ func (s *containerSchema) Install() {
 			Doc(`Retrieves this container minus the given OCI annotation.`).
 			ArgDoc("name", `The name of the annotation.`),
 
-		dagql.Func("publish", s.publish).
+		dagql.NodeFunc("publish", s.publish).
 			Impure("Writes to the specified Docker registry.").
 			Doc(`Publishes this container as a new image to the specified address.`,
 				`Publish returns a fully qualified ref.`,
@@ -533,7 +601,18 @@ func (s *containerSchema) Install() {
 				`Use the specified media types for the published image's layers.`,
 				`Defaults to OCI, which is largely compatible with most recent
 				registries, but Docker may be needed for older registries without OCI
-				support.`),
+				support.`).
+			ArgDoc("sign",
+				`Sign the published image with this private key, the way cosign does.`,
+				`The signature, and the attestations, are pushed as OCI referrers of
+				the image. Verify it with "cosign verify --experimental-oci11 --key".`,
+				`Keys generated with "cosign generate-key-pair" are supported, as well
+				as unencrypted PKCS #8, EC and RSA keys in PEM format.`).
+			ArgDoc("signPassword",
+				`The password of the signing key, if it's encrypted.`).
+			ArgDoc("attestations",
+				`Attestations to push along with the image, as OCI referrers.`,
+				`They are signed with the signing key, if any, as DSSE envelopes.`),
 
 		dagql.Func("platform", s.platform).
 			Doc(`The platform this container executes and publishes as.`),
@@ -621,6 +700,31 @@ func (s *containerSchema) Install() {
 			ArgDoc("port", `Port number to unexpose`).
 			ArgDoc("protocol", `Port protocol to unexpose`),
 
+		dagql.Func("withHealthcheck", s.withHealthcheck).
+			Doc(`Configures a command that checks whether the container is ready when run as a service.`,
+				`Starting the service waits until the command exits successfully, in
+				addition to waiting for the exposed ports to accept connections.`).
+			ArgDoc("args", `Command to run inside the service's container (e.g., ["pg_isready"]).`).
+			ArgDoc("interval", `Number of seconds to wait between attempts.`).
+			ArgDoc("retries", `Number of consecutive failed attempts after which the service is considered unhealthy.`).
+			ArgDoc("startPeriod",
+				`Number of seconds to give the service to initialize, during which failed attempts don't count towards retries.`),
+
+		dagql.Func("withHTTPHealthcheck", s.withHTTPHealthcheck).
+			Doc(`Configures an HTTP request that checks whether the container is ready when run as a service.`,
+				`Starting the service waits until the request returns the expected status, in
+				addition to waiting for the exposed ports to accept connections.`).
+			ArgDoc("path", `Path to request (e.g., "/healthz").`).
+			ArgDoc("port", `Port to send the request to. Defaults to the first exposed TCP port.`).
+			ArgDoc("expectedStatus", `HTTP status code that indicates the service is healthy.`).
+			ArgDoc("interval", `Number of seconds to wait between attempts.`).
+			ArgDoc("retries", `Number of consecutive failed attempts after which the service is considered unhealthy.`).
+			ArgDoc("startPeriod",
+				`Number of seconds to give the service to initialize, during which failed attempts don't count towards retries.`),
+
+		dagql.Func("withoutHealthcheck", s.withoutHealthcheck).
+			Doc(`Removes the health check configured with withHealthcheck or withHTTPHealthcheck.`),
+
 		dagql.Func("exposedPorts", s.exposedPorts).
 			Doc(`Retrieves the list of exposed ports.`,
 				`This includes ports already exposed by the image, even if not explicitly added with dagger.`),
@@ -961,6 +1065,20 @@ func (s *containerSchema) stderrLegacy(ctx context.Context, parent *core.Contain
 	return out, err
 }
 
+type containerWithResourceLimitsArgs struct {
+	CPUQuota    float64 `name:"cpuQuota" default:"0"`
+	MemoryLimit int     `default:"0"`
+	PidsLimit   int     `default:"0"`
+}
+
+func (s *containerSchema) withResourceLimits(ctx context.Context, parent *core.Container, args containerWithResourceLimitsArgs) (*core.Container, error) {
+	return parent.WithResourceLimits(ctx, core.ContainerResourceLimits{
+		CPUQuota:    args.CPUQuota,
+		MemoryLimit: int64(args.MemoryLimit),
+		PidsLimit:   int64(args.PidsLimit),
+	})
+}
+
 type containerGpuArgs struct {
 	core.ContainerGPUOpts
 }
@@ -1313,19 +1431,59 @@ type containerPublishArgs struct {
 	PlatformVariants  []core.ContainerID `default:"[]"`
 	ForcedCompression dagql.Optional[core.ImageLayerCompression]
 	MediaTypes        core.ImageMediaTypes `default:"OCIMediaTypes"`
+	Sign              dagql.Optional[core.SecretID]
+	SignPassword      dagql.Optional[core.SecretID]
+	Attestations      []core.ImageAttestation `default:"[]"`
 }
 
-func (s *containerSchema) publish(ctx context.Context, parent *core.Container, args containerPublishArgs) (dagql.String, error) {
+func (s *containerSchema) publish(ctx context.Context, parent dagql.Instance[*core.Container], args containerPublishArgs) (dagql.String, error) {
 	variants, err := dagql.LoadIDs(ctx, s.srv, args.PlatformVariants)
 	if err != nil {
 		return "", err
 	}
-	ref, err := parent.Publish(
+
+	var signer *core.ImageSigner
+	if args.Sign.Valid {
+		secretStore, err := parent.Self.Query.Secrets(ctx)
+		if err != nil {
+			return "", err
+		}
+		key, err := args.Sign.Value.Load(ctx, s.srv)
+		if err != nil {
+			return "", err
+		}
+		keyBytes, err := secretStore.GetSecretPlaintext(ctx, key.Self.IDDigest)
+		if err != nil {
+			return "", err
+		}
+		var password []byte
+		if args.SignPassword.Valid {
+			passwordSecret, err := args.SignPassword.Value.Load(ctx, s.srv)
+			if err != nil {
+				return "", err
+			}
+			password, err = secretStore.GetSecretPlaintext(ctx, passwordSecret.Self.IDDigest)
+			if err != nil {
+				return "", err
+			}
+		}
+		signer, err = core.NewImageSigner(keyBytes, password)
+		if err != nil {
+			return "", err
+		}
+	} else if args.SignPassword.Valid {
+		return "", errors.New("signPassword is only used with sign")
+	}
+
+	ref, err := parent.Self.Publish(
 		ctx,
 		args.Address.String(),
 		variants,
 		args.ForcedCompression.Value,
 		args.MediaTypes,
+		signer,
+		args.Attestations,
+		parent.ID(),
 	)
 	if err != nil {
 		return "", err
@@ -1484,6 +1642,43 @@ func (s *containerSchema) file(ctx context.Context, parent *core.Container, args
 	return parent.File(ctx, path)
 }
 
+type containerExistsArgs struct {
+	Path         string
+	ExpectedType dagql.Optional[core.FileType]
+	Expand       bool `default:"false"`
+}
+
+func (s *containerSchema) exists(ctx context.Context, parent *core.Container, args containerExistsArgs) (dagql.Boolean, error) {
+	path, err := expandEnvVar(ctx, parent, args.Path, args.Expand)
+	if err != nil {
+		return false, err
+	}
+
+	exists, err := parent.Exists(ctx, path, args.ExpectedType.Value)
+	if err != nil {
+		return false, err
+	}
+	return dagql.NewBoolean(exists), nil
+}
+
+type containerStatArgs struct {
+	Path   string
+	Expand bool `default:"false"`
+}
+
+func (s *containerSchema) stat(ctx context.Context, parent *core.Container, args containerStatArgs) (core.FileInfo, error) {
+	path, err := expandEnvVar(ctx, parent, args.Path, args.Expand)
+	if err != nil {
+		return core.FileInfo{}, err
+	}
+
+	info, err := parent.StatPath(ctx, path, false)
+	if err != nil {
+		return core.FileInfo{}, err
+	}
+	return *info, nil
+}
+
 func absPath(workDir string, containerPath string) string {
 	if path.IsAbs(containerPath) {
 		return containerPath
@@ -1575,8 +1770,9 @@ func (s *containerSchema) withMountedSecret(ctx context.Context, parent *core.Co
 
 type containerWithDirectoryArgs struct {
 	WithDirectoryArgs
-	Owner  string `default:""`
-	Expand bool   `default:"false"`
+	Owner    string   `default:""`
+	OwnerMap []string `default:"[]"`
+	Expand   bool     `default:"false"`
 }
 
 func (s *containerSchema) withDirectory(ctx context.Context, parent *core.Container, args containerWithDirectoryArgs) (*core.Container, error) {
@@ -1590,13 +1786,14 @@ func (s *containerSchema) withDirectory(ctx context.Context, parent *core.Contai
 		return nil, err
 	}
 
-	return parent.WithDirectory(ctx, path, dir.Self, args.CopyFilter, args.Owner)
+	return parent.WithDirectory(ctx, path, dir.Self, args.CopyFilter, args.Owner, args.OwnerMap)
 }
 
 type containerWithFileArgs struct {
 	WithFileArgs
-	Owner  string `default:""`
-	Expand bool   `default:"false"`
+	Owner    string   `default:""`
+	OwnerMap []string `default:"[]"`
+	Expand   bool     `default:"false"`
 }
 
 func (s *containerSchema) withFile(ctx context.Context, parent *core.Container, args containerWithFileArgs) (*core.Container, error) {
@@ -1610,13 +1807,14 @@ func (s *containerSchema) withFile(ctx context.Context, parent *core.Container,
 		return nil, err
 	}
 
-	return parent.WithFile(ctx, path, file.Self, args.Permissions, args.Owner)
+	return parent.WithFile(ctx, path, file.Self, args.Permissions, args.Owner, args.OwnerMap)
 }
 
 type containerWithFilesArgs struct {
 	WithFilesArgs
-	Owner  string `default:""`
-	Expand bool   `default:"false"`
+	Owner    string   `default:""`
+	OwnerMap []string `default:"[]"`
+	Expand   bool     `default:"false"`
 }
 
 func (s *containerSchema) withFiles(ctx context.Context, parent *core.Container, args containerWithFilesArgs) (*core.Container, error) {
@@ -1634,7 +1832,7 @@ func (s *containerSchema) withFiles(ctx context.Context, parent *core.Container,
 		return nil, err
 	}
 
-	return parent.WithFiles(ctx, path, files, args.Permissions, args.Owner)
+	return parent.WithFiles(ctx, path, files, args.Permissions, args.Owner, args.OwnerMap)
 }
 
 type containerWithoutDirectoryArgs struct {
@@ -1711,6 +1909,21 @@ func (s *containerSchema) withNewFileLegacy(ctx context.Context, parent *core.Co
 	return parent.WithNewFile(ctx, args.Path, []byte(args.Contents), fs.FileMode(args.Permissions), args.Owner)
 }
 
+type containerWithSymlinkArgs struct {
+	Target   string
+	LinkName string
+	Expand   bool `default:"false"`
+}
+
+func (s *containerSchema) withSymlink(ctx context.Context, parent *core.Container, args containerWithSymlinkArgs) (*core.Container, error) {
+	linkName, err := expandEnvVar(ctx, parent, args.LinkName, args.Expand)
+	if err != nil {
+		return nil, err
+	}
+
+	return parent.WithSymlink(ctx, args.Target, linkName)
+}
+
 type containerWithUnixSocketArgs struct {
 	Path   string
 	Source core.SocketID
@@ -2045,6 +2258,49 @@ func (s *containerSchema) withoutExposedPort(ctx context.Context, parent *core.C
 	return parent.WithoutExposedPort(args.Port, args.Protocol)
 }
 
+type containerHealthcheckTimingArgs struct {
+	Interval    int `default:"1"`
+	Retries     int `default:"10"`
+	StartPeriod int `default:"0"`
+}
+
+func (args containerHealthcheckTimingArgs) apply(hc core.ContainerHealthcheck) core.ContainerHealthcheck {
+	hc.Interval = time.Duration(args.Interval) * time.Second
+	hc.Retries = args.Retries
+	hc.StartPeriod = time.Duration(args.StartPeriod) * time.Second
+	return hc
+}
+
+type containerWithHealthcheckArgs struct {
+	Args []string
+	containerHealthcheckTimingArgs
+}
+
+func (s *containerSchema) withHealthcheck(ctx context.Context, parent *core.Container, args containerWithHealthcheckArgs) (*core.Container, error) {
+	return parent.WithHealthcheck(ctx, args.apply(core.ContainerHealthcheck{
+		Args: args.Args,
+	}))
+}
+
+type containerWithHTTPHealthcheckArgs struct {
+	Path           string
+	Port           int `default:"0"`
+	ExpectedStatus int `default:"200"`
+	containerHealthcheckTimingArgs
+}
+
+func (s *containerSchema) withHTTPHealthcheck(ctx context.Context, parent *core.Container, args containerWithHTTPHealthcheckArgs) (*core.Container, error) {
+	return parent.WithHealthcheck(ctx, args.apply(core.ContainerHealthcheck{
+		HTTPPath:   args.Path,
+		HTTPPort:   args.Port,
+		HTTPStatus: args.ExpectedStatus,
+	}))
+}
+
+func (s *containerSchema) withoutHealthcheck(ctx context.Context, parent *core.Container, args struct{}) (*core.Container, error) {
+	return parent.WithoutHealthcheck(ctx)
+}
+
 func (s *containerSchema) exposedPorts(ctx context.Context, parent *core.Container, args struct{}) ([]core.Port, error) {
 	// get descriptions from `Container.Ports` (not in the OCI spec)
 	ports := make(map[string]core.Port, len(parent.Ports))
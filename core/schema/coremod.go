@@ -76,6 +76,37 @@ func (m *CoreMod) ModTypeFor(ctx context.Context, typeDef *core.TypeDef, checkDi
 			Underlying: underlyingType,
 		}
 
+	case core.TypeDefKindMap:
+		underlyingType, ok, err := m.ModTypeFor(ctx, typeDef.AsMap.Value.ValueTypeDef, checkDirectDeps)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get underlying type: %w", err)
+		}
+		if !ok {
+			return nil, false, nil
+		}
+		modType = &core.MapType{
+			Value:      typeDef.AsMap.Value.ValueTypeDef,
+			Underlying: underlyingType,
+		}
+
+	case core.TypeDefKindUnion:
+		union := typeDef.AsUnion.Value
+		members := make(map[string]core.ModType, len(union.Members))
+		for _, member := range union.Members {
+			memberType, ok, err := m.ModTypeFor(ctx, member.TypeDef, checkDirectDeps)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to get type of member %q: %w", member.Name, err)
+			}
+			if !ok {
+				return nil, false, nil
+			}
+			members[member.Name] = memberType
+		}
+		modType = &core.UnionType{
+			Def:     union,
+			Members: members,
+		}
+
 	case core.TypeDefKindScalar:
 		_, ok := m.Dag.ScalarType(typeDef.AsScalar.Value.Name)
 		if !ok {
@@ -145,6 +176,10 @@ func (m *CoreMod) TypeDefs(ctx context.Context) ([]*core.TypeDef, error) {
 				fn := &core.Function{
 					Name:        introspectionField.Name,
 					Description: introspectionField.Description,
+					CachePolicy: core.FunctionCachePolicyDefault,
+				}
+				if introspectionField.IsDeprecated {
+					fn.Deprecated = &introspectionField.DeprecationReason
 				}
 
 				rtType, ok, err := introspectionRefToTypeDef(introspectionField.TypeRef, false, false)
@@ -161,6 +196,9 @@ func (m *CoreMod) TypeDefs(ctx context.Context) ([]*core.TypeDef, error) {
 						Name:        introspectionArg.Name,
 						Description: introspectionArg.Description,
 					}
+					if introspectionArg.IsDeprecated {
+						fnArg.Deprecated = &introspectionArg.DeprecationReason
+					}
 
 					if introspectionArg.DefaultValue != nil {
 						fnArg.DefaultValue = core.JSON(*introspectionArg.DefaultValue)
@@ -200,6 +238,9 @@ func (m *CoreMod) TypeDefs(ctx context.Context) ([]*core.TypeDef, error) {
 					Name:        introspectionField.Name,
 					Description: introspectionField.Description,
 				}
+				if introspectionField.IsDeprecated {
+					field.Deprecated = &introspectionField.DeprecationReason
+				}
 				fieldType, ok, err := introspectionRefToTypeDef(introspectionField.TypeRef, false, false)
 				if err != nil {
 					return nil, fmt.Errorf("failed to convert return type: %w", err)
@@ -222,10 +263,14 @@ func (m *CoreMod) TypeDefs(ctx context.Context) ([]*core.TypeDef, error) {
 			}
 
 			for _, value := range introspectionType.EnumValues {
-				typedef.Values = append(typedef.Values, &core.EnumValueTypeDef{
+				enumValue := &core.EnumValueTypeDef{
 					Name:        value.Name,
 					Description: value.Description,
-				})
+				}
+				if value.IsDeprecated {
+					enumValue.Deprecated = &value.DeprecationReason
+				}
+				typedef.Values = append(typedef.Values, enumValue)
 			}
 
 			typeDefs = append(typeDefs, &core.TypeDef{
@@ -40,6 +40,30 @@ func (s *directorySchema) Install() {
 		dagql.Func("glob", s.glob).
 			Doc(`Returns a list of files and directories that matche the given pattern.`).
 			ArgDoc("pattern", `Pattern to match (e.g., "*.md").`),
+		dagql.Func("search", s.search).
+			Doc(`Searches the contents of the files in this directory for a pattern.`,
+				`Binary files are skipped. Results are ordered by file path, then by
+				position within the file.`).
+			ArgDoc("pattern", `Regular expression to search for (e.g., "TODO|FIXME"), in RE2 syntax.`).
+			ArgDoc("literal", `Treat the pattern as a literal string rather than a regular expression.`).
+			ArgDoc("paths", `Only search within these paths (e.g., ["src/", "README.md"]).`).
+			ArgDoc("globs", `Only search files whose path matches one of these glob patterns (e.g., ["**/*.go"]).`).
+			ArgDoc("caseInsensitive", `Match the pattern case-insensitively.`).
+			ArgDoc("limit", `Stop after this many results. If 0 (the default), return all results.`),
+		dagql.Func("filter", s.filter).
+			Doc(`Retrieves this directory with only the artifacts that pass the given filters.`,
+				`Ignore files are read from the directory itself, so paths in them are
+				relative to the directory containing each ignore file. As with git, an
+				artifact inside an ignored directory cannot be re-included, unless
+				it's ignored by .dockerignore.`).
+			ArgDoc("exclude", `Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).`).
+			ArgDoc("include", `Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).`).
+			ArgDoc("gitignore", `Exclude artifacts ignored by .gitignore files in the directory, and by .git/info/exclude.`).
+			ArgDoc("ignoreFiles",
+				`Exclude artifacts ignored by ignore files with these names (e.g., [".dockerignore"]).`,
+				`They are read from every subdirectory, using .gitignore syntax, except
+				for .dockerignore, which is only read from the directory itself, using
+				the syntax of docker build.`),
 		dagql.Func("digest", s.digest).
 			Doc(
 				`Return the directory's digest.
@@ -49,6 +73,16 @@ func (s *directorySchema) Install() {
 		dagql.Func("file", s.file).
 			Doc(`Retrieves a file at the given path.`).
 			ArgDoc("path", `Location of the file to retrieve (e.g., "README.md").`),
+		dagql.Func("exists", s.exists).
+			Doc(`Returns whether a file, directory or other entry exists at the given path.`).
+			ArgDoc("path", `Location to check (e.g., "README.md").`).
+			ArgDoc("expectedType",
+				`If set, the entry must also be of this type.`,
+				`Symlinks are followed, unless the expected type is SYMLINK.`),
+		dagql.Func("stat", s.stat).
+			Doc(`Returns information about the entry at the given path.`,
+				`A symlink at the path is described itself, rather than its target.`).
+			ArgDoc("path", `Location of the entry (e.g., "README.md").`),
 		dagql.Func("withFile", s.withFile).
 			Doc(`Retrieves this directory plus the contents of the given file copied to the given path.`).
 			ArgDoc("path", `Location of the copied file (e.g., "/file.txt").`).
@@ -64,6 +98,10 @@ func (s *directorySchema) Install() {
 			ArgDoc("path", `Location of the written file (e.g., "/file.txt").`).
 			ArgDoc("contents", `Content of the written file (e.g., "Hello world!").`).
 			ArgDoc("permissions", `Permission given to the copied file (e.g., 0600).`),
+		dagql.Func("withSymlink", s.withSymlink).
+			Doc(`Retrieves this directory plus a symbolic link at the given path.`).
+			ArgDoc("target", `Location the symbolic link points to (e.g., "../lib/libfoo.so.1").`).
+			ArgDoc("linkName", `Location of the symbolic link (e.g., "/lib/libfoo.so").`),
 		dagql.Func("withoutFile", s.withoutFile).
 			Doc(`Retrieves this directory with the file at the given path removed.`).
 			ArgDoc("path", `Location of the file to remove (e.g., "/file.txt").`),
@@ -89,6 +127,12 @@ func (s *directorySchema) Install() {
 		dagql.Func("diff", s.diff).
 			Doc(`Gets the difference between this directory and an another directory.`).
 			ArgDoc("other", `Identifier of the directory to compare.`),
+		dagql.Func("asArchive", s.asArchive).
+			Doc(`Packs the contents of this directory into an archive file.`).
+			ArgDoc("format", `Format of the archive.`).
+			ArgDoc("reproducible",
+				`If true, modification times, ownership and entry order are normalized
+				so that archiving the same contents always produces the same bytes.`),
 		dagql.Func("export", s.export).
 			View(AllVersion).
 			Impure("Writes to the local host.").
@@ -209,6 +253,34 @@ func (s *directorySchema) glob(ctx context.Context, parent *core.Directory, args
 	return parent.Glob(ctx, args.Pattern)
 }
 
+type searchArgs struct {
+	Pattern         string
+	Literal         bool     `default:"false"`
+	Paths           []string `default:"[]"`
+	Globs           []string `default:"[]"`
+	CaseInsensitive bool     `default:"false"`
+	Limit           int      `default:"0"`
+}
+
+func (s *directorySchema) search(ctx context.Context, parent *core.Directory, args searchArgs) ([]core.SearchResult, error) {
+	return parent.Search(ctx, args.Pattern, core.SearchOpts{
+		Literal:         args.Literal,
+		Paths:           args.Paths,
+		Globs:           args.Globs,
+		CaseInsensitive: args.CaseInsensitive,
+		Limit:           args.Limit,
+	})
+}
+
+type filterArgs struct {
+	core.CopyFilter
+	core.IgnoreFilter
+}
+
+func (s *directorySchema) filter(ctx context.Context, parent *core.Directory, args filterArgs) (*core.Directory, error) {
+	return parent.Filter(ctx, args.CopyFilter, args.IgnoreFilter)
+}
+
 func (s *directorySchema) digest(ctx context.Context, parent *core.Directory, args struct{}) (dagql.String, error) {
 	digest, err := parent.Digest(ctx)
 	if err != nil {
@@ -218,6 +290,31 @@ func (s *directorySchema) digest(ctx context.Context, parent *core.Directory, ar
 	return dagql.NewString(digest), nil
 }
 
+type dirExistsArgs struct {
+	Path         string
+	ExpectedType dagql.Optional[core.FileType]
+}
+
+func (s *directorySchema) exists(ctx context.Context, parent *core.Directory, args dirExistsArgs) (dagql.Boolean, error) {
+	exists, err := parent.Exists(ctx, args.Path, args.ExpectedType.Value)
+	if err != nil {
+		return false, err
+	}
+	return dagql.NewBoolean(exists), nil
+}
+
+type dirStatArgs struct {
+	Path string
+}
+
+func (s *directorySchema) stat(ctx context.Context, parent *core.Directory, args dirStatArgs) (core.FileInfo, error) {
+	info, err := parent.StatPath(ctx, args.Path, false)
+	if err != nil {
+		return core.FileInfo{}, err
+	}
+	return *info, nil
+}
+
 type dirFileArgs struct {
 	Path string
 }
@@ -234,6 +331,13 @@ func (s *directorySchema) withNewFile(ctx context.Context, parent *core.Director
 	return parent.WithNewFile(ctx, args.Path, []byte(args.Contents), fs.FileMode(args.Permissions), nil)
 }
 
+func (s *directorySchema) withSymlink(ctx context.Context, parent *core.Directory, args struct {
+	Target   string
+	LinkName string
+}) (*core.Directory, error) {
+	return parent.WithSymlink(ctx, args.Target, args.LinkName)
+}
+
 type WithFileArgs struct {
 	Path        string
 	Source      core.FileID
@@ -304,6 +408,15 @@ func (s *directorySchema) diff(ctx context.Context, parent *core.Directory, args
 	return parent.Diff(ctx, dir.Self)
 }
 
+type dirAsArchiveArgs struct {
+	Format       core.ArchiveFormat `default:"TAR_GZ"`
+	Reproducible bool               `default:"true"`
+}
+
+func (s *directorySchema) asArchive(ctx context.Context, parent *core.Directory, args dirAsArchiveArgs) (*core.File, error) {
+	return parent.AsArchive(ctx, args.Format, args.Reproducible)
+}
+
 type dirExportArgs struct {
 	Path string
 	Wipe bool `default:"false"`
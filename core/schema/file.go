@@ -46,6 +46,10 @@ func (s *fileSchema) Install() {
 		dagql.Func("export", s.exportLegacy).
 			View(BeforeVersion("v0.12.0")).
 			Extend(),
+		dagql.Func("extract", s.extract).
+			Doc(`Unpacks this archive file into a directory.`,
+				`Supports tar archives, optionally compressed with gzip or zstd, and
+				zip archives. The format is detected from the file's contents.`),
 		dagql.Func("withTimestamps", s.withTimestamps).
 			Doc(`Retrieves this file with its created/modified timestamps set to the given time.`).
 			ArgDoc("timestamp", `Timestamp to set dir/files in.`,
@@ -96,6 +100,10 @@ func (s *fileSchema) withName(ctx context.Context, parent *core.File, args fileW
 	return parent.WithName(ctx, args.Name)
 }
 
+func (s *fileSchema) extract(ctx context.Context, parent *core.File, args struct{}) (*core.Directory, error) {
+	return parent.Extract(ctx)
+}
+
 type fileExportArgs struct {
 	Path               string
 	AllowParentDirPath bool `default:"false"`
@@ -11,6 +11,7 @@ import (
 	"log/slog"
 	"os"
 	"os/exec"
+	"path"
 	"regexp"
 	"strings"
 
@@ -44,7 +45,13 @@ func (s *gitSchema) Install() {
 			ArgDeprecated("keepGitDir", `Set to true to keep .git directory.`).
 			ArgDoc("sshKnownHosts", `Set SSH known hosts`).
 			ArgDoc("sshAuthSocket", `Set SSH auth socket`).
-			ArgDoc("experimentalServiceHost", `A service which must be started before the repo is fetched.`),
+			ArgDoc("experimentalServiceHost", `A service which must be started before the repo is fetched.`).
+			ArgDoc("depth", `Default number of commits of history to fetch into the .git directory of trees.`,
+				`Set to -1 to fetch the full history.`).
+			ArgDoc("sparsePaths", `Only check out these paths of trees by default, relative to the root of the repository.`).
+			ArgDoc("filter", `A default partial clone filter for trees (e.g., "blob:none", "blob:limit=1m" or "tree:0").`,
+				`Objects left out by the filter are fetched on demand, e.g. when checking out.`).
+			ArgDoc("submodules", `Set to false to skip checking out submodules in trees by default.`),
 		dagql.NodeFuncWithCacheKey("git", s.gitLegacy, nil).
 			View(BeforeVersion("v0.13.4")).
 			Doc(`Queries a Git repository.`).
@@ -77,6 +84,22 @@ func (s *gitSchema) Install() {
 			Doc(`Returns details of a commit.`).
 			// TODO: id is normally a reserved word; we should probably rename this
 			ArgDoc("id", `Identifier of the commit (e.g., "b6315d8f2810962c601af73f86831f6866ea798b").`),
+		dagql.Func("diff", s.diff).
+			Doc(`The paths of the files that differ between two refs.`).
+			ArgDoc("from", `The ref to compare from (a commit, tag, branch or fully-qualified ref).`).
+			ArgDoc("to", `The ref to compare to (a commit, tag, branch or fully-qualified ref).`),
+		dagql.Func("uncommittedChanges", s.uncommittedChanges).
+			Doc(`The paths in the working tree that differ from the index or HEAD,
+				including untracked files that aren't ignored.`,
+				`Only available for local repositories (see "host.gitRepository").`),
+		dagql.Func("push", s.push).
+			Impure("Writes to the remote repository.").
+			Doc(`Pushes the commit of a ref to the remote repository, using its
+				credentials, and returns the commit id.`).
+			ArgDoc("ref", `The ref to push, e.g. one returned by "GitRef.withChanges".`).
+			ArgDoc("remoteRef", `The ref to update on the remote (e.g., "main" or "refs/tags/v0.3.9").
+				Names that aren't fully-qualified are taken as branches.`).
+			ArgDoc("force", `Update the remote ref even if it isn't an ancestor of the commit.`),
 		dagql.Func("withAuthToken", s.withAuthToken).
 			Doc(`Token to authenticate the remote with.`).
 			ArgDoc("token", `Secret used to populate the password during basic HTTP Authorization`),
@@ -89,7 +112,17 @@ func (s *gitSchema) Install() {
 		dagql.Func("tree", s.tree).
 			View(AllVersion).
 			Doc(`The filesystem tree at this ref.`).
-			ArgDoc("discardGitDir", `Set to true to discard .git directory.`),
+			ArgDoc("discardGitDir", `Set to true to discard .git directory.`).
+			ArgDoc("depth", `Number of commits of history to fetch into the .git directory.`,
+				`Set to -1 to fetch the full history.`,
+				`Defaults to the repository's (see "git").`).
+			ArgDoc("sparsePaths", `Only check out these paths, relative to the root of the repository.`,
+				`Defaults to the repository's (see "git").`).
+			ArgDoc("filter", `A partial clone filter (e.g., "blob:none", "blob:limit=1m" or "tree:0").`,
+				`Objects left out by the filter are fetched on demand, e.g. when checking out.`,
+				`Defaults to the repository's (see "git").`).
+			ArgDoc("submodules", `Set to false to skip checking out submodules.`,
+				`Defaults to the repository's (see "git").`),
 		dagql.Func("tree", s.treeLegacy).
 			View(BeforeVersion("v0.12.0")).
 			Doc(`The filesystem tree at this ref.`).
@@ -98,6 +131,20 @@ func (s *gitSchema) Install() {
 			ArgDeprecated("sshAuthSocket", "This option should be passed to `git` instead."),
 		dagql.Func("commit", s.fetchCommit).
 			Doc(`The resolved commit id at this ref.`),
+		dagql.Func("log", s.log).
+			Doc(`The commits reachable from this ref, newest first.`).
+			ArgDoc("limit", `Maximum number of commits to return. Zero means no limit.`),
+		dagql.Func("describe", s.describe).
+			Doc(`The most recent tag reachable from this ref, like "git describe --tags".`,
+				`If the ref isn't tagged itself, the tag is followed by the number of commits
+				since it and the abbreviated commit id (e.g., "v0.3.9-14-g2414721"). If there
+				are no tags, only the abbreviated commit id is returned.`),
+		dagql.Func("withChanges", s.withChanges).
+			Doc(`A ref to a new commit on top of this one, recording the contents of a directory.`,
+				`The commit is only kept in the engine until it's pushed (see "GitRepository.push").`).
+			ArgDoc("directory", `The full contents of the repository at the new commit.`).
+			ArgDoc("message", `The commit message.`).
+			ArgDoc("author", `The author and committer, as "Name <email>".`),
 	}.Install(s.srv)
 }
 
@@ -108,10 +155,20 @@ type gitArgs struct {
 
 	SSHKnownHosts string                        `name:"sshKnownHosts" default:""`
 	SSHAuthSocket dagql.Optional[core.SocketID] `name:"sshAuthSocket"`
+
+	Depth       int      `default:"1"`
+	SparsePaths []string `default:"[]"`
+	Filter      string   `default:""`
+	Submodules  bool     `default:"true"`
 }
 
 //nolint:gocyclo
 func (s *gitSchema) git(ctx context.Context, parent dagql.Instance[*core.Query], args gitArgs) (inst dagql.Instance[*core.GitRepository], err error) {
+	checkoutOpts, err := gitCheckoutOpts(args.Depth, args.SparsePaths, args.Filter, args.Submodules)
+	if err != nil {
+		return inst, err
+	}
+
 	// 1. Setup experimental service host
 	var svcs core.ServiceBindings
 	if args.ExperimentalServiceHost.Valid {
@@ -259,6 +316,7 @@ func (s *gitSchema) git(ctx context.Context, parent dagql.Instance[*core.Query],
 		Query:         parent.Self,
 		URL:           args.URL,
 		DiscardGitDir: discardGitDir,
+		CheckoutOpts:  checkoutOpts,
 		SSHKnownHosts: args.SSHKnownHosts,
 		SSHAuthSocket: authSock,
 		Services:      svcs,
@@ -318,6 +376,8 @@ func (s *gitSchema) gitLegacy(ctx context.Context, parent dagql.Instance[*core.Q
 		ExperimentalServiceHost: args.ExperimentalServiceHost,
 		SSHKnownHosts:           args.SSHKnownHosts,
 		SSHAuthSocket:           args.SSHAuthSocket,
+		Depth:                   1,
+		Submodules:              true,
 	})
 }
 
@@ -381,6 +441,16 @@ type tagsArgs struct {
 }
 
 func (s *gitSchema) tags(ctx context.Context, parent *core.GitRepository, args tagsArgs) ([]string, error) {
+	if parent.LocalGitDir != nil {
+		var patterns []string
+		if args.Patterns.Valid {
+			for _, p := range args.Patterns.Value.ToArray() {
+				patterns = append(patterns, p.String())
+			}
+		}
+		return parent.LocalTags(ctx, patterns)
+	}
+
 	// standardize to the same ref that goes into the state (see llb.Git)
 	remote, err := gitutil.ParseURL(parent.URL)
 	if errors.Is(err, gitutil.ErrUnknownProtocol) {
@@ -520,10 +590,72 @@ func (s *gitSchema) withAuthHeader(ctx context.Context, parent *core.GitReposito
 
 type treeArgs struct {
 	DiscardGitDir bool `default:"false"`
+
+	Depth       dagql.Optional[dagql.Int]
+	SparsePaths dagql.Optional[dagql.ArrayInput[dagql.String]]
+	Filter      dagql.Optional[dagql.String]
+	Submodules  dagql.Optional[dagql.Boolean]
 }
 
+var gitFilterRegex = regexp.MustCompile(`^(blob:none|blob:limit=[0-9]+[kmg]?|tree:[0-9]+)$`)
+
 func (s *gitSchema) tree(ctx context.Context, parent *core.GitRef, args treeArgs) (*core.Directory, error) {
-	return parent.Tree(ctx, args.DiscardGitDir)
+	depth := parent.Repo.CheckoutOpts.Depth
+	if depth == 0 {
+		depth = 1
+	}
+	if args.Depth.Valid {
+		depth = args.Depth.Value.Int()
+	}
+	sparsePaths := parent.Repo.CheckoutOpts.SparsePaths
+	if args.SparsePaths.Valid {
+		sparsePaths = nil
+		for _, p := range args.SparsePaths.Value.ToArray() {
+			sparsePaths = append(sparsePaths, p.String())
+		}
+	}
+	filter := parent.Repo.CheckoutOpts.Filter
+	if args.Filter.Valid {
+		filter = args.Filter.Value.String()
+	}
+	submodules := !parent.Repo.CheckoutOpts.DiscardSubmodules
+	if args.Submodules.Valid {
+		submodules = args.Submodules.Value.Bool()
+	}
+	checkoutOpts, err := gitCheckoutOpts(depth, sparsePaths, filter, submodules)
+	if err != nil {
+		return nil, err
+	}
+	return parent.Tree(ctx, args.DiscardGitDir, checkoutOpts)
+}
+
+// gitCheckoutOpts validates the options for checking out a tree.
+func gitCheckoutOpts(depth int, sparsePaths []string, filter string, submodules bool) (core.GitCheckoutOpts, error) {
+	if depth == 0 || depth < -1 {
+		return core.GitCheckoutOpts{}, fmt.Errorf("invalid depth %d: must be positive, or -1 for the full history", depth)
+	}
+	if filter != "" && !gitFilterRegex.MatchString(filter) {
+		return core.GitCheckoutOpts{}, fmt.Errorf("invalid filter %q: expected blob:none, blob:limit=<n> or tree:<depth>", filter)
+	}
+	paths := make([]string, 0, len(sparsePaths))
+	for _, p := range sparsePaths {
+		p = path.Clean(strings.TrimPrefix(p, "/"))
+		if p == "." {
+			// the whole repository
+			paths = nil
+			break
+		}
+		if p == ".." || strings.HasPrefix(p, "../") {
+			return core.GitCheckoutOpts{}, fmt.Errorf("invalid sparse path %q: must be within the repository", p)
+		}
+		paths = append(paths, p)
+	}
+	return core.GitCheckoutOpts{
+		Depth:             depth,
+		SparsePaths:       paths,
+		Filter:            filter,
+		DiscardSubmodules: !submodules,
+	}, nil
 }
 
 type treeArgsLegacy struct {
@@ -549,7 +681,117 @@ func (s *gitSchema) treeLegacy(ctx context.Context, parent *core.GitRef, args tr
 		cp.SSHAuthSocket = authSock
 		res.Repo = &cp
 	}
-	return res.Tree(ctx, args.DiscardGitDir)
+	return res.Tree(ctx, args.DiscardGitDir, core.GitCheckoutOpts{})
+}
+
+type gitDiffArgs struct {
+	From string
+	To   string
+}
+
+func (s *gitSchema) diff(ctx context.Context, parent *core.GitRepository, args gitDiffArgs) (dagql.Array[dagql.String], error) {
+	paths, err := parent.Diff(ctx, args.From, args.To)
+	if err != nil {
+		return nil, err
+	}
+	return dagql.NewStringArray(paths...), nil
+}
+
+func (s *gitSchema) uncommittedChanges(ctx context.Context, parent *core.GitRepository, _ struct{}) (dagql.Array[dagql.String], error) {
+	if parent.LocalGitDir == nil {
+		return nil, errors.New("uncommitted changes are only available for local repositories")
+	}
+	// only sync the working tree now that it's needed
+	var workTree dagql.Instance[*core.Directory]
+	if err := s.srv.Select(ctx, s.srv.Root(), &workTree, dagql.Selector{
+		Field: "host",
+	}, dagql.Selector{
+		Field: "directory",
+		Args: []dagql.NamedInput{
+			{
+				Name:  "path",
+				Value: dagql.NewString(parent.LocalPath),
+			},
+			{
+				Name:  "exclude",
+				Value: dagql.ArrayInput[dagql.String]{dagql.NewString(".git")},
+			},
+			{
+				Name:  "gitignore",
+				Value: dagql.NewBoolean(true),
+			},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to load working tree of %q: %w", parent.LocalPath, err)
+	}
+	paths, err := parent.UncommittedChanges(ctx, workTree.Self)
+	if err != nil {
+		return nil, err
+	}
+	return dagql.NewStringArray(paths...), nil
+}
+
+type logArgs struct {
+	Limit int `default:"0"`
+}
+
+func (s *gitSchema) log(ctx context.Context, parent *core.GitRef, args logArgs) ([]core.GitCommit, error) {
+	if args.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d: must not be negative", args.Limit)
+	}
+	return parent.Log(ctx, args.Limit)
+}
+
+type withChangesArgs struct {
+	Directory core.DirectoryID
+	Message   string
+	Author    string
+}
+
+var gitAuthorRegex = regexp.MustCompile(`^\s*([^<>]+?)\s*<([^<>]+)>\s*$`)
+
+func (s *gitSchema) withChanges(ctx context.Context, parent *core.GitRef, args withChangesArgs) (*core.GitRef, error) {
+	if strings.TrimSpace(args.Message) == "" {
+		return nil, errors.New("commit message must not be empty")
+	}
+	author := gitAuthorRegex.FindStringSubmatch(args.Author)
+	if author == nil {
+		return nil, fmt.Errorf("invalid author %q: must be \"Name <email>\"", args.Author)
+	}
+	dir, err := args.Directory.Load(ctx, s.srv)
+	if err != nil {
+		return nil, err
+	}
+	return parent.WithChanges(ctx, dir.Self, args.Message, author[1], author[2])
+}
+
+type pushArgs struct {
+	Ref       core.GitRefID
+	RemoteRef string
+	Force     bool `default:"false"`
+}
+
+func (s *gitSchema) push(ctx context.Context, parent *core.GitRepository, args pushArgs) (dagql.String, error) {
+	if args.RemoteRef == "" || strings.HasPrefix(args.RemoteRef, "-") {
+		return "", fmt.Errorf("invalid remote ref %q", args.RemoteRef)
+	}
+	ref, err := args.Ref.Load(ctx, s.srv)
+	if err != nil {
+		return "", err
+	}
+	commit, err := parent.Push(ctx, ref.Self, args.RemoteRef, args.Force)
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(commit), nil
+}
+
+func (s *gitSchema) describe(ctx context.Context, parent *core.GitRef, _ struct{}) (dagql.String, error) {
+	desc, err := parent.Describe(ctx)
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(desc), nil
 }
 
 func (s *gitSchema) fetchCommit(ctx context.Context, parent *core.GitRef, _ struct{}) (dagql.String, error) {
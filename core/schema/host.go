@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/engine/distconsts"
 	"github.com/dagger/dagger/engine/slog"
+	localsource "github.com/dagger/dagger/engine/sources/local"
 )
 
 type hostSchema struct {
@@ -147,7 +149,19 @@ func (s *hostSchema) Install() {
 			Doc(`Accesses a directory on the host.`).
 			ArgDoc("path", `Location of the directory to access (e.g., ".").`).
 			ArgDoc("exclude", `Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).`).
-			ArgDoc("include", `Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).`),
+			ArgDoc("include", `Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).`).
+			ArgDoc("gitignore", `Exclude artifacts ignored by .gitignore files in the directory, and by .git/info/exclude.`).
+			ArgDoc("ignoreFiles",
+				`Exclude artifacts ignored by ignore files with these names (e.g., [".dockerignore"]).`,
+				`They are read from every subdirectory, using .gitignore syntax, except
+				for .dockerignore, which is only read from the directory itself, using
+				the syntax of docker build.`),
+
+		dagql.NodeFuncWithCacheKey("gitRepository", s.gitRepository, core.CachePerClient).
+			Doc(`Accesses a git repository on the host, such as the current checkout.`,
+				`Its .git directory is synced from the host, so any local commit can be
+				checked out, without pushing it to a remote first.`).
+			ArgDoc("path", `Location of the root of the repository (e.g., ".").`),
 
 		dagql.FuncWithCacheKey("file", s.file, core.CachePerClient).
 			Doc(`Accesses a file on the host.`).
@@ -213,6 +227,7 @@ type hostDirectoryArgs struct {
 	Path string
 
 	core.CopyFilter
+	core.IgnoreFilter
 }
 
 func (s *hostSchema) directory(ctx context.Context, host dagql.Instance[*core.Host], args hostDirectoryArgs) (i dagql.Instance[*core.Directory], err error) {
@@ -247,9 +262,17 @@ func (s *hostSchema) directory(ctx context.Context, host dagql.Instance[*core.Ho
 		localName += fmt.Sprintf(" (exclude: %s)", strings.Join(args.Exclude, ", "))
 		localOpts = append(localOpts, llb.ExcludePatterns(args.Exclude))
 	}
+	if args.Gitignore {
+		localName += " (gitignore)"
+		localOpts = append(localOpts, localsource.Gitignore())
+	}
+	if len(args.IgnoreFiles) > 0 {
+		localName += fmt.Sprintf(" (ignore files: %s)", strings.Join(args.IgnoreFiles, ", "))
+		localOpts = append(localOpts, localsource.IgnoreFiles(args.IgnoreFiles))
+	}
 	localOpts = append(localOpts, llb.WithCustomName(localName))
 
-	localLLB := llb.Local(args.Path, localOpts...)
+	localLLB := localsource.Local(args.Path, localOpts...)
 	localDef, err := localLLB.Marshal(ctx, llb.Platform(host.Self.Query.Platform().Spec()))
 	if err != nil {
 		return i, fmt.Errorf("failed to marshal local LLB: %w", err)
@@ -270,6 +293,117 @@ func (s *hostSchema) directory(ctx context.Context, host dagql.Instance[*core.Ho
 	return core.MakeDirectoryContentHashed(ctx, bk, dir)
 }
 
+type hostGitRepositoryArgs struct {
+	Path string
+}
+
+func (s *hostSchema) gitRepository(ctx context.Context, host dagql.Instance[*core.Host], args hostGitRepositoryArgs) (*core.GitRepository, error) {
+	bk, err := host.Self.Query.Buildkit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+	gitDirPath, commonDirPath, err := resolveHostGitDir(ctx, bk, path.Join(args.Path, ".git"))
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a git repository: %w", args.Path, err)
+	}
+
+	var gitDir dagql.Instance[*core.Directory]
+	if commonDirPath == "" {
+		if err := s.srv.Select(ctx, host, &gitDir, dagql.Selector{
+			Field: "directory",
+			Args: []dagql.NamedInput{
+				{Name: "path", Value: dagql.NewString(gitDirPath)},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to load git directory of %q: %w", args.Path, err)
+		}
+	} else {
+		// a linked worktree only has its own HEAD, while its objects and refs
+		// are shared with the main worktree
+		var head dagql.Instance[*core.File]
+		if err := s.srv.Select(ctx, host, &head, dagql.Selector{
+			Field: "file",
+			Args: []dagql.NamedInput{
+				{Name: "path", Value: dagql.NewString(path.Join(gitDirPath, "HEAD"))},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to load git HEAD of %q: %w", args.Path, err)
+		}
+		if err := s.srv.Select(ctx, host, &gitDir, dagql.Selector{
+			Field: "directory",
+			Args: []dagql.NamedInput{
+				{Name: "path", Value: dagql.NewString(commonDirPath)},
+			},
+		}, dagql.Selector{
+			Field: "withFile",
+			Args: []dagql.NamedInput{
+				{Name: "path", Value: dagql.NewString("HEAD")},
+				{Name: "source", Value: dagql.NewID[*core.File](head.ID())},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to load git directory of %q: %w", args.Path, err)
+		}
+	}
+
+	ok, err := gitDir.Self.Exists(ctx, "HEAD", core.FileTypeFile)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%q is not a git repository", args.Path)
+	}
+	return &core.GitRepository{
+		Query:       host.Self.Query,
+		Platform:    host.Self.Query.Platform(),
+		LocalGitDir: gitDir.Self,
+		LocalPath:   args.Path,
+	}, nil
+}
+
+// resolveHostGitDir returns the git directory that the .git entry at
+// gitPath on the host stands for. In a linked worktree or a submodule, .git
+// is a file pointing to the actual git directory with a "gitdir:" line, and
+// a linked worktree's git directory also points to the directory it shares
+// with the main worktree, which is returned as commonDir.
+func resolveHostGitDir(ctx context.Context, bk *buildkit.Client, gitPath string) (gitDir string, commonDir string, _ error) {
+	stat, err := bk.StatCallerHostPath(ctx, gitPath, false)
+	if err != nil {
+		return "", "", err
+	}
+	if os.FileMode(stat.Mode).IsDir() {
+		return gitPath, "", nil
+	}
+
+	contents, err := bk.ReadCallerHostFile(ctx, gitPath)
+	if err != nil {
+		return "", "", err
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(contents)), "gitdir:")
+	if !ok {
+		return "", "", fmt.Errorf("invalid .git file %q: no gitdir", gitPath)
+	}
+	gitDir = resolveHostPath(path.Dir(gitPath), strings.TrimSpace(gitDir))
+
+	commonDirPath := path.Join(gitDir, "commondir")
+	if _, err := bk.StatCallerHostPath(ctx, commonDirPath, false); err != nil {
+		// not a linked worktree
+		return gitDir, "", nil
+	}
+	contents, err = bk.ReadCallerHostFile(ctx, commonDirPath)
+	if err != nil {
+		return "", "", err
+	}
+	return gitDir, resolveHostPath(gitDir, strings.TrimSpace(string(contents))), nil
+}
+
+// resolveHostPath resolves p relative to dir, unless it's absolute.
+func resolveHostPath(dir string, p string) string {
+	if path.IsAbs(p) || filepath.IsAbs(p) {
+		return p
+	}
+	return path.Join(dir, p)
+}
+
 type hostSocketArgs struct {
 	Path string
 }
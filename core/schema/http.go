@@ -2,6 +2,9 @@ package schema
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path"
 
 	"github.com/moby/buildkit/client/llb"
 	"github.com/opencontainers/go-digest"
@@ -23,13 +26,40 @@ func (s *httpSchema) Install() {
 		dagql.Func("http", s.http).
 			Doc(`Returns a file containing an http remote url content.`).
 			ArgDoc("url", `HTTP url to get the content from (e.g., "https://docs.dagger.io").`).
-			ArgDoc("experimentalServiceHost", `A service which must be started before the URL is fetched.`),
+			ArgDoc("experimentalServiceHost", `A service which must be started before the URL is fetched.`).
+			ArgDoc("checksum",
+				`Expected digest of the content (e.g., "sha256:..."). If set, the
+				call fails if the downloaded content doesn't match.`).
+			ArgDoc("authHeader", `Secret used to populate the Authorization HTTP header.`).
+			ArgDoc("headers", `Additional HTTP headers to send with the request.`).
+			ArgDoc("name", `Name of the resulting file. Defaults to a digest of the URL.`).
+			ArgDoc("permissions", `Permission given to the resulting file (e.g., 0600).`),
 	}.Install(s.srv)
+
+	dagql.MustInputSpec(HTTPHeader{}).Install(s.srv)
+}
+
+type HTTPHeader struct {
+	Name  string `field:"true" doc:"The header name."`
+	Value string `field:"true" doc:"The header value."`
+}
+
+func (HTTPHeader) TypeName() string {
+	return "HTTPHeader"
+}
+
+func (HTTPHeader) TypeDescription() string {
+	return "Key value object that represents an HTTP header."
 }
 
 type httpArgs struct {
 	URL                     string
 	ExperimentalServiceHost dagql.Optional[core.ServiceID]
+	Checksum                string `default:""`
+	AuthHeader              dagql.Optional[core.SecretID]
+	Headers                 []dagql.InputObject[HTTPHeader] `default:"[]"`
+	Name                    string                          `default:""`
+	Permissions             dagql.Optional[dagql.Int]
 }
 
 func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs) (*core.File, error) {
@@ -38,6 +68,12 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	// of following more optimized cache codepaths.
 	// Do a hash encode to prevent conflicts with use of `/` in the URL while also not hitting max filename limits
 	filename := digest.FromString(args.URL).Encoded()
+	if args.Name != "" {
+		if args.Name != path.Base(args.Name) || args.Name == "." || args.Name == ".." {
+			return nil, fmt.Errorf("invalid name %q: must be a file name, not a path", args.Name)
+		}
+		filename = args.Name
+	}
 
 	svcs := core.ServiceBindings{}
 	if args.ExperimentalServiceHost.Valid {
@@ -59,6 +95,31 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	opts := []llb.HTTPOption{
 		llb.Filename(filename),
 	}
+	if args.Checksum != "" {
+		dgst, err := digest.Parse(args.Checksum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checksum %q: %w", args.Checksum, err)
+		}
+		if dgst.Algorithm() != digest.SHA256 {
+			return nil, fmt.Errorf("invalid checksum %q: only %s is supported", args.Checksum, digest.SHA256)
+		}
+		opts = append(opts, llb.Checksum(dgst))
+	}
+	if args.Permissions.Valid {
+		opts = append(opts, llb.Chmod(os.FileMode(args.Permissions.Value.Int())))
+	}
+	for _, header := range collectInputsSlice(args.Headers) {
+		opts = append(opts, httpdns.Header(header.Name, header.Value))
+	}
+	if args.AuthHeader.Valid {
+		// only the secret's ID ends up in the LLB; the engine fetches its
+		// plaintext when making the request
+		secret, err := args.AuthHeader.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, httpdns.AuthHeaderSecret(secret.Self.LLBID()))
+	}
 
 	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
 	if err != nil {
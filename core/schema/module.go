@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -56,6 +57,11 @@ func (s *moduleSchema) Install() {
 		dagql.FuncWithCacheKey("returnValue", s.functionCallReturnValue, core.CachePerClient).
 			Doc(`Set the return value of the function call to the provided value.`).
 			ArgDoc("value", `JSON serialization of the return value.`),
+		dagql.Func("returnItem", s.functionCallReturnItem).
+			Impure("Streams an item to the caller.").
+			Doc(`Send an item of the result of a streaming function call to its caller as soon as it's available.`,
+				`The function must still return its full result with returnValue once done.`).
+			ArgDoc("value", `JSON serialization of the item: an element of a list, or a chunk of a string.`),
 		dagql.FuncWithCacheKey("returnError", s.functionCallReturnError, core.CachePerClient).
 			Doc(`Return an error from the function.`).
 			ArgDoc("error", `The error to return.`),
@@ -118,6 +124,22 @@ func (s *moduleSchema) Install() {
 			Doc(`Returns the function with the given source map.`).
 			ArgDoc("sourceMap", `The source map for the function definition.`),
 
+		dagql.Func("withCachePolicy", s.functionWithCachePolicy).
+			Doc(`Returns the function with the given cache policy.`).
+			ArgDoc("policy", `How the results of calls to the function are cached.`).
+			ArgDoc("timeToLive", `For the TTL policy, how long results are cached, as a duration (e.g., "10m", "1h30m").`),
+
+		dagql.Func("withDeprecated", s.functionWithDeprecated).
+			Doc(`Returns the function marked as deprecated.`).
+			ArgDoc("reason", `The reason the function is deprecated, usually including what to use instead.`),
+
+		dagql.Func("withStreaming", s.functionWithStreaming).
+			Doc(`Returns the function marked as streaming its result.`,
+				`Subscribers to the function receive the elements of its list result, or
+				the chunks of its string result, as soon as they are returned with
+				returnItem. Queries still receive the full result once the function is
+				done.`),
+
 		dagql.Func("withArg", s.functionWithArg).
 			Doc(`Returns the function with the provided argument`).
 			ArgDoc("name", `The name of the argument`).
@@ -125,7 +147,14 @@ func (s *moduleSchema) Install() {
 			ArgDoc("description", `A doc string for the argument, if any`).
 			ArgDoc("defaultValue", `A default value to use for this argument if not explicitly set by the caller, if any`).
 			ArgDoc("defaultPath", `If the argument is a Directory or File type, default to load path from context directory, relative to root directory.`).
-			ArgDoc("ignore", `Patterns to ignore when loading the contextual argument value.`),
+			ArgDoc("ignore", `Patterns to ignore when loading the contextual argument value.`).
+			ArgDoc("deprecated", `If set, marks the argument as deprecated with the given reason. Required
+				arguments, without a default value, can't be deprecated.`).
+			ArgDoc("pattern", `If the argument is a String or a list of strings, a regular expression (RE2 syntax) that values must match.`).
+			ArgDoc("min", `If the argument is an Integer or Float, or a list of those, the minimum value, inclusive.`).
+			ArgDoc("max", `If the argument is an Integer or Float, or a list of those, the maximum value, inclusive.`).
+			ArgDoc("minLength", `If the argument is a String or a List, the minimum number of characters or elements.`).
+			ArgDoc("allowedValues", `If the argument is a String or a list of strings, the only values that are accepted.`),
 	}.Install(s.dag)
 
 	dagql.Fields[*core.FunctionArg]{}.Install(s.dag)
@@ -147,6 +176,27 @@ func (s *moduleSchema) Install() {
 		dagql.Func("withListOf", s.typeDefWithListOf).
 			Doc(`Returns a TypeDef of kind List with the provided type for its elements.`),
 
+		dagql.Func("withMapOf", s.typeDefWithMapOf).
+			Doc(`Returns a TypeDef of kind Map with the provided type for its values.`,
+				`Map keys are always strings. Maps are represented as a JSON object
+				in the API.`).
+			ArgDoc("valueType", `The type of the values in the map.`),
+
+		dagql.Func("withUnion", s.typeDefWithUnion).
+			Doc(`Returns a TypeDef of kind Union with the provided name.`,
+				`Members are added with withUnionMember. Union values are represented
+				as a JSON object in the API, with a single key naming the member that
+				is set.`).
+			ArgDoc("name", `The name of the union`).
+			ArgDoc("description", `A doc string for the union, if any`).
+			ArgDoc("sourceMap", `The source map for the union definition.`),
+
+		dagql.Func("withUnionMember", s.typeDefWithUnionMember).
+			Doc(`Adds a member to a Union TypeDef, failing if the type is not a union.`).
+			ArgDoc("name", `The name of the member, used as its tag in union values`).
+			ArgDoc("typeDef", `The type of the member`).
+			ArgDoc("description", `A doc string for the member, if any`),
+
 		dagql.Func("withObject", s.typeDefWithObject).
 			Doc(`Returns a TypeDef of kind Object with the provided name.`,
 				`Note that an object's fields and functions may be omitted if the
@@ -161,7 +211,8 @@ func (s *moduleSchema) Install() {
 			ArgDoc("name", `The name of the field in the object`).
 			ArgDoc("typeDef", `The type of the field`).
 			ArgDoc("description", `A doc string for the field, if any`).
-			ArgDoc("sourceMap", `The source map for the field definition.`),
+			ArgDoc("sourceMap", `The source map for the field definition.`).
+			ArgDoc("deprecated", `If set, marks the field as deprecated with the given reason.`),
 
 		dagql.Func("withFunction", s.typeDefWithFunction).
 			Doc(`Adds a function for an Object or Interface TypeDef, failing if the type is not one of those kinds.`),
@@ -181,7 +232,8 @@ func (s *moduleSchema) Install() {
 			Doc(`Adds a static value for an Enum TypeDef, failing if the type is not an enum.`).
 			ArgDoc("value", `The name of the value in the enum`).
 			ArgDoc("description", `A doc string for the value, if any`).
-			ArgDoc("sourceMap", `The source map for the enum value definition.`),
+			ArgDoc("sourceMap", `The source map for the enum value definition.`).
+			ArgDoc("deprecated", `If set, marks the enum value as deprecated with the given reason.`),
 	}.Install(s.dag)
 
 	dagql.Fields[*core.ObjectTypeDef]{}.Install(s.dag)
@@ -189,6 +241,9 @@ func (s *moduleSchema) Install() {
 	dagql.Fields[*core.InputTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.FieldTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.ListTypeDef]{}.Install(s.dag)
+	dagql.Fields[*core.MapTypeDef]{}.Install(s.dag)
+	dagql.Fields[*core.UnionTypeDef]{}.Install(s.dag)
+	dagql.Fields[*core.UnionMemberTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.ScalarTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.EnumTypeDef]{}.Install(s.dag)
 	dagql.Fields[*core.EnumValueTypeDef]{}.Install(s.dag)
@@ -230,6 +285,43 @@ func (s *moduleSchema) typeDefWithListOf(ctx context.Context, def *core.TypeDef,
 	return def.WithListOf(elemType.Self), nil
 }
 
+func (s *moduleSchema) typeDefWithMapOf(ctx context.Context, def *core.TypeDef, args struct {
+	ValueType core.TypeDefID
+}) (*core.TypeDef, error) {
+	valueType, err := args.ValueType.Load(ctx, s.dag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode value type: %w", err)
+	}
+	return def.WithMapOf(valueType.Self), nil
+}
+
+func (s *moduleSchema) typeDefWithUnion(ctx context.Context, def *core.TypeDef, args struct {
+	Name        string
+	Description string `default:""`
+	SourceMap   dagql.Optional[core.SourceMapID]
+}) (*core.TypeDef, error) {
+	if args.Name == "" {
+		return nil, fmt.Errorf("union type def must have a name")
+	}
+	sourceMap, err := s.loadSourceMap(ctx, args.SourceMap)
+	if err != nil {
+		return nil, err
+	}
+	return def.WithUnion(args.Name, args.Description, sourceMap), nil
+}
+
+func (s *moduleSchema) typeDefWithUnionMember(ctx context.Context, def *core.TypeDef, args struct {
+	Name        string
+	TypeDef     core.TypeDefID
+	Description string `default:""`
+}) (*core.TypeDef, error) {
+	memberType, err := args.TypeDef.Load(ctx, s.dag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode member type: %w", err)
+	}
+	return def.WithUnionMember(args.Name, memberType.Self, args.Description)
+}
+
 func (s *moduleSchema) typeDefWithObject(ctx context.Context, def *core.TypeDef, args struct {
 	Name        string
 	Description string `default:""`
@@ -265,6 +357,7 @@ func (s *moduleSchema) typeDefWithObjectField(ctx context.Context, def *core.Typ
 	TypeDef     core.TypeDefID
 	Description string `default:""`
 	SourceMap   dagql.Optional[core.SourceMapID]
+	Deprecated  dagql.Optional[dagql.String]
 }) (*core.TypeDef, error) {
 	fieldType, err := args.TypeDef.Load(ctx, s.dag)
 	if err != nil {
@@ -274,7 +367,7 @@ func (s *moduleSchema) typeDefWithObjectField(ctx context.Context, def *core.Typ
 	if err != nil {
 		return nil, err
 	}
-	return def.WithObjectField(args.Name, fieldType.Self, args.Description, sourceMap)
+	return def.WithObjectField(args.Name, fieldType.Self, args.Description, sourceMap, optionalString(args.Deprecated))
 }
 
 func (s *moduleSchema) typeDefWithFunction(ctx context.Context, def *core.TypeDef, args struct {
@@ -321,6 +414,7 @@ func (s *moduleSchema) typeDefWithEnumValue(ctx context.Context, def *core.TypeD
 	Value       string
 	Description string `default:""`
 	SourceMap   dagql.Optional[core.SourceMapID]
+	Deprecated  dagql.Optional[dagql.String]
 }) (*core.TypeDef, error) {
 	if args.Value == "" {
 		return nil, fmt.Errorf("enum value must not be empty")
@@ -329,7 +423,7 @@ func (s *moduleSchema) typeDefWithEnumValue(ctx context.Context, def *core.TypeD
 	if err != nil {
 		return nil, err
 	}
-	return def.WithEnumValue(args.Value, args.Description, sourceMap)
+	return def.WithEnumValue(args.Value, args.Description, sourceMap, optionalString(args.Deprecated))
 }
 
 func (s *moduleSchema) generatedCode(ctx context.Context, _ *core.Query, args struct {
@@ -383,6 +477,13 @@ func (s *moduleSchema) functionWithArg(ctx context.Context, fn *core.Function, a
 	DefaultPath  string    `default:""`
 	Ignore       []string  `default:"[]"`
 	SourceMap    dagql.Optional[core.SourceMapID]
+	Deprecated   dagql.Optional[dagql.String]
+
+	Pattern       string `default:""`
+	Min           dagql.Optional[dagql.Float]
+	Max           dagql.Optional[dagql.Float]
+	MinLength     dagql.Optional[dagql.Int]
+	AllowedValues []string `default:"[]"`
 }) (*core.Function, error) {
 	argType, err := args.TypeDef.Load(ctx, s.dag)
 	if err != nil {
@@ -418,7 +519,37 @@ func (s *moduleSchema) functionWithArg(ctx context.Context, fn *core.Function, a
 		td = td.WithOptional(true)
 	}
 
-	return fn.WithArg(args.Name, td, args.Description, args.DefaultValue, args.DefaultPath, args.Ignore, sourceMap), nil
+	fn, err = fn.WithArg(args.Name, td, args.Description, args.DefaultValue, args.DefaultPath, args.Ignore, sourceMap, optionalString(args.Deprecated))
+	if err != nil {
+		return nil, err
+	}
+
+	return fn.WithArgConstraints(args.Name, args.Pattern, optionalFloat(args.Min), optionalFloat(args.Max), optionalInt(args.MinLength), args.AllowedValues)
+}
+
+func (s *moduleSchema) functionWithCachePolicy(ctx context.Context, fn *core.Function, args struct {
+	Policy     core.FunctionCachePolicy
+	TimeToLive string `default:""`
+}) (*core.Function, error) {
+	var ttl time.Duration
+	if args.TimeToLive != "" {
+		var err error
+		ttl, err = time.ParseDuration(args.TimeToLive)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time to live %q: %w", args.TimeToLive, err)
+		}
+	}
+	return fn.WithCachePolicy(args.Policy, ttl)
+}
+
+func (s *moduleSchema) functionWithDeprecated(ctx context.Context, fn *core.Function, args struct {
+	Reason string
+}) (*core.Function, error) {
+	return fn.WithDeprecated(args.Reason), nil
+}
+
+func (s *moduleSchema) functionWithStreaming(ctx context.Context, fn *core.Function, args struct{}) (*core.Function, error) {
+	return fn.WithStreaming()
 }
 
 func (s *moduleSchema) functionWithSourceMap(ctx context.Context, fn *core.Function, args struct {
@@ -467,6 +598,13 @@ func (s *moduleSchema) functionCallReturnValue(ctx context.Context, fnCall *core
 	return dagql.Null[core.Void](), fnCall.ReturnValue(ctx, args.Value)
 }
 
+func (s *moduleSchema) functionCallReturnItem(ctx context.Context, fnCall *core.FunctionCall, args struct {
+	Value core.JSON
+},
+) (dagql.Nullable[core.Void], error) {
+	return dagql.Null[core.Void](), fnCall.ReturnItem(ctx, args.Value)
+}
+
 func (s *moduleSchema) functionCallReturnError(ctx context.Context, fnCall *core.FunctionCall, args struct {
 	Error dagql.ID[*core.Error]
 },
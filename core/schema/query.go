@@ -51,8 +51,12 @@ func (s *querySchema) Install() {
 	core.NetworkProtocols.Install(s.srv)
 	core.ImageLayerCompressions.Install(s.srv)
 	core.ImageMediaTypesEnum.Install(s.srv)
+	core.ImageAttestations.Install(s.srv)
+	core.ArchiveFormats.Install(s.srv)
+	core.FileTypes.Install(s.srv)
 	core.CacheSharingModes.Install(s.srv)
 	core.TypeDefKinds.Install(s.srv)
+	core.FunctionCachePolicies.Install(s.srv)
 	core.ModuleSourceKindEnum.Install(s.srv)
 	core.ReturnTypesEnum.Install(s.srv)
 
@@ -64,6 +68,10 @@ func (s *querySchema) Install() {
 
 	dagql.Fields[core.Port]{}.Install(s.srv)
 
+	dagql.Fields[core.SearchResult]{}.Install(s.srv)
+	dagql.Fields[core.FileInfo]{}.Install(s.srv)
+	dagql.Fields[core.GitCommit]{}.Install(s.srv)
+
 	dagql.Fields[Label]{}.Install(s.srv)
 
 	dagql.Fields[*core.Query]{
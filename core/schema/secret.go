@@ -3,6 +3,7 @@ package schema
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -43,7 +44,42 @@ func (s *secretSchema) Install() {
 			Sensitive().
 			Impure("A secret's `plaintext` value in the internal secret store state can change.").
 			Doc(`The value of this secret.`),
+		dagql.Func("template", s.template).
+			Impure("`template` mutates state in the internal secret store.").
+			Doc(`Derives a new secret by rendering a Go text/template with the value
+				of this secret and others, without exposing them.`,
+				`The value of this secret is available as {{.secret}}, and those of
+				the secrets in vars by their names. The template may also use
+				{{base64 .x}} and {{json .x}} to encode values.`).
+			ArgDoc("format", `The template to render, e.g. "user:{{.secret}}".`).
+			ArgDoc("vars", `Other secrets available to the template, by name.`),
+		dagql.Func("base64", s.base64).
+			Impure("`base64` mutates state in the internal secret store.").
+			Doc(`Derives a new secret from the standard base64 encoding of this
+				secret's value, without exposing it.`),
+		dagql.Func("jsonPath", s.jsonPath).
+			Impure("`jsonPath` mutates state in the internal secret store.").
+			Doc(`Derives a new secret from a single value of this secret's value,
+				which must be a JSON document, without exposing it.`,
+				`Strings are selected as is, and other values as JSON.`).
+			ArgDoc("path", `The JSONPath of the value, made of keys and indices,
+				e.g. '$.auths["ghcr.io"].auth' or "items[0].token".`),
 	}.Install(s.srv)
+
+	dagql.MustInputSpec(SecretVariable{}).Install(s.srv)
+}
+
+type SecretVariable struct {
+	Name   string        `field:"true" doc:"The variable name."`
+	Secret core.SecretID `field:"true" doc:"The secret holding the variable value."`
+}
+
+func (SecretVariable) TypeName() string {
+	return "SecretVariable"
+}
+
+func (SecretVariable) TypeDescription() string {
+	return "Key value object that represents a secret variable of a template."
 }
 
 type loadSecretFromNameArgs struct {
@@ -186,3 +222,102 @@ func (s *secretSchema) plaintext(ctx context.Context, secret *core.Secret, args
 
 	return dagql.NewString(string(plaintext)), nil
 }
+
+// secretTemplateVar is the name of the receiver secret in templates.
+const secretTemplateVar = "secret"
+
+type secretTemplateArgs struct {
+	Format string
+	Vars   []dagql.InputObject[SecretVariable] `default:"[]"`
+}
+
+func (s *secretSchema) template(ctx context.Context, secret *core.Secret, args secretTemplateArgs) (i dagql.Instance[*core.Secret], err error) {
+	parents := []*core.Secret{secret}
+	names := []string{secretTemplateVar}
+	for _, v := range args.Vars {
+		if v.Value.Name == "" {
+			return i, fmt.Errorf("variable name must not be empty")
+		}
+		if slices.Contains(names, v.Value.Name) {
+			return i, fmt.Errorf("duplicate variable %q", v.Value.Name)
+		}
+		inst, err := v.Value.Secret.Load(ctx, s.srv)
+		if err != nil {
+			return i, fmt.Errorf("failed to load secret %q: %w", v.Value.Name, err)
+		}
+		parents = append(parents, inst.Self)
+		names = append(names, v.Value.Name)
+	}
+	derive, err := core.SecretTemplate(args.Format, names)
+	if err != nil {
+		return i, err
+	}
+	return s.deriveSecret(ctx, secret, "template", parents, derive)
+}
+
+func (s *secretSchema) base64(ctx context.Context, secret *core.Secret, args struct{}) (i dagql.Instance[*core.Secret], err error) {
+	return s.deriveSecret(ctx, secret, "base64", []*core.Secret{secret}, core.SecretBase64)
+}
+
+type secretJSONPathArgs struct {
+	Path string
+}
+
+func (s *secretSchema) jsonPath(ctx context.Context, secret *core.Secret, args secretJSONPathArgs) (i dagql.Instance[*core.Secret], err error) {
+	derive, err := core.SecretJSONPath(args.Path)
+	if err != nil {
+		return i, err
+	}
+	return s.deriveSecret(ctx, secret, fmt.Sprintf("jsonPath(%q)", args.Path), []*core.Secret{secret}, derive)
+}
+
+// deriveSecret adds a secret derived from others to the store, named after
+// the secret it's called on and how it's derived.
+func (s *secretSchema) deriveSecret(
+	ctx context.Context,
+	secret *core.Secret,
+	derivation string,
+	parents []*core.Secret,
+	derive core.SecretDeriveFunc,
+) (i dagql.Instance[*core.Secret], err error) {
+	secretStore, err := secret.Query.Secrets(ctx)
+	if err != nil {
+		return i, fmt.Errorf("failed to get secret store: %w", err)
+	}
+	parentName, ok := secretStore.GetSecretNameOrURI(secret.IDDigest)
+	if !ok {
+		return i, fmt.Errorf("secret not found: %s", secret.IDDigest)
+	}
+	name := parentName + "." + derivation
+
+	// the derivation is identified by the current ID, which covers the
+	// secrets it's derived from and its args
+	accessor, err := core.GetClientResourceAccessor(ctx, secret.Query, dagql.CurrentID(ctx).Digest().String())
+	if err != nil {
+		return i, fmt.Errorf("failed to get client resource name: %w", err)
+	}
+
+	// NB: like setSecret, return a freshly minted Object that just gets the
+	// secret by name, so that this impure call doesn't taint its users
+	if err := s.srv.Select(ctx, s.srv.Root(), &i, dagql.Selector{
+		Field: "loadSecretFromName",
+		Args: []dagql.NamedInput{
+			{
+				Name:  "name",
+				Value: dagql.NewString(name),
+			},
+			{
+				Name:  "accessor",
+				Value: dagql.Opt(dagql.NewString(accessor)),
+			},
+		},
+	}); err != nil {
+		return i, fmt.Errorf("failed to select secret: %w", err)
+	}
+
+	if err := secretStore.AddDerivedSecret(i.Self, name, parents, derive); err != nil {
+		return i, fmt.Errorf("failed to add secret: %w", err)
+	}
+
+	return i, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"time"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -59,7 +60,11 @@ func (s *serviceSchema) Install() {
 				`Be sure to set any exposed ports before calling this api.`).
 			ArgDoc("random", `Bind each tunnel port to a random port on the host.`).
 			ArgDoc("ports", `List of frontend/backend port mappings to forward.`,
-				`Frontend is the port accepting traffic on the host, backend is the service port.`),
+				`Frontend is the port accepting traffic on the host, backend is the service port.`).
+			ArgDoc("timeout",
+				`Number of seconds to wait for the service's health checks to succeed. Zero means no timeout.`,
+				`When the timeout expires the service is killed and the call fails with an exec
+				error reporting that it timed out, including the output captured so far.`),
 
 		dagql.NodeFunc("up", s.containerUp).
 			View(AfterVersion("v0.15.2")).
@@ -69,6 +74,10 @@ func (s *serviceSchema) Install() {
 			ArgDoc("random", `Bind each tunnel port to a random port on the host.`).
 			ArgDoc("ports", `List of frontend/backend port mappings to forward.`,
 				`Frontend is the port accepting traffic on the host, backend is the service port.`).
+			ArgDoc("timeout",
+				`Number of seconds to wait for the service's health checks to succeed. Zero means no timeout.`,
+				`When the timeout expires the service is killed and the call fails with an exec
+				error reporting that it timed out, including the output captured so far.`).
 			ArgDoc("args",
 				`Command to run instead of the container's default command (e.g., ["go", "run", "main.go"]).`,
 				`If empty, the container's default command is used.`).
@@ -118,14 +127,22 @@ func (s *serviceSchema) Install() {
 		dagql.NodeFunc("start", s.start).
 			Impure("Imperatively mutates runtime state.").
 			Doc(`Start the service and wait for its health checks to succeed.`,
-				`Services bound to a Container do not need to be manually started.`),
+				`Services bound to a Container do not need to be manually started.`).
+			ArgDoc("timeout",
+				`Number of seconds to wait for the service's health checks to succeed. Zero means no timeout.`,
+				`When the timeout expires the service is killed and the call fails with an exec
+				error reporting that it timed out, including the output captured so far.`),
 
 		dagql.NodeFunc("up", s.up).
 			Impure("Starts a host tunnel, possibly with ports that change each time it's started.").
 			Doc(`Creates a tunnel that forwards traffic from the caller's network to this service.`).
 			ArgDoc("random", `Bind each tunnel port to a random port on the host.`).
 			ArgDoc("ports", `List of frontend/backend port mappings to forward.`,
-				`Frontend is the port accepting traffic on the host, backend is the service port.`),
+				`Frontend is the port accepting traffic on the host, backend is the service port.`).
+			ArgDoc("timeout",
+				`Number of seconds to wait for the service's health checks to succeed. Zero means no timeout.`,
+				`When the timeout expires the service is killed and the call fails with an exec
+				error reporting that it timed out, including the output captured so far.`),
 
 		dagql.NodeFunc("stop", s.stop).
 			Impure("Imperatively mutates runtime state.").
@@ -259,7 +276,11 @@ func (s *serviceSchema) endpoint(ctx context.Context, parent dagql.Instance[*cor
 	return dagql.NewString(str), nil
 }
 
-func (s *serviceSchema) start(ctx context.Context, parent dagql.Instance[*core.Service], args struct{}) (core.ServiceID, error) {
+type serviceStartArgs struct {
+	Timeout int `default:"0"`
+}
+
+func (s *serviceSchema) start(ctx context.Context, parent dagql.Instance[*core.Service], args serviceStartArgs) (core.ServiceID, error) {
 	defer func() {
 		if err := recover(); err != nil {
 			debug.PrintStack()
@@ -267,7 +288,17 @@ func (s *serviceSchema) start(ctx context.Context, parent dagql.Instance[*core.S
 		}
 	}()
 
-	if err := parent.Self.StartAndTrack(ctx, parent.ID()); err != nil {
+	svc := parent.Self
+	if args.Timeout != 0 {
+		timeout, err := startTimeout(args.Timeout)
+		if err != nil {
+			return core.ServiceID{}, err
+		}
+		svc = svc.Clone()
+		svc.StartTimeout = timeout
+	}
+
+	if err := svc.StartAndTrack(ctx, parent.ID()); err != nil {
 		return core.ServiceID{}, err
 	}
 
@@ -286,8 +317,16 @@ func (s *serviceSchema) stop(ctx context.Context, parent dagql.Instance[*core.Se
 }
 
 type UpArgs struct {
-	Ports  []dagql.InputObject[core.PortForward] `default:"[]"`
-	Random bool                                  `default:"false"`
+	Ports   []dagql.InputObject[core.PortForward] `default:"[]"`
+	Random  bool                                  `default:"false"`
+	Timeout int                                   `default:"0"`
+}
+
+func startTimeout(seconds int) (time.Duration, error) {
+	if seconds < 0 {
+		return 0, fmt.Errorf("invalid timeout %d: must not be negative", seconds)
+	}
+	return time.Duration(seconds) * time.Second, nil
 }
 
 const InstrumentationLibrary = "dagger.io/engine.schema"
@@ -315,11 +354,21 @@ func (s *serviceSchema) up(ctx context.Context, svc dagql.Instance[*core.Service
 		return void, fmt.Errorf("failed to select host service: %w", err)
 	}
 
+	startSvc := hostSvc.Self
+	if args.Timeout != 0 {
+		timeout, err := startTimeout(args.Timeout)
+		if err != nil {
+			return void, err
+		}
+		startSvc = startSvc.Clone()
+		startSvc.StartTimeout = timeout
+	}
+
 	svcs, err := hostSvc.Self.Query.Services(ctx)
 	if err != nil {
 		return void, fmt.Errorf("failed to get host services: %w", err)
 	}
-	runningSvc, err := svcs.Start(ctx, hostSvc.ID(), hostSvc.Self)
+	runningSvc, err := svcs.Start(ctx, hostSvc.ID(), startSvc)
 	if err != nil {
 		return void, fmt.Errorf("failed to start host service: %w", err)
 	}
@@ -106,3 +106,24 @@ func (maxVersion BeforeVersion) Contains(version string) bool {
 func ptr[T any](v T) *T {
 	return &v
 }
+
+func optionalString(v dagql.Optional[dagql.String]) *string {
+	if !v.Valid {
+		return nil
+	}
+	return ptr(v.Value.String())
+}
+
+func optionalFloat(v dagql.Optional[dagql.Float]) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return ptr(v.Value.Float64())
+}
+
+func optionalInt(v dagql.Optional[dagql.Int]) *int {
+	if !v.Valid {
+		return nil
+	}
+	return ptr(v.Value.Int())
+}
@@ -1,9 +1,15 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"text/template"
 
 	"github.com/dagger/dagger/engine/client/secretprovider"
 	bksession "github.com/moby/buildkit/session"
@@ -67,8 +73,19 @@ type storedSecret struct {
 
 	// The id of the buildkit session the secret will be retrieved through.
 	BuildkitSessionID string
+
+	// The secrets this secret is derived from, if it's derived.
+	Parents []digest.Digest
+
+	// Derive computes the plaintext of a derived secret from the plaintexts
+	// of its parents.
+	Derive SecretDeriveFunc
 }
 
+// SecretDeriveFunc computes the plaintext of a derived secret from the
+// plaintexts of its parents, in order.
+type SecretDeriveFunc func(parents [][]byte) ([]byte, error)
+
 func (s *storedSecret) Clone() *storedSecret {
 	cp := *s
 	cp.Secret = s.Secret.Clone()
@@ -114,7 +131,9 @@ func (store *SecretStore) NewSecret(secret *Secret, buildkitSessionID, uri strin
 		return fmt.Errorf("secret must have an ID digest")
 	}
 
-	_, _, err := secretprovider.ResolverForID(uri)
+	// only the syntax is checked here; the client may have plugins for
+	// schemes the engine doesn't know about
+	_, _, err := secretprovider.ParseID(uri)
 	if err != nil {
 		return err
 	}
@@ -129,6 +148,38 @@ func (store *SecretStore) NewSecret(secret *Secret, buildkitSessionID, uri strin
 	return nil
 }
 
+// AddDerivedSecret adds a secret whose plaintext is computed from the
+// plaintexts of other secrets in the store when it's needed, so that it
+// follows changes to them, e.g. rotated secrets in remote stores.
+func (store *SecretStore) AddDerivedSecret(secret *Secret, name string, parents []*Secret, derive SecretDeriveFunc) error {
+	if secret == nil {
+		return fmt.Errorf("secret must not be nil")
+	}
+	if secret.Query == nil {
+		return fmt.Errorf("secret must have a query")
+	}
+	if secret.IDDigest == "" {
+		return fmt.Errorf("secret must have an ID digest")
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	parentDigests := make([]digest.Digest, 0, len(parents))
+	for _, parent := range parents {
+		if _, ok := store.secrets[parent.IDDigest]; !ok {
+			return fmt.Errorf("secret %s: %w", parent.IDDigest, secrets.ErrNotFound)
+		}
+		parentDigests = append(parentDigests, parent.IDDigest)
+	}
+	store.secrets[secret.IDDigest] = &storedSecret{
+		Secret:  secret,
+		Name:    name,
+		Parents: parentDigests,
+		Derive:  derive,
+	}
+	return nil
+}
+
 func (store *SecretStore) AddSecretFromOtherStore(secret *Secret, otherStore *SecretStore) error {
 	otherStore.mu.RLock()
 	secretVals, ok := otherStore.secrets[secret.IDDigest]
@@ -137,6 +188,23 @@ func (store *SecretStore) AddSecretFromOtherStore(secret *Secret, otherStore *Se
 		return fmt.Errorf("secret %s not found in other store", secret.IDDigest)
 	}
 
+	// the secrets a derived secret is derived from aren't in its ID, so they
+	// have to be brought along
+	for _, parent := range secretVals.Parents {
+		if store.HasSecret(parent) {
+			continue
+		}
+		otherStore.mu.RLock()
+		parentVals, ok := otherStore.secrets[parent]
+		otherStore.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("secret %s not found in other store", parent)
+		}
+		if err := store.AddSecretFromOtherStore(parentVals.Secret, otherStore); err != nil {
+			return err
+		}
+	}
+
 	secretVals = secretVals.Clone()
 	secretVals.Secret = secret
 
@@ -192,12 +260,25 @@ func (store *SecretStore) GetSecretNameOrURI(idDgst digest.Digest) (string, bool
 
 func (store *SecretStore) GetSecretPlaintext(ctx context.Context, idDgst digest.Digest) ([]byte, error) {
 	store.mu.RLock()
-	defer store.mu.RUnlock()
 	secret, ok := store.secrets[idDgst]
+	store.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("secret %s: %w", idDgst, secrets.ErrNotFound)
 	}
 
+	// If the secret is derived from others, derive it from their plaintexts.
+	if secret.Derive != nil {
+		parents := make([][]byte, len(secret.Parents))
+		for i, parent := range secret.Parents {
+			plaintext, err := store.GetSecretPlaintext(ctx, parent)
+			if err != nil {
+				return nil, err
+			}
+			parents[i] = plaintext
+		}
+		return secret.Derive(parents)
+	}
+
 	// If the secret is stored locally (setSecret), return the plaintext.
 	if secret.URI == "" {
 		return secret.Plaintext, nil
@@ -235,3 +316,151 @@ var _ secrets.SecretStore = &buildkitSecretStore{}
 func (bkStore *buildkitSecretStore) GetSecret(ctx context.Context, llbID string) ([]byte, error) {
 	return bkStore.inner.GetSecretPlaintext(ctx, digest.Digest(llbID))
 }
+
+// SecretBase64 derives the base64 encoding of a secret.
+func SecretBase64(parents [][]byte) ([]byte, error) {
+	return []byte(base64.StdEncoding.EncodeToString(parents[0])), nil
+}
+
+var secretTemplateFuncs = template.FuncMap{
+	"base64": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+	"json": func(s string) (string, error) {
+		dt, err := json.Marshal(s)
+		return string(dt), err
+	},
+}
+
+// SecretTemplate returns a SecretDeriveFunc that renders a Go text/template
+// with the plaintexts of secrets as variables, given their names in order.
+func SecretTemplate(format string, names []string) (SecretDeriveFunc, error) {
+	tmpl, err := template.New("secret").
+		Option("missingkey=error").
+		Funcs(secretTemplateFuncs).
+		Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return func(parents [][]byte) ([]byte, error) {
+		vars := make(map[string]string, len(names))
+		for i, name := range names {
+			vars[name] = string(parents[i])
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, vars); err != nil {
+			return nil, fmt.Errorf("render secret template: %w", err)
+		}
+		return buf.Bytes(), nil
+	}, nil
+}
+
+// SecretJSONPath returns a SecretDeriveFunc that selects a single value from a
+// secret holding a JSON document, with a JSONPath expression made of keys and
+// indices, like `$.auths["ghcr.io"].auth` or `items[0].token`. Strings are
+// selected as is, and other values as JSON.
+func SecretJSONPath(path string) (SecretDeriveFunc, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return func(parents [][]byte) ([]byte, error) {
+		dec := json.NewDecoder(bytes.NewReader(parents[0]))
+		dec.UseNumber()
+		var val any
+		if err := dec.Decode(&val); err != nil {
+			// NB: decoding errors quote the plaintext, so they're left out
+			return nil, fmt.Errorf("secret is not valid JSON")
+		}
+		for i, step := range steps {
+			switch step := step.(type) {
+			case string:
+				obj, ok := val.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("json path %s: not an object", formatJSONPath(steps[:i]))
+				}
+				val, ok = obj[step]
+				if !ok {
+					return nil, fmt.Errorf("json path %s: %w", formatJSONPath(steps[:i+1]), secrets.ErrNotFound)
+				}
+			case int:
+				arr, ok := val.([]any)
+				if !ok {
+					return nil, fmt.Errorf("json path %s: not an array", formatJSONPath(steps[:i]))
+				}
+				if step >= len(arr) {
+					return nil, fmt.Errorf("json path %s: %w", formatJSONPath(steps[:i+1]), secrets.ErrNotFound)
+				}
+				val = arr[step]
+			}
+		}
+		if str, ok := val.(string); ok {
+			return []byte(str), nil
+		}
+		return json.Marshal(val)
+	}, nil
+}
+
+// parseJSONPath parses a JSONPath expression made of keys and indices into a
+// list of string keys and int indices. The leading `$` is optional.
+func parseJSONPath(path string) ([]any, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid json path %q: %s", path, reason)
+	}
+
+	rest, rooted := strings.CutPrefix(path, "$")
+	if !rooted && rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+	var steps []any
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, invalid("empty key")
+			}
+			steps = append(steps, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, invalid("unterminated [")
+			}
+			inner := rest[1:end]
+			switch {
+			case len(inner) >= 2 && inner[0] == '"' && inner[len(inner)-1] == '"',
+				len(inner) >= 2 && inner[0] == '\'' && inner[len(inner)-1] == '\'':
+				steps = append(steps, inner[1:len(inner)-1])
+			default:
+				idx, err := strconv.Atoi(inner)
+				if err != nil || idx < 0 {
+					return nil, invalid(fmt.Sprintf("invalid index %q", inner))
+				}
+				steps = append(steps, idx)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, invalid(fmt.Sprintf("unexpected %q", rest[0]))
+		}
+	}
+	return steps, nil
+}
+
+func formatJSONPath(steps []any) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, step := range steps {
+		switch step := step.(type) {
+		case string:
+			fmt.Fprintf(&sb, "[%q]", step)
+		case int:
+			fmt.Fprintf(&sb, "[%d]", step)
+		}
+	}
+	return sb.String()
+}
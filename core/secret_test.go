@@ -28,3 +28,72 @@ func TestSecretStoreNotFound(t *testing.T) {
 	_, err := store.AsBuildkitSecretStore().GetSecret(context.Background(), "foo")
 	require.ErrorIs(t, err, secrets.ErrNotFound)
 }
+
+func TestSecretStoreDerived(t *testing.T) {
+	ctx := context.Background()
+	store := NewSecretStore(nil)
+	user := &Secret{Query: &Query{}, IDDigest: "user"}
+	token := &Secret{Query: &Query{}, IDDigest: "token"}
+	require.NoError(t, store.AddSecret(user, "user", []byte("admin")))
+	require.NoError(t, store.AddSecret(token, "token", []byte("hunter2")))
+
+	derive, err := SecretTemplate("{{.user}}:{{.token}}", []string{"user", "token"})
+	require.NoError(t, err)
+	basicAuth := &Secret{Query: &Query{}, IDDigest: "basic-auth"}
+	require.NoError(t, store.AddDerivedSecret(basicAuth, "basic-auth", []*Secret{user, token}, derive))
+	encoded := &Secret{Query: &Query{}, IDDigest: "encoded"}
+	require.NoError(t, store.AddDerivedSecret(encoded, "encoded", []*Secret{basicAuth}, SecretBase64))
+
+	plaintext, err := store.GetSecretPlaintext(ctx, "encoded")
+	require.NoError(t, err)
+	require.Equal(t, "YWRtaW46aHVudGVyMg==", string(plaintext))
+
+	// derived secrets follow their parents
+	require.NoError(t, store.AddSecret(token, "token", []byte("rotated")))
+	plaintext, err = store.GetSecretPlaintext(ctx, "basic-auth")
+	require.NoError(t, err)
+	require.Equal(t, "admin:rotated", string(plaintext))
+
+	// the parents are copied along to other stores
+	other := NewSecretStore(nil)
+	require.NoError(t, other.AddSecretFromOtherStore(encoded, store))
+	require.True(t, other.HasSecret("token"))
+	plaintext, err = other.GetSecretPlaintext(ctx, "encoded")
+	require.NoError(t, err)
+	require.Equal(t, "YWRtaW46cm90YXRlZA==", string(plaintext))
+
+	err = store.AddDerivedSecret(&Secret{Query: &Query{}, IDDigest: "orphan"}, "orphan", []*Secret{{IDDigest: "missing"}}, SecretBase64)
+	require.ErrorIs(t, err, secrets.ErrNotFound)
+}
+
+func TestSecretJSONPath(t *testing.T) {
+	doc := []byte(`{"auths":{"ghcr.io":{"auth":"abc"}},"items":[1,{"n":12345678901234567890}],"a.b":true}`)
+	for path, expected := range map[string]string{
+		`$.auths["ghcr.io"].auth`: "abc",
+		`auths['ghcr.io']`:        `{"auth":"abc"}`,
+		`items[0]`:                "1",
+		`$.items[1].n`:            "12345678901234567890",
+		`["a.b"]`:                 "true",
+	} {
+		derive, err := SecretJSONPath(path)
+		require.NoError(t, err, path)
+		val, err := derive([][]byte{doc})
+		require.NoError(t, err, path)
+		require.Equal(t, expected, string(val), path)
+	}
+
+	for _, path := range []string{"a..b", "a[", "a[-1]", "a[x]", "$x"} {
+		_, err := SecretJSONPath(path)
+		require.ErrorContains(t, err, "invalid json path", path)
+	}
+
+	derive, err := SecretJSONPath("items[5]")
+	require.NoError(t, err)
+	_, err = derive([][]byte{doc})
+	require.ErrorIs(t, err, secrets.ErrNotFound)
+
+	// the plaintext isn't quoted in errors
+	_, err = derive([][]byte{[]byte("hunter2")})
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "hunter2")
+}
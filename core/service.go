@@ -10,11 +10,13 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	bkgw "github.com/moby/buildkit/frontend/gateway/client"
+	bkgwpb "github.com/moby/buildkit/frontend/gateway/pb"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/sourcegraph/conc/pool"
@@ -54,6 +56,11 @@ type Service struct {
 
 	// The sockets on the host to reverse tunnel
 	HostSockets []*Socket `json:"host_sockets,omitempty"`
+
+	// StartTimeout bounds how long starting the service may take, including
+	// waiting for its health checks to pass. It's set by the caller starting
+	// the service rather than being part of the service's identity.
+	StartTimeout time.Duration `json:"-"`
 }
 
 func (*Service) Type() *ast.Type {
@@ -409,14 +416,35 @@ func (svc *Service) startContainer(
 		}
 	}()
 
+	env := append([]string{}, execOp.Meta.Env...)
+	env = append(env, telemetry.PropagationEnv(ctx)...)
+
+	// the custom health check may need to exec in the container, which must
+	// wait until the service's own process has started
+	procStarted := make(chan struct{})
+
+	checkCtx, checkCancel := context.WithCancelCause(ctx)
+	defer checkCancel(errors.New("service start finished"))
 	checked := make(chan error, 1)
 	go func() {
-		checked <- newHealth(bk, gc, fullHost, ctr.Ports).Check(ctx)
+		err := newHealth(bk, gc, fullHost, ctr.Ports).Check(checkCtx)
+		if err == nil && ctr.Healthcheck != nil {
+			select {
+			case <-procStarted:
+				err = newCustomHealth(bk, gc, fullHost, ctr.Ports, ctr.Healthcheck, bkgw.StartRequest{
+					Env:          env,
+					Cwd:          execOp.Meta.Cwd,
+					User:         execOp.Meta.User,
+					SecretEnv:    execOp.Secretenv,
+					SecurityMode: execOp.Security,
+				}).Check(checkCtx)
+			case <-checkCtx.Done():
+				err = context.Cause(checkCtx)
+			}
+		}
+		checked <- err
 	}()
 
-	env := append([]string{}, execOp.Meta.Env...)
-	env = append(env, telemetry.PropagationEnv(ctx)...)
-
 	var stdinCtr, stdoutClient, stderrClient io.ReadCloser
 	var stdinClient, stdoutCtr, stderrCtr io.WriteCloser
 	if forwardStdin != nil {
@@ -431,6 +459,13 @@ func (svc *Service) startContainer(
 		stderrClient, stderrCtr = io.Pipe()
 	}
 
+	// keep the tail of the output around so it can be reported if the service
+	// doesn't start in time
+	stdoutTail := newTailBuffer(serviceOutputTailSize)
+	stderrTail := newTailBuffer(serviceOutputTailSize)
+	stdoutCtr = teeWriteCloser(stdoutCtr, stdoutTail)
+	stderrCtr = teeWriteCloser(stderrCtr, stderrTail)
+
 	svcProc, err := gc.Start(execCtx, bkgw.StartRequest{
 		Args:         execOp.Meta.Args,
 		Env:          env,
@@ -446,6 +481,7 @@ func (svc *Service) startContainer(
 	if err != nil {
 		return nil, fmt.Errorf("start container: %w", err)
 	}
+	close(procStarted)
 
 	if forwardStdin != nil {
 		forwardStdin(stdinClient, svcProc)
@@ -527,9 +563,42 @@ func (svc *Service) startContainer(
 		}
 	}
 
+	var timedOut <-chan time.Time
+	if svc.StartTimeout > 0 {
+		timer := time.NewTimer(svc.StartTimeout)
+		defer timer.Stop()
+		timedOut = timer.C
+	}
+
 	select {
+	case <-timedOut:
+		checkCancel(fmt.Errorf("service start %w after %s", buildkit.ErrExecTimedOut, svc.StartTimeout))
+		// kill the whole process tree; the exit handler above takes care of
+		// releasing the container and detaching dependent services
+		exitCode := -1
+		if err := stopSvc(context.WithoutCancel(ctx), true); err != nil {
+			slog.Warn("failed to kill timed out service", "err", err)
+		} else {
+			var gwExitErr *bkgwpb.ExitError
+			if errors.As(exitErr, &gwExitErr) {
+				exitCode = int(gwExitErr.ExitCode)
+			}
+		}
+		execErr := buildkit.NewExecError(
+			fmt.Errorf("service did not become healthy: %w", context.Cause(checkCtx)),
+			execOp.Meta.Args,
+			exitCode,
+			strings.TrimSpace(stdoutTail.String()),
+			strings.TrimSpace(stderrTail.String()),
+		)
+		execErr.TimedOut = true
+		return nil, execErr
 	case err := <-checked:
 		if err != nil {
+			// don't leave an unhealthy service running
+			if stopErr := stopSvc(context.WithoutCancel(ctx), true); stopErr != nil {
+				slog.Warn("failed to kill unhealthy service", "err", stopErr)
+			}
 			return nil, fmt.Errorf("health check errored: %w", err)
 		}
 
@@ -575,7 +644,12 @@ func (svc *Service) startTunnel(ctx context.Context, id *call.ID) (running *Runn
 		return nil, fmt.Errorf("failed to get buildkit client: %w", err)
 	}
 
-	upstream, err := svcs.Start(svcCtx, svc.TunnelUpstream.ID(), svc.TunnelUpstream.Self)
+	upstreamSvc := svc.TunnelUpstream.Self
+	if svc.StartTimeout > 0 {
+		upstreamSvc = upstreamSvc.Clone()
+		upstreamSvc.StartTimeout = svc.StartTimeout
+	}
+	upstream, err := svcs.Start(svcCtx, svc.TunnelUpstream.ID(), upstreamSvc)
 	if err != nil {
 		return nil, fmt.Errorf("start upstream: %w", err)
 	}
@@ -827,3 +901,50 @@ func (bndp *ServiceBindings) Merge(other ServiceBindings) {
 
 	*bndp = merged
 }
+
+// serviceOutputTailSize is how much of a service's stdout and stderr is kept
+// around for reporting errors.
+const serviceOutputTailSize = 64 * 1024
+
+// tailBuffer is a writer that retains only the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+	max int
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	return &tailBuffer{max: max}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) Close() error {
+	return nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
+
+// teeWriteCloser returns a WriteCloser that writes to both w and tee, closing
+// w when closed. w may be nil.
+func teeWriteCloser(w io.WriteCloser, tee io.WriteCloser) io.WriteCloser {
+	if w == nil {
+		return tee
+	}
+	return struct {
+		io.Writer
+		io.Closer
+	}{io.MultiWriter(w, tee), w}
+}
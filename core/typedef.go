@@ -7,13 +7,18 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/iancoleman/strcase"
 	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine"
 )
 
 type Function struct {
@@ -25,6 +30,13 @@ type Function struct {
 
 	SourceMap *SourceMap `field:"true" doc:"The location of this function declaration."`
 
+	CachePolicy     FunctionCachePolicy `field:"true" doc:"The policy for caching the results of calls to the function."`
+	CacheTTLSeconds int                 `field:"true" name:"cacheTTLSeconds" doc:"Number of seconds for which the results of calls to the function are cached, if its cache policy is TTL."`
+
+	Deprecated *string `field:"true" doc:"The reason this function is deprecated, if it is."`
+
+	Streaming bool `field:"true" doc:"Whether the function returns its result incrementally, as a stream of items delivered to subscribers as they are available."`
+
 	// Below are not in public API
 
 	// OriginalName of the parent object
@@ -40,6 +52,7 @@ func NewFunction(name string, returnType *TypeDef) *Function {
 		Name:         strcase.ToLowerCamel(name),
 		ReturnType:   returnType,
 		OriginalName: name,
+		CachePolicy:  FunctionCachePolicyDefault,
 	}
 }
 
@@ -74,9 +87,11 @@ func (fn Function) Clone() *Function {
 
 func (fn *Function) FieldSpec() (dagql.FieldSpec, error) {
 	spec := dagql.FieldSpec{
-		Name:        fn.Name,
-		Description: formatGqlDescription(fn.Description),
-		Type:        fn.ReturnType.ToTyped(),
+		Name:             fn.Name,
+		Description:      formatGqlDescription(fn.Description),
+		Type:             fn.ReturnType.ToTyped(),
+		DeprecatedReason: deprecationReason(fn.Deprecated),
+		Streaming:        fn.Streaming,
 	}
 	for _, arg := range fn.Args {
 		input := arg.TypeDef.ToInput()
@@ -94,12 +109,20 @@ func (fn *Function) FieldSpec() (dagql.FieldSpec, error) {
 				return spec, fmt.Errorf("failed to decode default value for arg %q: %w", arg.Name, err)
 			}
 		}
-		spec.Args = append(spec.Args, dagql.InputSpec{
-			Name:        arg.Name,
-			Description: formatGqlDescription(arg.Description),
-			Type:        input,
-			Default:     defaultVal,
-		})
+		argSpec := dagql.InputSpec{
+			Name:             arg.Name,
+			Description:      formatGqlDescription(arg.Description),
+			Type:             input,
+			Default:          defaultVal,
+			DeprecatedReason: deprecationReason(arg.Deprecated),
+		}
+		if directive := arg.TypeDef.JSONTypeDirective(); directive != nil {
+			argSpec.Directives = append(argSpec.Directives, directive)
+		}
+		spec.Args = append(spec.Args, argSpec)
+	}
+	if directive := fn.ReturnType.JSONTypeDirective(); directive != nil {
+		spec.Directives = append(spec.Directives, directive)
 	}
 	return spec, nil
 }
@@ -110,7 +133,12 @@ func (fn *Function) WithDescription(desc string) *Function {
 	return fn
 }
 
-func (fn *Function) WithArg(name string, typeDef *TypeDef, desc string, defaultValue JSON, defaultPath string, ignore []string, sourceMap *SourceMap) *Function {
+// WithArg returns the function with the given argument, failing if it's
+// deprecated while required, since callers can't stop setting it.
+func (fn *Function) WithArg(name string, typeDef *TypeDef, desc string, defaultValue JSON, defaultPath string, ignore []string, sourceMap *SourceMap, deprecated *string) (*Function, error) {
+	if deprecated != nil && !typeDef.Optional && defaultValue == nil {
+		return nil, fmt.Errorf("argument %q is required, so it can't be deprecated: make it optional or give it a default value", strcase.ToLowerCamel(name))
+	}
 	fn = fn.Clone()
 	fn.Args = append(fn.Args, &FunctionArg{
 		Name:         strcase.ToLowerCamel(name),
@@ -121,8 +149,66 @@ func (fn *Function) WithArg(name string, typeDef *TypeDef, desc string, defaultV
 		OriginalName: name,
 		DefaultPath:  defaultPath,
 		Ignore:       ignore,
+		Deprecated:   deprecated,
 	})
-	return fn
+	return fn, nil
+}
+
+// WithArgConstraints sets the constraints that values of the named argument
+// must satisfy, failing if they don't apply to the type of the argument or if
+// its default value doesn't satisfy them.
+func (fn *Function) WithArgConstraints(name string, pattern string, minValue, maxValue *float64, minLength *int, allowedValues []string) (*Function, error) {
+	fn = fn.Clone()
+	arg, ok := fn.LookupArg(strcase.ToLowerCamel(name))
+	if !ok {
+		return nil, fmt.Errorf("function %q has no argument %q", fn.Name, name)
+	}
+
+	kind := arg.TypeDef.Kind
+	elemKind := kind
+	if kind == TypeDefKindList {
+		elemKind = arg.TypeDef.AsList.Value.ElementTypeDef.Kind
+	}
+	if pattern != "" {
+		if elemKind != TypeDefKindString {
+			return nil, fmt.Errorf("argument %q: pattern only applies to strings, not %s", arg.Name, arg.TypeDef.ToType())
+		}
+		if _, err := compilePattern(pattern); err != nil {
+			return nil, fmt.Errorf("argument %q: invalid pattern %q: %w", arg.Name, pattern, err)
+		}
+	}
+	if minValue != nil || maxValue != nil {
+		if elemKind != TypeDefKindInteger && elemKind != TypeDefKindFloat {
+			return nil, fmt.Errorf("argument %q: min and max only apply to numbers, not %s", arg.Name, arg.TypeDef.ToType())
+		}
+		if minValue != nil && maxValue != nil && *minValue > *maxValue {
+			return nil, fmt.Errorf("argument %q: min %s is greater than max %s", arg.Name, formatConstraint(*minValue), formatConstraint(*maxValue))
+		}
+	}
+	if minLength != nil {
+		if kind != TypeDefKindString && kind != TypeDefKindList {
+			return nil, fmt.Errorf("argument %q: minLength only applies to strings and lists, not %s", arg.Name, arg.TypeDef.ToType())
+		}
+		if *minLength < 0 {
+			return nil, fmt.Errorf("argument %q: minLength must not be negative, got %d", arg.Name, *minLength)
+		}
+	}
+	if len(allowedValues) > 0 && elemKind != TypeDefKindString {
+		return nil, fmt.Errorf("argument %q: allowedValues only applies to strings, not %s", arg.Name, arg.TypeDef.ToType())
+	}
+
+	arg.Pattern = pattern
+	arg.Min = minValue
+	arg.Max = maxValue
+	arg.MinLength = minLength
+	arg.AllowedValues = allowedValues
+
+	if arg.DefaultValue != nil {
+		if err := arg.CheckConstraints(arg.DefaultValue); err != nil {
+			return nil, fmt.Errorf("argument %q: invalid default value: %w", arg.Name, err)
+		}
+	}
+	return fn, nil
 }
 
 func (fn *Function) WithSourceMap(sourceMap *SourceMap) *Function {
@@ -131,6 +217,47 @@ func (fn *Function) WithSourceMap(sourceMap *SourceMap) *Function {
 	return fn
 }
 
+func (fn *Function) WithCachePolicy(policy FunctionCachePolicy, ttl time.Duration) (*Function, error) {
+	switch policy {
+	case FunctionCachePolicyTTL:
+		if ttl < time.Second {
+			return nil, fmt.Errorf("cache policy %s requires a time to live of at least 1s, got %s", policy, ttl)
+		}
+	default:
+		if ttl != 0 {
+			return nil, fmt.Errorf("cache policy %s does not accept a time to live", policy)
+		}
+	}
+	fn = fn.Clone()
+	fn.CachePolicy = policy
+	fn.CacheTTLSeconds = int(ttl / time.Second)
+	return fn, nil
+}
+
+func (fn *Function) WithDeprecated(reason string) *Function {
+	fn = fn.Clone()
+	fn.Deprecated = &reason
+	return fn
+}
+
+func (fn *Function) WithStreaming() (*Function, error) {
+	// only strings and lists of leaf values can be streamed: objects don't have
+	// an ID until the call that returns them has completed
+	switch ret := fn.ReturnType; ret.Kind {
+	case TypeDefKindString:
+	case TypeDefKindList:
+		switch elemKind := ret.AsList.Value.ElementTypeDef.Kind; elemKind {
+		case TypeDefKindObject, TypeDefKindInterface, TypeDefKindList:
+			return nil, fmt.Errorf("function %q cannot stream a list of %s", fn.Name, elemKind)
+		}
+	default:
+		return nil, fmt.Errorf("function %q cannot stream a %s result: only strings and lists can be streamed", fn.Name, ret.Kind)
+	}
+	fn = fn.Clone()
+	fn.Streaming = true
+	return fn, nil
+}
+
 func (fn *Function) IsSubtypeOf(otherFn *Function) bool {
 	if fn == nil || otherFn == nil {
 		return false
@@ -193,6 +320,13 @@ type FunctionArg struct {
 	DefaultValue JSON       `field:"true" doc:"A default value to use for this argument when not explicitly set by the caller, if any."`
 	DefaultPath  string     `field:"true" doc:"Only applies to arguments of type File or Directory. If the argument is not set, load it from the given path in the context directory"`
 	Ignore       []string   `field:"true" doc:"Only applies to arguments of type Directory. The ignore patterns are applied to the input directory, and matching entries are filtered out, in a cache-efficient manner."`
+	Deprecated   *string    `field:"true" doc:"The reason this argument is deprecated, if it is."`
+
+	Pattern       string   `field:"true" doc:"Only applies to arguments of type String, or lists of strings. A regular expression (RE2 syntax) that values must match, if any."`
+	Min           *float64 `field:"true" doc:"Only applies to arguments of type Integer or Float, or lists of those. The minimum value, inclusive, if any."`
+	Max           *float64 `field:"true" doc:"Only applies to arguments of type Integer or Float, or lists of those. The maximum value, inclusive, if any."`
+	MinLength     *int     `field:"true" doc:"Only applies to arguments of type String or List. The minimum number of characters of a string, or of elements of a list, if any."`
+	AllowedValues []string `field:"true" doc:"Only applies to arguments of type String, or lists of strings. The values that are accepted, if restricted."`
 
 	// Below are not in public API
 
@@ -214,6 +348,105 @@ func (arg FunctionArg) Clone() *FunctionArg {
 	return &cp
 }
 
+// HasConstraints returns true if values of the argument are constrained.
+func (arg *FunctionArg) HasConstraints() bool {
+	return arg.Pattern != "" || arg.Min != nil || arg.Max != nil || arg.MinLength != nil || len(arg.AllowedValues) > 0
+}
+
+// CheckConstraints returns an error if the given JSON-encoded value of the
+// argument doesn't satisfy its constraints. Null values are always accepted,
+// since they can only be given to optional arguments.
+func (arg *FunctionArg) CheckConstraints(value JSON) error {
+	if !arg.HasConstraints() {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("failed to decode value: %w", err)
+	}
+	if v == nil {
+		return nil
+	}
+
+	if arg.MinLength != nil {
+		var length int
+		switch x := v.(type) {
+		case string:
+			length = len([]rune(x))
+		case []any:
+			length = len(x)
+		}
+		if length < *arg.MinLength {
+			return fmt.Errorf("length %d is less than the minimum length %d", length, *arg.MinLength)
+		}
+	}
+
+	values := []any{v}
+	if list, ok := v.([]any); ok {
+		values = list
+	}
+	for _, v := range values {
+		if err := arg.checkValueConstraints(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (arg *FunctionArg) checkValueConstraints(v any) error {
+	switch x := v.(type) {
+	case string:
+		if arg.Pattern != "" {
+			re, err := compilePattern(arg.Pattern)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", arg.Pattern, err)
+			}
+			if !re.MatchString(x) {
+				return fmt.Errorf("value %q does not match pattern %q", x, arg.Pattern)
+			}
+		}
+		if len(arg.AllowedValues) > 0 && !slices.Contains(arg.AllowedValues, x) {
+			return fmt.Errorf("value %q is not one of the allowed values: %s", x, strings.Join(arg.AllowedValues, ", "))
+		}
+	case json.Number:
+		n, err := x.Float64()
+		if err != nil {
+			return fmt.Errorf("invalid number %s: %w", x, err)
+		}
+		if arg.Min != nil && n < *arg.Min {
+			return fmt.Errorf("value %s is less than the minimum %s", x, formatConstraint(*arg.Min))
+		}
+		if arg.Max != nil && n > *arg.Max {
+			return fmt.Errorf("value %s is greater than the maximum %s", x, formatConstraint(*arg.Max))
+		}
+	}
+	return nil
+}
+
+// compiledPatterns caches the compiled argument patterns, so that they're
+// compiled once however many values are checked, and however many times the
+// argument is rebuilt from its typedef.
+var compiledPatterns sync.Map // pattern -> *regexp.Regexp
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := compiledPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compiledPatterns.Store(pattern, re)
+	return re, nil
+}
+
+func formatConstraint(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 // Type returns the GraphQL FunctionArg! type.
 func (*FunctionArg) Type() *ast.Type {
 	return &ast.Type{
@@ -302,6 +535,8 @@ type TypeDef struct {
 	AsInput     dagql.Nullable[*InputTypeDef]     `field:"true" doc:"If kind is INPUT, the input-specific type definition. If kind is not INPUT, this will be null."`
 	AsScalar    dagql.Nullable[*ScalarTypeDef]    `field:"true" doc:"If kind is SCALAR, the scalar-specific type definition. If kind is not SCALAR, this will be null."`
 	AsEnum      dagql.Nullable[*EnumTypeDef]      `field:"true" doc:"If kind is ENUM, the enum-specific type definition. If kind is not ENUM, this will be null."`
+	AsMap       dagql.Nullable[*MapTypeDef]       `field:"true" doc:"If kind is MAP, the map-specific type definition. If kind is not MAP, this will be null."`
+	AsUnion     dagql.Nullable[*UnionTypeDef]     `field:"true" doc:"If kind is UNION, the union-specific type definition. If kind is not UNION, this will be null."`
 }
 
 func (typeDef TypeDef) Clone() *TypeDef {
@@ -324,6 +559,12 @@ func (typeDef TypeDef) Clone() *TypeDef {
 	if typeDef.AsEnum.Valid {
 		cp.AsEnum.Value = typeDef.AsEnum.Value.Clone()
 	}
+	if typeDef.AsMap.Valid {
+		cp.AsMap.Value = typeDef.AsMap.Value.Clone()
+	}
+	if typeDef.AsUnion.Valid {
+		cp.AsUnion.Value = typeDef.AsUnion.Value.Clone()
+	}
 	return &cp
 }
 
@@ -355,6 +596,9 @@ func (typeDef *TypeDef) ToTyped() dagql.Typed {
 		typed = &ModuleEnum{TypeDef: typeDef.AsEnum.Value}
 	case TypeDefKindList:
 		typed = dagql.DynamicArrayOutput{Elem: typeDef.AsList.Value.ElementTypeDef.ToTyped()}
+	case TypeDefKindMap, TypeDefKindUnion:
+		// GraphQL has no map or input union types, so both are represented as JSON
+		typed = JSON{}
 	case TypeDefKindObject:
 		typed = &ModuleObject{TypeDef: typeDef.AsObject.Value}
 	case TypeDefKindInterface:
@@ -391,6 +635,8 @@ func (typeDef *TypeDef) ToInput() dagql.Input {
 		typed = dagql.DynamicArrayInput{
 			Elem: typeDef.AsList.Value.ElementTypeDef.ToInput(),
 		}
+	case TypeDefKindMap, TypeDefKindUnion:
+		typed = JSON{}
 	case TypeDefKindObject:
 		typed = DynamicID{typeName: typeDef.AsObject.Value.Name}
 	case TypeDefKindInterface:
@@ -419,6 +665,80 @@ func (typeDef *TypeDef) Underlying() *TypeDef {
 	}
 }
 
+// JSONTypeDirective returns a directive giving the definition of a map or a
+// union type, or a list of those, since they're represented as JSON in the
+// schema. It returns nil for any other type.
+func (typeDef *TypeDef) JSONTypeDirective() *ast.Directive {
+	switch typeDef.Underlying().Kind {
+	case TypeDefKindMap, TypeDefKindUnion:
+	default:
+		return nil
+	}
+	bs, err := json.Marshal(typeDef.jsonTypeRef())
+	if err != nil {
+		// only strings and booleans are encoded, so this can't happen
+		panic(fmt.Sprintf("failed to encode type definition: %v", err))
+	}
+	return &ast.Directive{
+		Name: "jsonType",
+		Arguments: ast.ArgumentList{
+			{
+				Name: "typeDef",
+				Value: &ast.Value{
+					Kind: ast.StringValue,
+					Raw:  string(bs),
+				},
+			},
+		},
+	}
+}
+
+// jsonTypeRef is the definition of a type in the jsonType directive.
+type jsonTypeRef struct {
+	Kind     TypeDefKind `json:"kind"`
+	Optional bool        `json:"optional,omitempty"`
+	// Name is the name of an object, interface, enum, scalar or union.
+	Name string `json:"name,omitempty"`
+	// Of is the type of the elements of a list, or of the values of a map.
+	Of      *jsonTypeRef          `json:"of,omitempty"`
+	Members []*jsonUnionMemberRef `json:"members,omitempty"`
+}
+
+type jsonUnionMemberRef struct {
+	Name string       `json:"name"`
+	Type *jsonTypeRef `json:"type"`
+}
+
+func (typeDef *TypeDef) jsonTypeRef() *jsonTypeRef {
+	ref := &jsonTypeRef{
+		Kind:     typeDef.Kind,
+		Optional: typeDef.Optional,
+	}
+	switch typeDef.Kind {
+	case TypeDefKindList:
+		ref.Of = typeDef.AsList.Value.ElementTypeDef.jsonTypeRef()
+	case TypeDefKindMap:
+		ref.Of = typeDef.AsMap.Value.ValueTypeDef.jsonTypeRef()
+	case TypeDefKindUnion:
+		ref.Name = typeDef.AsUnion.Value.Name
+		for _, member := range typeDef.AsUnion.Value.Members {
+			ref.Members = append(ref.Members, &jsonUnionMemberRef{
+				Name: member.Name,
+				Type: member.TypeDef.jsonTypeRef(),
+			})
+		}
+	case TypeDefKindObject:
+		ref.Name = typeDef.AsObject.Value.Name
+	case TypeDefKindInterface:
+		ref.Name = typeDef.AsInterface.Value.Name
+	case TypeDefKindEnum:
+		ref.Name = typeDef.AsEnum.Value.Name
+	case TypeDefKindScalar:
+		ref.Name = typeDef.AsScalar.Value.Name
+	}
+	return ref
+}
+
 func (typeDef *TypeDef) WithKind(kind TypeDefKind) *TypeDef {
 	typeDef = typeDef.Clone()
 	typeDef.Kind = kind
@@ -439,6 +759,43 @@ func (typeDef *TypeDef) WithListOf(elem *TypeDef) *TypeDef {
 	return typeDef
 }
 
+func (typeDef *TypeDef) WithMapOf(value *TypeDef) *TypeDef {
+	typeDef = typeDef.WithKind(TypeDefKindMap)
+	typeDef.AsMap = dagql.NonNull(&MapTypeDef{
+		ValueTypeDef: value,
+	})
+	return typeDef
+}
+
+func (typeDef *TypeDef) WithUnion(name, desc string, sourceMap *SourceMap) *TypeDef {
+	typeDef = typeDef.WithKind(TypeDefKindUnion)
+	typeDef.AsUnion = dagql.NonNull(NewUnionTypeDef(name, desc, sourceMap))
+	return typeDef
+}
+
+func (typeDef *TypeDef) WithUnionMember(name string, memberType *TypeDef, desc string) (*TypeDef, error) {
+	if !typeDef.AsUnion.Valid {
+		return nil, fmt.Errorf("cannot add member to non-union type: %s", typeDef.Kind)
+	}
+	union := typeDef.AsUnion.Value
+	if memberType.Optional {
+		return nil, fmt.Errorf("union %q member %q cannot be optional", union.Name, name)
+	}
+	if memberType.Kind == TypeDefKindVoid {
+		return nil, fmt.Errorf("union %q member %q cannot be void", union.Name, name)
+	}
+	member := NewUnionMemberTypeDef(name, memberType, desc)
+	if member.Name == "" {
+		return nil, fmt.Errorf("union %q member must have a name", union.Name)
+	}
+	if _, ok := union.MemberByName(member.Name); ok {
+		return nil, fmt.Errorf("union %q member %q is already defined", union.Name, member.Name)
+	}
+	typeDef = typeDef.Clone()
+	typeDef.AsUnion.Value.Members = append(typeDef.AsUnion.Value.Members, member)
+	return typeDef, nil
+}
+
 func (typeDef *TypeDef) WithObject(name, desc string, sourceMap *SourceMap) *TypeDef {
 	typeDef = typeDef.WithKind(TypeDefKindObject)
 	typeDef.AsObject = dagql.NonNull(NewObjectTypeDef(name, desc).WithSourceMap(sourceMap))
@@ -457,7 +814,7 @@ func (typeDef *TypeDef) WithOptional(optional bool) *TypeDef {
 	return typeDef
 }
 
-func (typeDef *TypeDef) WithObjectField(name string, fieldType *TypeDef, desc string, sourceMap *SourceMap) (*TypeDef, error) {
+func (typeDef *TypeDef) WithObjectField(name string, fieldType *TypeDef, desc string, sourceMap *SourceMap, deprecated *string) (*TypeDef, error) {
 	if !typeDef.AsObject.Valid {
 		return nil, fmt.Errorf("cannot add function to non-object type: %s", typeDef.Kind)
 	}
@@ -468,6 +825,7 @@ func (typeDef *TypeDef) WithObjectField(name string, fieldType *TypeDef, desc st
 		Description:  desc,
 		SourceMap:    sourceMap,
 		TypeDef:      fieldType,
+		Deprecated:   deprecated,
 	})
 	return typeDef, nil
 }
@@ -507,7 +865,7 @@ func (typeDef *TypeDef) WithEnum(name, desc string, sourceMap *SourceMap) *TypeD
 	return typeDef
 }
 
-func (typeDef *TypeDef) WithEnumValue(name, desc string, sourceMap *SourceMap) (*TypeDef, error) {
+func (typeDef *TypeDef) WithEnumValue(name, desc string, sourceMap *SourceMap, deprecated *string) (*TypeDef, error) {
 	if !typeDef.AsEnum.Valid {
 		return nil, fmt.Errorf("cannot add value to non-enum type: %s", typeDef.Kind)
 	}
@@ -533,7 +891,7 @@ func (typeDef *TypeDef) WithEnumValue(name, desc string, sourceMap *SourceMap) (
 	}
 
 	typeDef = typeDef.Clone()
-	typeDef.AsEnum.Value.Values = append(typeDef.AsEnum.Value.Values, NewEnumValueTypeDef(name, desc, sourceMap))
+	typeDef.AsEnum.Value.Values = append(typeDef.AsEnum.Value.Values, NewEnumValueTypeDef(name, desc, sourceMap, deprecated))
 
 	return typeDef, nil
 }
@@ -559,6 +917,16 @@ func (typeDef *TypeDef) IsSubtypeOf(otherDef *TypeDef) bool {
 			return false
 		}
 		return typeDef.AsList.Value.ElementTypeDef.IsSubtypeOf(otherDef.AsList.Value.ElementTypeDef)
+	case TypeDefKindMap:
+		if otherDef.Kind != TypeDefKindMap {
+			return false
+		}
+		return typeDef.AsMap.Value.ValueTypeDef.IsSubtypeOf(otherDef.AsMap.Value.ValueTypeDef)
+	case TypeDefKindUnion:
+		if otherDef.Kind != TypeDefKindUnion {
+			return false
+		}
+		return typeDef.AsUnion.Value.IsSubtypeOf(otherDef.AsUnion.Value)
 	case TypeDefKindObject:
 		switch otherDef.Kind {
 		case TypeDefKindObject:
@@ -718,6 +1086,8 @@ type FieldTypeDef struct {
 
 	SourceMap *SourceMap `field:"true" doc:"The location of this field declaration."`
 
+	Deprecated *string `field:"true" doc:"The reason this field is deprecated, if it is."`
+
 	// Below are not in public API
 
 	// The original name of the object as provided by the SDK that defined it, used
@@ -886,6 +1256,145 @@ func (typeDef ListTypeDef) Clone() *ListTypeDef {
 	return &cp
 }
 
+type MapTypeDef struct {
+	ValueTypeDef *TypeDef `field:"true" doc:"The type of the values in the map. Keys are always strings."`
+}
+
+func (*MapTypeDef) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "MapTypeDef",
+		NonNull:   true,
+	}
+}
+
+func (*MapTypeDef) TypeDescription() string {
+	return "A definition of a map type in a Module, with string keys."
+}
+
+func (typeDef MapTypeDef) Clone() *MapTypeDef {
+	cp := typeDef
+	if typeDef.ValueTypeDef != nil {
+		cp.ValueTypeDef = typeDef.ValueTypeDef.Clone()
+	}
+	return &cp
+}
+
+type UnionTypeDef struct {
+	Name        string                `field:"true" doc:"The name of the union."`
+	Description string                `field:"true" doc:"A doc string for the union, if any."`
+	Members     []*UnionMemberTypeDef `field:"true" doc:"The members of the union, exactly one of which is set in a value."`
+	SourceMap   *SourceMap            `field:"true" doc:"The location of this union declaration."`
+
+	// Below are not in public API
+
+	// The original name of the union as provided by the SDK that defined it
+	OriginalName string
+}
+
+func (*UnionTypeDef) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "UnionTypeDef",
+		NonNull:   true,
+	}
+}
+
+func (*UnionTypeDef) TypeDescription() string {
+	return `A definition of a tagged union in a Module.
+
+A union value is a JSON object with exactly one key, naming the member that is
+set, e.g. {"directory": "<id>"}.`
+}
+
+func NewUnionTypeDef(name, description string, sourceMap *SourceMap) *UnionTypeDef {
+	return &UnionTypeDef{
+		Name:         strcase.ToCamel(name),
+		OriginalName: name,
+		Description:  description,
+		SourceMap:    sourceMap,
+	}
+}
+
+func (union UnionTypeDef) Clone() *UnionTypeDef {
+	cp := union
+
+	cp.Members = make([]*UnionMemberTypeDef, len(union.Members))
+	for i, member := range union.Members {
+		cp.Members[i] = member.Clone()
+	}
+	if union.SourceMap != nil {
+		cp.SourceMap = union.SourceMap.Clone()
+	}
+
+	return &cp
+}
+
+// MemberByName returns the member with the given name, matching either its
+// standardized or original name.
+func (union *UnionTypeDef) MemberByName(name string) (*UnionMemberTypeDef, bool) {
+	for _, member := range union.Members {
+		if member.Name == name || member.OriginalName == name {
+			return member, true
+		}
+	}
+	return nil, false
+}
+
+func (union *UnionTypeDef) IsSubtypeOf(other *UnionTypeDef) bool {
+	if union == nil || other == nil {
+		return false
+	}
+	if union.Name != other.Name || len(union.Members) != len(other.Members) {
+		return false
+	}
+	for _, member := range union.Members {
+		otherMember, ok := other.MemberByName(member.Name)
+		if !ok || !member.TypeDef.IsSubtypeOf(otherMember.TypeDef) {
+			return false
+		}
+	}
+	return true
+}
+
+type UnionMemberTypeDef struct {
+	Name        string   `field:"true" doc:"The name of the member, used as its tag in union values."`
+	Description string   `field:"true" doc:"A doc string for the member, if any."`
+	TypeDef     *TypeDef `field:"true" doc:"The type of the member."`
+
+	// Below are not in public API
+
+	// The original name of the member as provided by the SDK that defined it,
+	// used as its tag when invoking the SDK
+	OriginalName string
+}
+
+func (*UnionMemberTypeDef) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "UnionMemberTypeDef",
+		NonNull:   true,
+	}
+}
+
+func (*UnionMemberTypeDef) TypeDescription() string {
+	return "A definition of a member of a union defined in a Module."
+}
+
+func NewUnionMemberTypeDef(name string, typeDef *TypeDef, description string) *UnionMemberTypeDef {
+	return &UnionMemberTypeDef{
+		Name:         strcase.ToLowerCamel(name),
+		OriginalName: name,
+		Description:  description,
+		TypeDef:      typeDef,
+	}
+}
+
+func (member UnionMemberTypeDef) Clone() *UnionMemberTypeDef {
+	cp := member
+	if member.TypeDef != nil {
+		cp.TypeDef = member.TypeDef.Clone()
+	}
+	return &cp
+}
+
 type InputTypeDef struct {
 	Name   string          `field:"true" doc:"The name of the input object."`
 	Fields []*FieldTypeDef `field:"true" doc:"Static fields defined on this input object, if any."`
@@ -922,9 +1431,10 @@ func (typeDef *InputTypeDef) ToInputObjectSpec() dagql.InputObjectSpec {
 	}
 	for _, field := range typeDef.Fields {
 		spec.Fields = append(spec.Fields, dagql.InputSpec{
-			Name:        field.Name,
-			Description: field.Description,
-			Type:        field.TypeDef.ToInput(),
+			Name:             field.Name,
+			Description:      field.Description,
+			Type:             field.TypeDef.ToInput(),
+			DeprecatedReason: deprecationReason(field.Deprecated),
 		})
 	}
 	return spec
@@ -963,10 +1473,14 @@ func (enum *EnumTypeDef) ListValues() ast.EnumValueList {
 	var values ast.EnumValueList
 
 	for _, val := range enum.Values {
-		values = append(values, &ast.EnumValueDefinition{
+		def := &ast.EnumValueDefinition{
 			Name:        val.Name,
 			Description: val.Description,
-		})
+		}
+		if reason := deprecationReason(val.Deprecated); reason != "" {
+			def.Directives = append(def.Directives, dagql.DeprecatedDirective(reason))
+		}
+		values = append(values, def)
 	}
 
 	return values
@@ -999,6 +1513,7 @@ type EnumValueTypeDef struct {
 	Name        string     `field:"true" doc:"The name of the enum value."`
 	Description string     `field:"true" doc:"A doc string for the enum value, if any."`
 	SourceMap   *SourceMap `field:"true" doc:"The location of this enum value declaration."`
+	Deprecated  *string    `field:"true" doc:"The reason this enum value is deprecated, if it is."`
 }
 
 func (*EnumValueTypeDef) Type() *ast.Type {
@@ -1012,11 +1527,12 @@ func (*EnumValueTypeDef) TypeDescription() string {
 	return "A definition of a value in a custom enum defined in a Module."
 }
 
-func NewEnumValueTypeDef(name, description string, sourceMap *SourceMap) *EnumValueTypeDef {
+func NewEnumValueTypeDef(name, description string, sourceMap *SourceMap, deprecated *string) *EnumValueTypeDef {
 	return &EnumValueTypeDef{
 		Name:        name,
 		Description: description,
 		SourceMap:   sourceMap,
+		Deprecated:  deprecated,
 	}
 }
 
@@ -1074,6 +1590,16 @@ var (
 		"A GraphQL enum type and its values",
 		"Always paired with an EnumTypeDef.",
 	)
+	TypeDefKindMap = TypeDefKinds.Register("MAP_KIND",
+		"A map of string keys to values all having the same type.",
+		`Always paired with a MapTypeDef. Maps are represented as a JSON object in
+		the GraphQL schema.`,
+	)
+	TypeDefKindUnion = TypeDefKinds.Register("UNION_KIND",
+		"A tagged union of values of different types, exactly one of which is set.",
+		`Always paired with a UnionTypeDef. Unions are represented as a JSON object
+		with a single key in the GraphQL schema.`,
+	)
 )
 
 func (k TypeDefKind) Type() *ast.Type {
@@ -1095,6 +1621,48 @@ func (k TypeDefKind) ToLiteral() call.Literal {
 	return TypeDefKinds.Literal(k)
 }
 
+type FunctionCachePolicy string
+
+func (p FunctionCachePolicy) String() string {
+	return string(p)
+}
+
+var FunctionCachePolicies = dagql.NewEnum[FunctionCachePolicy]()
+
+var (
+	FunctionCachePolicyDefault = FunctionCachePolicies.Register("DEFAULT",
+		`Calls are cached for the duration of the session that made them, or
+		across sessions for calls made by the engine itself.`)
+	FunctionCachePolicyNever = FunctionCachePolicies.Register("NEVER",
+		"Calls are never cached: the function runs every time it's called.")
+	FunctionCachePolicyPerSession = FunctionCachePolicies.Register("PER_SESSION",
+		"Calls are cached for the duration of the session that made them.")
+	FunctionCachePolicyTTL = FunctionCachePolicies.Register("TTL",
+		`Calls are cached across sessions, for the time to live of the
+		function.`,
+		`Cached results are dropped when the current time window of that
+		length ends, so they're never older than the time to live.`)
+)
+
+func (p FunctionCachePolicy) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "FunctionCachePolicy",
+		NonNull:   true,
+	}
+}
+
+func (p FunctionCachePolicy) TypeDescription() string {
+	return `How the results of calls to a function are cached.`
+}
+
+func (p FunctionCachePolicy) Decoder() dagql.InputDecoder {
+	return FunctionCachePolicies
+}
+
+func (p FunctionCachePolicy) ToLiteral() call.Literal {
+	return FunctionCachePolicies.Literal(p)
+}
+
 type FunctionCall struct {
 	Query *Query `json:"-"`
 
@@ -1132,6 +1700,21 @@ func (fnCall *FunctionCall) ReturnValue(ctx context.Context, val JSON) error {
 	)
 }
 
+func (fnCall *FunctionCall) ReturnItem(ctx context.Context, item JSON) error {
+	// Items are sent straight to the caller subscribed to the function's
+	// results, if any. They are not part of the result cached with the
+	// function's Exec, so the function must still return the full value.
+	streams, err := fnCall.Query.FunctionCallStreams(ctx)
+	if err != nil {
+		return fmt.Errorf("get function call streams: %w", err)
+	}
+	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("get client metadata: %w", err)
+	}
+	return streams.Send(ctx, clientMetadata.ClientID, item)
+}
+
 func (fnCall *FunctionCall) ReturnError(ctx context.Context, errID dagql.ID[*Error]) error {
 	// The return is implemented by exporting the result back to the caller's
 	// filesystem. This ensures that the result is cached as part of the module
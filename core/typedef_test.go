@@ -1,8 +1,11 @@
 package core
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/dagger/dagger/dagql"
 )
@@ -57,6 +60,23 @@ var Samples = map[TypeDefKind]*TypeDef{
 	TypeDefKindVoid: {
 		Kind: TypeDefKindVoid,
 	},
+	TypeDefKindMap: {
+		Kind: TypeDefKindMap,
+		AsMap: dagql.NonNull(&MapTypeDef{
+			ValueTypeDef: &TypeDef{
+				Kind: TypeDefKindString,
+			},
+		}),
+	},
+	TypeDefKindUnion: {
+		Kind: TypeDefKindUnion,
+		AsUnion: dagql.NonNull(&UnionTypeDef{
+			Name: "FooUnion",
+			Members: []*UnionMemberTypeDef{
+				{Name: "foo", TypeDef: &TypeDef{Kind: TypeDefKindString}},
+			},
+		}),
+	},
 }
 
 func TestTypeDefConversions(t *testing.T) {
@@ -82,3 +102,302 @@ func TestTypeDefConversions(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionWithCachePolicy(t *testing.T) {
+	fn := NewFunction("foo", Samples[TypeDefKindString])
+	if fn.CachePolicy != FunctionCachePolicyDefault {
+		t.Fatalf("expected default cache policy, got %s", fn.CachePolicy)
+	}
+
+	ttlFn, err := fn.WithCachePolicy(FunctionCachePolicyTTL, 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttlFn.CachePolicy != FunctionCachePolicyTTL || ttlFn.CacheTTLSeconds != 600 {
+		t.Fatalf("unexpected cache policy %s with TTL %d", ttlFn.CachePolicy, ttlFn.CacheTTLSeconds)
+	}
+	if fn.CachePolicy != FunctionCachePolicyDefault {
+		t.Fatalf("original function was modified")
+	}
+
+	if _, err := fn.WithCachePolicy(FunctionCachePolicyTTL, 0); err == nil {
+		t.Fatal("expected an error for a TTL policy without a time to live")
+	}
+	if _, err := fn.WithCachePolicy(FunctionCachePolicyNever, time.Minute); err == nil {
+		t.Fatal("expected an error for a NEVER policy with a time to live")
+	}
+}
+
+func TestFunctionWithStreaming(t *testing.T) {
+	for _, returnType := range []*TypeDef{
+		Samples[TypeDefKindString],
+		(&TypeDef{}).WithListOf(Samples[TypeDefKindInteger]),
+		(&TypeDef{}).WithListOf(Samples[TypeDefKindEnum]),
+	} {
+		t.Run(returnType.ToType().String(), func(t *testing.T) {
+			fn := NewFunction("foo", returnType)
+			streamingFn, err := fn.WithStreaming()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !streamingFn.Streaming {
+				t.Fatal("expected function to be streaming")
+			}
+			if fn.Streaming {
+				t.Fatal("original function was modified")
+			}
+		})
+	}
+
+	for _, returnType := range []*TypeDef{
+		Samples[TypeDefKindInteger],
+		Samples[TypeDefKindObject],
+		(&TypeDef{}).WithListOf(Samples[TypeDefKindObject]),
+		(&TypeDef{}).WithListOf(Samples[TypeDefKindInterface]),
+		(&TypeDef{}).WithListOf(Samples[TypeDefKindList]),
+	} {
+		t.Run(returnType.ToType().String(), func(t *testing.T) {
+			if _, err := NewFunction("foo", returnType).WithStreaming(); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestFunctionWithArgDeprecated(t *testing.T) {
+	fn := NewFunction("build", Samples[TypeDefKindString])
+	reason := "Not needed anymore."
+
+	for _, tc := range []struct {
+		name         string
+		typeDef      *TypeDef
+		defaultValue JSON
+	}{
+		{"tag", Samples[TypeDefKindString].WithOptional(true), nil},
+		{"mode", Samples[TypeDefKindString], JSON(`"fast"`)},
+	} {
+		deprecatedFn, err := fn.WithArg(tc.name, tc.typeDef, "", tc.defaultValue, "", nil, nil, &reason)
+		if err != nil {
+			t.Fatal(err)
+		}
+		arg, _ := deprecatedFn.LookupArg(tc.name)
+		if arg.Deprecated == nil || *arg.Deprecated != reason {
+			t.Fatalf("expected %q to be deprecated", tc.name)
+		}
+	}
+
+	if _, err := fn.WithArg("version", Samples[TypeDefKindString], "", nil, "", nil, nil, &reason); err == nil {
+		t.Fatal("expected an error for a required argument")
+	}
+}
+
+func TestTypeDefWithUnionMember(t *testing.T) {
+	union := (&TypeDef{}).WithUnion("Source", "", nil)
+	union, err := union.WithUnionMember("GitURL", Samples[TypeDefKindString], "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, ok := union.AsUnion.Value.MemberByName("gitUrl")
+	if !ok || member.OriginalName != "GitURL" {
+		t.Fatalf("unexpected member %+v", member)
+	}
+
+	if _, err := union.WithUnionMember("gitURL", Samples[TypeDefKindInteger], ""); err == nil {
+		t.Fatal("expected an error for a duplicate member")
+	}
+	if _, err := union.WithUnionMember("path", Samples[TypeDefKindString].WithOptional(true), ""); err == nil {
+		t.Fatal("expected an error for an optional member")
+	}
+	if _, err := Samples[TypeDefKindString].WithUnionMember("path", Samples[TypeDefKindString], ""); err == nil {
+		t.Fatal("expected an error for a non-union type")
+	}
+}
+
+func TestMapAndUnionTypeConversions(t *testing.T) {
+	ctx := context.Background()
+	intType := &PrimitiveType{Def: Samples[TypeDefKindInteger]}
+
+	mapType := &MapType{Value: intType.Def, Underlying: intType}
+	typed, err := mapType.ConvertFromSDKResult(ctx, map[string]any{"a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(typed.(JSON)) != `{"a":1}` {
+		t.Fatalf("unexpected map result %s", typed)
+	}
+	if _, err := mapType.ConvertFromSDKResult(ctx, map[string]any{"a": "nope"}); err == nil {
+		t.Fatal("expected an error for a value of the wrong type")
+	}
+
+	unionDef, err := (&TypeDef{}).WithUnion("Value", "", nil).WithUnionMember("Count", intType.Def, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unionType := &UnionType{
+		Def:     unionDef.AsUnion.Value,
+		Members: map[string]ModType{"count": intType},
+	}
+	typed, err = unionType.ConvertFromSDKResult(ctx, map[string]any{"Count": 2, "Other": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(typed.(JSON)) != `{"count":2}` {
+		t.Fatalf("unexpected union result %s", typed)
+	}
+	input, err := unionType.ConvertToSDKInput(ctx, typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := input.(map[string]any)["Count"]; !ok {
+		t.Fatalf("expected SDK input to use the original member name, got %v", input)
+	}
+	if _, err := unionType.ConvertToSDKInput(ctx, JSON(`{}`)); err == nil {
+		t.Fatal("expected an error for a union value with no member set")
+	}
+	if _, err := unionType.ConvertToSDKInput(ctx, JSON(`{"count":1,"nope":2}`)); err == nil {
+		t.Fatal("expected an error for a union value with an unknown member")
+	}
+
+	// values may also be written as object literals in a query
+	literal, err := JSON{}.DecodeInput(map[string]any{"count": int64(3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(literal.(JSON)) != `{"count":3}` {
+		t.Fatalf("unexpected decoded literal %s", literal)
+	}
+}
+
+func TestTypeDefJSONTypeDirective(t *testing.T) {
+	if directive := Samples[TypeDefKindString].JSONTypeDirective(); directive != nil {
+		t.Fatalf("unexpected directive for a string: %+v", directive)
+	}
+
+	unionDef, err := (&TypeDef{}).WithUnion("Source", "", nil).WithUnionMember("directory", Samples[TypeDefKindObject], "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unionDef, err = unionDef.WithUnionMember("gitURL", Samples[TypeDefKindString], "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		typeDef *TypeDef
+		want    string
+	}{
+		{
+			(&TypeDef{}).WithMapOf(Samples[TypeDefKindString]).WithOptional(true),
+			`{"kind":"MAP_KIND","optional":true,"of":{"kind":"STRING_KIND"}}`,
+		},
+		{
+			(&TypeDef{}).WithListOf((&TypeDef{}).WithMapOf(Samples[TypeDefKindObject])),
+			`{"kind":"LIST_KIND","of":{"kind":"MAP_KIND","of":{"kind":"OBJECT_KIND","name":"FooObject"}}}`,
+		},
+		{
+			unionDef,
+			`{"kind":"UNION_KIND","name":"Source","members":[{"name":"directory","type":{"kind":"OBJECT_KIND","name":"FooObject"}},{"name":"gitUrl","type":{"kind":"STRING_KIND"}}]}`,
+		},
+	} {
+		directive := tc.typeDef.JSONTypeDirective()
+		if directive == nil {
+			t.Fatalf("missing directive for %s", tc.typeDef.Kind)
+		}
+		if directive.Name != "jsonType" {
+			t.Fatalf("unexpected directive %q", directive.Name)
+		}
+		if got := directive.Arguments.ForName("typeDef").Value.Raw; got != tc.want {
+			t.Errorf("unexpected type definition:\n got: %s\nwant: %s", got, tc.want)
+		}
+	}
+}
+
+func TestFunctionWithArgConstraints(t *testing.T) {
+	fn := NewFunction("build", Samples[TypeDefKindString])
+	fn, err := fn.WithArg("version", Samples[TypeDefKindString], "", nil, "", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn, err = fn.WithArg("replicas", Samples[TypeDefKindInteger], "", JSON("3"), "", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn, err = fn.WithArg("tags", (&TypeDef{}).WithListOf(Samples[TypeDefKindString]), "", nil, "", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fn, err = fn.WithArgConstraints("version", `^v\d+\.\d+\.\d+$`, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn, err = fn.WithArgConstraints("replicas", "", ptr(1.0), ptr(64.0), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn, err = fn.WithArgConstraints("tags", "", nil, nil, ptr(1), []string{"latest", "stable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		arg   string
+		value string
+		valid bool
+	}{
+		{"version", `"v1.2.3"`, true},
+		{"version", `"1.2"`, false},
+		{"version", `null`, true},
+		{"replicas", `1`, true},
+		{"replicas", `0`, false},
+		{"replicas", `65`, false},
+		{"tags", `["latest","stable"]`, true},
+		{"tags", `[]`, false},
+		{"tags", `["latest","edge"]`, false},
+	} {
+		arg, ok := fn.LookupArg(tc.arg)
+		if !ok {
+			t.Fatalf("missing arg %q", tc.arg)
+		}
+		err := arg.CheckConstraints(JSON(tc.value))
+		if tc.valid && err != nil {
+			t.Errorf("%s=%s: unexpected error: %v", tc.arg, tc.value, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%s=%s: expected an error", tc.arg, tc.value)
+		}
+	}
+
+	// the constraints still hold when the function is rebuilt from its JSON
+	// encoding, like a typedef loaded from the cache
+	fnJSON, err := json.Marshal(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reloaded Function
+	if err := json.Unmarshal(fnJSON, &reloaded); err != nil {
+		t.Fatal(err)
+	}
+	arg, ok := reloaded.LookupArg("version")
+	if !ok {
+		t.Fatal("missing arg \"version\"")
+	}
+	if err := arg.CheckConstraints(JSON(`"1.2"`)); err == nil {
+		t.Fatal("expected an error for a value not matching the pattern of a reloaded argument")
+	}
+	if err := arg.CheckConstraints(JSON(`"v1.2.3"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := fn.WithArgConstraints("replicas", `^\d+$`, nil, nil, nil, nil); err == nil {
+		t.Fatal("expected an error for a pattern on an integer argument")
+	}
+	if _, err := fn.WithArgConstraints("version", `(`, nil, nil, nil, nil); err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+	if _, err := fn.WithArgConstraints("replicas", "", ptr(10.0), ptr(1.0), nil, nil); err == nil {
+		t.Fatal("expected an error for min greater than max")
+	}
+	if _, err := fn.WithArgConstraints("replicas", "", ptr(5.0), nil, nil, nil); err == nil {
+		t.Fatal("expected an error for a default value below the minimum")
+	}
+}
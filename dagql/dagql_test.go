@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
@@ -520,6 +521,121 @@ func TestListResults(t *testing.T) {
 	assert.Check(t, res.NullableListOfObjects == nil)
 }
 
+func TestSubscriptions(t *testing.T) {
+	srv := dagql.NewServer(Query{})
+	points.Install[Query](srv)
+
+	dagql.Fields[Query]{
+		dagql.Func("chunks", func(ctx context.Context, self Query, args struct{}) (dagql.String, error) {
+			for _, chunk := range []string{"a", "b", "c"} {
+				if err := dagql.SendStreamItem(ctx, chunk); err != nil {
+					return "", err
+				}
+			}
+			return "abc", nil
+		}).Streaming(),
+		dagql.Func("listOfInts", func(ctx context.Context, self Query, args struct{}) ([]int, error) {
+			return []int{1, 2, 3}, nil
+		}).Streaming(),
+		dagql.Func("greeting", func(ctx context.Context, self Query, args struct{}) (dagql.String, error) {
+			return "hello", nil
+		}),
+	}.Install(srv)
+
+	dagql.Fields[*points.Point]{
+		dagql.Func("coordinates", func(ctx context.Context, self *points.Point, args struct{}) ([]int, error) {
+			return []int{self.X, self.Y}, nil
+		}).Streaming(),
+	}.Install(srv)
+
+	gql := client.New(dagql.NewDefaultHandler(srv))
+
+	subscribe := func(t *testing.T, query string) ([]map[string]any, error) {
+		t.Helper()
+		sse := gql.SSE(context.Background(), query)
+		defer sse.Close()
+		var responses []map[string]any
+		for {
+			var res client.SSEResponse
+			if err := sse.Next(&res); err != nil {
+				return responses, err
+			}
+			data, ok := res.Data.(map[string]any)
+			if !ok {
+				// the stream is complete
+				return responses, nil
+			}
+			responses = append(responses, data)
+		}
+	}
+
+	t.Run("streamed items", func(t *testing.T) {
+		responses, err := subscribe(t, `subscription { chunks }`)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []map[string]any{
+			{"chunks": "a"},
+			{"chunks": "b"},
+			{"chunks": "c"},
+		}, responses)
+	})
+
+	t.Run("list elements", func(t *testing.T) {
+		responses, err := subscribe(t, `subscription { listOfInts }`)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []map[string]any{
+			{"listOfInts": float64(1)},
+			{"listOfInts": float64(2)},
+			{"listOfInts": float64(3)},
+		}, responses)
+	})
+
+	t.Run("nested field", func(t *testing.T) {
+		responses, err := subscribe(t, `subscription { point(x: 1, y: 2) { shiftLeft { coordinates } } }`)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []map[string]any{
+			{"point": map[string]any{"shiftLeft": map[string]any{"coordinates": float64(0)}}},
+			{"point": map[string]any{"shiftLeft": map[string]any{"coordinates": float64(2)}}},
+		}, responses)
+	})
+
+	t.Run("fields of a list", func(t *testing.T) {
+		_, err := subscribe(t, `subscription { point(x: 1, y: 2) { neighbors { coordinates } } }`)
+		assert.ErrorContains(t, err, "cannot subscribe to the fields of a list")
+	})
+
+	t.Run("multiple fields", func(t *testing.T) {
+		_, err := subscribe(t, `subscription { point(x: 1, y: 2) { x y } }`)
+		assert.ErrorContains(t, err, "subscriptions must select exactly one field at a time")
+	})
+
+	t.Run("not streaming", func(t *testing.T) {
+		_, err := subscribe(t, `subscription { greeting }`)
+		assert.ErrorContains(t, err, `Cannot query field \"greeting\" on type \"Subscription\"`)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		schema := srv.Schema()
+		assert.Assert(t, schema.Subscription != nil)
+		assert.Equal(t, "Subscription", schema.Subscription.Name)
+		var fields []string
+		for _, field := range schema.Subscription.Fields {
+			fields = append(fields, field.Name)
+		}
+		assert.Check(t, cmp.Contains(fields, "chunks"))
+		assert.Check(t, cmp.Contains(fields, "listOfInts"))
+		assert.Check(t, cmp.Contains(fields, "point"))
+		assert.Check(t, !slices.Contains(fields, "greeting"))
+	})
+
+	t.Run("queries are not streamed", func(t *testing.T) {
+		var res struct {
+			Chunks string
+		}
+		req(t, gql, `query { chunks }`, &res)
+		assert.Equal(t, "abc", res.Chunks)
+	})
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
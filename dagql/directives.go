@@ -70,7 +70,8 @@ var (
 	DirectiveLocationInputFieldDefinition = DirectiveLocations.Register("INPUT_FIELD_DEFINITION")
 )
 
-func deprecated(reason string) *ast.Directive {
+// DeprecatedDirective returns a @deprecated directive with the given reason.
+func DeprecatedDirective(reason string) *ast.Directive {
 	return &ast.Directive{
 		Name: "deprecated",
 		Arguments: []*ast.Argument{
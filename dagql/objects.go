@@ -793,6 +793,9 @@ type FieldSpec struct {
 	ImpurityReason string
 	// DeprecatedReason deprecates the field and provides a reason.
 	DeprecatedReason string
+	// Streaming indicates that the field's result may be delivered incrementally
+	// to subscriptions, with SendStreamItem.
+	Streaming bool
 	// Module is the module that provides the field's implementation.
 	Module *call.Module
 	// Directives is the list of GraphQL directives attached to this field.
@@ -814,7 +817,7 @@ func (spec FieldSpec) FieldDefinition() *ast.FieldDefinition {
 		def.Directives = append([]*ast.Directive{}, spec.Directives...)
 	}
 	if spec.DeprecatedReason != "" {
-		def.Directives = append(def.Directives, deprecated(spec.DeprecatedReason))
+		def.Directives = append(def.Directives, DeprecatedDirective(spec.DeprecatedReason))
 	}
 	if spec.ImpurityReason != "" {
 		def.Directives = append(def.Directives, impure(spec.ImpurityReason))
@@ -870,7 +873,7 @@ func (specs InputSpecs) ArgumentDefinitions() []*ast.ArgumentDefinition {
 			schemaArg.Directives = append([]*ast.Directive{}, spec.Directives...)
 		}
 		if spec.DeprecatedReason != "" {
-			schemaArg.Directives = append(schemaArg.Directives, deprecated(spec.DeprecatedReason))
+			schemaArg.Directives = append(schemaArg.Directives, DeprecatedDirective(spec.DeprecatedReason))
 		}
 		defs[i] = schemaArg
 	}
@@ -892,7 +895,7 @@ func (specs InputSpecs) FieldDefinitions() []*ast.FieldDefinition {
 			field.Directives = append([]*ast.Directive{}, spec.Directives...)
 		}
 		if spec.DeprecatedReason != "" {
-			field.Directives = append(field.Directives, deprecated(spec.DeprecatedReason))
+			field.Directives = append(field.Directives, DeprecatedDirective(spec.DeprecatedReason))
 		}
 		fields[i] = field
 	}
@@ -1099,6 +1102,16 @@ func (field Field[T]) Meta() Field[T] {
 	return field
 }
 
+// Streaming indicates that the field's result may be delivered incrementally
+// to subscriptions, which exposes the field on the Subscription root type.
+func (field Field[T]) Streaming() Field[T] {
+	if field.Spec.extend {
+		panic("cannot call on extended field")
+	}
+	field.Spec.Streaming = true
+	return field
+}
+
 // FieldDefinition returns the schema definition of the field.
 func (field Field[T]) FieldDefinition() *ast.FieldDefinition {
 	spec := field.Spec
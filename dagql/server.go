@@ -8,10 +8,14 @@ import (
 	"reflect"
 	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/errcode"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/iancoleman/strcase"
 	"github.com/opencontainers/go-digest"
 	"github.com/sourcegraph/conc/pool"
@@ -123,8 +127,29 @@ func NewServer[T Typed](root T) *Server {
 }
 
 func NewDefaultHandler(es graphql.ExecutableSchema) *handler.Server {
-	// TODO: avoid this deprecated method, and customize the options
-	return handler.NewDefaultServer(es) //nolint: staticcheck
+	srv := handler.New(es)
+
+	// subscriptions are streamed as server-sent events, which needs to be
+	// checked before plain POST requests
+	srv.AddTransport(transport.SSE{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](100),
+	})
+
+	return srv
 }
 
 var coreScalars = []ScalarType{
@@ -221,6 +246,24 @@ var coreDirectives = []DirectiveSpec{
 			DirectiveLocationInputObject,
 		},
 	},
+	{
+		Name: "jsonType",
+		Description: FormatDescription(
+			`Indicates that a JSON field or argument holds a value of a type that
+			GraphQL can't represent, such as a map or a union, so that clients can
+			give it a more specific type.`),
+		Args: []InputSpec{
+			{
+				Name:        "typeDef",
+				Description: FormatDescription(`The JSON-encoded definition of the type.`),
+				Type:        String(""),
+			},
+		},
+		Locations: []DirectiveLocation{
+			DirectiveLocationFieldDefinition,
+			DirectiveLocationArgumentDefinition,
+		},
+	},
 }
 
 // Root returns the root object of the server. It is suitable for passing to
@@ -358,6 +401,11 @@ func (s *Server) Schema() *ast.Schema { // TODO: change this to be updated whene
 		schema.AddTypes(def)
 		schema.AddPossibleType(def.Name, def)
 	}
+	if def := s.subscriptionDefinition(schema); def != nil {
+		schema.Subscription = def
+		schema.AddTypes(def)
+		schema.AddPossibleType(def.Name, def)
+	}
 	for _, t := range s.scalars {
 		def := definition(ast.Scalar, t, s.View)
 		schema.AddTypes(def)
@@ -389,6 +437,9 @@ type ExtendedError interface {
 
 // Exec implements graphql.ExecutableSchema.
 func (s *Server) Exec(ctx1 context.Context) graphql.ResponseHandler {
+	if op := graphql.GetOperationContext(ctx1).Operation; op != nil && op.Operation == ast.Subscription {
+		return s.subscriptionResponses()
+	}
 	return func(ctx context.Context) (res *graphql.Response) {
 		gqlOp := graphql.GetOperationContext(ctx)
 
@@ -429,20 +480,8 @@ func gqlErrs(err error) (errs gqlerror.List) {
 }
 
 func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (map[string]any, error) {
-	if gqlOp.Doc == nil {
-		var err error
-		gqlOp.Doc, err = parser.ParseQuery(&ast.Source{Input: gqlOp.RawQuery})
-		if err != nil {
-			return nil, gqlErrs(err)
-		}
-
-		listErr := validator.Validate(s.Schema(), gqlOp.Doc)
-		if len(listErr) != 0 {
-			for _, e := range listErr {
-				errcode.Set(e, errcode.ValidationFailed)
-			}
-			return nil, listErr
-		}
+	if err := s.parseOp(gqlOp); err != nil {
+		return nil, err
 	}
 	results := make(map[string]any)
 	for _, op := range gqlOp.Doc.Operations {
@@ -463,13 +502,34 @@ func (s *Server) ExecOp(ctx context.Context, gqlOp *graphql.OperationContext) (m
 			// TODO
 			return nil, fmt.Errorf("mutations not supported")
 		case ast.Subscription:
-			// TODO
-			return nil, fmt.Errorf("subscriptions not supported")
+			return nil, fmt.Errorf("subscriptions must be executed with Subscribe")
 		}
 	}
 	return results, nil
 }
 
+// parseOp parses and validates the operation's query, unless it already has
+// been.
+func (s *Server) parseOp(gqlOp *graphql.OperationContext) error {
+	if gqlOp.Doc != nil {
+		return nil
+	}
+	var err error
+	gqlOp.Doc, err = parser.ParseQuery(&ast.Source{Input: gqlOp.RawQuery})
+	if err != nil {
+		return gqlErrs(err)
+	}
+
+	listErr := validator.Validate(s.Schema(), gqlOp.Doc)
+	if len(listErr) != 0 {
+		for _, e := range listErr {
+			errcode.Set(e, errcode.ValidationFailed)
+		}
+		return listErr
+	}
+	return nil
+}
+
 // Resolve resolves the given selections on the given object.
 //
 // Each selection is resolved in parallel, and the results are returned in a
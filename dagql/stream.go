@@ -0,0 +1,269 @@
+package dagql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Subscriptions deliver the result of the selected field incrementally: each
+// response holds one item of the result, nested under the path of the selected
+// fields. Items are the elements of a list, or the chunks of a string streamed
+// by its resolver.
+//
+// The Subscription root type has the fields of the Query root type that lead
+// to a streaming field, and they are resolved against the same root object. A
+// subscription selects a single field at each level, so that every response
+// has the same shape.
+
+// subscriptionType is the name of the root type of subscriptions.
+const subscriptionType = "Subscription"
+
+type streamKey struct{}
+
+// fieldStream delivers the items of the field being resolved by a
+// subscription.
+type fieldStream struct {
+	send func(ctx context.Context, item any) error
+
+	mu   sync.Mutex
+	sent bool
+}
+
+// IsStreaming returns true if items sent with SendStreamItem are delivered to
+// a subscriber, i.e. if the field being resolved is the one selected by a
+// subscription.
+func IsStreaming(ctx context.Context) bool {
+	_, ok := ctx.Value(streamKey{}).(*fieldStream)
+	return ok
+}
+
+// SendStreamItem sends an item of the result of the field being resolved to
+// the subscriber of the current subscription, as soon as it's available. It
+// does nothing outside of subscriptions.
+//
+// Resolvers that send items are responsible for sending all of them: the
+// result they return is then not sent again.
+func SendStreamItem(ctx context.Context, item any) error {
+	st, ok := ctx.Value(streamKey{}).(*fieldStream)
+	if !ok {
+		return nil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sent = true
+	return st.send(ctx, item)
+}
+
+// subscriptionDefinition returns the definition of the Subscription root type,
+// or nil if no field streams its result.
+func (s *Server) subscriptionDefinition(schema *ast.Schema) *ast.Definition {
+	if schema.Query == nil {
+		return nil
+	}
+	streaming := s.streamingTypes(schema)
+	queryType, ok := s.objects[schema.Query.Name]
+	if !ok {
+		return nil
+	}
+	var fields ast.FieldList
+	for _, field := range schema.Query.Fields {
+		if s.fieldStreams(queryType, field, streaming) {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ast.Definition{
+		Kind:        ast.Object,
+		Name:        subscriptionType,
+		Description: "The root of subscriptions, which deliver the result of a streaming field as soon as each of its items is available.",
+		Fields:      fields,
+	}
+}
+
+// streamingTypes returns the names of the object types that have a field
+// streaming its result, directly or through the objects it returns.
+func (s *Server) streamingTypes(schema *ast.Schema) map[string]bool {
+	streaming := map[string]bool{}
+	for changed := true; changed; {
+		changed = false
+		for name, objType := range s.objects {
+			def, ok := schema.Types[name]
+			if !ok || streaming[name] {
+				continue
+			}
+			for _, field := range def.Fields {
+				if s.fieldStreams(objType, field, streaming) {
+					streaming[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return streaming
+}
+
+// fieldStreams returns true if the given field of the object type streams its
+// result, or returns an object of one of the streaming types.
+func (s *Server) fieldStreams(objType ObjectType, field *ast.FieldDefinition, streaming map[string]bool) bool {
+	if spec, ok := objType.FieldSpec(field.Name, s.View); ok && spec.Streaming {
+		return true
+	}
+	// the fields of list elements can't be subscribed to
+	return field.Type.Elem == nil && streaming[field.Type.NamedType]
+}
+
+// Subscribe executes a subscription operation, calling send with the data of
+// each response as soon as it's available.
+func (s *Server) Subscribe(ctx context.Context, gqlOp *graphql.OperationContext, send func(map[string]any) error) error {
+	if err := s.parseOp(gqlOp); err != nil {
+		return err
+	}
+	for _, op := range gqlOp.Doc.Operations {
+		if op.Operation != ast.Subscription {
+			continue
+		}
+		if gqlOp.OperationName != "" && gqlOp.OperationName != op.Name {
+			continue
+		}
+		sels, err := s.parseASTSelections(ctx, gqlOp, s.root.Type(), op.SelectionSet)
+		if err != nil {
+			return fmt.Errorf("subscription:\n%s\n\nerror: parse selections: %w", gqlOp.RawQuery, err)
+		}
+		return s.subscribe(ctx, s.root, sels, nil, send)
+	}
+	return fmt.Errorf("no subscription operation found")
+}
+
+func (s *Server) subscribe(ctx context.Context, self Object, sels []Selection, path []string, send func(map[string]any) error) (rerr error) {
+	if len(sels) != 1 {
+		return fmt.Errorf("subscriptions must select exactly one field at a time, got %d", len(sels))
+	}
+	sel := sels[0]
+	path = append(path, sel.Name())
+
+	defer func() {
+		if rerr != nil {
+			rerr = gqlErr(rerr, append(idToPath(self.ID()), ast.PathName(sel.Name())))
+		}
+	}()
+
+	sendItem := func(_ context.Context, item any) error {
+		data := item
+		for i := len(path) - 1; i >= 0; i-- {
+			data = map[string]any{path[i]: data}
+		}
+		return send(data.(map[string]any))
+	}
+
+	if len(sel.Subselections) > 0 {
+		val, chainedID, err := self.Select(ctx, s, sel.Selector)
+		if err != nil {
+			return err
+		}
+		if wrapped, ok := val.(Derefable); ok {
+			val, ok = wrapped.Deref()
+			if !ok {
+				val = nil
+			}
+		}
+		if val == nil {
+			// a nil value ignores all sub-selections
+			return sendItem(ctx, nil)
+		}
+		if _, ok := val.(Enumerable); ok {
+			return fmt.Errorf("cannot subscribe to the fields of a list")
+		}
+		node, err := s.toSelectable(chainedID, val)
+		if err != nil {
+			return fmt.Errorf("instantiate: %w", err)
+		}
+		return s.subscribe(ctx, node, sel.Subselections, path, send)
+	}
+
+	st := &fieldStream{send: sendItem}
+	val, _, err := self.Select(context.WithValue(ctx, streamKey{}, st), s, sel.Selector)
+	if err != nil {
+		return err
+	}
+	st.mu.Lock()
+	sent := st.sent
+	st.mu.Unlock()
+	if sent {
+		return nil
+	}
+
+	// the resolver didn't stream its result, e.g. because it was cached, so
+	// send it now, one element at a time for lists
+	if wrapped, ok := val.(Derefable); ok {
+		val, ok = wrapped.Deref()
+		if !ok {
+			return sendItem(ctx, nil)
+		}
+	}
+	enum, ok := val.(Enumerable)
+	if !ok {
+		return sendItem(ctx, val)
+	}
+	for nth := 1; nth <= enum.Len(); nth++ {
+		item, err := enum.Nth(nth)
+		if err != nil {
+			return err
+		}
+		if err := sendItem(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscriptionResponses returns a handler that delivers the responses of a
+// subscription, one per call, as soon as each is available.
+func (s *Server) subscriptionResponses() graphql.ResponseHandler {
+	var once sync.Once
+	responses := make(chan *graphql.Response)
+	return func(ctx context.Context) *graphql.Response {
+		once.Do(func() {
+			go func() {
+				defer close(responses)
+				respond := func(res *graphql.Response) error {
+					select {
+					case responses <- res:
+						return nil
+					case <-ctx.Done():
+						return context.Cause(ctx)
+					}
+				}
+
+				gqlOp := graphql.GetOperationContext(ctx)
+				if err := gqlOp.Validate(ctx); err != nil {
+					respond(graphql.ErrorResponse(ctx, "validate: %s", err))
+					return
+				}
+				err := s.Subscribe(ctx, gqlOp, func(data map[string]any) error {
+					payload, err := json.Marshal(data)
+					if err != nil {
+						return fmt.Errorf("marshal: %w", err)
+					}
+					return respond(&graphql.Response{Data: json.RawMessage(payload)})
+				})
+				if err != nil {
+					respond(&graphql.Response{Errors: gqlErrs(err)})
+				}
+			}()
+		})
+		select {
+		case res := <-responses:
+			return res
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -299,6 +299,12 @@ func (Float) DecodeInput(val any) (Input, error) {
 		return NewFloat(float64(x)), nil
 	case float64:
 		return NewFloat(x), nil
+	case int: // ints are valid float inputs
+		return NewFloat(float64(x)), nil
+	case int32:
+		return NewFloat(float64(x)), nil
+	case int64:
+		return NewFloat(float64(x)), nil
 	case json.Number:
 		i, err := x.Float64()
 		if err != nil {
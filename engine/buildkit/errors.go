@@ -1,5 +1,11 @@
 package buildkit
 
+import "errors"
+
+// ErrExecTimedOut is the cause of an exec being killed for running past its
+// timeout.
+var ErrExecTimedOut = errors.New("timed out")
+
 // ExecError is an error that occurred while executing an `Op_Exec`.
 type ExecError struct {
 	original error
@@ -7,6 +13,24 @@ type ExecError struct {
 	ExitCode int
 	Stdout   string
 	Stderr   string
+
+	// OOMKilled is set if the exec was killed for exceeding its memory limit.
+	OOMKilled bool
+
+	// TimedOut is set if the exec was killed for running past its timeout.
+	TimedOut bool
+}
+
+// NewExecError returns an ExecError for a command run outside of an LLB exec,
+// such as a service's process.
+func NewExecError(original error, cmd []string, exitCode int, stdout, stderr string) *ExecError {
+	return &ExecError{
+		original: original,
+		Cmd:      cmd,
+		ExitCode: exitCode,
+		Stdout:   stdout,
+		Stderr:   stderr,
+	}
 }
 
 func (e *ExecError) Error() string {
@@ -18,11 +42,18 @@ func (e *ExecError) Unwrap() error {
 }
 
 func (e *ExecError) Extensions() map[string]interface{} {
-	return map[string]interface{}{
+	ext := map[string]interface{}{
 		"_type":    "EXEC_ERROR",
 		"cmd":      e.Cmd,
 		"exitCode": e.ExitCode,
 		"stdout":   e.Stdout,
 		"stderr":   e.Stderr,
 	}
+	if e.OOMKilled {
+		ext["oomKilled"] = true
+	}
+	if e.TimedOut {
+		ext["timedOut"] = true
+	}
+	return ext
 }
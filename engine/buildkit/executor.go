@@ -102,6 +102,15 @@ type ExecutionMetadata struct {
 
 	// If true, skip injecting dagger-init into the container.
 	NoInit bool
+
+	// Resource limits applied to the container's cgroup. Zero values mean
+	// unlimited.
+	CPUQuota    float64
+	MemoryLimit int64
+	PidsLimit   int64
+
+	// If set, the container is killed if it's still running after this long.
+	Timeout time.Duration
 }
 
 const executionMetadataKey = "dagger.executionMetadata"
@@ -170,6 +179,7 @@ func (w *Worker) Run(
 		w.setupSecretScrubbing,
 		w.setProxyEnvs,
 		w.enableGPU,
+		w.setResourceLimits,
 		w.createCWD,
 		w.setupNestedClient,
 		w.installCACerts,
@@ -40,6 +40,8 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
+	"golang.org/x/text/transform"
+	"google.golang.org/protobuf/proto"
 
 	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/engine"
@@ -58,6 +60,7 @@ const (
 	DaggerRedirectStderrEnv  = "_DAGGER_REDIRECT_STDERR"
 	DaggerHostnameAliasesEnv = "_DAGGER_HOSTNAME_ALIASES"
 	DaggerNoInitEnv          = "_DAGGER_NOINIT"
+	DaggerResourceLimitsEnv  = "_DAGGER_RESOURCE_LIMITS"
 
 	DaggerSessionPortEnv  = "DAGGER_SESSION_PORT"
 	DaggerSessionTokenEnv = "DAGGER_SESSION_TOKEN"
@@ -69,6 +72,9 @@ const (
 
 	cgroupSampleInterval     = 3 * time.Second
 	finalCgroupSampleTimeout = 3 * time.Second
+
+	// the CFS period used when converting a CPU quota to cgroup settings
+	cpuCFSPeriod = 100_000
 )
 
 var removeEnvs = map[string]struct{}{
@@ -79,6 +85,7 @@ var removeEnvs = map[string]struct{}{
 	DaggerRedirectStderrEnv:  {},
 	DaggerHostnameAliasesEnv: {},
 	DaggerNoInitEnv:          {},
+	DaggerResourceLimitsEnv:  {},
 }
 
 type execState struct {
@@ -101,6 +108,7 @@ type execState struct {
 	metaMount          *specs.Mount
 	origEnvMap         map[string]string
 	sessionClientConnF *os.File
+	logScrubber        *logScrubber
 
 	startedOnce *sync.Once
 	startedCh   chan<- struct{}
@@ -680,15 +688,47 @@ func (w *Worker) setupOTel(ctx context.Context, state *execState) error {
 	if err != nil {
 		return fmt.Errorf("otel tcp proxy listen: %w", err)
 	}
-	otelSrv := &http.Server{
-		Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-			if r.Header == nil {
-				r.Header = http.Header{}
+	// Secrets are scrubbed from the container's own logs too, once
+	// setupSecretScrubbing has loaded them.
+	state.logScrubber = &logScrubber{}
+	proxy := func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header == nil {
+			r.Header = http.Header{}
+		}
+		r.Header.Set("X-Dagger-Session-ID", destSession)
+		r.Header.Set("X-Dagger-Client-ID", destClientID)
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/logs" {
+			if err := state.logScrubber.ScrubRequest(r); err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
 			}
-			r.Header.Set("X-Dagger-Session-ID", destSession)
-			r.Header.Set("X-Dagger-Client-ID", destClientID)
-			w.telemetryPubSub.ServeHTTP(rw, r)
-		}),
+		}
+		w.telemetryPubSub.ServeHTTP(rw, r)
+	}
+	// NB: added before the proxy cleanups so that it runs after them
+	state.cleanups.Add("flush scrubbed logs", func() error {
+		req := state.logScrubber.Flush()
+		if req == nil {
+			return nil
+		}
+		body, err := proto.Marshal(req)
+		if err != nil {
+			return err
+		}
+		r, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, "/v1/logs", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		rw := &discardResponseWriter{}
+		proxy(rw, r)
+		if rw.status >= 300 {
+			return fmt.Errorf("export scrubbed logs: status %d", rw.status)
+		}
+		return nil
+	})
+
+	otelSrv := &http.Server{
+		Handler:           http.HandlerFunc(proxy),
 		ReadHeaderTimeout: 5 * time.Second, // for gocritic
 	}
 	listenerPool := pool.New().WithErrors()
@@ -772,17 +812,19 @@ func (w *Worker) setupSecretScrubbing(ctx context.Context, state *execState) err
 		}
 	}
 
-	stdoutR, stdoutW := io.Pipe()
-	stdoutScrubReader, err := NewSecretScrubReader(stdoutR, state.spec.Process.Env, w.execMD.SecretEnvNames, secretFilePaths)
+	trie, err := newSecretScrubTrie(state.spec.Process.Env, w.execMD.SecretEnvNames, secretFilePaths)
 	if err != nil {
-		return fmt.Errorf("setup stdout secret scrubbing: %w", err)
+		return fmt.Errorf("setup secret scrubbing: %w", err)
 	}
-	stderrR, stderrW := io.Pipe()
-	stderrScrubReader, err := NewSecretScrubReader(stderrR, state.spec.Process.Env, w.execMD.SecretEnvNames, secretFilePaths)
-	if err != nil {
-		return fmt.Errorf("setup stderr secret scrubbing: %w", err)
+	if state.logScrubber != nil {
+		state.logScrubber.SetTrie(trie)
 	}
 
+	stdoutR, stdoutW := io.Pipe()
+	stdoutScrubReader := transform.NewReader(stdoutR, newCensor(trie))
+	stderrR, stderrW := io.Pipe()
+	stderrScrubReader := transform.NewReader(stderrR, newCensor(trie))
+
 	var pipeWg sync.WaitGroup
 
 	finalStdout := state.procInfo.Stdout
@@ -884,6 +926,50 @@ func (w *Worker) enableGPU(_ context.Context, state *execState) error {
 	return nil
 }
 
+func (w *Worker) setResourceLimits(_ context.Context, state *execState) error {
+	if w.execMD == nil {
+		return nil
+	}
+	if w.execMD.CPUQuota <= 0 && w.execMD.MemoryLimit <= 0 && w.execMD.PidsLimit <= 0 {
+		return nil
+	}
+
+	if state.spec.Linux == nil {
+		state.spec.Linux = &specs.Linux{}
+	}
+	if state.spec.Linux.Resources == nil {
+		state.spec.Linux.Resources = &specs.LinuxResources{}
+	}
+	resources := state.spec.Linux.Resources
+
+	if w.execMD.CPUQuota > 0 {
+		if resources.CPU == nil {
+			resources.CPU = &specs.LinuxCPU{}
+		}
+		period := uint64(cpuCFSPeriod)
+		quota := int64(w.execMD.CPUQuota * cpuCFSPeriod)
+		resources.CPU.Period = &period
+		resources.CPU.Quota = &quota
+	}
+
+	if w.execMD.MemoryLimit > 0 {
+		if resources.Memory == nil {
+			resources.Memory = &specs.LinuxMemory{}
+		}
+		limit := w.execMD.MemoryLimit
+		// set swap equal to the limit so the container can't exceed it by swapping
+		swap := w.execMD.MemoryLimit
+		resources.Memory.Limit = &limit
+		resources.Memory.Swap = &swap
+	}
+
+	if w.execMD.PidsLimit > 0 {
+		resources.Pids = &specs.LinuxPids{Limit: w.execMD.PidsLimit}
+	}
+
+	return nil
+}
+
 func (w *Worker) createCWD(_ context.Context, state *execState) error {
 	newp, err := fs.RootPath(state.rootfsPath, state.procInfo.Meta.Cwd)
 	if err != nil {
@@ -1241,5 +1327,60 @@ func (w *Worker) runContainer(ctx context.Context, state *execState) (rerr error
 		return err
 	}
 
-	return exitError(ctx, state.exitCodePath, w.callWithIO(ctx, state.procInfo, startedCallback, killer, runcCall), state.procInfo.Meta.ValidExitCodes)
+	runCtx := ctx
+	if w.execMD != nil && w.execMD.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeoutCause(ctx, w.execMD.Timeout,
+			fmt.Errorf("%w after %s", ErrExecTimedOut, w.execMD.Timeout))
+		defer cancel()
+	}
+
+	err = w.callWithIO(runCtx, state.procInfo, startedCallback, killer, runcCall)
+	if err != nil && cgroupPath != "" && w.execMD != nil && w.execMD.MemoryLimit > 0 {
+		w.recordOOMKill(ctx, state, cgroupPath)
+	}
+	if err != nil && errors.Is(context.Cause(runCtx), ErrExecTimedOut) {
+		w.recordTimeout(ctx, state)
+	}
+	return exitError(runCtx, state.exitCodePath, err, state.procInfo.Meta.ValidExitCodes)
+}
+
+// recordTimeout records in the meta mount that the container was killed for
+// running past its timeout so that the resulting exec error can say so.
+func (w *Worker) recordTimeout(ctx context.Context, state *execState) {
+	trace.SpanFromContext(ctx).AddEvent("Container timed out",
+		trace.WithAttributes(attribute.String("timeout", w.execMD.Timeout.String())))
+
+	if state.metaMount == nil {
+		return
+	}
+	timedOutPath := filepath.Join(state.metaMount.Source, MetaMountTimedOutPath)
+	if err := os.WriteFile(timedOutPath, []byte("true"), 0o600); err != nil {
+		bklog.G(ctx).Errorf("failed to write timeout marker to %s: %v", timedOutPath, err)
+	}
+}
+
+// recordOOMKill checks whether the container's cgroup hit its memory limit
+// and, if so, records that in the meta mount so that the resulting exec error
+// can be reported as an OOM.
+func (w *Worker) recordOOMKill(ctx context.Context, state *execState, cgroupPath string) {
+	oomKilled, err := resources.OOMKilled(cgroupPath)
+	if err != nil {
+		bklog.G(ctx).WithError(err).Warn("failed to check for oom kill")
+		return
+	}
+	if !oomKilled {
+		return
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("Container OOM killed",
+		trace.WithAttributes(attribute.Int64("memory.limit", w.execMD.MemoryLimit)))
+
+	if state.metaMount == nil {
+		return
+	}
+	oomKilledPath := filepath.Join(state.metaMount.Source, MetaMountOOMKilledPath)
+	if err := os.WriteFile(oomKilledPath, []byte("true"), 0o600); err != nil {
+		bklog.G(ctx).Errorf("failed to write oom kill marker to %s: %v", oomKilledPath, err)
+	}
 }
@@ -59,6 +59,12 @@ const (
 	MetaMountStdoutPath   = "stdout"
 	MetaMountStderrPath   = "stderr"
 	MetaMountClientIDPath = "clientID"
+	// MetaMountOOMKilledPath is written when the exec was killed for
+	// exceeding its memory limit.
+	MetaMountOOMKilledPath = "oomKilled"
+	// MetaMountTimedOutPath is written when the exec was killed for running
+	// longer than its timeout.
+	MetaMountTimedOutPath = "timedOut"
 )
 
 type Result = solverresult.Result[*ref]
@@ -159,6 +165,19 @@ func (r *ref) WalkDir(ctx context.Context, req WalkDirRequest) error {
 	return walkDir(ctx, mnt, req)
 }
 
+// Mount mounts the ref read-only and calls fn with the path of its root.
+func (r *ref) Mount(ctx context.Context, fn func(root string) error) error {
+	ctx = withOutgoingContext(ctx)
+	mnt, err := r.getMountable(ctx)
+	if err != nil {
+		return err
+	}
+	if mnt == nil {
+		return fmt.Errorf("cannot mount empty ref")
+	}
+	return withMount(mnt, fn)
+}
+
 func (r *ref) StatFile(ctx context.Context, req bkgw.StatRequest) (*fstypes.Stat, error) {
 	ctx = withOutgoingContext(ctx)
 	mnt, err := r.getMountable(ctx)
@@ -369,6 +388,19 @@ func WrapError(ctx context.Context, baseErr error, client *Client) error {
 			return errors.Join(err, baseErr)
 		}
 	}
+	oomKilledBytes, err := getExecMetaFile(ctx, client, mntable, MetaMountOOMKilledPath)
+	if err != nil {
+		return errors.Join(err, baseErr)
+	}
+	oomKilled := len(oomKilledBytes) > 0
+	if oomKilled {
+		baseErr = fmt.Errorf("%w: killed after exceeding its memory limit", baseErr)
+	}
+	timedOutBytes, err := getExecMetaFile(ctx, client, mntable, MetaMountTimedOutPath)
+	if err != nil {
+		return errors.Join(err, baseErr)
+	}
+	timedOut := len(timedOutBytes) > 0
 
 	// Start a debug container if the exec failed
 	if err := debugContainer(ctx, execOp.Exec, execErr, opErr, client); err != nil {
@@ -376,11 +408,13 @@ func WrapError(ctx context.Context, baseErr error, client *Client) error {
 	}
 
 	return &ExecError{
-		original: baseErr,
-		Cmd:      execOp.Exec.Meta.Args,
-		ExitCode: exitCode,
-		Stdout:   strings.TrimSpace(string(stdoutBytes)),
-		Stderr:   strings.TrimSpace(string(stderrBytes)),
+		original:  baseErr,
+		Cmd:       execOp.Exec.Meta.Args,
+		ExitCode:  exitCode,
+		Stdout:    strings.TrimSpace(string(stdoutBytes)),
+		Stderr:    strings.TrimSpace(string(stderrBytes)),
+		OOMKilled: oomKilled,
+		TimedOut:  timedOut,
 	}
 }
 
@@ -0,0 +1,239 @@
+package buildkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
+	bksession "github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/resolver"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ImageReferrer is an artifact to push along with an image, referring to it.
+type ImageReferrer struct {
+	// ArtifactType is the type of the artifact, e.g.
+	// application/vnd.dev.cosign.artifact.sig.v1+json
+	ArtifactType string
+	// Layers are the blobs of the artifact.
+	Layers []ImageReferrerLayer
+	// Annotations are set on the manifest of the artifact, and on its
+	// descriptor in the referrers index.
+	Annotations map[string]string
+}
+
+type ImageReferrerLayer struct {
+	MediaType   string
+	Data        []byte
+	Annotations map[string]string
+}
+
+// PushImageReferrers pushes artifacts referring to the image manifest or
+// index described by subject, in the repository of ref.
+//
+// Each artifact is pushed as an OCI image manifest with a subject, so
+// registries supporting the referrers API index it themselves. The referrers
+// tag schema index (e.g. "sha256-<hex>") is updated too, for the registries
+// that don't.
+func (c *Client) PushImageReferrers(
+	ctx context.Context,
+	ref string,
+	subject ocispecs.Descriptor,
+	referrers []ImageReferrer,
+) error {
+	ctx, cancel, err := c.withClientCloseCancel(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel(errors.New("push image referrers done"))
+
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return fmt.Errorf("parse ref %q: %w", ref, err)
+	}
+	repo := named.Name()
+
+	res := resolver.DefaultPool.GetResolver(c.Worker.RegistryHosts, repo, "push", c.SessionManager, bksession.NewGroup(c.ID()))
+	pusher, err := res.Pusher(ctx, repo+"@"+subject.Digest.String())
+	if err != nil {
+		return fmt.Errorf("get pusher for %s: %w", repo, err)
+	}
+	push := func(desc ocispecs.Descriptor, data []byte) error {
+		w, err := pusher.Push(ctx, desc)
+		if err != nil {
+			if errdefs.IsAlreadyExists(err) {
+				return nil
+			}
+			return fmt.Errorf("push %s: %w", desc.Digest, err)
+		}
+		defer w.Close()
+		if err := content.Copy(ctx, w, bytes.NewReader(data), desc.Size, desc.Digest); err != nil {
+			return fmt.Errorf("push %s: %w", desc.Digest, err)
+		}
+		return nil
+	}
+
+	// the config of every artifact
+	if err := push(ocispecs.DescriptorEmptyJSON, ocispecs.DescriptorEmptyJSON.Data); err != nil {
+		return err
+	}
+	var descs []ocispecs.Descriptor
+	for _, referrer := range referrers {
+		manifest, err := referrerManifest(subject, referrer)
+		if err != nil {
+			return err
+		}
+		for i, layer := range referrer.Layers {
+			if err := push(manifest.Layers[i], layer.Data); err != nil {
+				return err
+			}
+		}
+		manifestData, err := json.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		desc := ocispecs.Descriptor{
+			MediaType:    manifest.MediaType,
+			ArtifactType: manifest.ArtifactType,
+			Digest:       digest.FromBytes(manifestData),
+			Size:         int64(len(manifestData)),
+			Annotations:  manifest.Annotations,
+		}
+		if err := push(desc, manifestData); err != nil {
+			return err
+		}
+		descs = append(descs, desc)
+	}
+
+	// update the referrers tag schema index
+	tagRef := repo + ":" + referrersTag(subject.Digest)
+	var existing []byte
+	_, indexDesc, err := res.Resolve(ctx, tagRef)
+	switch {
+	case err == nil:
+		fetcher, err := res.Fetcher(ctx, tagRef)
+		if err != nil {
+			return fmt.Errorf("get fetcher for %s: %w", tagRef, err)
+		}
+		rc, err := fetcher.Fetch(ctx, indexDesc)
+		if err != nil {
+			return fmt.Errorf("fetch %s: %w", tagRef, err)
+		}
+		existing, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("fetch %s: %w", tagRef, err)
+		}
+	case errdefs.IsNotFound(err):
+	default:
+		return fmt.Errorf("resolve %s: %w", tagRef, err)
+	}
+	indexData, err := mergeReferrersIndex(existing, descs)
+	if err != nil {
+		return fmt.Errorf("update %s: %w", tagRef, err)
+	}
+	tagPusher, err := res.Pusher(ctx, tagRef)
+	if err != nil {
+		return fmt.Errorf("get pusher for %s: %w", tagRef, err)
+	}
+	indexDesc = ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageIndex,
+		Digest:    digest.FromBytes(indexData),
+		Size:      int64(len(indexData)),
+	}
+	w, err := tagPusher.Push(ctx, indexDesc)
+	if err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			return nil
+		}
+		return fmt.Errorf("push %s: %w", tagRef, err)
+	}
+	defer w.Close()
+	if err := content.Copy(ctx, w, bytes.NewReader(indexData), indexDesc.Size, indexDesc.Digest); err != nil {
+		return fmt.Errorf("push %s: %w", tagRef, err)
+	}
+	return nil
+}
+
+// referrerManifest returns the manifest of an artifact referring to subject.
+func referrerManifest(subject ocispecs.Descriptor, referrer ImageReferrer) (*ocispecs.Manifest, error) {
+	if referrer.ArtifactType == "" {
+		return nil, errors.New("referrer has no artifact type")
+	}
+	manifest := &ocispecs.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispecs.MediaTypeImageManifest,
+		ArtifactType: referrer.ArtifactType,
+		Config:       ocispecs.DescriptorEmptyJSON,
+		Layers:       make([]ocispecs.Descriptor, 0, len(referrer.Layers)),
+		Subject: &ocispecs.Descriptor{
+			MediaType: subject.MediaType,
+			Digest:    subject.Digest,
+			Size:      subject.Size,
+		},
+		Annotations: referrer.Annotations,
+	}
+	for _, layer := range referrer.Layers {
+		manifest.Layers = append(manifest.Layers, ocispecs.Descriptor{
+			MediaType:   layer.MediaType,
+			Digest:      digest.FromBytes(layer.Data),
+			Size:        int64(len(layer.Data)),
+			Annotations: layer.Annotations,
+		})
+	}
+	if len(manifest.Layers) == 0 {
+		// an artifact must have at least one layer
+		manifest.Layers = append(manifest.Layers, ocispecs.DescriptorEmptyJSON)
+	}
+	return manifest, nil
+}
+
+// referrersTag returns the tag of the referrers index of the given digest,
+// following the referrers tag schema of the OCI distribution spec.
+func referrersTag(dgst digest.Digest) string {
+	tag := dgst.Algorithm().String() + "-" + dgst.Encoded()
+	if len(tag) > 128 {
+		tag = tag[:128]
+	}
+	return tag
+}
+
+// mergeReferrersIndex returns the referrers index existing, if any, with the
+// given descriptors added.
+func mergeReferrersIndex(existing []byte, descs []ocispecs.Descriptor) ([]byte, error) {
+	index := ocispecs.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispecs.MediaTypeImageIndex,
+	}
+	if len(existing) > 0 {
+		if err := json.Unmarshal(existing, &index); err != nil {
+			return nil, fmt.Errorf("invalid referrers index: %w", err)
+		}
+		if index.MediaType != "" && index.MediaType != ocispecs.MediaTypeImageIndex {
+			return nil, fmt.Errorf("invalid referrers index: unexpected media type %q", index.MediaType)
+		}
+	}
+	seen := map[digest.Digest]struct{}{}
+	for _, desc := range index.Manifests {
+		seen[desc.Digest] = struct{}{}
+	}
+	for _, desc := range descs {
+		if _, ok := seen[desc.Digest]; ok {
+			continue
+		}
+		seen[desc.Digest] = struct{}{}
+		index.Manifests = append(index.Manifests, desc)
+	}
+	if index.Manifests == nil {
+		index.Manifests = []ocispecs.Descriptor{}
+	}
+	return json.Marshal(index)
+}
@@ -0,0 +1,76 @@
+package buildkit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferrerManifest(t *testing.T) {
+	subject := ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageIndex,
+		Digest:    digest.FromString("subject"),
+		Size:      42,
+		Platform:  &ocispecs.Platform{OS: "linux"},
+	}
+
+	manifest, err := referrerManifest(subject, ImageReferrer{
+		ArtifactType: "application/example",
+		Layers:       []ImageReferrerLayer{{MediaType: "text/plain", Data: []byte("hello")}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "application/example", manifest.ArtifactType)
+	require.Equal(t, ocispecs.DescriptorEmptyJSON, manifest.Config)
+	require.Equal(t, &ocispecs.Descriptor{
+		MediaType: subject.MediaType,
+		Digest:    subject.Digest,
+		Size:      subject.Size,
+	}, manifest.Subject)
+	require.Len(t, manifest.Layers, 1)
+	require.Equal(t, digest.FromString("hello"), manifest.Layers[0].Digest)
+	require.EqualValues(t, 5, manifest.Layers[0].Size)
+
+	manifest, err = referrerManifest(subject, ImageReferrer{ArtifactType: "application/example"})
+	require.NoError(t, err)
+	require.Equal(t, []ocispecs.Descriptor{ocispecs.DescriptorEmptyJSON}, manifest.Layers)
+
+	_, err = referrerManifest(subject, ImageReferrer{})
+	require.Error(t, err)
+}
+
+func TestReferrersTag(t *testing.T) {
+	dgst := digest.FromString("subject")
+	require.Equal(t, "sha256-"+dgst.Encoded(), referrersTag(dgst))
+
+	long := digest.NewDigestFromEncoded("sha512", strings.Repeat("a", 128))
+	require.Len(t, referrersTag(long), 128)
+}
+
+func TestMergeReferrersIndex(t *testing.T) {
+	a := ocispecs.Descriptor{MediaType: ocispecs.MediaTypeImageManifest, Digest: digest.FromString("a"), Size: 1}
+	b := ocispecs.Descriptor{MediaType: ocispecs.MediaTypeImageManifest, Digest: digest.FromString("b"), Size: 1}
+
+	data, err := mergeReferrersIndex(nil, []ocispecs.Descriptor{a})
+	require.NoError(t, err)
+	var index ocispecs.Index
+	require.NoError(t, json.Unmarshal(data, &index))
+	require.Equal(t, ocispecs.MediaTypeImageIndex, index.MediaType)
+	require.Equal(t, []ocispecs.Descriptor{a}, index.Manifests)
+
+	data, err = mergeReferrersIndex(data, []ocispecs.Descriptor{a, b})
+	require.NoError(t, err)
+	index = ocispecs.Index{}
+	require.NoError(t, json.Unmarshal(data, &index))
+	require.Equal(t, []ocispecs.Descriptor{a, b}, index.Manifests)
+
+	data, err = mergeReferrersIndex(nil, nil)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"manifests":[]`)
+
+	_, err = mergeReferrersIndex([]byte(`{"mediaType":"application/vnd.oci.image.manifest.v1+json"}`), nil)
+	require.Error(t, err)
+}
@@ -15,6 +15,7 @@ import (
 const (
 	memoryCurrentFile = "memory.current"
 	memoryPeakFile    = "memory.peak"
+	memoryEventsFile  = "memory.events"
 )
 
 type memoryCurrentSampler struct {
@@ -106,3 +107,23 @@ func (s *memoryPeakSampler) sample(ctx context.Context) error {
 
 	return nil
 }
+
+// OOMKilled reports whether any process in the given cgroup (relative to the
+// cgroup mountpoint) has been killed by the OOM killer.
+func OOMKilled(cgroupNSSubpath string) (bool, error) {
+	memoryEventsFilePath := filepath.Join(defaultMountpoint, cgroupNSSubpath, memoryEventsFile)
+	bs, err := os.ReadFile(memoryEventsFilePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to read %s: %w", memoryEventsFilePath, err)
+	}
+
+	for key, value := range flatKeyValuesInt64(bs) {
+		if key == "oom_kill" {
+			return value > 0, nil
+		}
+	}
+	return false, nil
+}
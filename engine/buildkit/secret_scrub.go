@@ -2,8 +2,12 @@ package buildkit
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,6 +26,20 @@ func NewSecretScrubReader(
 	secretEnvs []string,
 	secretFiles []string,
 ) (io.Reader, error) {
+	trie, err := newSecretScrubTrie(env, secretEnvs, secretFiles)
+	if err != nil {
+		return nil, err
+	}
+	return transform.NewReader(r, newCensor(trie)), nil
+}
+
+// newSecretScrubTrie builds a trie of the secrets to scrub, and their common
+// encodings, from the given env and files.
+func newSecretScrubTrie(
+	env []string,
+	secretEnvs []string,
+	secretFiles []string,
+) (*Trie, error) {
 	secrets := loadSecretsToScrubFromEnv(env, secretEnvs)
 
 	fileSecrets, err := loadSecretsToScrubFromFiles(secretFiles)
@@ -41,20 +59,101 @@ func NewSecretScrubReader(
 
 	trie := &Trie{}
 	for _, s := range secretAsBytes {
-		trie.Insert(s, scrubString)
-		if strimmed := bytes.TrimSpace(s); len(strimmed) != len(s) {
-			trie.Insert(strimmed, scrubString)
+		variants := [][]byte{s}
+		if strimmed := bytes.TrimSpace(s); len(strimmed) != len(s) && len(strimmed) > 0 {
+			variants = append(variants, strimmed)
+		}
+		for _, v := range variants {
+			trie.Insert(v, scrubString)
+			for _, encoded := range secretEncodings(v) {
+				trie.Insert(encoded, scrubString)
+			}
 		}
 	}
-	transformer := &censor{
+	return trie, nil
+}
+
+func newCensor(trie *Trie) *censor {
+	return &censor{
 		trieRoot: trie,
-		trie:     trie.Iter(),
+		trie:     TrieIter{Trie: trie},
 		// NOTE: keep these sizes the same as the default transform sizes
 		srcBuf: make([]byte, 0, 4096),
 		dstBuf: make([]byte, 0, 4096),
 	}
+}
+
+// minEncodedSecretLen is the minimum length of a secret for its encodings to
+// be scrubbed too. The encodings of shorter secrets are too likely to turn up
+// by chance, e.g. in hashes.
+const minEncodedSecretLen = 8
+
+// secretEncodings returns the common encodings of a secret that tools might
+// print instead of the secret itself: base64 (standard and URL-safe), hex,
+// URL escaping and JSON escaping.
+func secretEncodings(secret []byte) [][]byte {
+	if len(secret) < minEncodedSecretLen {
+		return nil
+	}
 
-	return transform.NewReader(r, transformer), nil
+	var encodings []string
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding} {
+		encodings = append(encodings,
+			enc.EncodeToString(secret),
+			enc.WithPadding(base64.NoPadding).EncodeToString(secret),
+		)
+		// the secret may also be part of a larger base64 string, like
+		// "user:secret" in a basic auth header, at any offset
+		for offset := range 3 {
+			encodings = append(encodings, base64Inner(enc.WithPadding(base64.NoPadding), secret, offset))
+		}
+	}
+
+	hexSecret := hex.EncodeToString(secret)
+	encodings = append(encodings,
+		hexSecret,
+		strings.ToUpper(hexSecret),
+		url.QueryEscape(string(secret)),
+		url.PathEscape(string(secret)),
+	)
+
+	// JSON, with and without HTML escaping
+	if dt, err := json.Marshal(string(secret)); err == nil {
+		encodings = append(encodings, string(dt[1:len(dt)-1]))
+	}
+	var buf bytes.Buffer
+	jsonEnc := json.NewEncoder(&buf)
+	jsonEnc.SetEscapeHTML(false)
+	if err := jsonEnc.Encode(string(secret)); err == nil {
+		dt := bytes.TrimSpace(buf.Bytes())
+		encodings = append(encodings, string(dt[1:len(dt)-1]))
+	}
+
+	seen := map[string]bool{string(secret): true}
+	var result [][]byte
+	for _, encoded := range encodings {
+		if seen[encoded] {
+			continue
+		}
+		seen[encoded] = true
+		result = append(result, []byte(encoded))
+	}
+	return result
+}
+
+// base64Inner returns the part of the base64 encoding of secret that only
+// depends on the secret itself, when the secret starts offset bytes into the
+// encoded data. The characters at either end are left out, since they also
+// encode bits of the surrounding bytes.
+func base64Inner(enc *base64.Encoding, secret []byte, offset int) string {
+	n := offset + len(secret)
+	data := make([]byte, n)
+	copy(data[offset:], secret)
+	encoded := enc.EncodeToString(data)
+
+	start := [3]int{0, 2, 3}[offset]
+	end := n/3*4 + n%3
+	return encoded[start:end]
 }
 
 // loadSecretsToScrubFromEnv loads secrets value from env if they are in secretsToScrub.
@@ -103,9 +202,9 @@ type censor struct {
 	// trieRoot is the root of the trie
 	trieRoot *Trie
 	// trie is the current node we are at in the trie
-	trie *TrieIter
-	// match is the last trie node that we found a match from
-	match    *TrieIter
+	trie TrieIter
+	// match is the replacement for the last match that we found
+	match    []byte
 	matchLen int
 
 	// srcBuf is the source buffer, which contains bytes read from the src that
@@ -148,15 +247,13 @@ func (c *censor) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err err
 		// bytes, or we've filled the destination buffer
 		for ; nSrc < len(src) && nDst+len(c.dstBuf) < len(dst); nSrc++ {
 			ch := src[nSrc]
-			c.trie = c.trie.Step(ch)
-
-			if c.trie == nil {
+			if !c.trie.advance(ch) {
 				// we had found a match somewhere in this string previously, so
 				// flush the secret replacement and the rest of the source
 				// buffer
 				if c.match != nil {
-					c.trie = c.trieRoot.Iter()
-					c.dstBuf = append(c.dstBuf, c.match.Value()...)
+					c.trie = TrieIter{Trie: c.trieRoot}
+					c.dstBuf = append(c.dstBuf, c.match...)
 					c.dstBuf = append(c.dstBuf, c.srcBuf[c.matchLen:]...)
 					c.srcBuf = c.srcBuf[:0]
 					c.match = nil
@@ -173,7 +270,7 @@ func (c *censor) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err err
 				// no match possible, so flush the source buffer into the
 				// destination buffer
 				if len(c.srcBuf) != 0 {
-					c.trie = c.trieRoot.Iter()
+					c.trie = TrieIter{Trie: c.trieRoot}
 					c.dstBuf = append(c.dstBuf, c.srcBuf...)
 					c.srcBuf = c.srcBuf[:0]
 
@@ -184,12 +281,11 @@ func (c *censor) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err err
 
 				// put the current byte either into the destination buffer, or
 				// the source buffer, depending on whether it's a partial match
-				c.trie = c.trieRoot.Step(ch)
-				if c.trie == nil {
-					c.trie = c.trieRoot.Iter()
+				c.trie = TrieIter{Trie: c.trieRoot}
+				if !c.trie.advance(ch) {
 					c.dstBuf = append(c.dstBuf, ch)
 				} else if replace := c.trie.Value(); replace != nil {
-					c.trie = c.trieRoot.Iter()
+					c.trie = TrieIter{Trie: c.trieRoot}
 					c.dstBuf = append(c.dstBuf, replace...)
 				} else {
 					c.srcBuf = append(c.srcBuf, ch)
@@ -198,7 +294,7 @@ func (c *censor) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err err
 				// aha, we made a match, mark it, and we'll come back and flush
 				// the censored string later
 				c.srcBuf = append(c.srcBuf, ch)
-				c.match = c.trie
+				c.match = replace
 				c.matchLen = len(c.srcBuf)
 			} else {
 				// we're in the middle of a match
@@ -209,7 +305,7 @@ func (c *censor) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err err
 		// at this point, no more matches are possible, so flush
 		if atEOF {
 			if c.match != nil {
-				c.dstBuf = append(c.dstBuf, c.match.Value()...)
+				c.dstBuf = append(c.dstBuf, c.match...)
 				c.dstBuf = append(c.dstBuf, c.srcBuf[c.matchLen:]...)
 				c.match = nil
 				c.matchLen = 0
@@ -222,11 +318,27 @@ func (c *censor) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err err
 }
 
 func (c *censor) Reset() {
-	c.trie = c.trieRoot.Iter()
+	c.trie = TrieIter{Trie: c.trieRoot}
 	c.srcBuf = c.srcBuf[:0]
 	c.dstBuf = c.dstBuf[:0]
 }
 
+// scrub censors a chunk of a stream, returning the bytes that are known not to
+// be part of a secret. The rest are held back until the next call, or until
+// atEOF is set.
+func (c *censor) scrub(src []byte, atEOF bool) []byte {
+	var out []byte
+	dst := make([]byte, len(src)+len(c.srcBuf)+len(c.dstBuf)+len(scrubString))
+	for {
+		nDst, nSrc, err := c.Transform(dst, src, atEOF)
+		out = append(out, dst[:nDst]...)
+		src = src[nSrc:]
+		if err != transform.ErrShortDst {
+			return out
+		}
+	}
+}
+
 // Trie is a simple implementation of a compressed trie (or radix tree). In
 // essence, it's a key-value store that allows easily selecting all entries
 // that have a given prefix.
@@ -379,6 +491,27 @@ func (t *TrieIter) Step(ch byte) *TrieIter {
 	return nil
 }
 
+// advance is like Step, but moves the iterator in place instead of allocating
+// a new one, since it's in the hot path of the censor. If there's no such
+// node, it returns false and leaves the iterator as is.
+func (t *TrieIter) advance(ch byte) bool {
+	if t.idx < len(t.direct) {
+		if t.direct[t.idx] != ch {
+			return false
+		}
+		t.idx++
+		return true
+	}
+	if t.children != nil {
+		if child := t.children[ch]; child != nil {
+			t.Trie = child
+			t.idx = 0
+			return true
+		}
+	}
+	return false
+}
+
 // Value gets the value previously inserted at this node.
 func (t *TrieIter) Value() []byte {
 	if t == nil {
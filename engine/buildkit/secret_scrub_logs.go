@@ -0,0 +1,227 @@
+package buildkit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"dagger.io/dagger/telemetry"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// logScrubber scrubs secrets from the bodies of the OTLP log records that a
+// container exports through its telemetry proxy.
+//
+// Each log stream (the stdout or stderr of a span) gets its own censor, so a
+// secret that's split across records is still caught. Bytes that may be the
+// start of a secret are held back until the next record of the stream, and
+// flushed just before the stream's EOF record, or by Flush.
+type logScrubber struct {
+	mu      sync.Mutex
+	trie    *Trie
+	streams map[logStreamKey]*logStream
+}
+
+type logStreamKey struct {
+	traceID string
+	spanID  string
+	stream  int64
+}
+
+type logStream struct {
+	censor *censor
+
+	// the resource, scope and last record of the stream, used to emit the
+	// bytes still held back on Flush
+	resource *resourcepb.Resource
+	scope    *commonpb.InstrumentationScope
+	record   *logspb.LogRecord
+}
+
+// SetTrie sets the secrets to scrub. Until it's called, records pass through
+// untouched.
+func (s *logScrubber) SetTrie(trie *Trie) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trie = trie
+}
+
+// ScrubRequest scrubs the log records in the body of an OTLP/HTTP export
+// request, replacing the body.
+func (s *logScrubber) ScrubRequest(r *http.Request) error {
+	s.mu.Lock()
+	enabled := s.trie != nil
+	s.mu.Unlock()
+	if !enabled {
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("read logs request: %w", err)
+	}
+	var req collogspb.ExportLogsServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		return fmt.Errorf("unmarshal logs request: %w", err)
+	}
+	s.Scrub(&req)
+	body, err = proto.Marshal(&req)
+	if err != nil {
+		return fmt.Errorf("marshal logs request: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	return nil
+}
+
+// Scrub scrubs the string and bytes bodies of the log records in req, in
+// place.
+func (s *logScrubber) Scrub(req *collogspb.ExportLogsServiceRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.trie == nil {
+		return
+	}
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			records := make([]*logspb.LogRecord, 0, len(sl.LogRecords))
+			for _, rec := range sl.LogRecords {
+				key, eof := logStreamOf(rec)
+				stream := s.streams[key]
+				if stream == nil {
+					stream = &logStream{censor: newCensor(s.trie)}
+					if s.streams == nil {
+						s.streams = make(map[logStreamKey]*logStream)
+					}
+					s.streams[key] = stream
+				}
+				stream.resource = rl.GetResource()
+				stream.scope = sl.GetScope()
+				stream.record = rec
+
+				if eof {
+					// emit the rest of the stream just before the EOF record,
+					// which consumers may not expect to have a body
+					var rest []byte
+					switch body := rec.GetBody().GetValue().(type) {
+					case *commonpb.AnyValue_StringValue:
+						rest = stream.censor.scrub([]byte(body.StringValue), true)
+						body.StringValue = ""
+					case *commonpb.AnyValue_BytesValue:
+						rest = stream.censor.scrub(body.BytesValue, true)
+						body.BytesValue = nil
+					default:
+						rest = stream.censor.scrub(nil, true)
+					}
+					if len(rest) > 0 {
+						records = append(records, stream.restRecord(rest))
+					}
+				} else {
+					switch body := rec.GetBody().GetValue().(type) {
+					case *commonpb.AnyValue_StringValue:
+						body.StringValue = string(stream.censor.scrub([]byte(body.StringValue), false))
+					case *commonpb.AnyValue_BytesValue:
+						body.BytesValue = stream.censor.scrub(body.BytesValue, false)
+					}
+				}
+				records = append(records, rec)
+				if eof {
+					delete(s.streams, key)
+				}
+			}
+			sl.LogRecords = records
+		}
+	}
+}
+
+// Flush returns a request with the bytes still held back for each stream that
+// hasn't reached EOF, or nil if there are none.
+func (s *logScrubber) Flush() *collogspb.ExportLogsServiceRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req := &collogspb.ExportLogsServiceRequest{}
+	for key, stream := range s.streams {
+		delete(s.streams, key)
+		rest := stream.censor.scrub(nil, true)
+		if len(rest) == 0 {
+			continue
+		}
+		req.ResourceLogs = append(req.ResourceLogs, &logspb.ResourceLogs{
+			Resource: stream.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      stream.scope,
+				LogRecords: []*logspb.LogRecord{stream.restRecord(rest)},
+			}},
+		})
+	}
+	if len(req.ResourceLogs) == 0 {
+		return nil
+	}
+	return req
+}
+
+// restRecord returns a copy of the last record of the stream with the given
+// body, and without an EOF marker.
+func (stream *logStream) restRecord(body []byte) *logspb.LogRecord {
+	rec := proto.Clone(stream.record).(*logspb.LogRecord)
+	rec.Body = &commonpb.AnyValue{
+		Value: &commonpb.AnyValue_StringValue{StringValue: string(body)},
+	}
+	attrs := rec.Attributes[:0]
+	for _, attr := range rec.Attributes {
+		if attr.GetKey() != telemetry.StdioEOFAttr {
+			attrs = append(attrs, attr)
+		}
+	}
+	rec.Attributes = attrs
+	return rec
+}
+
+// logStreamOf returns the stream a record belongs to, and whether it's the
+// stream's EOF record.
+func logStreamOf(rec *logspb.LogRecord) (key logStreamKey, eof bool) {
+	key.traceID = string(rec.GetTraceId())
+	key.spanID = string(rec.GetSpanId())
+	for _, attr := range rec.GetAttributes() {
+		switch attr.GetKey() {
+		case telemetry.StdioStreamAttr:
+			key.stream = attr.GetValue().GetIntValue()
+		case telemetry.StdioEOFAttr:
+			eof = attr.GetValue().GetBoolValue()
+		}
+	}
+	return key, eof
+}
+
+// discardResponseWriter is an http.ResponseWriter for internal requests, that
+// only keeps the status code.
+type discardResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = http.Header{}
+	}
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return len(p), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
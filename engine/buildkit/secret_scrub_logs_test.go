@@ -0,0 +1,119 @@
+package buildkit
+
+import (
+	"strings"
+	"testing"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+func logRecord(spanID string, stream int64, body string, eof bool) *logspb.LogRecord {
+	rec := &logspb.LogRecord{
+		SpanId: []byte(spanID),
+		Body:   &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: body}},
+		Attributes: []*commonpb.KeyValue{{
+			Key:   telemetry.StdioStreamAttr,
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: stream}},
+		}},
+	}
+	if eof {
+		rec.Attributes = append(rec.Attributes, &commonpb.KeyValue{
+			Key:   telemetry.StdioEOFAttr,
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}},
+		})
+	}
+	return rec
+}
+
+func logsRequest(records ...*logspb.LogRecord) *collogspb.ExportLogsServiceRequest {
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			ScopeLogs: []*logspb.ScopeLogs{{LogRecords: records}},
+		}},
+	}
+}
+
+func logBodies(req *collogspb.ExportLogsServiceRequest) []string {
+	var bodies []string
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			for _, rec := range sl.GetLogRecords() {
+				body := rec.GetBody().GetStringValue()
+				if _, eof := logStreamOf(rec); eof {
+					body = "EOF" + body
+				}
+				bodies = append(bodies, body)
+			}
+		}
+	}
+	return bodies
+}
+
+func TestLogScrubber(t *testing.T) {
+	trie, err := newSecretScrubTrie([]string{"TOKEN=topsecret"}, []string{"TOKEN"}, nil)
+	require.NoError(t, err)
+
+	t.Run("disabled", func(t *testing.T) {
+		s := &logScrubber{}
+		req := logsRequest(logRecord("span", 1, "topsecret", false))
+		s.Scrub(req)
+		require.Equal(t, []string{"topsecret"}, logBodies(req))
+	})
+
+	t.Run("split across records", func(t *testing.T) {
+		s := &logScrubber{}
+		s.SetTrie(trie)
+
+		req := logsRequest(
+			logRecord("span", 1, "token: top", false),
+			// another stream in between doesn't interfere
+			logRecord("span", 2, "tops", false),
+			logRecord("span", 1, "sec", false),
+		)
+		s.Scrub(req)
+		require.Equal(t, []string{"token: ", "", ""}, logBodies(req))
+
+		// the rest of the secret arrives in the next request
+		req = logsRequest(logRecord("span", 1, "ret\nbye\n", false))
+		s.Scrub(req)
+		require.Equal(t, []string{"***\nbye\n"}, logBodies(req))
+
+		// the bytes held back for stderr are emitted before its EOF
+		req = logsRequest(
+			logRecord("span", 2, "", true),
+			logRecord("span", 1, "top", false),
+		)
+		s.Scrub(req)
+		require.Equal(t, []string{"tops", "EOF", ""}, logBodies(req))
+
+		// the bytes held back for stdout are flushed at the end
+		req = s.Flush()
+		require.Equal(t, []string{"top"}, logBodies(req))
+		require.Nil(t, s.Flush())
+	})
+
+	t.Run("encoded", func(t *testing.T) {
+		s := &logScrubber{}
+		s.SetTrie(trie)
+		req := logsRequest(logRecord("span", 1, "dG9wc2VjcmV0\n", false))
+		s.Scrub(req)
+		require.Equal(t, []string{"***\n"}, logBodies(req))
+	})
+
+	t.Run("many records", func(t *testing.T) {
+		s := &logScrubber{}
+		s.SetTrie(trie)
+		var records []*logspb.LogRecord
+		for _, ch := range "say topsecret twice, topsecret" {
+			records = append(records, logRecord("span", 1, string(ch), false))
+		}
+		records = append(records, logRecord("span", 1, "", true))
+		req := logsRequest(records...)
+		s.Scrub(req)
+		require.Equal(t, "say *** twice, ***EOF", strings.Join(logBodies(req), ""))
+	})
+}
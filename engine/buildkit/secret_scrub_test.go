@@ -4,9 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -192,6 +196,61 @@ func TestScrubSecretWrite(t *testing.T) {
 	})
 }
 
+func TestScrubSecretEncodings(t *testing.T) {
+	t.Parallel()
+	secret := "s3cr3t/t0ken+<value>"
+	env := []string{"TOKEN=" + secret, "SHORT=abc"}
+	secretEnvs := []string{"TOKEN", "SHORT"}
+
+	hexSecret := hex.EncodeToString([]byte(secret))
+	jsonSecret, err := json.Marshal(secret)
+	require.NoError(t, err)
+
+	for name, input := range map[string]string{
+		"base64":      base64.StdEncoding.EncodeToString([]byte(secret)),
+		"base64 url":  base64.URLEncoding.EncodeToString([]byte(secret)),
+		"base64 raw":  base64.RawStdEncoding.EncodeToString([]byte(secret)),
+		"hex":         hexSecret,
+		"hex upper":   strings.ToUpper(hexSecret),
+		"query":       url.QueryEscape(secret),
+		"path":        url.PathEscape(secret),
+		"json":        string(jsonSecret[1 : len(jsonSecret)-1]),
+		"json nohtml": `s3cr3t/t0ken+<value>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewSecretScrubReader(strings.NewReader("token: "+input+"\n"), env, secretEnvs, nil)
+			require.NoError(t, err)
+			out, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, "token: ***\n", string(out))
+		})
+	}
+
+	t.Run("within base64", func(t *testing.T) {
+		for _, prefix := range []string{"", "u:", "us:", "usr:"} {
+			encoded := base64.StdEncoding.EncodeToString([]byte(prefix + secret + "!"))
+			r, err := NewSecretScrubReader(strings.NewReader("Authorization: Basic "+encoded), env, secretEnvs, nil)
+			require.NoError(t, err)
+			out, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Contains(t, string(out), "***", prefix)
+			// only the few characters at the edges, which also encode the
+			// surrounding bytes, are left
+			require.LessOrEqual(t, len(out), len("Authorization: Basic ***")+2*len(prefix)+4, prefix)
+		}
+	})
+
+	t.Run("short secrets", func(t *testing.T) {
+		// the encodings of short secrets are too likely to be false positives
+		input := base64.StdEncoding.EncodeToString([]byte("abc")) + " " + hex.EncodeToString([]byte("abc"))
+		r, err := NewSecretScrubReader(strings.NewReader(input), env, secretEnvs, nil)
+		require.NoError(t, err)
+		out, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, input, string(out))
+	})
+}
+
 func TestScrubSecretLogLatency(t *testing.T) {
 	t.Parallel()
 	envMap := map[string]string{
@@ -485,6 +544,38 @@ func BenchmarkScrubSecret(b *testing.B) {
 	wg.Wait()
 }
 
+// BenchmarkScrubSecretThroughput measures the throughput of scrubbing a stream
+// with many secrets, and so many encodings of them, in the trie.
+func BenchmarkScrubSecretThroughput(b *testing.B) {
+	env := []string{}
+	secretEnvNames := []string{}
+	for i := range 50 {
+		name := fmt.Sprintf("SECRET_%d", i)
+		env = append(env, fmt.Sprintf("%s=%s-%d", name, strings.Repeat("s3cr3t", 5), i))
+		secretEnvNames = append(secretEnvNames, name)
+	}
+
+	// log-like data, with a secret and an encoded secret every so often
+	var data bytes.Buffer
+	rnd := rand.New(rand.NewSource(0))
+	for data.Len() < 1<<20 {
+		fmt.Fprintf(&data, "%d INFO processing item %x\n", rnd.Int(), rnd.Int63())
+		if rnd.Intn(100) == 0 {
+			secret := fmt.Sprintf("%s-%d", strings.Repeat("s3cr3t", 5), rnd.Intn(50))
+			fmt.Fprintf(&data, "token=%s auth=%s\n", secret, base64.StdEncoding.EncodeToString([]byte(secret)))
+		}
+	}
+
+	b.SetBytes(int64(data.Len()))
+	b.ResetTimer()
+	for range b.N {
+		r, err := NewSecretScrubReader(bytes.NewReader(data.Bytes()), env, secretEnvNames, nil)
+		require.NoError(b, err)
+		_, err = io.Copy(io.Discard, r)
+		require.NoError(b, err)
+	}
+}
+
 func TestTrie(t *testing.T) {
 	trie := Trie{}
 
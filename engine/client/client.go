@@ -24,7 +24,6 @@ import (
 	bkclient "github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/identity"
 	bksession "github.com/moby/buildkit/session"
-	"github.com/moby/buildkit/session/auth/authprovider"
 	"github.com/moby/buildkit/util/grpcerrors"
 	"github.com/vito/go-sse/sse"
 	"go.opentelemetry.io/otel/attribute"
@@ -45,6 +44,7 @@ import (
 	"dagger.io/dagger"
 	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/analytics"
+	"github.com/dagger/dagger/auth"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/client/drivers"
 	"github.com/dagger/dagger/engine/client/pathutil"
@@ -72,6 +72,11 @@ type Params struct {
 
 	DisableHostRW bool
 
+	// Give the engine registry credentials from the docker config of the host,
+	// through a cache, only running its credential helpers when a registry
+	// asks for them, one at a time.
+	HostRegistryAuth bool
+
 	EngineCallback   func(context.Context, string, string, string)
 	CloudURLCallback func(context.Context, string, string, bool)
 
@@ -339,13 +344,24 @@ func (c *Client) startSession(ctx context.Context) (rerr error) {
 	clientMetadata := c.clientMetadata()
 	c.internalCtx = engine.ContextWithClientMetadata(c.internalCtx, &clientMetadata)
 
+	// Registry credentials of the host are only given to the engine when asked
+	// to, otherwise registries are only authenticated with withRegistryAuth.
+	registryAuth := auth.NewRegistryAuthProvider()
+	if c.HostRegistryAuth {
+		registryAuth = auth.NewDockerConfigAuthProvider(config.LoadDefaultConfigFile(os.Stderr))
+	} else if len(config.LoadDefaultConfigFile(io.Discard).AuthConfigs) > 0 {
+		// they used to be given by default, so point users relying on a docker
+		// login to the opt-in
+		slog.SpanLogger(ctx, InstrumentationLibrary).Warn("registry credentials from the host's Docker config are no longer used by default: pass --host-registry-auth or set DAGGER_HOST_REGISTRY_AUTH=1 to use them")
+	}
+
 	attachables := []bksession.Attachable{
 		// sockets
 		SocketProvider{EnableHostNetworkAccess: !c.DisableHostRW},
 		// secrets
 		secretprovider.NewSecretProvider(),
 		// registry auth
-		authprovider.NewDockerAuthProvider(config.LoadDefaultConfigFile(os.Stderr), nil),
+		registryAuth,
 		// host=>container networking
 		session.NewTunnelListenerAttachable(ctx),
 		// terminal
@@ -18,6 +18,7 @@ import (
 
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/client/pathutil"
+	"github.com/dagger/dagger/engine/ignore"
 )
 
 type Filesyncer struct {
@@ -104,11 +105,19 @@ func (s FilesyncSource) DiffCopy(stream filesync.FileSync_DiffCopyServer) error
 		if err != nil {
 			return err
 		}
+		// apply ignore files here so that ignored paths are never sent
+		ignored := ignore.NewMatcher(absPath, opts.Gitignore, opts.IgnoreFiles)
+		ignoredMap := ignored.MapFunc()
 		fs, err = fsutil.NewFilterFS(fs, &fsutil.FilterOpt{
 			IncludePatterns: opts.IncludePatterns,
 			ExcludePatterns: opts.ExcludePatterns,
 			FollowPaths:     opts.FollowPaths,
 			Map: func(p string, st *fstypes.Stat) fsutil.MapResult {
+				if ignored.Enabled() {
+					if res := ignoredMap(p, st); res != fsutil.MapResultKeep {
+						return res
+					}
+				}
 				st.Uid = 0
 				st.Gid = 0
 				return fsutil.MapResultKeep
@@ -0,0 +1,103 @@
+package secretprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// AWS Secrets Manager provider for SecretProvider, e.g.
+// aws-sm://prod/db#password. The secret id can be a name or an ARN.
+//
+// The region and credentials are loaded like the AWS CLI does.
+// AWS_ENDPOINT_URL_SECRETS_MANAGER or AWS_ENDPOINT_URL override the endpoint.
+func awsProvider(ctx context.Context, key string) ([]byte, error) {
+	secretID, field := splitField(key)
+	if secretID == "" {
+		return nil, fmt.Errorf("invalid key format: %s", key)
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	overridden := endpoint != ""
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	region := cfg.Region
+	if region == "" {
+		if !overridden {
+			return nil, fmt.Errorf("no AWS region configured")
+		}
+		region = "us-east-1"
+	}
+	if !overridden {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	if !overridden || os.Getenv("AWS_ACCESS_KEY_ID") != "" {
+		creds, err := cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get AWS credentials: %w", err)
+		}
+		hash := sha256.Sum256(body)
+		err = v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", region, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign AWS request: %w", err)
+		}
+	}
+
+	var out struct {
+		SecretString *string
+		SecretBinary []byte
+	}
+	err = doSecretRequest(req, fmt.Sprintf("AWS secret %q", secretID), &out, awsNotFound)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := out.SecretBinary
+	if out.SecretString != nil {
+		plaintext = []byte(*out.SecretString)
+	}
+	return selectField(plaintext, field)
+}
+
+// awsNotFound reports whether an AWS JSON protocol error is for a missing
+// resource.
+func awsNotFound(resp *http.Response, body []byte) bool {
+	if resp.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	var awsErr struct {
+		Type string `json:"__type"`
+	}
+	if err := json.Unmarshal(body, &awsErr); err != nil {
+		return false
+	}
+	// the type may be prefixed with a namespace, e.g. "com.amazonaws...#"
+	return strings.HasSuffix(awsErr.Type, "ResourceNotFoundException")
+}
@@ -0,0 +1,89 @@
+package secretprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+const azureKeyVaultAPIVersion = "7.4"
+
+// Azure Key Vault provider for SecretProvider, e.g.
+// azure-kv://my-vault/db-password or azure-kv://my-vault/db/<version>#password.
+// The version defaults to the latest one.
+//
+// Credentials are found like the Azure SDKs do. AZURE_KEYVAULT_ENDPOINT
+// overrides the endpoint of every vault.
+func azureProvider(ctx context.Context, key string) ([]byte, error) {
+	secretPath, field := splitField(key)
+	parts := strings.Split(secretPath, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid key format: %s", key)
+	}
+	vaultName, secret, version := parts[0], parts[1], ""
+	if len(parts) == 3 {
+		version = parts[2]
+	}
+
+	endpoint := os.Getenv("AZURE_KEYVAULT_ENDPOINT")
+	overridden := endpoint != ""
+	if !overridden {
+		endpoint = fmt.Sprintf("https://%s.vault.azure.net", url.PathEscape(vaultName))
+	}
+
+	u := fmt.Sprintf("%s/secrets/%s", strings.TrimSuffix(endpoint, "/"), url.PathEscape(secret))
+	if version != "" {
+		u += "/" + url.PathEscape(version)
+	}
+	u += "?api-version=" + azureKeyVaultAPIVersion
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !overridden || os.Getenv("AZURE_CLIENT_ID") != "" {
+		token, err := azureAccessToken(ctx, overridden)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Azure credentials: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	err = doSecretRequest(req, fmt.Sprintf("Azure secret %q", secretPath), &out, nil)
+	if err != nil {
+		return nil, err
+	}
+	return selectField([]byte(out.Value), field)
+}
+
+// azureAccessToken returns a token for Key Vault, only using credentials from
+// the environment if envOnly is set.
+func azureAccessToken(ctx context.Context, envOnly bool) (string, error) {
+	var cred azcore.TokenCredential
+	var err error
+	if envOnly {
+		cred, err = azidentity.NewEnvironmentCredential(nil)
+	} else {
+		cred, err = azidentity.NewDefaultAzureCredential(nil)
+	}
+	if err != nil {
+		return "", err
+	}
+	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{"https://vault.azure.net/.default"},
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.Token, nil
+}
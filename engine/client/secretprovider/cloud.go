@@ -0,0 +1,72 @@
+package secretprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/moby/buildkit/session/secrets"
+)
+
+// The cloud secret managers below all accept an optional "#field" suffix, to
+// pick a field of a secret stored as a JSON object.
+//
+// Each can be pointed at another endpoint, such as a local emulator, with an
+// env var. Requests to such an endpoint only use credentials set directly in
+// the environment, if any, rather than looking for them elsewhere.
+
+// splitField splits the "#field" suffix off a secret path.
+func splitField(path string) (string, string) {
+	path, field, _ := strings.Cut(path, "#")
+	return path, field
+}
+
+// selectField returns the given field of a JSON object secret, or the secret
+// as-is if field is empty. String values are returned without quotes.
+func selectField(plaintext []byte, field string) ([]byte, error) {
+	if field == "" {
+		return plaintext, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(plaintext, &obj); err != nil {
+		return nil, fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	val, ok := obj[field]
+	if !ok {
+		return nil, fmt.Errorf("secret field %q: %w", field, secrets.ErrNotFound)
+	}
+	var str string
+	if err := json.Unmarshal(val, &str); err == nil {
+		return []byte(str), nil
+	}
+	return val, nil
+}
+
+// doSecretRequest sends req and decodes a JSON response into out. A 404, or
+// any other response notFound matches if set, is reported as
+// secrets.ErrNotFound.
+func doSecretRequest(req *http.Request, desc string, out any, notFound func(*http.Response, []byte) bool) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", desc, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", desc, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound,
+		notFound != nil && notFound(resp, body):
+		return fmt.Errorf("%s: %w", desc, secrets.ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("failed to get %s: %s: %s", desc, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", desc, err)
+	}
+	return nil
+}
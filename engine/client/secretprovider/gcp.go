@@ -0,0 +1,77 @@
+package secretprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Google Cloud Secret Manager provider for SecretProvider, e.g.
+// gcp-sm://my-project/db-password or gcp-sm://my-project/db/3#password. The
+// version defaults to "latest".
+//
+// The access token is read from GOOGLE_OAUTH_ACCESS_TOKEN, or else from the
+// gcloud CLI. GCP_SECRET_MANAGER_ENDPOINT overrides the endpoint.
+func gcpProvider(ctx context.Context, key string) ([]byte, error) {
+	secretPath, field := splitField(key)
+	parts := strings.Split(secretPath, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid key format: %s", key)
+	}
+	project, secret, version := parts[0], parts[1], "latest"
+	if len(parts) == 3 && parts[2] != "" {
+		version = parts[2]
+	}
+
+	endpoint := os.Getenv("GCP_SECRET_MANAGER_ENDPOINT")
+	overridden := endpoint != ""
+	if !overridden {
+		endpoint = "https://secretmanager.googleapis.com"
+	}
+
+	token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+	if token == "" && !overridden {
+		var err error
+		token, err = gcloudAccessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	u := fmt.Sprintf("%s/v1/projects/%s/secrets/%s/versions/%s:access",
+		strings.TrimSuffix(endpoint, "/"),
+		url.PathEscape(project), url.PathEscape(secret), url.PathEscape(version))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	var out struct {
+		Payload struct {
+			Data []byte `json:"data"`
+		} `json:"payload"`
+	}
+	err = doSecretRequest(req, fmt.Sprintf("GCP secret %q", secretPath), &out, nil)
+	if err != nil {
+		return nil, err
+	}
+	return selectField(out.Payload.Data, field)
+}
+
+func gcloudAccessToken(ctx context.Context) (string, error) {
+	if _, err := exec.LookPath("gcloud"); err != nil {
+		return "", fmt.Errorf("unable to get a GCP access token: Neither `GOOGLE_OAUTH_ACCESS_TOKEN` is set nor `gcloud` binary is present")
+	}
+	out, err := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to get a GCP access token: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,117 @@
+package secretprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/moby/buildkit/session/secrets"
+)
+
+// Secret provider plugins implement schemes that aren't built in. A plugin
+// for scheme "foo" is either the command in $DAGGER_SECRET_PROVIDER_FOO (run
+// with the shell, so it can be e.g. a "dagger call" to a module), or a
+// dagger-secret-foo binary in $PATH.
+//
+// Plugins are run with the argument "get", and read a pluginRequest as JSON
+// from stdin. They must write a pluginResponse as JSON to stdout, and exit
+// with status 0 unless they failed to answer at all.
+
+const (
+	pluginEnvPrefix    = "DAGGER_SECRET_PROVIDER_"
+	pluginBinaryPrefix = "dagger-secret-"
+
+	pluginProtocolVersion = 1
+)
+
+type pluginRequest struct {
+	Version int    `json:"version"`
+	Scheme  string `json:"scheme"`
+	Path    string `json:"path"`
+}
+
+type pluginResponse struct {
+	// Data is the plaintext of the secret, base64-encoded in JSON.
+	Data []byte `json:"data,omitempty"`
+	// Error is set if the secret couldn't be resolved.
+	Error string `json:"error,omitempty"`
+	// NotFound is set with Error if the secret doesn't exist.
+	NotFound bool `json:"notFound,omitempty"`
+}
+
+// only schemes like this are looked up, so that they can't be used to run
+// arbitrary binaries, e.g. with a path separator
+var pluginSchemeValid = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// schemes can contain characters that aren't valid in env var names
+var pluginEnvInvalid = regexp.MustCompile(`[^A-Z0-9_]`)
+
+type pluginProvider struct {
+	scheme string
+	// shell is a command to run with the shell; bin is a binary to exec
+	shell string
+	bin   string
+}
+
+func pluginForScheme(scheme string) (Provider, bool) {
+	if !pluginSchemeValid.MatchString(scheme) {
+		return nil, false
+	}
+	envName := pluginEnvPrefix + pluginEnvInvalid.ReplaceAllString(strings.ToUpper(scheme), "_")
+	if cmd := os.Getenv(envName); cmd != "" {
+		return &pluginProvider{scheme: scheme, shell: cmd}, true
+	}
+	if bin, err := exec.LookPath(pluginBinaryPrefix + scheme); err == nil {
+		return &pluginProvider{scheme: scheme, bin: bin}, true
+	}
+	return nil, false
+}
+
+func (p *pluginProvider) command(ctx context.Context) *exec.Cmd {
+	if p.bin != "" {
+		return exec.CommandContext(ctx, p.bin, "get")
+	}
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd.exe", "/C", p.shell+" get")
+	}
+	// #nosec G204
+	return exec.CommandContext(ctx, "sh", "-c", p.shell+" get")
+}
+
+func (p *pluginProvider) Resolve(ctx context.Context, path string) ([]byte, error) {
+	req, err := json.Marshal(pluginRequest{
+		Version: pluginProtocolVersion,
+		Scheme:  p.scheme,
+		Path:    path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := p.command(ctx)
+	cmd.Stdin = bytes.NewReader(req)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("secret provider plugin for %q failed: %w: %s", p.scheme, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp pluginResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("secret provider plugin for %q returned an invalid response: %w", p.scheme, err)
+	}
+	if resp.Error != "" {
+		if resp.NotFound {
+			return nil, fmt.Errorf("%s: %w", resp.Error, secrets.ErrNotFound)
+		}
+		return nil, fmt.Errorf("secret provider plugin for %q: %s", p.scheme, resp.Error)
+	}
+	return resp.Data, nil
+}
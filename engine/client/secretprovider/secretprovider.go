@@ -12,27 +12,56 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// Provider resolves the plaintext of secrets with a given URI scheme, from the
+// rest of the URI. It should wrap secrets.ErrNotFound if the secret doesn't
+// exist.
+type Provider interface {
+	Resolve(ctx context.Context, path string) ([]byte, error)
+}
+
 type SecretResolver func(context.Context, string) ([]byte, error)
 
-var resolvers = map[string]SecretResolver{
-	"env":   envProvider,
-	"file":  fileProvider,
-	"cmd":   cmdProvider,
-	"op":    opProvider,
-	"vault": vaultProvider,
+func (r SecretResolver) Resolve(ctx context.Context, path string) ([]byte, error) {
+	return r(ctx, path)
 }
 
-func ResolverForID(id string) (SecretResolver, string, error) {
+var providers = map[string]Provider{
+	"env":      SecretResolver(envProvider),
+	"file":     SecretResolver(fileProvider),
+	"cmd":      SecretResolver(cmdProvider),
+	"op":       SecretResolver(opProvider),
+	"vault":    SecretResolver(vaultProvider),
+	"aws-sm":   SecretResolver(awsProvider),
+	"gcp-sm":   SecretResolver(gcpProvider),
+	"azure-kv": SecretResolver(azureProvider),
+}
+
+// ParseID splits a secret URI into its scheme and path. It doesn't check
+// that there is a provider for the scheme, since plugins are only known to
+// the client.
+func ParseID(id string) (scheme string, path string, _ error) {
 	scheme, path, ok := strings.Cut(id, "://")
-	if !ok {
-		return nil, "", fmt.Errorf("parse %q: malformed id", id)
+	if !ok || scheme == "" {
+		return "", "", fmt.Errorf("parse %q: malformed id", id)
+	}
+	return scheme, path, nil
+}
+
+func ResolverForID(id string) (SecretResolver, string, error) {
+	scheme, path, err := ParseID(id)
+	if err != nil {
+		return nil, "", err
 	}
 
-	resolver, ok := resolvers[scheme]
+	provider, ok := providers[scheme]
 	if !ok {
-		return nil, "", fmt.Errorf("unsupported secret provider: %q", scheme)
+		// fall back to a plugin, if one is installed
+		provider, ok = pluginForScheme(scheme)
+		if !ok {
+			return nil, "", fmt.Errorf("unsupported secret provider: %q", scheme)
+		}
 	}
-	return resolver, path, nil
+	return provider.Resolve, path, nil
 }
 
 type SecretProvider struct {
@@ -0,0 +1,204 @@
+package secretprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSecretServer serves the parts of the AWS Secrets Manager, GCP Secret
+// Manager and Azure Key Vault APIs used by the providers, from a map of
+// secret names to values.
+type stubSecretServer struct {
+	*httptest.Server
+	secrets map[string]string
+	// authorization is the Authorization header of the last request
+	authorization string
+}
+
+func newStubSecretServer(t *testing.T, secrets map[string]string) *stubSecretServer {
+	srv := &stubSecretServer{secrets: secrets}
+	srv.Server = httptest.NewServer(http.HandlerFunc(srv.serveHTTP))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (srv *stubSecretServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	srv.authorization = r.Header.Get("Authorization")
+	switch {
+	case r.Header.Get("X-Amz-Target") == "secretsmanager.GetSecretValue":
+		var req struct{ SecretId string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		val, ok := srv.secrets[req.SecretId]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"__type": "ResourceNotFoundException"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"SecretString": val})
+	case strings.HasPrefix(r.URL.Path, "/v1/projects/"):
+		// /v1/projects/<project>/secrets/<secret>/versions/<version>:access
+		parts := strings.Split(strings.TrimSuffix(r.URL.Path, ":access"), "/")
+		if len(parts) != 8 {
+			http.NotFound(w, r)
+			return
+		}
+		val, ok := srv.secrets[parts[3]+"/"+parts[5]+"/"+parts[7]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"payload": map[string][]byte{"data": []byte(val)}})
+	case strings.HasPrefix(r.URL.Path, "/secrets/") && r.URL.Query().Get("api-version") != "":
+		val, ok := srv.secrets[strings.TrimPrefix(r.URL.Path, "/secrets/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"value": val})
+	default:
+		http.Error(w, "unexpected request", http.StatusTeapot)
+	}
+}
+
+func resolve(t *testing.T, id string) ([]byte, error) {
+	t.Helper()
+	resolver, path, err := ResolverForID(id)
+	require.NoError(t, err)
+	return resolver(context.Background(), path)
+}
+
+func TestResolverForID(t *testing.T) {
+	_, _, err := ResolverForID("nope://foo")
+	require.ErrorContains(t, err, `unsupported secret provider: "nope"`)
+
+	_, _, err = ResolverForID("nope")
+	require.ErrorContains(t, err, "malformed id")
+}
+
+func TestPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin script needs a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1" = get ] || exit 1
+read -r req
+case "$req" in
+*'"path":"missing"'*) echo '{"error":"no such secret","notFound":true}' ;;
+*'"path":"broken"'*) echo 'not json' ;;
+*) echo '{"data":"c2hoaA=="}' ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dagger-secret-stub"), []byte(script), 0o755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	plaintext, err := resolve(t, "stub://anything")
+	require.NoError(t, err)
+	require.Equal(t, "shhh", string(plaintext))
+
+	_, err = resolve(t, "stub://missing")
+	require.ErrorIs(t, err, secrets.ErrNotFound)
+
+	_, err = resolve(t, "stub://broken")
+	require.ErrorContains(t, err, "invalid response")
+
+	// a command from the env takes precedence over the binary
+	t.Setenv("DAGGER_SECRET_PROVIDER_STUB_ENV", `echo '{"data":"ZW52"}'; true`)
+	plaintext, err = resolve(t, "stub-env://anything")
+	require.NoError(t, err)
+	require.Equal(t, "env", string(plaintext))
+
+	// schemes that could name a binary elsewhere aren't looked up
+	for _, scheme := range []string{"stub/../stub", "STUB", "stub env"} {
+		_, _, err = ResolverForID(scheme + "://anything")
+		require.ErrorContains(t, err, "unsupported secret provider")
+	}
+}
+
+func TestCloudProviders(t *testing.T) {
+	srv := newStubSecretServer(t, map[string]string{
+		"prod/db":                 `{"user":"admin","password":"hunter2","port":5432}`,
+		"my-project/token/latest": "gcp-token",
+		"my-project/token/3":      "gcp-token-v3",
+		"token":                   "azure-token",
+		"token/0123456789abcdef":  "azure-token-old",
+	})
+
+	// keep credentials on the host out of the way
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+	t.Setenv("AZURE_CLIENT_ID", "")
+
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", srv.URL)
+	t.Setenv("GCP_SECRET_MANAGER_ENDPOINT", srv.URL)
+	t.Setenv("AZURE_KEYVAULT_ENDPOINT", srv.URL)
+
+	for _, tc := range []struct {
+		id       string
+		expected string
+	}{
+		{"aws-sm://prod/db#password", "hunter2"},
+		{"aws-sm://prod/db#port", "5432"},
+		{"gcp-sm://my-project/token", "gcp-token"},
+		{"gcp-sm://my-project/token/3", "gcp-token-v3"},
+		{"azure-kv://my-vault/token", "azure-token"},
+		{"azure-kv://my-vault/token/0123456789abcdef", "azure-token-old"},
+	} {
+		t.Run(tc.id, func(t *testing.T) {
+			plaintext, err := resolve(t, tc.id)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(plaintext))
+			require.Empty(t, srv.authorization)
+		})
+	}
+
+	for _, id := range []string{
+		"aws-sm://missing",
+		"aws-sm://prod/db#missing",
+		"gcp-sm://my-project/missing",
+		"azure-kv://my-vault/missing",
+	} {
+		t.Run(id, func(t *testing.T) {
+			_, err := resolve(t, id)
+			require.ErrorIs(t, err, secrets.ErrNotFound)
+		})
+	}
+
+	t.Run("credentials from env", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+		plaintext, err := resolve(t, "aws-sm://prod/db#user")
+		require.NoError(t, err)
+		require.Equal(t, "admin", string(plaintext))
+		require.True(t, strings.HasPrefix(srv.authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"))
+
+		t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "gcp-access-token")
+		_, err = resolve(t, "gcp-sm://my-project/token")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer gcp-access-token", srv.authorization)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, id := range []string{"aws-sm://", "gcp-sm://my-project", "azure-kv://my-vault/a/b/c"} {
+			_, err := resolve(t, id)
+			require.ErrorContains(t, err, "invalid key format")
+		}
+	})
+}
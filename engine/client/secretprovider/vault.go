@@ -2,96 +2,433 @@ package secretprovider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	vault "github.com/hashicorp/vault/api"
 	auth "github.com/hashicorp/vault/api/auth/approle"
+	"github.com/moby/buildkit/session/secrets"
 )
 
-var (
-	vaultClient *vault.Client
-	vaultCache  = make(map[string]map[string]any)
+const (
+	// vaultDefaultCacheTTL is how long a secret is cached if Vault doesn't
+	// say, and the upper bound otherwise, so rotated secrets get picked up.
+	vaultDefaultCacheTTL = 5 * time.Minute
+
+	// vaultTokenRefreshMargin is how long before its expiry a token from a
+	// login is replaced.
+	vaultTokenRefreshMargin = 30 * time.Second
+
+	vaultDefaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
 )
 
+var defaultVault = &vaultSecrets{}
+
 // HashiCorp Vault provider for SecretProvider
 func vaultProvider(ctx context.Context, key string) ([]byte, error) {
-	// KVv2 mount path. Default "secret"
+	return defaultVault.resolve(ctx, key)
+}
+
+// vaultSecrets reads secrets from a KV secrets engine, caching them for
+// their lease duration.
+type vaultSecrets struct {
+	// loginMu serializes creating the client and logging in, so that reads
+	// racing past an expired or revoked token only log in once
+	loginMu sync.Mutex
+
+	// mu guards the fields below, but isn't held while talking to Vault, so
+	// that a slow request doesn't hold up cached secrets
+	mu sync.Mutex
+
+	client *vault.Client
+	// login is the auth method the client logged in with, if not a token
+	login vault.AuthMethod
+	// tokenExpiry is when the token from the last login expires, if ever
+	tokenExpiry time.Time
+
+	// kvVersions is the KV version of each mount
+	kvVersions map[string]int
+	cache      map[vaultCacheKey]vaultCacheEntry
+
+	// now is time.Now, unless overridden in tests
+	now func() time.Time
+}
+
+type vaultCacheKey struct {
+	mount   string
+	path    string
+	version int
+}
+
+type vaultCacheEntry struct {
+	data    map[string]any
+	expires time.Time
+}
+
+// resolve returns a field of a secret, from a key like
+// "path/to/secret.field?version=3". The version is optional, and only
+// supported by KV v2.
+func (v *vaultSecrets) resolve(ctx context.Context, key string) ([]byte, error) {
+	// KV mount path. Default "secret"
 	mount := os.Getenv("VAULT_PATH_PREFIX")
 	if mount == "" {
 		mount = "secret"
 	}
 
+	secretKey, query, _ := strings.Cut(key, "?")
 	// split key into path and field, e.g. "path/to/secret.field"
-	keyParts := strings.Split(key, ".")
+	keyParts := strings.Split(secretKey, ".")
 	if len(keyParts) != 2 {
 		return nil, fmt.Errorf("invalid key format: %s", key)
 	}
 	secretPath := keyParts[0]
 	secretField := keyParts[1]
 
-	// check if client is initialized
-	if vaultClient == nil {
-		err := vaultConfigureClient(ctx)
-		if err != nil {
-			return nil, err
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key format: %s: %w", key, err)
+	}
+	var version int
+	for name, vals := range params {
+		switch name {
+		case "version":
+			version, err = strconv.Atoi(vals[len(vals)-1])
+			if err != nil || version < 1 {
+				return nil, fmt.Errorf("invalid version %q: must be a positive integer", vals[len(vals)-1])
+			}
+		default:
+			return nil, fmt.Errorf("unknown parameter %q in %s", name, key)
 		}
 	}
 
-	// check if path is in key cache
-	if _, ok := vaultCache[key]; !ok {
-		// read the secret
-		s, err := vaultClient.KVv2(mount).Get(ctx, secretPath)
-		if err != nil {
+	data, err := v.read(ctx, vaultCacheKey{mount: mount, path: secretPath, version: version})
+	if err != nil {
+		return nil, err
+	}
+	val, ok := data[secretField]
+	if !ok {
+		return nil, fmt.Errorf("field %q of vault secret %q: %w", secretField, secretPath, secrets.ErrNotFound)
+	}
+	if str, ok := val.(string); ok {
+		return []byte(str), nil
+	}
+	return json.Marshal(val)
+}
+
+// read returns the data of a secret, from the cache if it hasn't expired.
+func (v *vaultSecrets) read(ctx context.Context, key vaultCacheKey) (map[string]any, error) {
+	now := v.timeNow()
+	v.mu.Lock()
+	entry, ok := v.cache[key]
+	v.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.data, nil
+	}
+
+	client, login, err := v.ensureClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	kvVersion, err := v.kvVersion(ctx, client, key.mount)
+	if err != nil {
+		return nil, err
+	}
+
+	if kvVersion == 1 && key.version != 0 {
+		return nil, fmt.Errorf("versions are only supported by KV v2, but %q is KV v1", key.mount)
+	}
+	get := func() (*vault.KVSecret, error) {
+		switch {
+		case kvVersion == 1:
+			return client.KVv1(key.mount).Get(ctx, key.path)
+		case key.version != 0:
+			return client.KVv2(key.mount).GetVersion(ctx, key.path, key.version)
+		default:
+			return client.KVv2(key.mount).Get(ctx, key.path)
+		}
+	}
+	token := client.Token()
+	s, err := get()
+	var respErr *vault.ResponseError
+	if err != nil && login != nil && errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden {
+		// the token may have been revoked early; log in again and retry once
+		if err := v.reauthenticate(ctx, client, login, token); err != nil {
 			return nil, err
 		}
-		// cache response
-		vaultCache[key] = s.Data
+		s, err = get()
+	}
+	if err != nil {
+		if errors.Is(err, vault.ErrSecretNotFound) {
+			return nil, fmt.Errorf("%w: %w", err, secrets.ErrNotFound)
+		}
+		return nil, err
 	}
 
-	return []byte(vaultCache[key][secretField].(string)), nil
+	ttl := vaultCacheTTL()
+	if s.Raw != nil && s.Raw.LeaseDuration > 0 {
+		ttl = min(ttl, time.Duration(s.Raw.LeaseDuration)*time.Second)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.cache == nil {
+		v.cache = make(map[vaultCacheKey]vaultCacheEntry)
+	}
+	v.cache[key] = vaultCacheEntry{data: s.Data, expires: now.Add(ttl)}
+	return s.Data, nil
+}
+
+// ensureClient returns the client and the auth method it logged in with,
+// creating it first if needed, and logs in again if the token from the last
+// login is about to expire.
+func (v *vaultSecrets) ensureClient(ctx context.Context) (*vault.Client, vault.AuthMethod, error) {
+	v.mu.Lock()
+	client, login, tokenExpiry := v.client, v.login, v.tokenExpiry
+	v.mu.Unlock()
+	if client != nil && !v.tokenExpiring(login, tokenExpiry) {
+		return client, login, nil
+	}
+
+	v.loginMu.Lock()
+	defer v.loginMu.Unlock()
+	// another read may have done it while we were waiting
+	v.mu.Lock()
+	client, login, tokenExpiry = v.client, v.login, v.tokenExpiry
+	v.mu.Unlock()
+	if client == nil {
+		return v.configureClient(ctx)
+	}
+	if v.tokenExpiring(login, tokenExpiry) {
+		if err := v.authenticate(ctx, client, login); err != nil {
+			return nil, nil, err
+		}
+	}
+	return client, login, nil
 }
 
-// Load configuration from environment and create a new vault client
-func vaultConfigureClient(ctx context.Context) error {
+// tokenExpiring returns true if the token from a login with the given auth
+// method is about to expire.
+func (v *vaultSecrets) tokenExpiring(login vault.AuthMethod, tokenExpiry time.Time) bool {
+	return login != nil && !tokenExpiry.IsZero() &&
+		v.timeNow().Add(vaultTokenRefreshMargin).After(tokenExpiry)
+}
+
+// Load configuration from environment and create a new vault client. Must be
+// called with loginMu held.
+func (v *vaultSecrets) configureClient(ctx context.Context) (*vault.Client, vault.AuthMethod, error) {
 	config := vault.DefaultConfig()
 
 	// Load configuration from environment
 	err := config.ReadEnvironment()
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Create client. Auths with VAULT_TOKEN by default
 	client, err := vault.NewClient(config)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	login, err := vaultAuthMethodFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	if login != nil {
+		if err := v.authenticate(ctx, client, login); err != nil {
+			return nil, nil, err
+		}
 	}
 
+	// Set client
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.client = client
+	v.login = login
+	v.kvVersions = nil
+	return client, login, nil
+}
+
+// vaultAuthMethodFromEnv returns the auth method configured in the
+// environment, or nil to use VAULT_TOKEN.
+func vaultAuthMethodFromEnv() (vault.AuthMethod, error) {
 	// Use AppRole if provided
-	roleID := os.Getenv("VAULT_APPROLE_ROLE_ID")
-	if roleID != "" {
+	if roleID := os.Getenv("VAULT_APPROLE_ROLE_ID"); roleID != "" {
 		secretID := &auth.SecretID{FromEnv: "VAULT_APPROLE_SECRET_ID"}
-		// Authenticate
 		appRoleAuth, err := auth.NewAppRoleAuth(
 			roleID,
 			secretID,
 		)
 		if err != nil {
-			return fmt.Errorf("unable to initialize Vault AppRole auth method: %w", err)
+			return nil, fmt.Errorf("unable to initialize Vault AppRole auth method: %w", err)
 		}
+		return appRoleAuth, nil
+	}
 
-		authInfo, err := client.Auth().Login(ctx, appRoleAuth)
-		if err != nil {
-			return fmt.Errorf("unable to login to Vault AppRole auth method: %w", err)
+	// Use Kubernetes if provided, with the pod's service account token
+	if role := os.Getenv("VAULT_KUBERNETES_ROLE"); role != "" {
+		tokenPath := os.Getenv("VAULT_KUBERNETES_TOKEN_PATH")
+		if tokenPath == "" {
+			tokenPath = vaultDefaultKubernetesTokenPath
+		}
+		return &vaultJWTAuth{
+			name:      "Kubernetes",
+			mount:     envOr("VAULT_KUBERNETES_MOUNT", "kubernetes"),
+			role:      role,
+			tokenPath: tokenPath,
+		}, nil
+	}
+
+	// Use JWT/OIDC if provided, with a token from the env or a file
+	if role := os.Getenv("VAULT_JWT_ROLE"); role != "" {
+		jwtAuth := &vaultJWTAuth{
+			name:      "JWT",
+			mount:     envOr("VAULT_JWT_MOUNT", "jwt"),
+			role:      role,
+			token:     os.Getenv("VAULT_JWT"),
+			tokenPath: os.Getenv("VAULT_JWT_FILE"),
 		}
-		if authInfo == nil {
-			return fmt.Errorf("no auth info was returned after Vault AppRole login")
+		if jwtAuth.token == "" && jwtAuth.tokenPath == "" {
+			return nil, fmt.Errorf("unable to initialize Vault JWT auth method: neither VAULT_JWT nor VAULT_JWT_FILE is set")
 		}
+		return jwtAuth, nil
 	}
 
-	// Set client
-	vaultClient = client
+	return nil, nil
+}
+
+// authenticate logs in with the given auth method, and swaps the new token
+// into the client, which may be in use meanwhile. Must be called with loginMu
+// held.
+func (v *vaultSecrets) authenticate(ctx context.Context, client *vault.Client, login vault.AuthMethod) error {
+	// log in with a copy of the client, so that the expiring token isn't sent
+	// along, and reads keep using it until the new one is set
+	loginClient, err := client.CloneWithHeaders()
+	if err != nil {
+		return fmt.Errorf("unable to copy Vault client: %w", err)
+	}
+	loginClient.ClearToken()
+	authInfo, err := loginClient.Auth().Login(ctx, login)
+	if err != nil {
+		return fmt.Errorf("unable to login to Vault: %w", err)
+	}
+	if authInfo == nil || authInfo.Auth == nil {
+		return fmt.Errorf("no auth info was returned after Vault login")
+	}
+	var tokenExpiry time.Time
+	if authInfo.Auth.LeaseDuration > 0 {
+		tokenExpiry = v.timeNow().Add(time.Duration(authInfo.Auth.LeaseDuration) * time.Second)
+	}
+	client.SetToken(authInfo.Auth.ClientToken)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tokenExpiry = tokenExpiry
 	return nil
 }
+
+// reauthenticate logs the client in again after staleToken was rejected,
+// unless another read already did.
+func (v *vaultSecrets) reauthenticate(ctx context.Context, client *vault.Client, login vault.AuthMethod, staleToken string) error {
+	v.loginMu.Lock()
+	defer v.loginMu.Unlock()
+	if client.Token() != staleToken {
+		return nil
+	}
+	return v.authenticate(ctx, client, login)
+}
+
+// kvVersion returns the version of the KV secrets engine at mount, from
+// VAULT_KV_VERSION or else by asking Vault. If Vault can't tell, e.g. for
+// lack of permissions, it's assumed to be 2.
+func (v *vaultSecrets) kvVersion(ctx context.Context, client *vault.Client, mount string) (int, error) {
+	switch env := os.Getenv("VAULT_KV_VERSION"); env {
+	case "":
+	case "1", "2":
+		return strconv.Atoi(env)
+	default:
+		return 0, fmt.Errorf("invalid VAULT_KV_VERSION %q: must be 1 or 2", env)
+	}
+
+	v.mu.Lock()
+	version, ok := v.kvVersions[mount]
+	v.mu.Unlock()
+	if ok {
+		return version, nil
+	}
+	version = 2
+	s, err := client.Logical().ReadWithContext(ctx, "sys/internal/ui/mounts/"+mount)
+	if err == nil && s != nil && s.Data["type"] == "kv" {
+		version = 1
+		if opts, ok := s.Data["options"].(map[string]any); ok && opts["version"] == "2" {
+			version = 2
+		}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.kvVersions == nil {
+		v.kvVersions = make(map[string]int)
+	}
+	v.kvVersions[mount] = version
+	return version, nil
+}
+
+func (v *vaultSecrets) timeNow() time.Time {
+	if v.now != nil {
+		return v.now()
+	}
+	return time.Now()
+}
+
+// vaultCacheTTL returns the maximum time to cache secrets for, from
+// VAULT_CACHE_TTL if it's a valid duration.
+func vaultCacheTTL() time.Duration {
+	if ttl, err := time.ParseDuration(os.Getenv("VAULT_CACHE_TTL")); err == nil && ttl >= 0 {
+		return ttl
+	}
+	return vaultDefaultCacheTTL
+}
+
+// vaultJWTAuth logs in to the Kubernetes or JWT/OIDC auth methods, which take
+// the same parameters. The token is read again on every login, since
+// projected tokens are rotated.
+type vaultJWTAuth struct {
+	name      string
+	mount     string
+	role      string
+	token     string
+	tokenPath string
+}
+
+func (a *vaultJWTAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	token := a.token
+	if token == "" {
+		dt, err := os.ReadFile(a.tokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s token: %w", a.name, err)
+		}
+		token = strings.TrimSpace(string(dt))
+	}
+	authInfo, err := client.Logical().WriteWithContext(ctx, "auth/"+a.mount+"/login", map[string]any{
+		"role": a.role,
+		"jwt":  token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to Vault %s auth method: %w", a.name, err)
+	}
+	return authInfo, nil
+}
+
+func envOr(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,371 @@
+package secretprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/stretchr/testify/require"
+)
+
+// stubVault serves a KV v2 engine at "secret" and a KV v1 engine at "kv1",
+// and the Kubernetes auth method.
+type stubVault struct {
+	mu sync.Mutex
+	// versions are the versions of each KV v2 secret, oldest first
+	versions map[string][]map[string]any
+	// v1 are the KV v1 secrets
+	v1 map[string]map[string]any
+	// tokens are the valid tokens
+	tokens map[string]bool
+	logins int
+	reads  int
+}
+
+func newStubVault(t *testing.T) (*stubVault, string) {
+	sv := &stubVault{
+		versions: map[string][]map[string]any{},
+		v1:       map[string]map[string]any{},
+		tokens:   map[string]bool{"root": true},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(sv.serveHTTP))
+	t.Cleanup(srv.Close)
+	return sv, srv.URL
+}
+
+func (sv *stubVault) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	reply := func(v any) {
+		json.NewEncoder(w).Encode(v)
+	}
+	deny := func() {
+		w.WriteHeader(http.StatusForbidden)
+		reply(map[string]any{"errors": []string{"permission denied"}})
+	}
+
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		if r.URL.Path == "/v1/auth/kubernetes/login" {
+			var req struct{ Role, JWT string }
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Role != "dagger" || req.JWT != "k8s-jwt" {
+				deny()
+				return
+			}
+			sv.logins++
+			token := fmt.Sprintf("token-%d", sv.logins)
+			sv.tokens[token] = true
+			reply(map[string]any{"auth": map[string]any{"client_token": token, "lease_duration": 60, "renewable": true}})
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	if !sv.tokens[r.Header.Get("X-Vault-Token")] {
+		deny()
+		return
+	}
+	switch path := strings.TrimPrefix(r.URL.Path, "/v1/"); {
+	case path == "sys/internal/ui/mounts/secret":
+		reply(map[string]any{"data": map[string]any{"type": "kv", "options": map[string]any{"version": "2"}}})
+	case path == "sys/internal/ui/mounts/kv1":
+		reply(map[string]any{"data": map[string]any{"type": "kv", "options": nil}})
+	case strings.HasPrefix(path, "secret/data/"):
+		sv.reads++
+		versions := sv.versions[strings.TrimPrefix(path, "secret/data/")]
+		version := len(versions)
+		if v := r.URL.Query().Get("version"); v != "" {
+			fmt.Sscan(v, &version)
+		}
+		if version < 1 || version > len(versions) {
+			w.WriteHeader(http.StatusNotFound)
+			reply(map[string]any{"errors": []string{}})
+			return
+		}
+		reply(map[string]any{"data": map[string]any{
+			"data":     versions[version-1],
+			"metadata": map[string]any{"version": version},
+		}})
+	case strings.HasPrefix(path, "kv1/"):
+		sv.reads++
+		data, ok := sv.v1[strings.TrimPrefix(path, "kv1/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			reply(map[string]any{"errors": []string{}})
+			return
+		}
+		reply(map[string]any{"data": data, "lease_duration": 60})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (sv *stubVault) put(path string, data map[string]any) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.versions[path] = append(sv.versions[path], data)
+}
+
+func clearVaultEnv(t *testing.T) {
+	for _, name := range []string{
+		"VAULT_PATH_PREFIX", "VAULT_KV_VERSION", "VAULT_CACHE_TTL",
+		"VAULT_APPROLE_ROLE_ID", "VAULT_KUBERNETES_ROLE", "VAULT_JWT_ROLE",
+		"VAULT_TOKEN", "VAULT_NAMESPACE",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	ctx := context.Background()
+	clearVaultEnv(t)
+	sv, addr := newStubVault(t)
+	t.Setenv("VAULT_ADDR", addr)
+	t.Setenv("VAULT_TOKEN", "root")
+
+	sv.put("app", map[string]any{"password": "one", "port": 5432})
+	sv.put("app", map[string]any{"password": "two", "port": 5432})
+
+	now := time.Now()
+	v := &vaultSecrets{now: func() time.Time { return now }}
+
+	t.Run("latest", func(t *testing.T) {
+		val, err := v.resolve(ctx, "app.password")
+		require.NoError(t, err)
+		require.Equal(t, "two", string(val))
+
+		val, err = v.resolve(ctx, "app.port")
+		require.NoError(t, err)
+		require.Equal(t, "5432", string(val))
+	})
+
+	t.Run("version", func(t *testing.T) {
+		val, err := v.resolve(ctx, "app.password?version=1")
+		require.NoError(t, err)
+		require.Equal(t, "one", string(val))
+
+		_, err = v.resolve(ctx, "app.password?version=0")
+		require.ErrorContains(t, err, "invalid version")
+		_, err = v.resolve(ctx, "app.password?foo=1")
+		require.ErrorContains(t, err, "unknown parameter")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := v.resolve(ctx, "missing.password")
+		require.ErrorIs(t, err, secrets.ErrNotFound)
+		_, err = v.resolve(ctx, "app.missing")
+		require.ErrorIs(t, err, secrets.ErrNotFound)
+		_, err = v.resolve(ctx, "app.password?version=9")
+		require.ErrorIs(t, err, secrets.ErrNotFound)
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		sv.put("app", map[string]any{"password": "three"})
+
+		// still cached
+		val, err := v.resolve(ctx, "app.password")
+		require.NoError(t, err)
+		require.Equal(t, "two", string(val))
+
+		now = now.Add(vaultDefaultCacheTTL + time.Second)
+		val, err = v.resolve(ctx, "app.password")
+		require.NoError(t, err)
+		require.Equal(t, "three", string(val))
+	})
+
+	t.Run("kv v1", func(t *testing.T) {
+		t.Setenv("VAULT_PATH_PREFIX", "kv1")
+		sv.v1["app"] = map[string]any{"password": "v1"}
+
+		val, err := v.resolve(ctx, "app.password")
+		require.NoError(t, err)
+		require.Equal(t, "v1", string(val))
+
+		_, err = v.resolve(ctx, "app.password?version=1")
+		require.ErrorContains(t, err, "only supported by KV v2")
+
+		// the lease duration is shorter than the default TTL
+		sv.v1["app"] = map[string]any{"password": "v1-rotated"}
+		now = now.Add(61 * time.Second)
+		val, err = v.resolve(ctx, "app.password")
+		require.NoError(t, err)
+		require.Equal(t, "v1-rotated", string(val))
+	})
+}
+
+func TestVaultKubernetesAuth(t *testing.T) {
+	ctx := context.Background()
+	clearVaultEnv(t)
+	sv, addr := newStubVault(t)
+	t.Setenv("VAULT_ADDR", addr)
+	sv.put("app", map[string]any{"password": "shhh"})
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("k8s-jwt\n"), 0o600))
+	t.Setenv("VAULT_KUBERNETES_ROLE", "dagger")
+	t.Setenv("VAULT_KUBERNETES_TOKEN_PATH", tokenPath)
+	t.Setenv("VAULT_CACHE_TTL", "0s")
+
+	now := time.Now()
+	v := &vaultSecrets{now: func() time.Time { return now }}
+
+	val, err := v.resolve(ctx, "app.password")
+	require.NoError(t, err)
+	require.Equal(t, "shhh", string(val))
+	require.Equal(t, 1, sv.logins)
+
+	// the token is still valid
+	now = now.Add(10 * time.Second)
+	_, err = v.resolve(ctx, "app.password")
+	require.NoError(t, err)
+	require.Equal(t, 1, sv.logins)
+
+	// the token is about to expire
+	now = now.Add(30 * time.Second)
+	_, err = v.resolve(ctx, "app.password")
+	require.NoError(t, err)
+	require.Equal(t, 2, sv.logins)
+
+	// the token was revoked
+	sv.mu.Lock()
+	sv.tokens = map[string]bool{}
+	sv.mu.Unlock()
+	_, err = v.resolve(ctx, "app.password")
+	require.NoError(t, err)
+	require.Equal(t, 3, sv.logins)
+}
+
+func TestVaultConcurrentLogins(t *testing.T) {
+	ctx := context.Background()
+	clearVaultEnv(t)
+	sv, addr := newStubVault(t)
+	t.Setenv("VAULT_ADDR", addr)
+	sv.put("app", map[string]any{"password": "shhh"})
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("k8s-jwt\n"), 0o600))
+	t.Setenv("VAULT_KUBERNETES_ROLE", "dagger")
+	t.Setenv("VAULT_KUBERNETES_TOKEN_PATH", tokenPath)
+	t.Setenv("VAULT_CACHE_TTL", "0s")
+
+	var clockMu sync.Mutex
+	now := time.Now()
+	v := &vaultSecrets{now: func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return now
+	}}
+	logins := func() int {
+		sv.mu.Lock()
+		defer sv.mu.Unlock()
+		return sv.logins
+	}
+	readConcurrently := func() {
+		t.Helper()
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				val, err := v.resolve(ctx, "app.password")
+				if err == nil && string(val) != "shhh" {
+					err = fmt.Errorf("unexpected value %q", val)
+				}
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+	}
+
+	// the client is only configured once
+	readConcurrently()
+	require.Equal(t, 1, logins())
+
+	// reads racing past the token's expiry only log in once
+	clockMu.Lock()
+	now = now.Add(45 * time.Second)
+	clockMu.Unlock()
+	readConcurrently()
+	require.Equal(t, 2, logins())
+
+	// reads racing past a revoked token only log in once
+	sv.mu.Lock()
+	sv.tokens = map[string]bool{}
+	sv.mu.Unlock()
+	readConcurrently()
+	require.Equal(t, 3, logins())
+}
+
+// TestVaultDevServer runs against a real Vault, if the vault binary is
+// installed.
+func TestVaultDevServer(t *testing.T) {
+	bin, err := exec.LookPath("vault")
+	if err != nil {
+		t.Skip("vault binary not found")
+	}
+	ctx := context.Background()
+	clearVaultEnv(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listenAddr := l.Addr().String()
+	l.Close()
+
+	cmd := exec.Command(bin, "server", "-dev", "-dev-root-token-id=root", "-dev-listen-address="+listenAddr)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	addr := "http://" + listenAddr
+	t.Setenv("VAULT_ADDR", addr)
+	t.Setenv("VAULT_TOKEN", "root")
+
+	client, err := vault.NewClient(&vault.Config{Address: addr})
+	require.NoError(t, err)
+	client.SetToken("root")
+	require.Eventually(t, func() bool {
+		health, err := client.Sys().HealthWithContext(ctx)
+		return err == nil && health.Initialized && !health.Sealed
+	}, 30*time.Second, 100*time.Millisecond)
+
+	_, err = client.KVv2("secret").Put(ctx, "app", map[string]any{"password": "one"})
+	require.NoError(t, err)
+	_, err = client.KVv2("secret").Put(ctx, "app", map[string]any{"password": "two"})
+	require.NoError(t, err)
+	require.NoError(t, client.Sys().MountWithContext(ctx, "kv1", &vault.MountInput{Type: "kv"}))
+	require.NoError(t, client.KVv1("kv1").Put(ctx, "app", map[string]any{"password": "v1"}))
+
+	v := &vaultSecrets{}
+	val, err := v.resolve(ctx, "app.password")
+	require.NoError(t, err)
+	require.Equal(t, "two", string(val))
+	val, err = v.resolve(ctx, "app.password?version=1")
+	require.NoError(t, err)
+	require.Equal(t, "one", string(val))
+
+	t.Setenv("VAULT_PATH_PREFIX", "kv1")
+	val, err = v.resolve(ctx, "app.password")
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(val))
+}
@@ -0,0 +1,259 @@
+// Package ignore filters directory trees using ignore files that live inside
+// them, following the semantics of .gitignore, or of .dockerignore for the
+// file of that name.
+package ignore
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	continuityfs "github.com/containerd/continuity/fs"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+	"github.com/tonistiigi/fsutil"
+	fstypes "github.com/tonistiigi/fsutil/types"
+)
+
+const (
+	// GitignoreFile is the name of the ignore file read in every directory
+	// when gitignore is enabled.
+	GitignoreFile = ".gitignore"
+
+	// DockerignoreFile is read only from the root, with the semantics of
+	// docker build, when it's one of the ignore files.
+	DockerignoreFile = ".dockerignore"
+
+	// gitInfoExclude is read once, relative to the root, when gitignore is
+	// enabled.
+	gitInfoExclude = ".git/info/exclude"
+)
+
+// Matcher decides whether paths under root are ignored by the ignore files
+// found in root and its subdirectories.
+//
+// Ignore files are read lazily as directories are visited, so a Matcher is
+// cheap to create and only reads what it needs. Patterns in a deeper ignore
+// file take precedence over those in its parents, and nothing below an
+// ignored directory can be re-included, just like git.
+//
+// A .dockerignore file is the exception: like docker build, only the one in
+// root is read, its patterns are anchored to root and use filepath.Match
+// syntax with "**", and a "!" pattern can re-include a path below an
+// ignored directory.
+type Matcher struct {
+	root         string
+	files        []string
+	gitignore    bool
+	dockerignore bool
+
+	mu       sync.Mutex
+	patterns []gitignore.Pattern
+	docker   *patternmatcher.PatternMatcher
+	loaded   map[string]bool
+	ignored  map[string]bool
+	err      error
+}
+
+// NewMatcher returns a Matcher for the tree at root. If gitignore is set,
+// .gitignore files and .git/info/exclude are honored. Any additional
+// ignoreFiles are read in every directory, with the same syntax, except for
+// .dockerignore, which is only read in root.
+func NewMatcher(root string, gitignore bool, ignoreFiles []string) *Matcher {
+	m := &Matcher{
+		root:      root,
+		gitignore: gitignore,
+		loaded:    map[string]bool{},
+		ignored:   map[string]bool{},
+	}
+	if gitignore {
+		m.files = append(m.files, GitignoreFile)
+	}
+	for _, name := range ignoreFiles {
+		if name == DockerignoreFile {
+			m.dockerignore = true
+			continue
+		}
+		m.files = append(m.files, name)
+	}
+	return m
+}
+
+// Enabled returns whether the Matcher has any ignore files to honor.
+func (m *Matcher) Enabled() bool {
+	return len(m.files) > 0 || m.dockerignore
+}
+
+// Ignored returns whether the path p, relative to the root, is ignored.
+func (m *Matcher) Ignored(p string, isDir bool) bool {
+	ignored, _ := m.match(p, isDir)
+	return ignored
+}
+
+// Err returns the first error encountered reading an ignore file.
+func (m *Matcher) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// MapFunc returns an fsutil.MapFunc that skips ignored paths, for use with
+// fsutil.NewFilterFS.
+func (m *Matcher) MapFunc() fsutil.MapFunc {
+	return func(p string, st *fstypes.Stat) fsutil.MapResult {
+		isDir := os.FileMode(st.Mode).IsDir()
+		ignored, final := m.match(p, isDir)
+		switch {
+		case !ignored:
+			return fsutil.MapResultKeep
+		case isDir && final:
+			return fsutil.MapResultSkipDir
+		default:
+			return fsutil.MapResultExclude
+		}
+	}
+}
+
+// match returns whether p is ignored, and whether that also holds for
+// everything below it, which is only false when a .dockerignore exclusion
+// may re-include a path inside an ignored directory.
+func (m *Matcher) match(p string, isDir bool) (ignored bool, final bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p = path.Clean(filepath.ToSlash(p))
+	p = strings.TrimPrefix(p, "/")
+	if p == "." || p == "" {
+		return false, false
+	}
+	parts := strings.Split(p, "/")
+
+	m.load(nil)
+	if m.gitignored(parts, isDir) {
+		return true, true
+	}
+	if m.docker == nil {
+		return false, false
+	}
+	ignored, err := m.docker.MatchesOrParentMatches(filepath.FromSlash(p))
+	if err != nil {
+		m.setErr(err)
+		return false, false
+	}
+	return ignored, ignored && !m.docker.Exclusions()
+}
+
+// gitignored returns whether the path split in parts is ignored by the
+// gitignore-style ignore files.
+func (m *Matcher) gitignored(parts []string, isDir bool) bool {
+	// load ignore files from the root down, bailing out early if any parent
+	// directory is itself ignored
+	for i := 1; i < len(parts); i++ {
+		if m.ignoredDir(parts[:i]) {
+			return true
+		}
+		m.load(parts[:i])
+	}
+	if isDir {
+		return m.ignoredDir(parts)
+	}
+	return gitignore.NewMatcher(m.patterns).Match(parts, false)
+}
+
+func (m *Matcher) ignoredDir(parts []string) bool {
+	key := strings.Join(parts, "/")
+	ignored, ok := m.ignored[key]
+	if !ok {
+		ignored = gitignore.NewMatcher(m.patterns).Match(parts, true)
+		m.ignored[key] = ignored
+	}
+	return ignored
+}
+
+func (m *Matcher) load(dir []string) {
+	key := strings.Join(dir, "/")
+	if m.loaded[key] {
+		return
+	}
+	m.loaded[key] = true
+
+	if key == "" && m.gitignore {
+		m.read(nil, gitInfoExclude)
+	}
+	if key == "" && m.dockerignore {
+		m.readDockerignore()
+	}
+	for _, name := range m.files {
+		m.read(dir, path.Join(key, name))
+	}
+}
+
+func (m *Matcher) read(domain []string, name string) {
+	f, err := m.open(name)
+	if err != nil || f == nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		m.patterns = append(m.patterns, gitignore.ParsePattern(line, domain))
+	}
+	if err := scanner.Err(); err != nil {
+		m.setErr(err)
+	}
+}
+
+func (m *Matcher) readDockerignore() {
+	f, err := m.open(DockerignoreFile)
+	if err != nil || f == nil {
+		return
+	}
+	defer f.Close()
+
+	patterns, err := ignorefile.ReadAll(f)
+	if err != nil {
+		m.setErr(err)
+		return
+	}
+	if len(patterns) == 0 {
+		return
+	}
+	m.docker, err = patternmatcher.New(patterns)
+	if err != nil {
+		m.setErr(err)
+	}
+}
+
+// open opens the file name under the root, returning nil if it's missing or
+// unreadable.
+func (m *Matcher) open(name string) (*os.File, error) {
+	fp, err := continuityfs.RootPath(m.root, name)
+	if err != nil {
+		m.setErr(err)
+		return nil, err
+	}
+	f, err := os.Open(fp)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, os.ErrPermission) {
+			m.setErr(err)
+		}
+		return nil, nil
+	}
+	return f, nil
+}
+
+func (m *Matcher) setErr(err error) {
+	if m.err == nil {
+		m.err = err
+	}
+}
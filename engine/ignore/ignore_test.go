@@ -0,0 +1,106 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonistiigi/fsutil"
+	fstypes "github.com/tonistiigi/fsutil/types"
+)
+
+func TestMatcher(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		".gitignore":        "*.log\n/rootonly\nbuild/\n!keep.log\n",
+		".git/info/exclude": "secret.txt\n",
+		"sub/.gitignore":    "# comment\n\nlocal.txt\n!debug.log\n",
+		"sub/.dockerignore": "docker.txt\n",
+		"sub/.ignore":       "other.txt\n",
+		".ignore":           "foo\n",
+		".dockerignore":     "# comment\nfoo\n/*.md\n!README.md\nvendor\n!vendor/keep\n",
+	} {
+		p := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+
+	t.Run("gitignore", func(t *testing.T) {
+		m := NewMatcher(root, true, nil)
+		require.True(t, m.Enabled())
+
+		for _, tc := range []struct {
+			path    string
+			isDir   bool
+			ignored bool
+		}{
+			{"a.log", false, true},
+			{"keep.log", false, false},
+			{"a.txt", false, false},
+			{"rootonly", false, true},
+			{"sub/rootonly", false, false},
+			{"build", true, true},
+			{"build/keep.log", false, true},
+			{"sub/build/x", false, true},
+			{"secret.txt", false, true},
+			{"sub/local.txt", false, true},
+			{"local.txt", false, false},
+			{"sub/debug.log", false, false},
+			{"sub/docker.txt", false, false},
+			{"/sub/a.log", false, true},
+		} {
+			require.Equal(t, tc.ignored, m.Ignored(tc.path, tc.isDir), tc.path)
+		}
+		require.NoError(t, m.Err())
+	})
+
+	t.Run("ignore files", func(t *testing.T) {
+		m := NewMatcher(root, false, []string{".ignore"})
+		require.True(t, m.Enabled())
+		require.True(t, m.Ignored("sub/other.txt", false))
+		require.False(t, m.Ignored("other.txt", false))
+		require.True(t, m.Ignored("foo", false))
+		require.True(t, m.Ignored("a/foo", false))
+		require.False(t, m.Ignored("a.log", false))
+		require.False(t, m.Ignored("secret.txt", false))
+	})
+
+	t.Run("dockerignore", func(t *testing.T) {
+		m := NewMatcher(root, false, []string{".dockerignore"})
+		require.True(t, m.Enabled())
+
+		for _, tc := range []struct {
+			path    string
+			isDir   bool
+			ignored bool
+		}{
+			// anchored to the root, unlike the same pattern in .ignore
+			{"foo", false, true},
+			{"foo/bar", false, true},
+			{"a/foo", false, false},
+			{"a.md", false, true},
+			{"README.md", false, false},
+			{"sub/a.md", false, false},
+			// nested .dockerignore files aren't read
+			{"sub/docker.txt", false, false},
+			// unlike git, paths can be re-included below an ignored directory
+			{"vendor", true, true},
+			{"vendor/other", false, true},
+			{"vendor/keep", false, false},
+		} {
+			require.Equal(t, tc.ignored, m.Ignored(tc.path, tc.isDir), tc.path)
+		}
+		require.NoError(t, m.Err())
+
+		mapFn := m.MapFunc()
+		require.Equal(t, fsutil.MapResultExclude, mapFn("vendor", &fstypes.Stat{Mode: uint32(os.ModeDir | 0o755)}))
+		require.Equal(t, fsutil.MapResultKeep, mapFn("vendor/keep", &fstypes.Stat{Mode: 0o644}))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		m := NewMatcher(root, false, nil)
+		require.False(t, m.Enabled())
+		require.False(t, m.Ignored("a.log", false))
+	})
+}
@@ -135,6 +135,8 @@ type LocalImportOpts struct {
 	IncludePatterns    []string `json:"include_patterns"`
 	ExcludePatterns    []string `json:"exclude_patterns"`
 	FollowPaths        []string `json:"follow_paths"`
+	Gitignore          bool     `json:"gitignore"`
+	IgnoreFiles        []string `json:"ignore_files"`
 	ReadSingleFileOnly bool     `json:"read_single_file_only"`
 	MaxFileSize        int64    `json:"max_file_size"`
 	StatPathOnly       bool     `json:"stat_path_only"`
@@ -36,6 +36,10 @@ func (p *authProxy) Credentials(ctx context.Context, req *bkauth.CredentialsRequ
 	}
 
 	resp, err = bkauth.NewAuthClient(caller.Conn()).Credentials(ctx, req)
+	if status.Code(err) == codes.NotFound {
+		// the client has no credential for the host either, pull anonymously
+		return &bkauth.CredentialsResponse{}, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get credentials: %w", err)
 	}
@@ -19,6 +19,7 @@ import (
 	"github.com/koron-go/prefixw"
 	"github.com/moby/buildkit/cache/remotecache"
 	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/executor/oci"
 	bkfrontend "github.com/moby/buildkit/frontend"
 	bkgw "github.com/moby/buildkit/frontend/gateway/client"
 	bksession "github.com/moby/buildkit/session"
@@ -76,6 +77,8 @@ type daggerSession struct {
 
 	services *core.Services
 
+	functionCallStreams *core.FunctionCallStreams
+
 	analytics analytics.Tracker
 
 	authProvider *auth.RegistryAuthProvider
@@ -246,6 +249,7 @@ func (srv *Server) initializeDaggerSession(
 	sess.endpoints = map[string]http.Handler{}
 	sess.shutdownCh = make(chan struct{})
 	sess.services = core.NewServices()
+	sess.functionCallStreams = core.NewFunctionCallStreams()
 	sess.authProvider = auth.NewRegistryAuthProvider()
 	sess.refs = map[buildkit.Reference]struct{}{}
 	sess.containers = map[bkgw.Container]struct{}{}
@@ -1382,6 +1386,15 @@ func (srv *Server) Services(ctx context.Context) (*core.Services, error) {
 	return client.daggerSession.services, nil
 }
 
+// The streams of function call results for the current client's session
+func (srv *Server) FunctionCallStreams(ctx context.Context) (*core.FunctionCallStreams, error) {
+	client, err := srv.clientFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.daggerSession.functionCallStreams, nil
+}
+
 // The default platform for the engine as a whole
 func (srv *Server) Platform() core.Platform {
 	return core.Platform(srv.defaultPlatform)
@@ -1397,6 +1410,11 @@ func (srv *Server) LeaseManager() *leaseutil.Manager {
 	return srv.leaseManager
 }
 
+// The DNS config for the engine as a whole
+func (srv *Server) DNSConfig() *oci.DNSConfig {
+	return srv.dns
+}
+
 // The nearest ancestor client that is not a module (either a caller from the host like the CLI
 // or a nested exec). Useful for figuring out where local sources should be resolved from through
 // chains of dependency modules.
@@ -25,6 +25,10 @@ type gitCLI struct {
 	sshAuthSock string   // SSH_AUTH_SOCK env value
 	knownHosts  string   // file path passed to SSH
 	auth        []string // extra auth flags passed to git
+	objectDirs  []string // alternate object directories
+	indexFile   string   // GIT_INDEX_FILE env value
+	config      []string // extra config passed with -c
+	env         []string // extra env values
 
 	hostsPath  string // generated /etc/hosts from network config
 	resolvPath string // generated /etc/resolv.conf from network config
@@ -70,6 +74,14 @@ func (cli *gitCLI) withinDir(gitDir, workDir string) *gitCLI {
 	return &cp
 }
 
+// withConfig returns a copy of the CLI that passes the given key=value config
+// to every command, without writing it to the repo's config.
+func (cli *gitCLI) withConfig(kvs ...string) *gitCLI {
+	cp := *cli
+	cp.config = append(append([]string{}, cli.config...), kvs...)
+	return &cp
+}
+
 func (cli *gitCLI) run(ctx context.Context, args ...string) (_ *bytes.Buffer, err error) {
 	for {
 		stdout, stderr, flush := logs.NewLogStreams(ctx, true)
@@ -84,6 +96,9 @@ func (cli *gitCLI) run(ctx context.Context, args ...string) (_ *bytes.Buffer, er
 		cmd := exec.Command("git")
 		// Block sneaky repositories from using repos from the filesystem as submodules.
 		cmd.Args = append(cmd.Args, "-c", "protocol.file.allow=user")
+		for _, kv := range cli.config {
+			cmd.Args = append(cmd.Args, "-c", kv)
+		}
 		if cli.gitDir != "" {
 			cmd.Args = append(cmd.Args, "--git-dir", cli.gitDir)
 		}
@@ -120,6 +135,13 @@ func (cli *gitCLI) run(ctx context.Context, args ...string) (_ *bytes.Buffer, er
 			}
 		}
 
+		cmd.Env = append(cmd.Env, cli.env...)
+		if cli.indexFile != "" {
+			cmd.Env = append(cmd.Env, "GIT_INDEX_FILE="+cli.indexFile)
+		}
+		if len(cli.objectDirs) > 0 {
+			cmd.Env = append(cmd.Env, "GIT_ALTERNATE_OBJECT_DIRECTORIES="+strings.Join(cli.objectDirs, ":"))
+		}
 		if cli.sshAuthSock != "" {
 			cmd.Env = append(cmd.Env, "SSH_AUTH_SOCK="+cli.sshAuthSock)
 		}
@@ -160,7 +182,7 @@ func GetGitSSHCommand(knownHosts string) string {
 func argsNoDepth(args []string) []string {
 	out := make([]string, 0, len(args))
 	for _, a := range args {
-		if a != "--depth=1" {
+		if !strings.HasPrefix(a, "--depth=") {
 			out = append(out, a)
 		}
 	}
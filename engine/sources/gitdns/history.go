@@ -0,0 +1,128 @@
+package gitdns
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Commit describes a single commit, as reported by git log.
+type Commit struct {
+	SHA            string
+	Parents        []string
+	AuthorName     string
+	AuthorEmail    string
+	AuthorTime     int64
+	CommitterName  string
+	CommitterEmail string
+	CommitTime     int64
+	Message        string
+}
+
+// logFields are the fields of a commit, separated by NULs in logFormat. With
+// -z, commits are separated by NULs too, so each commit is exactly
+// len(logFields) fields.
+var logFields = []string{"%H", "%P", "%an", "%ae", "%at", "%cn", "%ce", "%ct", "%B"}
+
+var logFormat = strings.Join(logFields, "%x00")
+
+// Log returns the commits reachable from rev in the repository at gitDir,
+// newest first. If limit is positive, at most that many are returned.
+//
+// The repository is expected to be a local checkout, such as one made with
+// NoCheckout; nothing is fetched.
+func Log(ctx context.Context, gitDir string, rev string, limit int) ([]Commit, error) {
+	git, cleanup, err := newGitCLI(gitDir, "", "", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	args := []string{"log", "-z", "--format=" + logFormat}
+	if limit > 0 {
+		args = append(args, "-n", strconv.Itoa(limit))
+	}
+	args = append(args, rev, "--")
+	buf, err := git.run(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to log %s", rev)
+	}
+	out := strings.TrimSuffix(buf.String(), "\x00")
+	if out == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(out, "\x00")
+	if len(fields)%len(logFields) != 0 {
+		return nil, errors.Errorf("unexpected git log output: %d fields", len(fields))
+	}
+	commits := make([]Commit, 0, len(fields)/len(logFields))
+	for i := 0; i < len(fields); i += len(logFields) {
+		f := fields[i : i+len(logFields)]
+		authorTime, err := strconv.ParseInt(f[4], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid author time for %s", f[0])
+		}
+		commitTime, err := strconv.ParseInt(f[7], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid commit time for %s", f[0])
+		}
+		commits = append(commits, Commit{
+			SHA:            f[0],
+			Parents:        strings.Fields(f[1]),
+			AuthorName:     f[2],
+			AuthorEmail:    f[3],
+			AuthorTime:     authorTime,
+			CommitterName:  f[5],
+			CommitterEmail: f[6],
+			CommitTime:     commitTime,
+			Message:        strings.TrimRight(f[8], "\n"),
+		})
+	}
+	return commits, nil
+}
+
+// Describe returns the most recent tag reachable from rev in the repository
+// at gitDir, suffixed with the number of commits since and the abbreviated
+// SHA if rev isn't tagged itself, as with git describe --tags. If there are no
+// tags, only the abbreviated SHA is returned.
+func Describe(ctx context.Context, gitDir string, rev string) (string, error) {
+	git, cleanup, err := newGitCLI(gitDir, "", "", "", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	buf, err := git.run(ctx, "describe", "--tags", "--always", rev)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe %s", rev)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// DiffNames returns the paths that differ between the trees of two commits,
+// which may be in different repositories: objects missing from gitDir are
+// looked up in the repositories at otherGitDirs.
+func DiffNames(ctx context.Context, gitDir string, otherGitDirs []string, from, to string) ([]string, error) {
+	git, cleanup, err := newGitCLI(gitDir, "", "", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	for _, dir := range otherGitDirs {
+		git.objectDirs = append(git.objectDirs, dir+"/objects")
+	}
+
+	// rename detection needs file contents, which may not have been fetched
+	buf, err := git.run(ctx, "diff-tree", "-r", "-z", "--name-only", "--no-renames", from, to, "--")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to diff %s and %s", from, to)
+	}
+	out := strings.TrimSuffix(buf.String(), "\x00")
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\x00"), nil
+}
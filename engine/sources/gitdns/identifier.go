@@ -4,10 +4,32 @@ import (
 	bkgit "github.com/moby/buildkit/source/git"
 )
 
-const AttrDNSNamespace = "dagger.dns.namespace"
+const (
+	AttrDNSNamespace = "dagger.dns.namespace"
+
+	AttrDepth        = "dagger.git.depth"
+	AttrSparsePaths  = "dagger.git.sparsepaths"
+	AttrFilter       = "dagger.git.filter"
+	AttrNoSubmodules = "dagger.git.nosubmodules"
+	AttrNoCheckout   = "dagger.git.nocheckout"
+	AttrTags         = "dagger.git.tags"
+)
 
 type GitIdentifier struct {
 	bkgit.GitIdentifier
 
 	Namespace string
+
+	// Depth is the number of commits of history to fetch, or 0 for all of it.
+	Depth int
+	// SparsePaths restricts the checkout to these paths, if set.
+	SparsePaths []string
+	// Filter is a partial clone filter spec, such as "blob:none".
+	Filter string
+	// NoSubmodules skips checking out submodules.
+	NoSubmodules bool
+	// NoCheckout only keeps the .git directory, without a working tree.
+	NoCheckout bool
+	// Tags fetches all tags along with the ref.
+	Tags bool
 }
@@ -0,0 +1,152 @@
+package gitdns
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	fscopy "github.com/tonistiigi/fsutil/copy"
+)
+
+// The functions below operate on a local repository, such as one synced from
+// the client, rather than a remote. They never fetch anything, and never write
+// to the repository itself, which may be mounted read-only.
+
+// ResolveCommit returns the SHA of the commit that rev points to in the
+// repository at gitDir.
+func ResolveCommit(ctx context.Context, gitDir string, rev string) (string, error) {
+	if strings.HasPrefix(rev, "-") {
+		return "", errors.Errorf("invalid ref %q", rev)
+	}
+	git, cleanup, err := newGitCLI(gitDir, "", "", "", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	buf, err := git.run(ctx, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", errors.Errorf("unknown ref %q", rev)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Checkout writes the tree of commit in the repository at gitDir to dest,
+// limited to sparsePaths if set. If keepGitDir is set, the repository is copied
+// to dest/.git too, with HEAD detached at the commit.
+func Checkout(ctx context.Context, gitDir string, commit string, dest string, keepGitDir bool, sparsePaths []string) error {
+	if !keepGitDir {
+		// check out with a throwaway index, so gitDir is left untouched
+		tmpDir, err := os.MkdirTemp("", "git-index")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		git, cleanup, err := newGitCLI(gitDir, dest, "", "", nil, nil)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		git.indexFile = filepath.Join(tmpDir, "index")
+
+		args := []string{"checkout", commit, "--"}
+		if len(sparsePaths) > 0 {
+			args = append(args, sparsePaths...)
+		} else {
+			args = append(args, ".")
+		}
+		if _, err := git.run(ctx, args...); err != nil {
+			return errors.Wrapf(err, "failed to checkout %s", commit)
+		}
+		return nil
+	}
+
+	destGitDir := filepath.Join(dest, ".git")
+	if err := fscopy.Copy(ctx, gitDir, "/", dest, ".git", func(ci *fscopy.CopyInfo) {
+		ci.CopyDirContents = true
+	}); err != nil {
+		return errors.Wrap(err, "failed to copy git directory")
+	}
+	git, cleanup, err := newGitCLI(destGitDir, dest, "", "", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// drop the client's index, which refers to its own working tree
+	if err := os.RemoveAll(filepath.Join(destGitDir, "index")); err != nil {
+		return err
+	}
+	if len(sparsePaths) > 0 {
+		if err := setSparseCheckout(ctx, git, destGitDir, sparsePaths); err != nil {
+			return err
+		}
+	}
+	if _, err := git.run(ctx, "checkout", "-f", "--detach", commit); err != nil {
+		return errors.Wrapf(err, "failed to checkout %s", commit)
+	}
+	return nil
+}
+
+// ListTags returns the names of the tags in the repository at gitDir, without the
+// refs/tags/ prefix. If patterns are given, only tags matching any of them are
+// returned, like git ls-remote.
+func ListTags(ctx context.Context, gitDir string, patterns []string) ([]string, error) {
+	git, cleanup, err := newGitCLI("", "", "", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	args := append([]string{"ls-remote", "--tags", "--refs", gitDir}, patterns...)
+	buf, err := git.run(ctx, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tags")
+	}
+	tags := []string{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		tags = append(tags, strings.TrimPrefix(fields[1], "refs/tags/"))
+	}
+	return tags, scanner.Err()
+}
+
+// Status returns the paths in workTree that differ from the index or HEAD of
+// the repository at gitDir, including untracked files, like git status.
+func Status(ctx context.Context, gitDir string, workTree string) ([]string, error) {
+	git, cleanup, err := newGitCLI(gitDir, workTree, "", "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// the working tree was synced, so every file will look modified to a
+	// stat-based check; without optional locks git compares contents instead
+	// of trying to refresh the index
+	buf, err := git.run(ctx, "--no-optional-locks", "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get status")
+	}
+	paths := []string{}
+	entries := strings.Split(strings.TrimSuffix(buf.String(), "\x00"), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		if entry[0] == 'R' || entry[0] == 'C' {
+			// renames and copies are followed by their source path
+			i++
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,149 @@
+package gitdns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moby/buildkit/executor/oci"
+	"github.com/moby/buildkit/util/gitutil"
+	"github.com/pkg/errors"
+
+	"github.com/dagger/dagger/network"
+)
+
+// CommitWorkTree records the contents of workTree as a new commit on top of
+// parent in the repository at gitDir, and points HEAD at it. Unlike the
+// functions in local.go, the repository must be writable.
+//
+// The author is also used as the committer, and both are dated at date.
+func CommitWorkTree(ctx context.Context, gitDir string, workTree string, parent string, message string, authorName string, authorEmail string, date time.Time) (string, error) {
+	// stage with a throwaway index, so the result only depends on workTree
+	tmpDir, err := os.MkdirTemp("", "git-index")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	git, cleanup, err := newGitCLI(gitDir, workTree, "", "", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	git.indexFile = filepath.Join(tmpDir, "index")
+
+	// everything in the directory is committed, even if a .gitignore says
+	// otherwise
+	if _, err := git.run(ctx, "add", "--all", "--force", "."); err != nil {
+		return "", errors.Wrap(err, "failed to add changes")
+	}
+	buf, err := git.run(ctx, "write-tree")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to write tree")
+	}
+	tree := strings.TrimSpace(buf.String())
+
+	rawDate := strconv.FormatInt(date.Unix(), 10) + date.Format(" -0700")
+	git.env = []string{"GIT_AUTHOR_DATE=" + rawDate, "GIT_COMMITTER_DATE=" + rawDate}
+
+	buf, err = git.run(ctx,
+		"-c", "user.name="+authorName,
+		"-c", "user.email="+authorEmail,
+		"commit-tree", tree, "-p", parent, "-m", message)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to commit")
+	}
+	commit := strings.TrimSpace(buf.String())
+
+	if _, err := git.run(ctx, "update-ref", "--no-deref", "HEAD", commit); err != nil {
+		return "", errors.Wrap(err, "failed to update HEAD")
+	}
+	return commit, nil
+}
+
+// PushOpts configures authentication and networking for Push, mirroring the
+// options of a Git source.
+type PushOpts struct {
+	// AuthToken is used as the password for HTTP basic auth.
+	AuthToken string
+	// AuthHeader is sent as the Authorization header as-is.
+	AuthHeader string
+	// SSHAuthSock is the path of an SSH agent socket.
+	SSHAuthSock string
+	// KnownHosts is the content of an SSH known_hosts file.
+	KnownHosts string
+	// Namespace is the client namespace to resolve service hostnames in.
+	Namespace string
+	// DNS is the engine's DNS config.
+	DNS *oci.DNSConfig
+}
+
+// Push pushes commit from the repository at gitDir to remoteRef on the
+// remote at url. If remoteRef isn't a full ref, it's taken as a branch name.
+func Push(ctx context.Context, gitDir string, url string, commit string, remoteRef string, force bool, opts PushOpts) error {
+	remote, err := gitutil.ParseURL(url)
+	if errors.Is(err, gitutil.ErrUnknownProtocol) {
+		url = "https://" + url
+		remote, err = gitutil.ParseURL(url)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "invalid remote %q", url)
+	}
+	url = remote.Remote
+
+	var auth []string
+	switch {
+	case opts.AuthHeader != "":
+		auth = authArgs(url, []byte(opts.AuthHeader), false, false)
+	case opts.AuthToken != "":
+		auth = authArgs(url, []byte(opts.AuthToken), true, remote.Host == "bitbucket.org")
+	}
+
+	var knownHosts string
+	if opts.KnownHosts != "" {
+		f, err := os.CreateTemp("", "")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(f.Name())
+		if _, err := f.WriteString(opts.KnownHosts); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		knownHosts = f.Name()
+	}
+
+	var dns *oci.DNSConfig
+	if opts.DNS != nil {
+		conf := *opts.DNS
+		if opts.Namespace != "" {
+			conf.SearchDomains = append([]string{network.SessionDomain(opts.Namespace)}, conf.SearchDomains...)
+		}
+		dns = &conf
+	}
+
+	git, cleanup, err := newGitCLI(gitDir, "", opts.SSHAuthSock, knownHosts, auth, dns)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if !strings.HasPrefix(remoteRef, "refs/") {
+		remoteRef = "refs/heads/" + remoteRef
+	}
+	args := []string{"push"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, url, commit+":"+remoteRef)
+	if _, err := git.run(ctx, args...); err != nil {
+		return errors.Wrapf(err, "failed to push to %s", remoteRef)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package gitdns
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
@@ -36,6 +37,11 @@ import (
 )
 
 var validHex = regexp.MustCompile(`^[a-f0-9]{40}$`)
+
+// remoteTagsPrefix is where the shared repo keeps the remote's tags, when
+// they're requested.
+const remoteTagsPrefix = "refs/remote-tags/"
+
 var defaultBranch = regexp.MustCompile(`refs/heads/(\S+)`)
 
 type Opt struct {
@@ -78,8 +84,30 @@ func (gs *gitSource) Identifier(scheme, ref string, attrs map[string]string, pla
 		GitIdentifier: *(srcid.(*srcgit.GitIdentifier)),
 	}
 
-	if v, ok := attrs[AttrDNSNamespace]; ok {
-		id.Namespace = v
+	id.Depth = 1
+	for k, v := range attrs {
+		switch k {
+		case AttrDNSNamespace:
+			id.Namespace = v
+		case AttrDepth:
+			depth, err := strconv.Atoi(v)
+			if err != nil || depth < 0 {
+				return nil, errors.Errorf("invalid git depth %q", v)
+			}
+			id.Depth = depth
+		case AttrSparsePaths:
+			if err := json.Unmarshal([]byte(v), &id.SparsePaths); err != nil {
+				return nil, errors.Wrap(err, "invalid git sparse paths")
+			}
+		case AttrFilter:
+			id.Filter = v
+		case AttrNoSubmodules:
+			id.NoSubmodules = v == "true"
+		case AttrNoCheckout:
+			id.NoCheckout = v == "true"
+		case AttrTags:
+			id.Tags = v == "true"
+		}
 	}
 
 	return id, nil
@@ -202,6 +230,26 @@ func (gs *gitSourceHandler) shaToCacheKey(sha string) string {
 	if gs.src.Subdir != "" {
 		key += ":" + gs.src.Subdir
 	}
+	// only include non-default options, so existing keys are unaffected
+	if gs.src.Depth != 1 {
+		key += ";depth=" + strconv.Itoa(gs.src.Depth)
+	}
+	if gs.src.Filter != "" {
+		key += ";filter=" + gs.src.Filter
+	}
+	if len(gs.src.SparsePaths) > 0 {
+		dt, _ := json.Marshal(gs.src.SparsePaths)
+		key += ";sparse=" + string(dt)
+	}
+	if gs.src.NoSubmodules {
+		key += ";nosubmodules"
+	}
+	if gs.src.NoCheckout {
+		key += ";nocheckout"
+	}
+	if gs.src.Tags {
+		key += ";tags"
+	}
 	return key
 }
 
@@ -256,29 +304,34 @@ func (gs *gitSourceHandler) getAuthToken(ctx context.Context, g session.Group) e
 				return err
 			}
 
-			// Handle token authentication
-			if s.token {
-				if s.credHelper {
-					// For Bitbucket Cloud Git operations, use credential helper
-					gs.auth = []string{
-						"-c", fmt.Sprintf("credential.helper=!f() { echo \"username=x-token-auth\"; echo \"password=%s\"; }; f", string(dt)),
-						"-c", "credential.https://bitbucket.org.username=x-token-auth",
-						"-c", "credential.useHttpPath=true",
-					}
-					break
-				}
-				// Encode token as basic auth header
-				dt = []byte("basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("x-access-token:%s", dt))))
-			}
-
-			// Set auth header (used for both auth headers and encoded tokens)
-			gs.auth = []string{"-c", "http." + tokenScope(gs.src.Remote) + ".extraheader=Authorization: " + string(dt)}
+			gs.auth = authArgs(gs.src.Remote, dt, s.token, s.credHelper)
 			break
 		}
 		return nil
 	})
 }
 
+// authArgs returns the flags for git to authenticate with the remote, given a
+// token or a raw Authorization header.
+func authArgs(remote string, dt []byte, token bool, credHelper bool) []string {
+	// Handle token authentication
+	if token {
+		if credHelper {
+			// For Bitbucket Cloud Git operations, use credential helper
+			return []string{
+				"-c", fmt.Sprintf("credential.helper=!f() { echo \"username=x-token-auth\"; echo \"password=%s\"; }; f", string(dt)),
+				"-c", "credential.https://bitbucket.org.username=x-token-auth",
+				"-c", "credential.useHttpPath=true",
+			}
+		}
+		// Encode token as basic auth header
+		dt = []byte("basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("x-access-token:%s", dt))))
+	}
+
+	// Set auth header (used for both auth headers and encoded tokens)
+	return []string{"-c", "http." + tokenScope(remote) + ".extraheader=Authorization: " + string(dt)}
+}
+
 func (gs *gitSourceHandler) mountSSHAuthSock(ctx context.Context, sshID string, g session.Group) (string, func() error, error) {
 	var caller session.Caller
 	err := gs.sm.Any(ctx, g, func(ctx context.Context, _ string, c session.Caller) error {
@@ -523,7 +576,7 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 	}
 
 	doFetch := true
-	if isCommitSHA(ref) {
+	if isCommitSHA(ref) && !gs.src.Tags {
 		// skip fetch if commit already exists
 		if _, err := git.run(ctx, "cat-file", "-e", ref+"^{commit}"); err == nil {
 			doFetch = false
@@ -534,16 +587,36 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		// make sure no old lock files have leaked
 		os.RemoveAll(filepath.Join(gitDir, "shallow.lock"))
 
+		_, shallowErr := os.Lstat(filepath.Join(gitDir, "shallow"))
+		isShallow := shallowErr == nil
+
 		args := []string{"fetch"}
 		if !isCommitSHA(ref) { // TODO: find a branch from ls-remote?
-			args = append(args, "--depth=1", "--no-tags")
+			if gs.src.Depth > 0 {
+				args = append(args, "--depth="+strconv.Itoa(gs.src.Depth))
+			} else if isShallow {
+				args = append(args, "--unshallow")
+			}
+			args = append(args, "--no-tags")
 		} else {
 			args = append(args, "--tags")
-			if _, err := os.Lstat(filepath.Join(gitDir, "shallow")); err == nil {
+			if isShallow {
 				args = append(args, "--unshallow")
 			}
 		}
+		fetchGit := git
+		if gs.src.Filter != "" {
+			// the shared repo is only a promisor for this fetch, so that its
+			// config stays the same for every checkout
+			fetchGit = git.withConfig("remote.origin.promisor=true")
+			args = append(args, "--filter="+gs.src.Filter)
+		}
 		args = append(args, "origin")
+		if gs.src.Tags {
+			// named refs are stored as tags below, so keep the remote's actual
+			// tags apart for checkouts that want them
+			args = append(args, "+refs/tags/*:"+remoteTagsPrefix+"*")
+		}
 		if isCommitSHA(ref) {
 			args = append(args, ref)
 		} else {
@@ -552,7 +625,7 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 			// TODO: is there a better way to do this?
 			args = append(args, "--force", ref+":tags/"+ref)
 		}
-		if _, err := git.run(ctx, args...); err != nil {
+		if _, err := fetchGit.run(ctx, args...); err != nil {
 			return nil, errors.Wrapf(err, "failed to fetch remote %s", urlutil.RedactCredentials(gs.src.Remote))
 		}
 		_, err = git.run(ctx, "reflog", "expire", "--all", "--expire=now")
@@ -629,11 +702,40 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		default:
 			pullref += ":" + pullref
 		}
-		_, err = checkoutGit.run(ctx, "fetch", "-u", "--depth=1", "origin", pullref)
+		fetchArgs := []string{"fetch", "-u"}
+		if gs.src.Depth > 0 {
+			fetchArgs = append(fetchArgs, "--depth="+strconv.Itoa(gs.src.Depth))
+		}
+		if gs.src.Filter != "" {
+			if err := setPromisor(ctx, checkoutGit); err != nil {
+				return nil, err
+			}
+			// the shared repo must serve filtered and lazy fetches to the
+			// checkout; -c flags aren't passed on to a local upload-pack, so
+			// they go in the upload-pack command instead
+			checkoutGit = checkoutGit.withConfig("remote.origin.uploadpack=" + filterUploadPack)
+			fetchArgs = append(fetchArgs, "--filter="+gs.src.Filter)
+		}
+		if gs.src.Tags {
+			// only the remote's actual tags, not named refs stored as tags
+			fetchArgs = append(fetchArgs, "--no-tags", "origin", pullref, "+"+remoteTagsPrefix+"*:refs/tags/*")
+		} else {
+			fetchArgs = append(fetchArgs, "origin", pullref)
+		}
+		_, err = checkoutGit.run(ctx, fetchArgs...)
 		if err != nil {
 			return nil, err
 		}
-		_, err = checkoutGit.run(ctx, "checkout", "FETCH_HEAD")
+		if len(gs.src.SparsePaths) > 0 {
+			if err := setSparseCheckout(ctx, checkoutGit, checkoutDirGit, gs.src.SparsePaths); err != nil {
+				return nil, err
+			}
+		}
+		if gs.src.NoCheckout {
+			_, err = checkoutGit.run(ctx, "update-ref", "--no-deref", "HEAD", "FETCH_HEAD^{commit}")
+		} else {
+			_, err = checkoutGit.run(ctx, "checkout", "FETCH_HEAD")
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to checkout remote %s", urlutil.RedactCredentials(gs.src.Remote))
 		}
@@ -657,7 +759,13 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 				return nil, errors.Wrapf(err, "failed to create temporary checkout dir")
 			}
 		}
-		_, err = git.withinDir(gitDir, cd).run(ctx, "checkout", ref, "--", ".")
+		checkoutArgs := []string{"checkout", ref, "--"}
+		if len(gs.src.SparsePaths) > 0 {
+			checkoutArgs = append(checkoutArgs, gs.src.SparsePaths...)
+		} else {
+			checkoutArgs = append(checkoutArgs, ".")
+		}
+		_, err = git.withinDir(gitDir, cd).run(ctx, checkoutArgs...)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to checkout remote %s", urlutil.RedactCredentials(gs.src.Remote))
 		}
@@ -690,9 +798,16 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		}
 	}
 
-	_, err = git.withinDir(gitDir, checkoutDir).run(ctx, "submodule", "update", "--init", "--recursive", "--depth=1")
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to update submodules for %s", urlutil.RedactCredentials(gs.src.Remote))
+	if !gs.src.NoSubmodules && !gs.src.NoCheckout {
+		submoduleArgs := []string{"submodule", "update", "--init", "--recursive", "--depth=1"}
+		if len(gs.src.SparsePaths) > 0 {
+			submoduleArgs = append(submoduleArgs, "--")
+			submoduleArgs = append(submoduleArgs, gs.src.SparsePaths...)
+		}
+		_, err = git.withinDir(gitDir, checkoutDir).run(ctx, submoduleArgs...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to update submodules for %s", urlutil.RedactCredentials(gs.src.Remote))
+		}
 	}
 
 	if idmap := mount.IdentityMapping(); idmap != nil {
@@ -727,6 +842,42 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 	return snap, nil
 }
 
+// filterUploadPack is the upload-pack command run on the shared repo for
+// filtered fetches into a checkout.
+const filterUploadPack = "git -c uploadpack.allowFilter=true -c uploadpack.allowAnySHA1InWant=true upload-pack"
+
+// setPromisor configures the origin remote of a repo as a partial clone
+// promisor, which git requires before fetching with a filter. Objects left out
+// by the filter are then fetched lazily from origin when needed.
+func setPromisor(ctx context.Context, git *gitCLI) error {
+	for _, kv := range [][2]string{
+		{"core.repositoryformatversion", "1"},
+		{"extensions.partialClone", "origin"},
+		{"remote.origin.promisor", "true"},
+	} {
+		if _, err := git.run(ctx, "config", kv[0], kv[1]); err != nil {
+			return errors.Wrapf(err, "failed to set %s", kv[0])
+		}
+	}
+	return nil
+}
+
+// setSparseCheckout restricts the checkout of a repo to the given paths,
+// relative to its root.
+func setSparseCheckout(ctx context.Context, git *gitCLI, gitDir string, paths []string) error {
+	if _, err := git.run(ctx, "config", "core.sparseCheckout", "true"); err != nil {
+		return errors.Wrap(err, "failed to enable sparse checkout")
+	}
+	var patterns strings.Builder
+	for _, p := range paths {
+		patterns.WriteString(path.Join("/", p) + "\n")
+	}
+	if err := os.MkdirAll(filepath.Join(gitDir, "info"), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(gitDir, "info", "sparse-checkout"), []byte(patterns.String()), 0o644)
+}
+
 func isCommitSHA(str string) bool {
 	return validHex.MatchString(str)
 }
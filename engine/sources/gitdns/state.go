@@ -1,7 +1,9 @@
 package gitdns
 
 import (
+	"encoding/json"
 	"path"
+	"strconv"
 
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/solver/pb"
@@ -41,10 +43,13 @@ func Git(url, ref string, namespace string, opts ...llb.GitOption) llb.State {
 		AuthHeaderSecret: "GIT_AUTH_HEADER",
 		AuthTokenSecret:  "GIT_AUTH_TOKEN",
 	}
+	attrs := map[string]string{}
 	for _, o := range opts {
 		o.SetGitOption(gi)
+		if o, ok := o.(gitAttrOption); ok {
+			o.setAttrs(attrs)
+		}
 	}
-	attrs := map[string]string{}
 	if gi.KeepGitDir {
 		attrs[pb.AttrKeepGitDir] = "true"
 	}
@@ -80,3 +85,50 @@ func Git(url, ref string, namespace string, opts ...llb.GitOption) llb.State {
 	source := llb.NewSource("git://"+id, attrs, gi.Constraints)
 	return llb.NewState(source.Output())
 }
+
+// gitAttrOption is an llb.GitOption for an attribute that llb.GitInfo has no
+// room for. It's only understood by Git.
+type gitAttrOption struct {
+	key   string
+	value string
+}
+
+func (gitAttrOption) SetGitOption(*llb.GitInfo) {}
+
+func (o gitAttrOption) setAttrs(attrs map[string]string) {
+	attrs[o.key] = o.value
+}
+
+// Depth sets the number of commits of history to fetch, or 0 to fetch all of
+// it. The default is 1.
+func Depth(depth int) llb.GitOption {
+	return gitAttrOption{key: AttrDepth, value: strconv.Itoa(depth)}
+}
+
+// SparsePaths restricts the checkout to the given paths.
+func SparsePaths(paths []string) llb.GitOption {
+	dt, _ := json.Marshal(paths) // empty on error
+	return gitAttrOption{key: AttrSparsePaths, value: string(dt)}
+}
+
+// Filter sets a partial clone filter spec, such as "blob:none", so that only
+// the objects needed for the checkout are fetched.
+func Filter(filter string) llb.GitOption {
+	return gitAttrOption{key: AttrFilter, value: filter}
+}
+
+// NoSubmodules skips checking out submodules.
+func NoSubmodules() llb.GitOption {
+	return gitAttrOption{key: AttrNoSubmodules, value: "true"}
+}
+
+// NoCheckout only keeps the .git directory, leaving out the working tree and
+// submodules. It requires llb.KeepGitDir.
+func NoCheckout() llb.GitOption {
+	return gitAttrOption{key: AttrNoCheckout, value: "true"}
+}
+
+// Tags fetches all tags along with the ref, e.g. for git describe.
+func Tags() llb.GitOption {
+	return gitAttrOption{key: AttrTags, value: "true"}
+}
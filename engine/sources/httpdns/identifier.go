@@ -4,10 +4,16 @@ import (
 	bkhttp "github.com/moby/buildkit/source/http"
 )
 
-const AttrDNSNamespace = "dagger.dns.namespace"
+const (
+	AttrDNSNamespace         = "dagger.dns.namespace"
+	AttrHTTPHeaderPrefix     = "dagger.http.header."
+	AttrHTTPAuthHeaderSecret = "dagger.http.authheadersecret"
+)
 
 type HTTPIdentifier struct {
 	bkhttp.HTTPIdentifier
 
-	Namespace string
+	Namespace        string
+	Headers          map[string]string
+	AuthHeaderSecret string
 }
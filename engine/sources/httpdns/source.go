@@ -19,6 +19,7 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/pb"
@@ -76,8 +77,18 @@ func (hs *httpSource) Identifier(scheme, ref string, attrs map[string]string, pl
 		HTTPIdentifier: *(srcid.(*srchttp.HTTPIdentifier)),
 	}
 
-	if v, ok := attrs[AttrDNSNamespace]; ok {
-		id.Namespace = v
+	for k, v := range attrs {
+		switch {
+		case k == AttrDNSNamespace:
+			id.Namespace = v
+		case k == AttrHTTPAuthHeaderSecret:
+			id.AuthHeaderSecret = v
+		case strings.HasPrefix(k, AttrHTTPHeaderPrefix):
+			if id.Headers == nil {
+				id.Headers = map[string]string{}
+			}
+			id.Headers[strings.TrimPrefix(k, AttrHTTPHeaderPrefix)] = v
+		}
 	}
 
 	return id, nil
@@ -98,10 +109,11 @@ func (hs *httpSource) Resolve(ctx context.Context, id source.Identifier, sm *ses
 
 type httpSourceHandler struct {
 	*httpSource
-	src      HTTPIdentifier
-	refID    string
-	cacheKey digest.Digest
-	sm       *session.Manager
+	src        HTTPIdentifier
+	refID      string
+	cacheKey   digest.Digest
+	sm         *session.Manager
+	authHeader string
 }
 
 func (hs *httpSourceHandler) client(g session.Group) *http.Client {
@@ -116,6 +128,39 @@ func (hs *httpSourceHandler) client(g session.Group) *http.Client {
 	return &http.Client{Transport: newTransport(hs.transport, hs.sm, g, &dns)}
 }
 
+// newRequest returns a GET request for the source URL with any configured
+// headers set.
+func (hs *httpSourceHandler) newRequest(ctx context.Context, g session.Group) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", hs.src.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range hs.src.Headers {
+		req.Header.Set(k, v)
+	}
+	if hs.src.AuthHeaderSecret != "" {
+		if err := hs.getAuthHeader(ctx, g); err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", hs.authHeader)
+	}
+	return req, nil
+}
+
+func (hs *httpSourceHandler) getAuthHeader(ctx context.Context, g session.Group) error {
+	if hs.authHeader != "" {
+		return nil
+	}
+	return hs.sm.Any(ctx, g, func(ctx context.Context, _ string, caller session.Caller) error {
+		dt, err := secrets.GetSecret(ctx, caller, hs.src.AuthHeaderSecret)
+		if err != nil {
+			return errors.Wrap(err, "failed to get auth header secret")
+		}
+		hs.authHeader = string(dt)
+		return nil
+	})
+}
+
 // urlHash is internal hash the etag is stored by that doesn't leak outside
 // this package.
 func (hs *httpSourceHandler) urlHash() (digest.Digest, error) {
@@ -171,11 +216,10 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 		return "", "", nil, false, errors.Wrapf(err, "failed to search metadata for %s", uh)
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, g)
 	if err != nil {
 		return "", "", nil, false, err
 	}
-	req = req.WithContext(ctx)
 	m := map[string]cacheRefMetadata{}
 
 	// If we request a single ETag in 'If-None-Match', some servers omit the
@@ -422,11 +466,10 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 		}
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, g)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
 
 	client := hs.client(g)
 
@@ -437,6 +480,9 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 	defer func() {
 		_ = resp.Body.Close()
 	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.Errorf("invalid response status %d", resp.StatusCode)
+	}
 
 	ref, dgst, err := hs.save(ctx, resp, g)
 	if err != nil {
@@ -444,6 +490,9 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 	}
 	if dgst != hs.cacheKey {
 		ref.Release(context.TODO())
+		if hs.src.Checksum != "" {
+			return nil, errors.Errorf("checksum mismatch: expected %s, got %s", hs.src.Checksum, dgst)
+		}
 		return nil, errors.Errorf("digest mismatch %s: %s", dgst, hs.cacheKey)
 	}
 
@@ -11,10 +11,13 @@ import (
 // set additional attributes.
 func HTTP(url string, namespace string, opts ...llb.HTTPOption) llb.State {
 	hi := &llb.HTTPInfo{}
+	attrs := map[string]string{}
 	for _, o := range opts {
 		o.SetHTTPOption(hi)
+		if o, ok := o.(daggerHTTPOption); ok {
+			o.setAttrs(attrs)
+		}
 	}
-	attrs := map[string]string{}
 	if hi.Checksum != "" {
 		attrs[pb.AttrHTTPChecksum] = hi.Checksum.String()
 	}
@@ -36,3 +39,32 @@ func HTTP(url string, namespace string, opts ...llb.HTTPOption) llb.State {
 	source := llb.NewSource(url, attrs, hi.Constraints)
 	return llb.NewState(source.Output())
 }
+
+// daggerHTTPOption is an llb.HTTPOption for an attribute that llb.HTTPInfo
+// has no room for. It's only understood by HTTP.
+type daggerHTTPOption interface {
+	llb.HTTPOption
+	setAttrs(attrs map[string]string)
+}
+
+type httpAttrOption struct {
+	key   string
+	value string
+}
+
+func (httpAttrOption) SetHTTPOption(*llb.HTTPInfo) {}
+
+func (o httpAttrOption) setAttrs(attrs map[string]string) {
+	attrs[o.key] = o.value
+}
+
+// Header sets an additional header to send with the request.
+func Header(name, value string) llb.HTTPOption {
+	return httpAttrOption{key: AttrHTTPHeaderPrefix + name, value: value}
+}
+
+// AuthHeaderSecret sets the name of the secret containing the value of the
+// Authorization header to send with the request.
+func AuthHeaderSecret(name string) llb.HTTPOption {
+	return httpAttrOption{key: AttrHTTPAuthHeaderSecret, value: name}
+}
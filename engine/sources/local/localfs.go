@@ -26,6 +26,7 @@ import (
 
 	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/engine/contenthash"
+	"github.com/dagger/dagger/engine/ignore"
 )
 
 const (
@@ -61,6 +62,12 @@ type localFS struct {
 	filterFS fsutil.FS
 	includes []string // the include patterns we're using for this sync
 	excludes []string // the exclude patterns we're using for this sync
+
+	// ignoring is set when the client is honoring ignore files, in which case filterFS also skips
+	// paths ignored by the ignore files currently in our cache filesystem
+	ignoring    bool
+	gitignore   bool
+	ignoreFiles []string
 }
 
 func newLocalFS(sharedState *localFSSharedState, subdir string, includes, excludes []string) (*localFS, error) {
@@ -85,6 +92,30 @@ func newLocalFS(sharedState *localFSSharedState, subdir string, includes, exclud
 	}, nil
 }
 
+// withIgnoreFiles returns a copy of the local fs that skips paths ignored by the given ignore files.
+//
+// The ignore files in our cache filesystem may be stale until the sync is done, so this is only
+// used to avoid needlessly deleting paths that were synced by others; the paths actually included
+// in the synced ref are the ones the client reports.
+func (local *localFS) withIgnoreFiles(gitignore bool, ignoreFiles []string) (*localFS, error) {
+	matcher := ignore.NewMatcher(filepath.Join(local.rootPath, local.subdir), gitignore, ignoreFiles)
+	if !matcher.Enabled() {
+		return local, nil
+	}
+	filterFS, err := fsutil.NewFilterFS(local.filterFS, &fsutil.FilterOpt{
+		Map: matcher.MapFunc(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ignore filter fs: %w", err)
+	}
+	cp := *local
+	cp.filterFS = filterFS
+	cp.ignoring = true
+	cp.gitignore = gitignore
+	cp.ignoreFiles = ignoreFiles
+	return &cp, nil
+}
+
 // Sync the given remote fs into the local fs, returning an immutable cache ref containing the files+dirs
 // as they appear in the client at the synced in path.
 //
@@ -165,9 +196,23 @@ func (local *localFS) Sync( //nolint:gocyclo
 	var hardlinks []*hardlinkChange
 	var hardlinkMu sync.Mutex
 
+	// When honoring ignore files, our cache filesystem may contain paths that this client ignores, so
+	// we keep track of every path the client reported in order to prune the rest from the copy.
+	synced := map[string]struct{}{}
+	var syncedMu sync.Mutex
+	markSynced := func(path string) {
+		if !local.ignoring {
+			return
+		}
+		syncedMu.Lock()
+		synced[filepath.Clean("/"+path)] = struct{}{}
+		syncedMu.Unlock()
+	}
+
 	doubleWalkDiff(egCtx, eg, local, remote, func(kind ChangeKind, path string, lowerStat, upperStat *types.Stat) error {
 		switch kind {
 		case ChangeKindAdd, ChangeKindModify:
+			markSynced(path)
 			switch {
 			case upperStat.IsDir():
 				appliedChange, err := local.Mkdir(egCtx, kind, path, upperStat)
@@ -251,6 +296,7 @@ func (local *localFS) Sync( //nolint:gocyclo
 			return nil
 
 		case ChangeKindNone:
+			markSynced(path)
 			appliedChange, err := local.GetPreviousChange(egCtx, path, lowerStat)
 			if err != nil {
 				return err
@@ -356,6 +402,12 @@ func (local *localFS) Sync( //nolint:gocyclo
 		return nil, fmt.Errorf("failed to copy %q: %w", local.subdir, err)
 	}
 
+	if local.ignoring {
+		if err := pruneUnsynced(copyRefMntPath, synced); err != nil {
+			return nil, fmt.Errorf("failed to prune ignored paths: %w", err)
+		}
+	}
+
 	if err := copyRefMnter.Unmount(); err != nil {
 		copyRefMnter = nil
 		return nil, fmt.Errorf("failed to unmount: %w", err)
@@ -400,7 +452,11 @@ func (local *localFS) Sync( //nolint:gocyclo
 	if err := (contenthash.CacheRefMetadata{RefMetadata: finalRef}).SetContentHashKey(dgst); err != nil {
 		return nil, fmt.Errorf("failed to set content hash key: %w", err)
 	}
-	if err := finalRef.SetDescription(fmt.Sprintf("local dir %s (include: %v) (exclude %v)", local.subdir, local.includes, local.excludes)); err != nil {
+	desc := fmt.Sprintf("local dir %s (include: %v) (exclude %v)", local.subdir, local.includes, local.excludes)
+	if local.ignoring {
+		desc += fmt.Sprintf(" (gitignore: %t) (ignore files: %v)", local.gitignore, local.ignoreFiles)
+	}
+	if err := finalRef.SetDescription(desc); err != nil {
 		return nil, fmt.Errorf("failed to set description: %w", err)
 	}
 
@@ -418,6 +474,33 @@ func (local *localFS) Sync( //nolint:gocyclo
 	return finalRef, nil
 }
 
+// pruneUnsynced removes everything under root that isn't in the synced set of paths, which are
+// absolute paths relative to root.
+func pruneUnsynced(root string, synced map[string]struct{}) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if _, ok := synced[filepath.Join("/", rel)]; ok {
+			return nil
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
 // the full absolute path on the local filesystem
 func (local *localFS) toFullPath(path string) string {
 	return filepath.Join(local.rootPath, local.subdir, path)
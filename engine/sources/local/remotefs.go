@@ -22,6 +22,10 @@ type remoteFS struct {
 	includes   []string
 	excludes   []string
 
+	// ignore files the client should honor when walking
+	gitignore   bool
+	ignoreFiles []string
+
 	startOnce   sync.Once
 	client      filesync.FileSync_DiffCopyClient
 	filesMu     sync.RWMutex
@@ -64,6 +68,8 @@ func (fs *remoteFS) Walk(ctx context.Context, path string, walkFn fs.WalkDirFunc
 		Path:            fs.clientPath,
 		IncludePatterns: fs.includes,
 		ExcludePatterns: fs.excludes,
+		Gitignore:       fs.gitignore,
+		IgnoreFiles:     fs.ignoreFiles,
 	}.AppendToOutgoingContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to create diff copy client: %w", err)
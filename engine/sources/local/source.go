@@ -57,11 +57,21 @@ func (ls *localSource) Schemes() []string {
 	return []string{srctypes.LocalScheme}
 }
 
+// LocalIdentifier extends the upstream identifier with support for ignore
+// files.
+type LocalIdentifier struct {
+	upstreamlocal.LocalIdentifier
+
+	Gitignore   bool
+	IgnoreFiles []string
+}
+
 func (ls *localSource) Identifier(scheme, ref string, attrs map[string]string, platform *pb.Platform) (source.Identifier, error) {
-	id, err := upstreamlocal.NewLocalIdentifier(ref)
+	upstreamID, err := upstreamlocal.NewLocalIdentifier(ref)
 	if err != nil {
 		return nil, err
 	}
+	id := &LocalIdentifier{LocalIdentifier: *upstreamID}
 
 	for k, v := range attrs {
 		switch k {
@@ -91,6 +101,14 @@ func (ls *localSource) Identifier(scheme, ref string, attrs map[string]string, p
 			id.FollowPaths = paths
 		case pb.AttrSharedKeyHint:
 			id.SharedKeyHint = v
+		case AttrGitignore:
+			id.Gitignore = v == "true"
+		case AttrIgnoreFiles:
+			var names []string
+			if err := json.Unmarshal([]byte(v), &names); err != nil {
+				return nil, err
+			}
+			id.IgnoreFiles = names
 		case pb.AttrLocalDiffer:
 			switch v {
 			case pb.AttrLocalDifferMetadata, "":
@@ -105,7 +123,7 @@ func (ls *localSource) Identifier(scheme, ref string, attrs map[string]string, p
 }
 
 func (ls *localSource) Resolve(ctx context.Context, id source.Identifier, sm *session.Manager, _ solver.Vertex) (source.SourceInstance, error) {
-	localIdentifier, ok := id.(*upstreamlocal.LocalIdentifier)
+	localIdentifier, ok := id.(*LocalIdentifier)
 	if !ok {
 		return nil, fmt.Errorf("invalid local identifier %v", id)
 	}
@@ -118,7 +136,7 @@ func (ls *localSource) Resolve(ctx context.Context, id source.Identifier, sm *se
 }
 
 type localSourceHandler struct {
-	src upstreamlocal.LocalIdentifier
+	src LocalIdentifier
 	sm  *session.Manager
 	*localSource
 }
@@ -138,7 +156,16 @@ func (ls *localSourceHandler) CacheKey(ctx context.Context, g session.Group, ind
 		IncludePatterns []string
 		ExcludePatterns []string
 		FollowPaths     []string
-	}{SessionID: sessionID, IncludePatterns: ls.src.IncludePatterns, ExcludePatterns: ls.src.ExcludePatterns, FollowPaths: ls.src.FollowPaths})
+		Gitignore       bool     `json:",omitempty"`
+		IgnoreFiles     []string `json:",omitempty"`
+	}{
+		SessionID:       sessionID,
+		IncludePatterns: ls.src.IncludePatterns,
+		ExcludePatterns: ls.src.ExcludePatterns,
+		FollowPaths:     ls.src.FollowPaths,
+		Gitignore:       ls.src.Gitignore,
+		IgnoreFiles:     ls.src.IgnoreFiles,
+	})
 	if err != nil {
 		return "", "", nil, false, err
 	}
@@ -259,10 +286,16 @@ func (ls *localSourceHandler) sync(
 
 	// now sync in the clientPath dir
 	remote := newRemoteFS(caller, drive+clientPath, ls.src.IncludePatterns, ls.src.ExcludePatterns)
+	remote.gitignore = ls.src.Gitignore
+	remote.ignoreFiles = ls.src.IgnoreFiles
 	local, err := newLocalFS(ref.sharedState, clientPath, ls.src.IncludePatterns, ls.src.ExcludePatterns)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create local fs: %w", err)
 	}
+	local, err = local.withIgnoreFiles(ls.src.Gitignore, ls.src.IgnoreFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local fs: %w", err)
+	}
 	return local.Sync(ctx, remote, ls.cm, session, false)
 }
 
@@ -0,0 +1,72 @@
+package local
+
+import (
+	"encoding/json"
+
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/solver/pb"
+)
+
+const (
+	AttrGitignore   = "dagger.local.gitignore"
+	AttrIgnoreFiles = "dagger.local.ignorefiles"
+)
+
+// Local is a helper mimicking the llb.Local function, but with the ability to
+// set additional attributes.
+func Local(name string, opts ...llb.LocalOption) llb.State {
+	li := &llb.LocalInfo{}
+	attrs := map[string]string{}
+	for _, o := range opts {
+		o.SetLocalOption(li)
+		if o, ok := o.(localAttrOption); ok {
+			o.setAttrs(attrs)
+		}
+	}
+	if li.SessionID != "" {
+		attrs[pb.AttrLocalSessionID] = li.SessionID
+	}
+	if li.IncludePatterns != "" {
+		attrs[pb.AttrIncludePatterns] = li.IncludePatterns
+	}
+	if li.FollowPaths != "" {
+		attrs[pb.AttrFollowPaths] = li.FollowPaths
+	}
+	if li.ExcludePatterns != "" {
+		attrs[pb.AttrExcludePatterns] = li.ExcludePatterns
+	}
+	if li.SharedKeyHint != "" {
+		attrs[pb.AttrSharedKeyHint] = li.SharedKeyHint
+	}
+	if li.Differ.Type != "" {
+		attrs[pb.AttrLocalDiffer] = string(li.Differ.Type)
+	}
+
+	source := llb.NewSource("local://"+name, attrs, li.Constraints)
+	return llb.NewState(source.Output())
+}
+
+// localAttrOption is an llb.LocalOption for an attribute that llb.LocalInfo
+// has no room for. It's only understood by Local.
+type localAttrOption struct {
+	key   string
+	value string
+}
+
+func (localAttrOption) SetLocalOption(*llb.LocalInfo) {}
+
+func (o localAttrOption) setAttrs(attrs map[string]string) {
+	attrs[o.key] = o.value
+}
+
+// Gitignore makes the sync honor .gitignore files in the synced directory.
+func Gitignore() llb.LocalOption {
+	return localAttrOption{key: AttrGitignore, value: "true"}
+}
+
+// IgnoreFiles makes the sync honor ignore files with the given names, using
+// .gitignore syntax.
+func IgnoreFiles(names []string) llb.LocalOption {
+	dt, _ := json.Marshal(names) // empty on error
+	return localAttrOption{key: AttrIgnoreFiles, value: string(dt)}
+}
@@ -35,6 +35,27 @@ defmodule Dagger.Function do
     end
   end
 
+  @doc "The policy for caching the results of calls to the function."
+  @spec cache_policy(t()) :: {:ok, Dagger.FunctionCachePolicy.t()} | {:error, term()}
+  def cache_policy(%__MODULE__{} = function) do
+    query_builder =
+      function.query_builder |> QB.select("cachePolicy")
+
+    case Client.execute(function.client, query_builder) do
+      {:ok, enum} -> {:ok, Dagger.FunctionCachePolicy.from_string(enum)}
+      error -> error
+    end
+  end
+
+  @doc "Number of seconds for which the results of calls to the function are cached, if its cache policy is TTL."
+  @spec cache_ttl_seconds(t()) :: {:ok, integer()} | {:error, term()}
+  def cache_ttl_seconds(%__MODULE__{} = function) do
+    query_builder =
+      function.query_builder |> QB.select("cacheTTLSeconds")
+
+    Client.execute(function.client, query_builder)
+  end
+
   @doc "A doc string for the function, if any."
   @spec description(t()) :: {:ok, String.t()} | {:error, term()}
   def description(%__MODULE__{} = function) do
@@ -112,6 +133,23 @@ defmodule Dagger.Function do
     }
   end
 
+  @doc "Returns the function with the given cache policy."
+  @spec with_cache_policy(t(), Dagger.FunctionCachePolicy.t(), [
+          {:time_to_live, String.t() | nil}
+        ]) :: Dagger.Function.t()
+  def with_cache_policy(%__MODULE__{} = function, policy, optional_args \\ []) do
+    query_builder =
+      function.query_builder
+      |> QB.select("withCachePolicy")
+      |> QB.put_arg("policy", policy)
+      |> QB.maybe_put_arg("timeToLive", optional_args[:time_to_live])
+
+    %Dagger.Function{
+      query_builder: query_builder,
+      client: function.client
+    }
+  end
+
   @doc "Returns the function with the given doc string."
   @spec with_description(t(), String.t()) :: Dagger.Function.t()
   def with_description(%__MODULE__{} = function, description) do
@@ -18,12 +18,27 @@ defmodule Dagger.Mod.Function do
       define_type(dag, Dagger.Client.type_def(dag), return)
     )
     |> maybe_with_description(Object.get_function_doc(module, name))
+    |> maybe_with_cache_policy(Keyword.get(fun_def, :cache))
     |> with_args(args, dag)
   end
 
   defp maybe_with_description(function, nil), do: function
   defp maybe_with_description(function, doc), do: Dagger.Function.with_description(function, doc)
 
+  defp maybe_with_cache_policy(function, nil), do: function
+
+  defp maybe_with_cache_policy(function, "never"),
+    do: Dagger.Function.with_cache_policy(function, Dagger.FunctionCachePolicy.never())
+
+  defp maybe_with_cache_policy(function, "session"),
+    do: Dagger.Function.with_cache_policy(function, Dagger.FunctionCachePolicy.per_session())
+
+  defp maybe_with_cache_policy(function, ttl) when is_binary(ttl),
+    do:
+      Dagger.Function.with_cache_policy(function, Dagger.FunctionCachePolicy.ttl(),
+        time_to_live: ttl
+      )
+
   defp with_args(fun, args, dag) do
     args
     |> Enum.reduce(fun, fn {name, arg_def}, fun ->
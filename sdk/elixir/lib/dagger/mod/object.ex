@@ -50,6 +50,18 @@ defmodule Dagger.Mod.Object do
 
   The function also support documentation by using Elixir standard documentation,
   `@doc`.
+
+  ## Function options
+
+  The `defn` accepts options after the return type:
+
+  * `:cache` - how the results of calls to the function are cached: `"never"`,
+    `"session"` for the duration of the session, or a time to live such as
+    `"10m"`. Defaults to the engine's caching.
+
+      defn latest_release() :: String.t(), cache: "10m" do
+        # ...
+      end
   """
 
   @type function_name() :: atom()
@@ -107,7 +119,7 @@ defmodule Dagger.Mod.Object do
     name = opts[:name]
 
     quote do
-      import Dagger.Mod.Object, only: [defn: 2]
+      import Dagger.Mod.Object, only: [defn: 2, defn: 3]
       import Dagger.Global, only: [dag: 0]
 
       Module.register_attribute(__MODULE__, :function, accumulate: true, persist: true)
@@ -133,19 +145,32 @@ defmodule Dagger.Mod.Object do
   @doc """
   Declare a function.
   """
-  defmacro defn(call, do: block) do
+  defmacro defn(call, opts \\ [], do: block) do
     {name, args, return} = extract_call(call)
     has_self? = is_tuple(args)
     arg_defs = compile_args(args)
     return_def = compile_typespec!(return)
+    fun_def = [self: has_self?, args: arg_defs, return: return_def] ++ compile_options(opts)
 
     quote do
-      @function {unquote(name),
-                 [self: unquote(has_self?), args: unquote(arg_defs), return: unquote(return_def)]}
+      @function {unquote(name), unquote(fun_def)}
       unquote(Defn.define(name, args, return, block))
     end
   end
 
+  defp compile_options(opts) do
+    opts
+    |> Keyword.validate!(cache: nil)
+    |> Enum.reject(fn {_, value} -> is_nil(value) end)
+    |> Enum.map(&validate_option!/1)
+  end
+
+  defp validate_option!({:cache, cache} = opt) when is_binary(cache), do: opt
+
+  defp validate_option!({name, value}) do
+    raise ArgumentError, "invalid value for option `#{name}`: #{inspect(value)}"
+  end
+
   defp extract_call({:"::", _, [call_def, return]}) do
     {name, args} = extract_call_def(call_def)
     {name, args, return}
@@ -100,7 +100,8 @@ defmodule Dagger.Mod.ObjectTest do
                  ],
                  return: :string
                ],
-               return_void: [self: false, args: [], return: Dagger.Void]
+               return_void: [self: false, args: [], return: Dagger.Void],
+               cached: [self: false, args: [], return: :string, cache: "10m"]
              ]
     end
 
@@ -178,6 +179,28 @@ defmodule Dagger.Mod.ObjectTest do
         end
       end
     end
+
+    test "function option validation" do
+      assert_raise ArgumentError, fn ->
+        defmodule FunOptUnknown do
+          use Dagger.Mod.Object, name: "FunOptUnknown"
+
+          defn should_fail() :: String.t(), unknown: true do
+            "fail"
+          end
+        end
+      end
+
+      assert_raise ArgumentError, "invalid value for option `cache`: 10", fn ->
+        defmodule FunOptCache do
+          use Dagger.Mod.Object, name: "FunOptCache"
+
+          defn should_fail() :: String.t(), cache: 10 do
+            "fail"
+          end
+        end
+      end
+    end
   end
 
   test "get_module_doc/1" do
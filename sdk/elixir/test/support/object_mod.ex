@@ -56,4 +56,8 @@ defmodule ObjectMod do
   defn return_void() :: Dagger.Void.t() do
     :ok
   end
+
+  defn cached() :: String.t(), cache: "10m" do
+    "Cached"
+  end
 end
@@ -1,8 +1,14 @@
 package dagger
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"strings"
 
 	"github.com/Khan/genqlient/graphql"
 	"github.com/vektah/gqlparser/v2/gqlerror"
@@ -99,7 +105,12 @@ func Connect(ctx context.Context, opts ...ClientOpt) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	gql := errorWrappedClient{graphql.NewClient("http://"+conn.Host()+"/query", conn)}
+	endpoint := "http://" + conn.Host() + "/query"
+	gql := errorWrappedClient{
+		Client:   graphql.NewClient(endpoint, conn),
+		endpoint: endpoint,
+		doer:     conn,
+	}
 
 	c := &Client{
 		query:  querybuilder.Query().Client(gql),
@@ -180,6 +191,9 @@ type Response struct {
 
 type errorWrappedClient struct {
 	graphql.Client
+
+	endpoint string
+	doer     graphql.Doer
 }
 
 func (c errorWrappedClient) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
@@ -192,3 +206,70 @@ func (c errorWrappedClient) MakeRequest(ctx context.Context, req *graphql.Reques
 	}
 	return nil
 }
+
+// Subscribe executes a subscription, calling fn with the data of each
+// response as soon as it's received. Responses are streamed by the engine as
+// server-sent events.
+func (c errorWrappedClient) Subscribe(ctx context.Context, req *graphql.Request, fn func(data json.RawMessage) error) error {
+	err := c.subscribe(ctx, req, fn)
+	if err != nil {
+		if e := getCustomError(err); e != nil {
+			return e
+		}
+		return err
+	}
+	return nil
+}
+
+func (c errorWrappedClient) subscribe(ctx context.Context, req *graphql.Request, fn func(data json.RawMessage) error) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	httpResp, err := c.doer.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body)
+		return fmt.Errorf("returned error %s: %s", httpResp.Status, respBody)
+	}
+
+	events := bufio.NewReader(httpResp.Body)
+	for {
+		line, err := events.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("subscription ended before completing")
+			}
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "event: complete":
+			return nil
+		case strings.HasPrefix(line, "data:"):
+			var resp struct {
+				Data   json.RawMessage `json:"data"`
+				Errors gqlerror.List   `json:"errors"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &resp); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+			if len(resp.Errors) > 0 {
+				return resp.Errors
+			}
+			if err := fn(resp.Data); err != nil {
+				return err
+			}
+		}
+	}
+}
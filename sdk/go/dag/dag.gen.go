@@ -222,6 +222,12 @@ func LoadFileFromID(id dagger.FileID) *dagger.File {
 	return client.LoadFileFromID(id)
 }
 
+// Load a FileInfo from its ID.
+func LoadFileInfoFromID(id dagger.FileInfoID) *dagger.FileInfo {
+	client := initClient()
+	return client.LoadFileInfoFromID(id)
+}
+
 // Load a FunctionArg from its ID.
 func LoadFunctionArgFromID(id dagger.FunctionArgID) *dagger.FunctionArg {
 	client := initClient()
@@ -252,6 +258,12 @@ func LoadGeneratedCodeFromID(id dagger.GeneratedCodeID) *dagger.GeneratedCode {
 	return client.LoadGeneratedCodeFromID(id)
 }
 
+// Load a GitCommit from its ID.
+func LoadGitCommitFromID(id dagger.GitCommitID) *dagger.GitCommit {
+	client := initClient()
+	return client.LoadGitCommitFromID(id)
+}
+
 // Load a GitRef from its ID.
 func LoadGitRefFromID(id dagger.GitRefID) *dagger.GitRef {
 	client := initClient()
@@ -294,6 +306,12 @@ func LoadListTypeDefFromID(id dagger.ListTypeDefID) *dagger.ListTypeDef {
 	return client.LoadListTypeDefFromID(id)
 }
 
+// Load a MapTypeDef from its ID.
+func LoadMapTypeDefFromID(id dagger.MapTypeDefID) *dagger.MapTypeDef {
+	client := initClient()
+	return client.LoadMapTypeDefFromID(id)
+}
+
 // Load a Module from its ID.
 func LoadModuleFromID(id dagger.ModuleID) *dagger.Module {
 	client := initClient()
@@ -330,6 +348,12 @@ func LoadScalarTypeDefFromID(id dagger.ScalarTypeDefID) *dagger.ScalarTypeDef {
 	return client.LoadScalarTypeDefFromID(id)
 }
 
+// Load a SearchResult from its ID.
+func LoadSearchResultFromID(id dagger.SearchResultID) *dagger.SearchResult {
+	client := initClient()
+	return client.LoadSearchResultFromID(id)
+}
+
 // Load a Secret from its ID.
 func LoadSecretFromID(id dagger.SecretID) *dagger.Secret {
 	client := initClient()
@@ -372,6 +396,18 @@ func LoadTypeDefFromID(id dagger.TypeDefID) *dagger.TypeDef {
 	return client.LoadTypeDefFromID(id)
 }
 
+// Load a UnionMemberTypeDef from its ID.
+func LoadUnionMemberTypeDefFromID(id dagger.UnionMemberTypeDefID) *dagger.UnionMemberTypeDef {
+	client := initClient()
+	return client.LoadUnionMemberTypeDefFromID(id)
+}
+
+// Load a UnionTypeDef from its ID.
+func LoadUnionTypeDefFromID(id dagger.UnionTypeDefID) *dagger.UnionTypeDef {
+	client := initClient()
+	return client.LoadUnionTypeDefFromID(id)
+}
+
 // Create a new module.
 func Module() *dagger.Module {
 	client := initClient()
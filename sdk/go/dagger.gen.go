@@ -71,6 +71,12 @@ func getCustomError(err error) error {
 		if stderr, ok := ext["stderr"].(string); ok {
 			e.Stderr = stderr
 		}
+		if oomKilled, ok := ext["oomKilled"].(bool); ok && oomKilled {
+			return &OOMError{ExecError: e}
+		}
+		if timedOut, ok := ext["timedOut"].(bool); ok && timedOut {
+			return &TimeoutError{ExecError: e}
+		}
 		return e
 	}
 
@@ -98,6 +104,26 @@ func (e *ExecError) Unwrap() error {
 	return e.original
 }
 
+// OOMError is an API error from an exec operation that was killed for
+// exceeding its memory limit.
+type OOMError struct {
+	*ExecError
+}
+
+func (e *OOMError) Unwrap() error {
+	return e.ExecError
+}
+
+// TimeoutError is an API error from an exec operation that was killed for
+// running past its timeout.
+type TimeoutError struct {
+	*ExecError
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.ExecError
+}
+
 // The `CacheVolumeID` scalar type represents an identifier for an object of type CacheVolume.
 type CacheVolumeID string
 
@@ -140,6 +166,9 @@ type FieldTypeDefID string
 // The `FileID` scalar type represents an identifier for an object of type File.
 type FileID string
 
+// The `FileInfoID` scalar type represents an identifier for an object of type FileInfo.
+type FileInfoID string
+
 // The `FunctionArgID` scalar type represents an identifier for an object of type FunctionArg.
 type FunctionArgID string
 
@@ -155,6 +184,9 @@ type FunctionID string
 // The `GeneratedCodeID` scalar type represents an identifier for an object of type GeneratedCode.
 type GeneratedCodeID string
 
+// The `GitCommitID` scalar type represents an identifier for an object of type GitCommit.
+type GitCommitID string
+
 // The `GitRefID` scalar type represents an identifier for an object of type GitRef.
 type GitRefID string
 
@@ -179,6 +211,9 @@ type LabelID string
 // The `ListTypeDefID` scalar type represents an identifier for an object of type ListTypeDef.
 type ListTypeDefID string
 
+// The `MapTypeDefID` scalar type represents an identifier for an object of type MapTypeDef.
+type MapTypeDefID string
+
 // The `ModuleID` scalar type represents an identifier for an object of type Module.
 type ModuleID string
 
@@ -202,6 +237,9 @@ type SDKConfigID string
 // The `ScalarTypeDefID` scalar type represents an identifier for an object of type ScalarTypeDef.
 type ScalarTypeDefID string
 
+// The `SearchResultID` scalar type represents an identifier for an object of type SearchResult.
+type SearchResultID string
+
 // The `SecretID` scalar type represents an identifier for an object of type Secret.
 type SecretID string
 
@@ -220,6 +258,12 @@ type TerminalID string
 // The `TypeDefID` scalar type represents an identifier for an object of type TypeDef.
 type TypeDefID string
 
+// The `UnionMemberTypeDefID` scalar type represents an identifier for an object of type UnionMemberTypeDef.
+type UnionMemberTypeDefID string
+
+// The `UnionTypeDefID` scalar type represents an identifier for an object of type UnionTypeDef.
+type UnionTypeDefID string
+
 // The absence of a value.
 //
 // A Null Void is used as a placeholder for resolvers that do not return anything.
@@ -234,6 +278,15 @@ type BuildArg struct {
 	Value string `json:"value"`
 }
 
+// Key value object that represents an HTTP header.
+type HTTPHeader struct {
+	// The header name.
+	Name string `json:"name"`
+
+	// The header value.
+	Value string `json:"value"`
+}
+
 // Key value object that represents a pipeline label.
 type PipelineLabel struct {
 	// Label name.
@@ -255,6 +308,15 @@ type PortForward struct {
 	Protocol NetworkProtocol `json:"protocol,omitempty"`
 }
 
+// Key value object that represents a secret variable of a template.
+type SecretVariable struct {
+	// The variable name.
+	Name string `json:"name"`
+
+	// The secret holding the variable value.
+	Secret *Secret `json:"secret"`
+}
+
 // A directory whose contents persist across runs.
 type CacheVolume struct {
 	query *querybuilder.Selection
@@ -313,6 +375,7 @@ type Container struct {
 	query *querybuilder.Selection
 
 	envVariable *string
+	exists      *bool
 	exitCode    *int
 	export      *string
 	id          *ContainerID
@@ -576,6 +639,42 @@ func (r *Container) EnvVariables(ctx context.Context) ([]EnvVariable, error) {
 	return convert(response), nil
 }
 
+// ContainerExistsOpts contains options for Container.Exists
+type ContainerExistsOpts struct {
+	// If set, the entry must also be of this type.
+	//
+	// Symlinks are followed, unless the expected type is SYMLINK.
+	ExpectedType FileType
+	// Replace "${VAR}" or "$VAR" in the value of path according to the current environment variables defined in the container (e.g. "/$VAR/foo.txt").
+	Expand bool
+}
+
+// Returns whether a file, directory or other entry exists at the given path.
+//
+// Mounts are included.
+func (r *Container) Exists(ctx context.Context, path string, opts ...ContainerExistsOpts) (bool, error) {
+	if r.exists != nil {
+		return *r.exists, nil
+	}
+	q := r.query.Select("exists")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `expectedType` optional argument
+		if !querybuilder.IsZeroValue(opts[i].ExpectedType) {
+			q = q.Arg("expectedType", opts[i].ExpectedType)
+		}
+		// `expand` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Expand) {
+			q = q.Arg("expand", opts[i].Expand)
+		}
+	}
+	q = q.Arg("path", path)
+
+	var response bool
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // The exit code of the last executed command.
 //
 // Returns an error if no command was set.
@@ -899,6 +998,18 @@ type ContainerPublishOpts struct {
 	//
 	// Defaults to OCI, which is largely compatible with most recent registries, but Docker may be needed for older registries without OCI support.
 	MediaTypes ImageMediaTypes
+	// Sign the published image with this private key, the way cosign does.
+	//
+	// The signature, and the attestations, are pushed as OCI referrers of the image. Verify it with "cosign verify --experimental-oci11 --key".
+	//
+	// Keys generated with "cosign generate-key-pair" are supported, as well as unencrypted PKCS #8, EC and RSA keys in PEM format.
+	Sign *Secret
+	// The password of the signing key, if it's encrypted.
+	SignPassword *Secret
+	// Attestations to push along with the image, as OCI referrers.
+	//
+	// They are signed with the signing key, if any, as DSSE envelopes.
+	Attestations []ImageAttestation
 }
 
 // Publishes this container as a new image to the specified address.
@@ -924,6 +1035,18 @@ func (r *Container) Publish(ctx context.Context, address string, opts ...Contain
 		if !querybuilder.IsZeroValue(opts[i].MediaTypes) {
 			q = q.Arg("mediaTypes", opts[i].MediaTypes)
 		}
+		// `sign` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Sign) {
+			q = q.Arg("sign", opts[i].Sign)
+		}
+		// `signPassword` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SignPassword) {
+			q = q.Arg("signPassword", opts[i].SignPassword)
+		}
+		// `attestations` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Attestations) {
+			q = q.Arg("attestations", opts[i].Attestations)
+		}
 	}
 	q = q.Arg("address", address)
 
@@ -942,6 +1065,30 @@ func (r *Container) Rootfs() *Directory {
 	}
 }
 
+// ContainerStatOpts contains options for Container.Stat
+type ContainerStatOpts struct {
+	// Replace "${VAR}" or "$VAR" in the value of path according to the current environment variables defined in the container (e.g. "/$VAR/foo.txt").
+	Expand bool
+}
+
+// Returns information about the entry at the given path.
+//
+// Mounts are included. A symlink at the path is described itself, rather than its target.
+func (r *Container) Stat(path string, opts ...ContainerStatOpts) *FileInfo {
+	q := r.query.Select("stat")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `expand` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Expand) {
+			q = q.Arg("expand", opts[i].Expand)
+		}
+	}
+	q = q.Arg("path", path)
+
+	return &FileInfo{
+		query: q,
+	}
+}
+
 // The error stream of the last executed command.
 //
 // Returns an error if no command was set.
@@ -1030,6 +1177,10 @@ type ContainerUpOpts struct {
 	Ports []PortForward
 	// Bind each tunnel port to a random port on the host.
 	Random bool
+	// Number of seconds to wait for the service's health checks to succeed. Zero means no timeout.
+	//
+	// When the timeout expires the service is killed and the call fails with an exec error reporting that it timed out, including the output captured so far.
+	Timeout int
 	// Command to run instead of the container's default command (e.g., ["go", "run", "main.go"]).
 	//
 	// If empty, the container's default command is used.
@@ -1067,6 +1218,10 @@ func (r *Container) Up(ctx context.Context, opts ...ContainerUpOpts) error {
 		if !querybuilder.IsZeroValue(opts[i].Random) {
 			q = q.Arg("random", opts[i].Random)
 		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
 		// `args` optional argument
 		if !querybuilder.IsZeroValue(opts[i].Args) {
 			q = q.Arg("args", opts[i].Args)
@@ -1172,6 +1327,10 @@ type ContainerWithDirectoryOpts struct {
 	//
 	// If the group is omitted, it defaults to the same as the user.
 	Owner string
+	// Changes of ownership to apply to the copied contents, as FROM:TO pairs (e.g., ["1000:0"]).
+	//
+	// FROM is a user ID recorded in the source, optionally followed by a group ID as UID/GID (e.g., "1000/100"), the group ID defaulting to the user ID. Files owned by the user get the user of TO, and files owned by the group get the group of TO. TO is a user ID or name, with the group defaulting to the user's. Cannot be used together with owner; if neither is set, the ownership recorded in the source is preserved.
+	OwnerMap []string
 	// Replace "${VAR}" or "$VAR" in the value of path according to the current environment variables defined in the container (e.g. "/$VAR/foo").
 	Expand bool
 }
@@ -1193,6 +1352,10 @@ func (r *Container) WithDirectory(path string, directory *Directory, opts ...Con
 		if !querybuilder.IsZeroValue(opts[i].Owner) {
 			q = q.Arg("owner", opts[i].Owner)
 		}
+		// `ownerMap` optional argument
+		if !querybuilder.IsZeroValue(opts[i].OwnerMap) {
+			q = q.Arg("ownerMap", opts[i].OwnerMap)
+		}
 		// `expand` optional argument
 		if !querybuilder.IsZeroValue(opts[i].Expand) {
 			q = q.Arg("expand", opts[i].Expand)
@@ -1275,6 +1438,24 @@ type ContainerWithExecOpts struct {
 	//
 	// This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
 	NoInit bool
+	// Number of CPUs the command may use (e.g., 0.5 or 2).
+	//
+	// Overrides the default set with withResourceLimits. Zero means unlimited.
+	CPUQuota float64
+	// Maximum memory in bytes the command may use.
+	//
+	// Overrides the default set with withResourceLimits. Zero means unlimited.
+	//
+	// If the command exceeds the limit it is killed and the call fails with an exec error reporting that it ran out of memory.
+	MemoryLimit int
+	// Maximum number of processes the command may create.
+	//
+	// Overrides the default set with withResourceLimits. Zero means unlimited.
+	PidsLimit int
+	// Number of seconds after which the command is killed if it's still running. Zero means no timeout.
+	//
+	// When the timeout expires the call fails with an exec error reporting that it timed out, including the output captured so far.
+	Timeout int
 }
 
 // Retrieves this container after executing the specified command inside it.
@@ -1317,6 +1498,22 @@ func (r *Container) WithExec(args []string, opts ...ContainerWithExecOpts) *Cont
 		if !querybuilder.IsZeroValue(opts[i].NoInit) {
 			q = q.Arg("noInit", opts[i].NoInit)
 		}
+		// `cpuQuota` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPUQuota) {
+			q = q.Arg("cpuQuota", opts[i].CPUQuota)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
 	}
 	q = q.Arg("args", args)
 
@@ -1375,6 +1572,10 @@ type ContainerWithFileOpts struct {
 	//
 	// If the group is omitted, it defaults to the same as the user.
 	Owner string
+	// Changes of ownership to apply to the copied file, as FROM:TO pairs (e.g., ["1000:0"]).
+	//
+	// FROM is a user ID recorded in the source, optionally followed by a group ID as UID/GID (e.g., "1000/100"), the group ID defaulting to the user ID. Files owned by the user get the user of TO, and files owned by the group get the group of TO. TO is a user ID or name, with the group defaulting to the user's. Cannot be used together with owner; if neither is set, the ownership recorded in the source is preserved.
+	OwnerMap []string
 	// Replace "${VAR}" or "$VAR" in the value of path according to the current environment variables defined in the container (e.g. "/$VAR/foo.txt").
 	Expand bool
 }
@@ -1392,6 +1593,10 @@ func (r *Container) WithFile(path string, source *File, opts ...ContainerWithFil
 		if !querybuilder.IsZeroValue(opts[i].Owner) {
 			q = q.Arg("owner", opts[i].Owner)
 		}
+		// `ownerMap` optional argument
+		if !querybuilder.IsZeroValue(opts[i].OwnerMap) {
+			q = q.Arg("ownerMap", opts[i].OwnerMap)
+		}
 		// `expand` optional argument
 		if !querybuilder.IsZeroValue(opts[i].Expand) {
 			q = q.Arg("expand", opts[i].Expand)
@@ -1415,6 +1620,10 @@ type ContainerWithFilesOpts struct {
 	//
 	// If the group is omitted, it defaults to the same as the user.
 	Owner string
+	// Changes of ownership to apply to the copied files, as FROM:TO pairs (e.g., ["1000:0"]).
+	//
+	// FROM is a user ID recorded in the source, optionally followed by a group ID as UID/GID (e.g., "1000/100"), the group ID defaulting to the user ID. Files owned by the user get the user of TO, and files owned by the group get the group of TO. TO is a user ID or name, with the group defaulting to the user's. Cannot be used together with owner; if neither is set, the ownership recorded in the source is preserved.
+	OwnerMap []string
 	// Replace "${VAR}" or "$VAR" in the value of path according to the current environment variables defined in the container (e.g. "/$VAR/foo.txt").
 	Expand bool
 }
@@ -1431,6 +1640,10 @@ func (r *Container) WithFiles(path string, sources []*File, opts ...ContainerWit
 		if !querybuilder.IsZeroValue(opts[i].Owner) {
 			q = q.Arg("owner", opts[i].Owner)
 		}
+		// `ownerMap` optional argument
+		if !querybuilder.IsZeroValue(opts[i].OwnerMap) {
+			q = q.Arg("ownerMap", opts[i].OwnerMap)
+		}
 		// `expand` optional argument
 		if !querybuilder.IsZeroValue(opts[i].Expand) {
 			q = q.Arg("expand", opts[i].Expand)
@@ -1444,6 +1657,90 @@ func (r *Container) WithFiles(path string, sources []*File, opts ...ContainerWit
 	}
 }
 
+// ContainerWithHTTPHealthcheckOpts contains options for Container.WithHTTPHealthcheck
+type ContainerWithHTTPHealthcheckOpts struct {
+	// Port to send the request to. Defaults to the first exposed TCP port.
+	Port int
+	// HTTP status code that indicates the service is healthy.
+	ExpectedStatus int
+	// Number of seconds to wait between attempts.
+	Interval int
+	// Number of consecutive failed attempts after which the service is considered unhealthy.
+	Retries int
+	// Number of seconds to give the service to initialize, during which failed attempts don't count towards retries.
+	StartPeriod int
+}
+
+// Configures an HTTP request that checks whether the container is ready when run as a service.
+//
+// Starting the service waits until the request returns the expected status, in addition to waiting for the exposed ports to accept connections.
+func (r *Container) WithHTTPHealthcheck(path string, opts ...ContainerWithHTTPHealthcheckOpts) *Container {
+	q := r.query.Select("withHTTPHealthcheck")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `port` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Port) {
+			q = q.Arg("port", opts[i].Port)
+		}
+		// `expectedStatus` optional argument
+		if !querybuilder.IsZeroValue(opts[i].ExpectedStatus) {
+			q = q.Arg("expectedStatus", opts[i].ExpectedStatus)
+		}
+		// `interval` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Interval) {
+			q = q.Arg("interval", opts[i].Interval)
+		}
+		// `retries` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Retries) {
+			q = q.Arg("retries", opts[i].Retries)
+		}
+		// `startPeriod` optional argument
+		if !querybuilder.IsZeroValue(opts[i].StartPeriod) {
+			q = q.Arg("startPeriod", opts[i].StartPeriod)
+		}
+	}
+	q = q.Arg("path", path)
+
+	return &Container{
+		query: q,
+	}
+}
+
+// ContainerWithHealthcheckOpts contains options for Container.WithHealthcheck
+type ContainerWithHealthcheckOpts struct {
+	// Number of seconds to wait between attempts.
+	Interval int
+	// Number of consecutive failed attempts after which the service is considered unhealthy.
+	Retries int
+	// Number of seconds to give the service to initialize, during which failed attempts don't count towards retries.
+	StartPeriod int
+}
+
+// Configures a command that checks whether the container is ready when run as a service.
+//
+// Starting the service waits until the command exits successfully, in addition to waiting for the exposed ports to accept connections.
+func (r *Container) WithHealthcheck(args []string, opts ...ContainerWithHealthcheckOpts) *Container {
+	q := r.query.Select("withHealthcheck")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `interval` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Interval) {
+			q = q.Arg("interval", opts[i].Interval)
+		}
+		// `retries` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Retries) {
+			q = q.Arg("retries", opts[i].Retries)
+		}
+		// `startPeriod` optional argument
+		if !querybuilder.IsZeroValue(opts[i].StartPeriod) {
+			q = q.Arg("startPeriod", opts[i].StartPeriod)
+		}
+	}
+	q = q.Arg("args", args)
+
+	return &Container{
+		query: q,
+	}
+}
+
 // Retrieves this container plus the given label.
 func (r *Container) WithLabel(name string, value string) *Container {
 	q := r.query.Select("withLabel")
@@ -1693,6 +1990,41 @@ func (r *Container) WithRegistryAuth(address string, username string, secret *Se
 	}
 }
 
+// ContainerWithResourceLimitsOpts contains options for Container.WithResourceLimits
+type ContainerWithResourceLimitsOpts struct {
+	// Number of CPUs the container's commands may use (e.g., 0.5 or 2).
+	CPUQuota float64
+	// Maximum memory in bytes the container's commands may use.
+	MemoryLimit int
+	// Maximum number of processes the container's commands may create.
+	PidsLimit int
+}
+
+// Retrieves this container with default resource limits for all subsequent withExec calls.
+//
+// Limits that are not set (or set to zero) keep their previous value.
+func (r *Container) WithResourceLimits(opts ...ContainerWithResourceLimitsOpts) *Container {
+	q := r.query.Select("withResourceLimits")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `cpuQuota` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPUQuota) {
+			q = q.Arg("cpuQuota", opts[i].CPUQuota)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
+	}
+
+	return &Container{
+		query: q,
+	}
+}
+
 // Retrieves the container with the given directory mounted to /.
 func (r *Container) WithRootfs(directory *Directory) *Container {
 	assertNotNil("directory", directory)
@@ -1734,6 +2066,29 @@ func (r *Container) WithServiceBinding(alias string, service *Service) *Containe
 	}
 }
 
+// ContainerWithSymlinkOpts contains options for Container.WithSymlink
+type ContainerWithSymlinkOpts struct {
+	// Replace "${VAR}" or "$VAR" in the value of linkName according to the current environment variables defined in the container (e.g. "/$VAR/foo").
+	Expand bool
+}
+
+// Retrieves this container plus a symbolic link at the given path.
+func (r *Container) WithSymlink(target string, linkName string, opts ...ContainerWithSymlinkOpts) *Container {
+	q := r.query.Select("withSymlink")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `expand` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Expand) {
+			q = q.Arg("expand", opts[i].Expand)
+		}
+	}
+	q = q.Arg("target", target)
+	q = q.Arg("linkName", linkName)
+
+	return &Container{
+		query: q,
+	}
+}
+
 // ContainerWithUnixSocketOpts contains options for Container.WithUnixSocket
 type ContainerWithUnixSocketOpts struct {
 	// A user:group to set for the mounted socket.
@@ -1938,6 +2293,15 @@ func (r *Container) WithoutFiles(paths []string, opts ...ContainerWithoutFilesOp
 	}
 }
 
+// Removes the health check configured with withHealthcheck or withHTTPHealthcheck.
+func (r *Container) WithoutHealthcheck() *Container {
+	q := r.query.Select("withoutHealthcheck")
+
+	return &Container{
+		query: q,
+	}
+}
+
 // Retrieves this container minus the given environment label.
 func (r *Container) WithoutLabel(name string) *Container {
 	q := r.query.Select("withoutLabel")
@@ -2166,6 +2530,7 @@ type Directory struct {
 	query *querybuilder.Selection
 
 	digest *string
+	exists *bool
 	export *string
 	id     *DirectoryID
 	name   *string
@@ -2186,6 +2551,33 @@ func (r *Directory) WithGraphQLQuery(q *querybuilder.Selection) *Directory {
 	}
 }
 
+// DirectoryAsArchiveOpts contains options for Directory.AsArchive
+type DirectoryAsArchiveOpts struct {
+	// Format of the archive.
+	Format ArchiveFormat
+	// If true, modification times, ownership and entry order are normalized so that archiving the same contents always produces the same bytes.
+	Reproducible bool
+}
+
+// Packs the contents of this directory into an archive file.
+func (r *Directory) AsArchive(opts ...DirectoryAsArchiveOpts) *File {
+	q := r.query.Select("asArchive")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `format` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Format) {
+			q = q.Arg("format", opts[i].Format)
+		}
+		// `reproducible` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Reproducible) {
+			q = q.Arg("reproducible", opts[i].Reproducible)
+		}
+	}
+
+	return &File{
+		query: q,
+	}
+}
+
 // DirectoryAsModuleOpts contains options for Directory.AsModule
 type DirectoryAsModuleOpts struct {
 	// An optional subpath of the directory which contains the module's configuration file.
@@ -2335,6 +2727,34 @@ func (r *Directory) Entries(ctx context.Context, opts ...DirectoryEntriesOpts) (
 	return response, q.Execute(ctx)
 }
 
+// DirectoryExistsOpts contains options for Directory.Exists
+type DirectoryExistsOpts struct {
+	// If set, the entry must also be of this type.
+	//
+	// Symlinks are followed, unless the expected type is SYMLINK.
+	ExpectedType FileType
+}
+
+// Returns whether a file, directory or other entry exists at the given path.
+func (r *Directory) Exists(ctx context.Context, path string, opts ...DirectoryExistsOpts) (bool, error) {
+	if r.exists != nil {
+		return *r.exists, nil
+	}
+	q := r.query.Select("exists")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `expectedType` optional argument
+		if !querybuilder.IsZeroValue(opts[i].ExpectedType) {
+			q = q.Arg("expectedType", opts[i].ExpectedType)
+		}
+	}
+	q = q.Arg("path", path)
+
+	var response bool
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // DirectoryExportOpts contains options for Directory.Export
 type DirectoryExportOpts struct {
 	// If true, then the host directory will be wiped clean before exporting so that it exactly matches the directory being exported; this means it will delete any files on the host that aren't in the exported dir. If false (the default), the contents of the directory will be merged with any existing contents of the host directory, leaving any existing files on the host that aren't in the exported directory alone.
@@ -2371,6 +2791,49 @@ func (r *Directory) File(path string) *File {
 	}
 }
 
+// DirectoryFilterOpts contains options for Directory.Filter
+type DirectoryFilterOpts struct {
+	// Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
+	Exclude []string
+	// Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
+	Include []string
+	// Exclude artifacts ignored by .gitignore files in the directory, and by .git/info/exclude.
+	Gitignore bool
+	// Exclude artifacts ignored by ignore files with these names (e.g., [".dockerignore"]).
+	//
+	// They are read from every subdirectory, using .gitignore syntax, except for .dockerignore, which is only read from the directory itself, using the syntax of docker build.
+	IgnoreFiles []string
+}
+
+// Retrieves this directory with only the artifacts that pass the given filters.
+//
+// Ignore files are read from the directory itself, so paths in them are relative to the directory containing each ignore file. As with git, an artifact inside an ignored directory cannot be re-included, unless it's ignored by .dockerignore.
+func (r *Directory) Filter(opts ...DirectoryFilterOpts) *Directory {
+	q := r.query.Select("filter")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `exclude` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Exclude) {
+			q = q.Arg("exclude", opts[i].Exclude)
+		}
+		// `include` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Include) {
+			q = q.Arg("include", opts[i].Include)
+		}
+		// `gitignore` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Gitignore) {
+			q = q.Arg("gitignore", opts[i].Gitignore)
+		}
+		// `ignoreFiles` optional argument
+		if !querybuilder.IsZeroValue(opts[i].IgnoreFiles) {
+			q = q.Arg("ignoreFiles", opts[i].IgnoreFiles)
+		}
+	}
+
+	return &Directory{
+		query: q,
+	}
+}
+
 // Returns a list of files and directories that matche the given pattern.
 func (r *Directory) Glob(ctx context.Context, pattern string) ([]string, error) {
 	q := r.query.Select("glob")
@@ -2435,6 +2898,90 @@ func (r *Directory) Name(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// DirectorySearchOpts contains options for Directory.Search
+type DirectorySearchOpts struct {
+	// Treat the pattern as a literal string rather than a regular expression.
+	Literal bool
+	// Only search within these paths (e.g., ["src/", "README.md"]).
+	Paths []string
+	// Only search files whose path matches one of these glob patterns (e.g., ["**/*.go"]).
+	Globs []string
+	// Match the pattern case-insensitively.
+	CaseInsensitive bool
+	// Stop after this many results. If 0 (the default), return all results.
+	Limit int
+}
+
+// Searches the contents of the files in this directory for a pattern.
+//
+// Binary files are skipped. Results are ordered by file path, then by position within the file.
+func (r *Directory) Search(ctx context.Context, pattern string, opts ...DirectorySearchOpts) ([]SearchResult, error) {
+	q := r.query.Select("search")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `literal` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Literal) {
+			q = q.Arg("literal", opts[i].Literal)
+		}
+		// `paths` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Paths) {
+			q = q.Arg("paths", opts[i].Paths)
+		}
+		// `globs` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Globs) {
+			q = q.Arg("globs", opts[i].Globs)
+		}
+		// `caseInsensitive` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CaseInsensitive) {
+			q = q.Arg("caseInsensitive", opts[i].CaseInsensitive)
+		}
+		// `limit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Limit) {
+			q = q.Arg("limit", opts[i].Limit)
+		}
+	}
+	q = q.Arg("pattern", pattern)
+
+	q = q.Select("id")
+
+	type search struct {
+		Id SearchResultID
+	}
+
+	convert := func(fields []search) []SearchResult {
+		out := []SearchResult{}
+
+		for i := range fields {
+			val := SearchResult{id: &fields[i].Id}
+			val.query = q.Root().Select("loadSearchResultFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []search
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// Returns information about the entry at the given path.
+//
+// A symlink at the path is described itself, rather than its target.
+func (r *Directory) Stat(path string) *FileInfo {
+	q := r.query.Select("stat")
+	q = q.Arg("path", path)
+
+	return &FileInfo{
+		query: q,
+	}
+}
+
 // Force evaluation in the engine.
 func (r *Directory) Sync(ctx context.Context) (*Directory, error) {
 	q := r.query.Select("sync")
@@ -2611,9 +3158,20 @@ func (r *Directory) WithNewFile(path string, contents string, opts ...DirectoryW
 	}
 }
 
-// Retrieves this directory with all file/dir timestamps set to the given time.
-func (r *Directory) WithTimestamps(timestamp int) *Directory {
-	q := r.query.Select("withTimestamps")
+// Retrieves this directory plus a symbolic link at the given path.
+func (r *Directory) WithSymlink(target string, linkName string) *Directory {
+	q := r.query.Select("withSymlink")
+	q = q.Arg("target", target)
+	q = q.Arg("linkName", linkName)
+
+	return &Directory{
+		query: q,
+	}
+}
+
+// Retrieves this directory with all file/dir timestamps set to the given time.
+func (r *Directory) WithTimestamps(timestamp int) *Directory {
+	q := r.query.Select("withTimestamps")
 	q = q.Arg("timestamp", timestamp)
 
 	return &Directory{
@@ -3232,6 +3790,7 @@ func (r *EnumTypeDef) Values(ctx context.Context) ([]EnumValueTypeDef, error) {
 type EnumValueTypeDef struct {
 	query *querybuilder.Selection
 
+	deprecated  *string
 	description *string
 	id          *EnumValueTypeDefID
 	name        *string
@@ -3243,6 +3802,19 @@ func (r *EnumValueTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *EnumValu
 	}
 }
 
+// The reason this enum value is deprecated, if it is.
+func (r *EnumValueTypeDef) Deprecated(ctx context.Context) (string, error) {
+	if r.deprecated != nil {
+		return *r.deprecated, nil
+	}
+	q := r.query.Select("deprecated")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A doc string for the enum value, if any.
 func (r *EnumValueTypeDef) Description(ctx context.Context) (string, error) {
 	if r.description != nil {
@@ -3471,6 +4043,7 @@ func (r *Error) Message(ctx context.Context) (string, error) {
 type FieldTypeDef struct {
 	query *querybuilder.Selection
 
+	deprecated  *string
 	description *string
 	id          *FieldTypeDefID
 	name        *string
@@ -3482,6 +4055,19 @@ func (r *FieldTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *FieldTypeDef
 	}
 }
 
+// The reason this field is deprecated, if it is.
+func (r *FieldTypeDef) Deprecated(ctx context.Context) (string, error) {
+	if r.deprecated != nil {
+		return *r.deprecated, nil
+	}
+	q := r.query.Select("deprecated")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A doc string for the field, if any.
 func (r *FieldTypeDef) Description(ctx context.Context) (string, error) {
 	if r.description != nil {
@@ -3657,6 +4243,17 @@ func (r *File) Export(ctx context.Context, path string, opts ...FileExportOpts)
 	return response, q.Execute(ctx)
 }
 
+// Unpacks this archive file into a directory.
+//
+// Supports tar archives, optionally compressed with gzip or zstd, and zip archives. The format is detected from the file's contents.
+func (r *File) Extract() *Directory {
+	q := r.query.Select("extract")
+
+	return &Directory{
+		query: q,
+	}
+}
+
 // A unique identifier for this File.
 func (r *File) ID(ctx context.Context) (FileID, error) {
 	if r.id != nil {
@@ -3756,15 +4353,184 @@ func (r *File) WithTimestamps(timestamp int) *File {
 	}
 }
 
+// Information about a file, directory or other filesystem entry.
+type FileInfo struct {
+	query *querybuilder.Selection
+
+	fileType      *FileType
+	gid           *int
+	id            *FileInfoID
+	mode          *int
+	modifiedTime  *int
+	name          *string
+	size          *int
+	symlinkTarget *string
+	uid           *int
+}
+
+func (r *FileInfo) WithGraphQLQuery(q *querybuilder.Selection) *FileInfo {
+	return &FileInfo{
+		query: q,
+	}
+}
+
+// The type of the entry.
+func (r *FileInfo) FileType(ctx context.Context) (FileType, error) {
+	if r.fileType != nil {
+		return *r.fileType, nil
+	}
+	q := r.query.Select("fileType")
+
+	var response FileType
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The group ID owning the entry.
+func (r *FileInfo) Gid(ctx context.Context) (int, error) {
+	if r.gid != nil {
+		return *r.gid, nil
+	}
+	q := r.query.Select("gid")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this FileInfo.
+func (r *FileInfo) ID(ctx context.Context) (FileInfoID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response FileInfoID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *FileInfo) XXX_GraphQLType() string {
+	return "FileInfo"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *FileInfo) XXX_GraphQLIDType() string {
+	return "FileInfoID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *FileInfo) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *FileInfo) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The permission bits of the entry (e.g., 0644).
+func (r *FileInfo) Mode(ctx context.Context) (int, error) {
+	if r.mode != nil {
+		return *r.mode, nil
+	}
+	q := r.query.Select("mode")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The last modification time of the entry, in seconds since the Unix epoch.
+func (r *FileInfo) ModifiedTime(ctx context.Context) (int, error) {
+	if r.modifiedTime != nil {
+		return *r.modifiedTime, nil
+	}
+	q := r.query.Select("modifiedTime")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The base name of the entry.
+func (r *FileInfo) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.query.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The size of the entry in bytes.
+func (r *FileInfo) Size(ctx context.Context) (int, error) {
+	if r.size != nil {
+		return *r.size, nil
+	}
+	q := r.query.Select("size")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The target of the entry if it is a symbolic link, or an empty string.
+func (r *FileInfo) SymlinkTarget(ctx context.Context) (string, error) {
+	if r.symlinkTarget != nil {
+		return *r.symlinkTarget, nil
+	}
+	q := r.query.Select("symlinkTarget")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The user ID owning the entry.
+func (r *FileInfo) UID(ctx context.Context) (int, error) {
+	if r.uid != nil {
+		return *r.uid, nil
+	}
+	q := r.query.Select("uid")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // Function represents a resolver provided by a Module.
 //
 // A function always evaluates against a parent object and is given a set of named arguments.
 type Function struct {
 	query *querybuilder.Selection
 
-	description *string
-	id          *FunctionID
-	name        *string
+	cachePolicy     *FunctionCachePolicy
+	cacheTTLSeconds *int
+	deprecated      *string
+	description     *string
+	id              *FunctionID
+	name            *string
+	streaming       *bool
 }
 type WithFunctionFunc func(r *Function) *Function
 
@@ -3814,6 +4580,45 @@ func (r *Function) Args(ctx context.Context) ([]FunctionArg, error) {
 	return convert(response), nil
 }
 
+// The policy for caching the results of calls to the function.
+func (r *Function) CachePolicy(ctx context.Context) (FunctionCachePolicy, error) {
+	if r.cachePolicy != nil {
+		return *r.cachePolicy, nil
+	}
+	q := r.query.Select("cachePolicy")
+
+	var response FunctionCachePolicy
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Number of seconds for which the results of calls to the function are cached, if its cache policy is TTL.
+func (r *Function) CacheTTLSeconds(ctx context.Context) (int, error) {
+	if r.cacheTTLSeconds != nil {
+		return *r.cacheTTLSeconds, nil
+	}
+	q := r.query.Select("cacheTTLSeconds")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The reason this function is deprecated, if it is.
+func (r *Function) Deprecated(ctx context.Context) (string, error) {
+	if r.deprecated != nil {
+		return *r.deprecated, nil
+	}
+	q := r.query.Select("deprecated")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A doc string for the function, if any.
 func (r *Function) Description(ctx context.Context) (string, error) {
 	if r.description != nil {
@@ -3898,6 +4703,19 @@ func (r *Function) SourceMap() *SourceMap {
 	}
 }
 
+// Whether the function returns its result incrementally, as a stream of items delivered to subscribers as they are available.
+func (r *Function) Streaming(ctx context.Context) (bool, error) {
+	if r.streaming != nil {
+		return *r.streaming, nil
+	}
+	q := r.query.Select("streaming")
+
+	var response bool
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // FunctionWithArgOpts contains options for Function.WithArg
 type FunctionWithArgOpts struct {
 	// A doc string for the argument, if any
@@ -3910,6 +4728,18 @@ type FunctionWithArgOpts struct {
 	Ignore []string
 
 	SourceMap *SourceMap
+	// If set, marks the argument as deprecated with the given reason. Required arguments, without a default value, can't be deprecated.
+	Deprecated string
+	// If the argument is a String or a list of strings, a regular expression (RE2 syntax) that values must match.
+	Pattern string
+	// If the argument is an Integer or Float, or a list of those, the minimum value, inclusive.
+	Min float64
+	// If the argument is an Integer or Float, or a list of those, the maximum value, inclusive.
+	Max float64
+	// If the argument is a String or a List, the minimum number of characters or elements.
+	MinLength int
+	// If the argument is a String or a list of strings, the only values that are accepted.
+	AllowedValues []string
 }
 
 // Returns the function with the provided argument
@@ -3937,6 +4767,30 @@ func (r *Function) WithArg(name string, typeDef *TypeDef, opts ...FunctionWithAr
 		if !querybuilder.IsZeroValue(opts[i].SourceMap) {
 			q = q.Arg("sourceMap", opts[i].SourceMap)
 		}
+		// `deprecated` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Deprecated) {
+			q = q.Arg("deprecated", opts[i].Deprecated)
+		}
+		// `pattern` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Pattern) {
+			q = q.Arg("pattern", opts[i].Pattern)
+		}
+		// `min` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Min) {
+			q = q.Arg("min", opts[i].Min)
+		}
+		// `max` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Max) {
+			q = q.Arg("max", opts[i].Max)
+		}
+		// `minLength` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MinLength) {
+			q = q.Arg("minLength", opts[i].MinLength)
+		}
+		// `allowedValues` optional argument
+		if !querybuilder.IsZeroValue(opts[i].AllowedValues) {
+			q = q.Arg("allowedValues", opts[i].AllowedValues)
+		}
 	}
 	q = q.Arg("name", name)
 	q = q.Arg("typeDef", typeDef)
@@ -3946,6 +4800,38 @@ func (r *Function) WithArg(name string, typeDef *TypeDef, opts ...FunctionWithAr
 	}
 }
 
+// FunctionWithCachePolicyOpts contains options for Function.WithCachePolicy
+type FunctionWithCachePolicyOpts struct {
+	// For the TTL policy, how long results are cached, as a duration (e.g., "10m", "1h30m").
+	TimeToLive string
+}
+
+// Returns the function with the given cache policy.
+func (r *Function) WithCachePolicy(policy FunctionCachePolicy, opts ...FunctionWithCachePolicyOpts) *Function {
+	q := r.query.Select("withCachePolicy")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `timeToLive` optional argument
+		if !querybuilder.IsZeroValue(opts[i].TimeToLive) {
+			q = q.Arg("timeToLive", opts[i].TimeToLive)
+		}
+	}
+	q = q.Arg("policy", policy)
+
+	return &Function{
+		query: q,
+	}
+}
+
+// Returns the function marked as deprecated.
+func (r *Function) WithDeprecated(reason string) *Function {
+	q := r.query.Select("withDeprecated")
+	q = q.Arg("reason", reason)
+
+	return &Function{
+		query: q,
+	}
+}
+
 // Returns the function with the given doc string.
 func (r *Function) WithDescription(description string) *Function {
 	q := r.query.Select("withDescription")
@@ -3967,6 +4853,17 @@ func (r *Function) WithSourceMap(sourceMap *SourceMap) *Function {
 	}
 }
 
+// Returns the function marked as streaming its result.
+//
+// Subscribers to the function receive the elements of its list result, or the chunks of its string result, as soon as they are returned with returnItem. Queries still receive the full result once the function is done.
+func (r *Function) WithStreaming() *Function {
+	q := r.query.Select("withStreaming")
+
+	return &Function{
+		query: q,
+	}
+}
+
 // An argument accepted by a function.
 //
 // This is a specification for an argument at function definition time, not an argument passed at function call time.
@@ -3975,9 +4872,14 @@ type FunctionArg struct {
 
 	defaultPath  *string
 	defaultValue *JSON
+	deprecated   *string
 	description  *string
 	id           *FunctionArgID
+	max          *float64
+	min          *float64
+	minLength    *int
 	name         *string
+	pattern      *string
 }
 
 func (r *FunctionArg) WithGraphQLQuery(q *querybuilder.Selection) *FunctionArg {
@@ -3986,6 +4888,16 @@ func (r *FunctionArg) WithGraphQLQuery(q *querybuilder.Selection) *FunctionArg {
 	}
 }
 
+// Only applies to arguments of type String, or lists of strings. The values that are accepted, if restricted.
+func (r *FunctionArg) AllowedValues(ctx context.Context) ([]string, error) {
+	q := r.query.Select("allowedValues")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // Only applies to arguments of type File or Directory. If the argument is not set, load it from the given path in the context directory
 func (r *FunctionArg) DefaultPath(ctx context.Context) (string, error) {
 	if r.defaultPath != nil {
@@ -4012,6 +4924,19 @@ func (r *FunctionArg) DefaultValue(ctx context.Context) (JSON, error) {
 	return response, q.Execute(ctx)
 }
 
+// The reason this argument is deprecated, if it is.
+func (r *FunctionArg) Deprecated(ctx context.Context) (string, error) {
+	if r.deprecated != nil {
+		return *r.deprecated, nil
+	}
+	q := r.query.Select("deprecated")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A doc string for the argument, if any.
 func (r *FunctionArg) Description(ctx context.Context) (string, error) {
 	if r.description != nil {
@@ -4075,6 +5000,45 @@ func (r *FunctionArg) Ignore(ctx context.Context) ([]string, error) {
 	return response, q.Execute(ctx)
 }
 
+// Only applies to arguments of type Integer or Float, or lists of those. The maximum value, inclusive, if any.
+func (r *FunctionArg) Max(ctx context.Context) (float64, error) {
+	if r.max != nil {
+		return *r.max, nil
+	}
+	q := r.query.Select("max")
+
+	var response float64
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Only applies to arguments of type Integer or Float, or lists of those. The minimum value, inclusive, if any.
+func (r *FunctionArg) Min(ctx context.Context) (float64, error) {
+	if r.min != nil {
+		return *r.min, nil
+	}
+	q := r.query.Select("min")
+
+	var response float64
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Only applies to arguments of type String or List. The minimum number of characters of a string, or of elements of a list, if any.
+func (r *FunctionArg) MinLength(ctx context.Context) (int, error) {
+	if r.minLength != nil {
+		return *r.minLength, nil
+	}
+	q := r.query.Select("minLength")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // The name of the argument in lowerCamelCase format.
 func (r *FunctionArg) Name(ctx context.Context) (string, error) {
 	if r.name != nil {
@@ -4088,6 +5052,19 @@ func (r *FunctionArg) Name(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// Only applies to arguments of type String, or lists of strings. A regular expression (RE2 syntax) that values must match, if any.
+func (r *FunctionArg) Pattern(ctx context.Context) (string, error) {
+	if r.pattern != nil {
+		return *r.pattern, nil
+	}
+	q := r.query.Select("pattern")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // The location of this arg declaration.
 func (r *FunctionArg) SourceMap() *SourceMap {
 	q := r.query.Select("sourceMap")
@@ -4115,6 +5092,7 @@ type FunctionCall struct {
 	parent      *JSON
 	parentName  *string
 	returnError *Void
+	returnItem  *Void
 	returnValue *Void
 }
 
@@ -4248,6 +5226,19 @@ func (r *FunctionCall) ReturnError(ctx context.Context, error *Error) error {
 	return q.Execute(ctx)
 }
 
+// Send an item of the result of a streaming function call to its caller as soon as it's available.
+//
+// The function must still return its full result with returnValue once done.
+func (r *FunctionCall) ReturnItem(ctx context.Context, value JSON) error {
+	if r.returnItem != nil {
+		return nil
+	}
+	q := r.query.Select("returnItem")
+	q = q.Arg("value", value)
+
+	return q.Execute(ctx)
+}
+
 // Set the return value of the function call to the provided value.
 func (r *FunctionCall) ReturnValue(ctx context.Context, value JSON) error {
 	if r.returnValue != nil {
@@ -4450,12 +5441,196 @@ func (r *GeneratedCode) WithVCSIgnoredPaths(paths []string) *GeneratedCode {
 	}
 }
 
+// A commit in a git repository.
+type GitCommit struct {
+	query *querybuilder.Selection
+
+	authorEmail    *string
+	authorName     *string
+	authorTime     *int
+	commitTime     *int
+	committerEmail *string
+	committerName  *string
+	id             *GitCommitID
+	message        *string
+	sha            *string
+}
+
+func (r *GitCommit) WithGraphQLQuery(q *querybuilder.Selection) *GitCommit {
+	return &GitCommit{
+		query: q,
+	}
+}
+
+// The email address of the author of the commit.
+func (r *GitCommit) AuthorEmail(ctx context.Context) (string, error) {
+	if r.authorEmail != nil {
+		return *r.authorEmail, nil
+	}
+	q := r.query.Select("authorEmail")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The name of the author of the commit.
+func (r *GitCommit) AuthorName(ctx context.Context) (string, error) {
+	if r.authorName != nil {
+		return *r.authorName, nil
+	}
+	q := r.query.Select("authorName")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The time the commit was authored, in seconds since the Unix epoch.
+func (r *GitCommit) AuthorTime(ctx context.Context) (int, error) {
+	if r.authorTime != nil {
+		return *r.authorTime, nil
+	}
+	q := r.query.Select("authorTime")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The time the commit was committed, in seconds since the Unix epoch.
+func (r *GitCommit) CommitTime(ctx context.Context) (int, error) {
+	if r.commitTime != nil {
+		return *r.commitTime, nil
+	}
+	q := r.query.Select("commitTime")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The email address of the committer of the commit.
+func (r *GitCommit) CommitterEmail(ctx context.Context) (string, error) {
+	if r.committerEmail != nil {
+		return *r.committerEmail, nil
+	}
+	q := r.query.Select("committerEmail")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The name of the committer of the commit.
+func (r *GitCommit) CommitterName(ctx context.Context) (string, error) {
+	if r.committerName != nil {
+		return *r.committerName, nil
+	}
+	q := r.query.Select("committerName")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this GitCommit.
+func (r *GitCommit) ID(ctx context.Context) (GitCommitID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response GitCommitID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *GitCommit) XXX_GraphQLType() string {
+	return "GitCommit"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *GitCommit) XXX_GraphQLIDType() string {
+	return "GitCommitID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *GitCommit) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *GitCommit) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The full commit message.
+func (r *GitCommit) Message(ctx context.Context) (string, error) {
+	if r.message != nil {
+		return *r.message, nil
+	}
+	q := r.query.Select("message")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The SHAs of the parents of the commit.
+func (r *GitCommit) Parents(ctx context.Context) ([]string, error) {
+	q := r.query.Select("parents")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The SHA of the commit.
+func (r *GitCommit) Sha(ctx context.Context) (string, error) {
+	if r.sha != nil {
+		return *r.sha, nil
+	}
+	q := r.query.Select("sha")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A git ref (tag, branch, or commit).
 type GitRef struct {
 	query *querybuilder.Selection
 
-	commit *string
-	id     *GitRefID
+	commit   *string
+	describe *string
+	id       *GitRefID
+}
+type WithGitRefFunc func(r *GitRef) *GitRef
+
+// With calls the provided function with current GitRef.
+//
+// This is useful for reusability and readability by not breaking the calling chain.
+func (r *GitRef) With(f WithGitRefFunc) *GitRef {
+	return f(r)
 }
 
 func (r *GitRef) WithGraphQLQuery(q *querybuilder.Selection) *GitRef {
@@ -4477,6 +5652,21 @@ func (r *GitRef) Commit(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// The most recent tag reachable from this ref, like "git describe --tags".
+//
+// If the ref isn't tagged itself, the tag is followed by the number of commits since it and the abbreviated commit id (e.g., "v0.3.9-14-g2414721"). If there are no tags, only the abbreviated commit id is returned.
+func (r *GitRef) Describe(ctx context.Context) (string, error) {
+	if r.describe != nil {
+		return *r.describe, nil
+	}
+	q := r.query.Select("describe")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A unique identifier for this GitRef.
 func (r *GitRef) ID(ctx context.Context) (GitRefID, error) {
 	if r.id != nil {
@@ -4517,10 +5707,75 @@ func (r *GitRef) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// GitRefLogOpts contains options for GitRef.Log
+type GitRefLogOpts struct {
+	// Maximum number of commits to return. Zero means no limit.
+	Limit int
+}
+
+// The commits reachable from this ref, newest first.
+func (r *GitRef) Log(ctx context.Context, opts ...GitRefLogOpts) ([]GitCommit, error) {
+	q := r.query.Select("log")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `limit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Limit) {
+			q = q.Arg("limit", opts[i].Limit)
+		}
+	}
+
+	q = q.Select("id")
+
+	type log struct {
+		Id GitCommitID
+	}
+
+	convert := func(fields []log) []GitCommit {
+		out := []GitCommit{}
+
+		for i := range fields {
+			val := GitCommit{id: &fields[i].Id}
+			val.query = q.Root().Select("loadGitCommitFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []log
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
 // GitRefTreeOpts contains options for GitRef.Tree
 type GitRefTreeOpts struct {
 	// Set to true to discard .git directory.
 	DiscardGitDir bool
+	// Number of commits of history to fetch into the .git directory.
+	//
+	// Set to -1 to fetch the full history.
+	//
+	// Defaults to the repository's (see "git").
+	Depth int
+	// Only check out these paths, relative to the root of the repository.
+	//
+	// Defaults to the repository's (see "git").
+	SparsePaths []string
+	// A partial clone filter (e.g., "blob:none", "blob:limit=1m" or "tree:0").
+	//
+	// Objects left out by the filter are fetched on demand, e.g. when checking out.
+	//
+	// Defaults to the repository's (see "git").
+	Filter string
+	// Set to false to skip checking out submodules.
+	//
+	// Defaults to the repository's (see "git").
+	Submodules bool
 }
 
 // The filesystem tree at this ref.
@@ -4531,6 +5786,22 @@ func (r *GitRef) Tree(opts ...GitRefTreeOpts) *Directory {
 		if !querybuilder.IsZeroValue(opts[i].DiscardGitDir) {
 			q = q.Arg("discardGitDir", opts[i].DiscardGitDir)
 		}
+		// `depth` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Depth) {
+			q = q.Arg("depth", opts[i].Depth)
+		}
+		// `sparsePaths` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SparsePaths) {
+			q = q.Arg("sparsePaths", opts[i].SparsePaths)
+		}
+		// `filter` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Filter) {
+			q = q.Arg("filter", opts[i].Filter)
+		}
+		// `submodules` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Submodules) {
+			q = q.Arg("submodules", opts[i].Submodules)
+		}
 	}
 
 	return &Directory{
@@ -4538,11 +5809,27 @@ func (r *GitRef) Tree(opts ...GitRefTreeOpts) *Directory {
 	}
 }
 
+// A ref to a new commit on top of this one, recording the contents of a directory.
+//
+// The commit is only kept in the engine until it's pushed (see "GitRepository.push").
+func (r *GitRef) WithChanges(directory *Directory, message string, author string) *GitRef {
+	assertNotNil("directory", directory)
+	q := r.query.Select("withChanges")
+	q = q.Arg("directory", directory)
+	q = q.Arg("message", message)
+	q = q.Arg("author", author)
+
+	return &GitRef{
+		query: q,
+	}
+}
+
 // A git repository.
 type GitRepository struct {
 	query *querybuilder.Selection
 
-	id *GitRepositoryID
+	id   *GitRepositoryID
+	push *string
 }
 type WithGitRepositoryFunc func(r *GitRepository) *GitRepository
 
@@ -4579,6 +5866,18 @@ func (r *GitRepository) Commit(id string) *GitRef {
 	}
 }
 
+// The paths of the files that differ between two refs.
+func (r *GitRepository) Diff(ctx context.Context, from string, to string) ([]string, error) {
+	q := r.query.Select("diff")
+	q = q.Arg("from", from)
+	q = q.Arg("to", to)
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // Returns details for HEAD.
 func (r *GitRepository) Head() *GitRef {
 	q := r.query.Select("head")
@@ -4628,6 +5927,34 @@ func (r *GitRepository) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// GitRepositoryPushOpts contains options for GitRepository.Push
+type GitRepositoryPushOpts struct {
+	// Update the remote ref even if it isn't an ancestor of the commit.
+	Force bool
+}
+
+// Pushes the commit of a ref to the remote repository, using its credentials, and returns the commit id.
+func (r *GitRepository) Push(ctx context.Context, ref *GitRef, remoteRef string, opts ...GitRepositoryPushOpts) (string, error) {
+	assertNotNil("ref", ref)
+	if r.push != nil {
+		return *r.push, nil
+	}
+	q := r.query.Select("push")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `force` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Force) {
+			q = q.Arg("force", opts[i].Force)
+		}
+	}
+	q = q.Arg("ref", ref)
+	q = q.Arg("remoteRef", remoteRef)
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // Returns details of a ref.
 func (r *GitRepository) Ref(name string) *GitRef {
 	q := r.query.Select("ref")
@@ -4670,6 +5997,18 @@ func (r *GitRepository) Tags(ctx context.Context, opts ...GitRepositoryTagsOpts)
 	return response, q.Execute(ctx)
 }
 
+// The paths in the working tree that differ from the index or HEAD, including untracked files that aren't ignored.
+//
+// Only available for local repositories (see "host.gitRepository").
+func (r *GitRepository) UncommittedChanges(ctx context.Context) ([]string, error) {
+	q := r.query.Select("uncommittedChanges")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // Header to authenticate the remote with.
 func (r *GitRepository) WithAuthHeader(header *Secret) *GitRepository {
 	assertNotNil("header", header)
@@ -4711,6 +6050,12 @@ type HostDirectoryOpts struct {
 	Exclude []string
 	// Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
 	Include []string
+	// Exclude artifacts ignored by .gitignore files in the directory, and by .git/info/exclude.
+	Gitignore bool
+	// Exclude artifacts ignored by ignore files with these names (e.g., [".dockerignore"]).
+	//
+	// They are read from every subdirectory, using .gitignore syntax, except for .dockerignore, which is only read from the directory itself, using the syntax of docker build.
+	IgnoreFiles []string
 }
 
 // Accesses a directory on the host.
@@ -4725,6 +6070,14 @@ func (r *Host) Directory(path string, opts ...HostDirectoryOpts) *Directory {
 		if !querybuilder.IsZeroValue(opts[i].Include) {
 			q = q.Arg("include", opts[i].Include)
 		}
+		// `gitignore` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Gitignore) {
+			q = q.Arg("gitignore", opts[i].Gitignore)
+		}
+		// `ignoreFiles` optional argument
+		if !querybuilder.IsZeroValue(opts[i].IgnoreFiles) {
+			q = q.Arg("ignoreFiles", opts[i].IgnoreFiles)
+		}
 	}
 	q = q.Arg("path", path)
 
@@ -4743,6 +6096,18 @@ func (r *Host) File(path string) *File {
 	}
 }
 
+// Accesses a git repository on the host, such as the current checkout.
+//
+// Its .git directory is synced from the host, so any local commit can be checked out, without pushing it to a remote first.
+func (r *Host) GitRepository(path string) *GitRepository {
+	q := r.query.Select("gitRepository")
+	q = q.Arg("path", path)
+
+	return &GitRepository{
+		query: q,
+	}
+}
+
 // A unique identifier for this Host.
 func (r *Host) ID(ctx context.Context) (HostID, error) {
 	if r.id != nil {
@@ -5248,39 +6613,101 @@ func (r *ListTypeDef) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
-// A Dagger module.
-type Module struct {
+// A definition of a map type in a Module, with string keys.
+type MapTypeDef struct {
 	query *querybuilder.Selection
 
-	description *string
-	id          *ModuleID
-	name        *string
-	serve       *Void
-	sync        *ModuleID
+	id *MapTypeDefID
 }
-type WithModuleFunc func(r *Module) *Module
 
-// With calls the provided function with current Module.
-//
-// This is useful for reusability and readability by not breaking the calling chain.
-func (r *Module) With(f WithModuleFunc) *Module {
-	return f(r)
+func (r *MapTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *MapTypeDef {
+	return &MapTypeDef{
+		query: q,
+	}
 }
 
-func (r *Module) WithGraphQLQuery(q *querybuilder.Selection) *Module {
-	return &Module{
-		query: q,
+// A unique identifier for this MapTypeDef.
+func (r *MapTypeDef) ID(ctx context.Context) (MapTypeDefID, error) {
+	if r.id != nil {
+		return *r.id, nil
 	}
+	q := r.query.Select("id")
+
+	var response MapTypeDefID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
 }
 
-// The dependencies of the module.
-func (r *Module) Dependencies(ctx context.Context) ([]Module, error) {
-	q := r.query.Select("dependencies")
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *MapTypeDef) XXX_GraphQLType() string {
+	return "MapTypeDef"
+}
 
-	q = q.Select("id")
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *MapTypeDef) XXX_GraphQLIDType() string {
+	return "MapTypeDefID"
+}
 
-	type dependencies struct {
-		Id ModuleID
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *MapTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *MapTypeDef) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The type of the values in the map. Keys are always strings.
+func (r *MapTypeDef) ValueTypeDef() *TypeDef {
+	q := r.query.Select("valueTypeDef")
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
+// A Dagger module.
+type Module struct {
+	query *querybuilder.Selection
+
+	description *string
+	id          *ModuleID
+	name        *string
+	serve       *Void
+	sync        *ModuleID
+}
+type WithModuleFunc func(r *Module) *Module
+
+// With calls the provided function with current Module.
+//
+// This is useful for reusability and readability by not breaking the calling chain.
+func (r *Module) With(f WithModuleFunc) *Module {
+	return f(r)
+}
+
+func (r *Module) WithGraphQLQuery(q *querybuilder.Selection) *Module {
+	return &Module{
+		query: q,
+	}
+}
+
+// The dependencies of the module.
+func (r *Module) Dependencies(ctx context.Context) ([]Module, error) {
+	q := r.query.Select("dependencies")
+
+	q = q.Select("id")
+
+	type dependencies struct {
+		Id ModuleID
 	}
 
 	convert := func(fields []dependencies) []Module {
@@ -6557,6 +7984,18 @@ type GitOpts struct {
 	SSHKnownHosts string
 	// Set SSH auth socket
 	SSHAuthSocket *Socket
+	// Default number of commits of history to fetch into the .git directory of trees.
+	//
+	// Set to -1 to fetch the full history.
+	Depth int
+	// Only check out these paths of trees by default, relative to the root of the repository.
+	SparsePaths []string
+	// A default partial clone filter for trees (e.g., "blob:none", "blob:limit=1m" or "tree:0").
+	//
+	// Objects left out by the filter are fetched on demand, e.g. when checking out.
+	Filter string
+	// Set to false to skip checking out submodules in trees by default.
+	Submodules bool
 }
 
 // Queries a Git repository.
@@ -6579,6 +8018,22 @@ func (r *Client) Git(url string, opts ...GitOpts) *GitRepository {
 		if !querybuilder.IsZeroValue(opts[i].SSHAuthSocket) {
 			q = q.Arg("sshAuthSocket", opts[i].SSHAuthSocket)
 		}
+		// `depth` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Depth) {
+			q = q.Arg("depth", opts[i].Depth)
+		}
+		// `sparsePaths` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SparsePaths) {
+			q = q.Arg("sparsePaths", opts[i].SparsePaths)
+		}
+		// `filter` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Filter) {
+			q = q.Arg("filter", opts[i].Filter)
+		}
+		// `submodules` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Submodules) {
+			q = q.Arg("submodules", opts[i].Submodules)
+		}
 	}
 	q = q.Arg("url", url)
 
@@ -6600,6 +8055,16 @@ func (r *Client) Host() *Host {
 type HTTPOpts struct {
 	// A service which must be started before the URL is fetched.
 	ExperimentalServiceHost *Service
+	// Expected digest of the content (e.g., "sha256:..."). If set, the call fails if the downloaded content doesn't match.
+	Checksum string
+	// Secret used to populate the Authorization HTTP header.
+	AuthHeader *Secret
+	// Additional HTTP headers to send with the request.
+	Headers []HTTPHeader
+	// Name of the resulting file. Defaults to a digest of the URL.
+	Name string
+	// Permission given to the resulting file (e.g., 0600).
+	Permissions int
 }
 
 // Returns a file containing an http remote url content.
@@ -6610,6 +8075,26 @@ func (r *Client) HTTP(url string, opts ...HTTPOpts) *File {
 		if !querybuilder.IsZeroValue(opts[i].ExperimentalServiceHost) {
 			q = q.Arg("experimentalServiceHost", opts[i].ExperimentalServiceHost)
 		}
+		// `checksum` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Checksum) {
+			q = q.Arg("checksum", opts[i].Checksum)
+		}
+		// `authHeader` optional argument
+		if !querybuilder.IsZeroValue(opts[i].AuthHeader) {
+			q = q.Arg("authHeader", opts[i].AuthHeader)
+		}
+		// `headers` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Headers) {
+			q = q.Arg("headers", opts[i].Headers)
+		}
+		// `name` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Name) {
+			q = q.Arg("name", opts[i].Name)
+		}
+		// `permissions` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Permissions) {
+			q = q.Arg("permissions", opts[i].Permissions)
+		}
 	}
 	q = q.Arg("url", url)
 
@@ -6758,6 +8243,16 @@ func (r *Client) LoadFileFromID(id FileID) *File {
 	}
 }
 
+// Load a FileInfo from its ID.
+func (r *Client) LoadFileInfoFromID(id FileInfoID) *FileInfo {
+	q := r.query.Select("loadFileInfoFromID")
+	q = q.Arg("id", id)
+
+	return &FileInfo{
+		query: q,
+	}
+}
+
 // Load a FunctionArg from its ID.
 func (r *Client) LoadFunctionArgFromID(id FunctionArgID) *FunctionArg {
 	q := r.query.Select("loadFunctionArgFromID")
@@ -6808,6 +8303,16 @@ func (r *Client) LoadGeneratedCodeFromID(id GeneratedCodeID) *GeneratedCode {
 	}
 }
 
+// Load a GitCommit from its ID.
+func (r *Client) LoadGitCommitFromID(id GitCommitID) *GitCommit {
+	q := r.query.Select("loadGitCommitFromID")
+	q = q.Arg("id", id)
+
+	return &GitCommit{
+		query: q,
+	}
+}
+
 // Load a GitRef from its ID.
 func (r *Client) LoadGitRefFromID(id GitRefID) *GitRef {
 	q := r.query.Select("loadGitRefFromID")
@@ -6878,6 +8383,16 @@ func (r *Client) LoadListTypeDefFromID(id ListTypeDefID) *ListTypeDef {
 	}
 }
 
+// Load a MapTypeDef from its ID.
+func (r *Client) LoadMapTypeDefFromID(id MapTypeDefID) *MapTypeDef {
+	q := r.query.Select("loadMapTypeDefFromID")
+	q = q.Arg("id", id)
+
+	return &MapTypeDef{
+		query: q,
+	}
+}
+
 // Load a Module from its ID.
 func (r *Client) LoadModuleFromID(id ModuleID) *Module {
 	q := r.query.Select("loadModuleFromID")
@@ -6938,6 +8453,16 @@ func (r *Client) LoadScalarTypeDefFromID(id ScalarTypeDefID) *ScalarTypeDef {
 	}
 }
 
+// Load a SearchResult from its ID.
+func (r *Client) LoadSearchResultFromID(id SearchResultID) *SearchResult {
+	q := r.query.Select("loadSearchResultFromID")
+	q = q.Arg("id", id)
+
+	return &SearchResult{
+		query: q,
+	}
+}
+
 // Load a Secret from its ID.
 func (r *Client) LoadSecretFromID(id SecretID) *Secret {
 	q := r.query.Select("loadSecretFromID")
@@ -7019,6 +8544,26 @@ func (r *Client) LoadTypeDefFromID(id TypeDefID) *TypeDef {
 	}
 }
 
+// Load a UnionMemberTypeDef from its ID.
+func (r *Client) LoadUnionMemberTypeDefFromID(id UnionMemberTypeDefID) *UnionMemberTypeDef {
+	q := r.query.Select("loadUnionMemberTypeDefFromID")
+	q = q.Arg("id", id)
+
+	return &UnionMemberTypeDef{
+		query: q,
+	}
+}
+
+// Load a UnionTypeDef from its ID.
+func (r *Client) LoadUnionTypeDefFromID(id UnionTypeDefID) *UnionTypeDef {
+	q := r.query.Select("loadUnionTypeDefFromID")
+	q = q.Arg("id", id)
+
+	return &UnionTypeDef{
+		query: q,
+	}
+}
+
 // Create a new module.
 func (r *Client) Module() *Module {
 	q := r.query.Select("module")
@@ -7284,6 +8829,115 @@ func (r *ScalarTypeDef) SourceModuleName(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// A match of a pattern within a file.
+type SearchResult struct {
+	query *querybuilder.Selection
+
+	column      *int
+	filePath    *string
+	id          *SearchResultID
+	lineNumber  *int
+	matchedText *string
+}
+
+func (r *SearchResult) WithGraphQLQuery(q *querybuilder.Selection) *SearchResult {
+	return &SearchResult{
+		query: q,
+	}
+}
+
+// The 1-based byte offset of the match within its line.
+func (r *SearchResult) Column(ctx context.Context) (int, error) {
+	if r.column != nil {
+		return *r.column, nil
+	}
+	q := r.query.Select("column")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The path of the matching file, relative to the searched directory.
+func (r *SearchResult) FilePath(ctx context.Context) (string, error) {
+	if r.filePath != nil {
+		return *r.filePath, nil
+	}
+	q := r.query.Select("filePath")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this SearchResult.
+func (r *SearchResult) ID(ctx context.Context) (SearchResultID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response SearchResultID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *SearchResult) XXX_GraphQLType() string {
+	return "SearchResult"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *SearchResult) XXX_GraphQLIDType() string {
+	return "SearchResultID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *SearchResult) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *SearchResult) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The 1-based line number of the match.
+func (r *SearchResult) LineNumber(ctx context.Context) (int, error) {
+	if r.lineNumber != nil {
+		return *r.lineNumber, nil
+	}
+	q := r.query.Select("lineNumber")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The text that matched the pattern.
+func (r *SearchResult) MatchedText(ctx context.Context) (string, error) {
+	if r.matchedText != nil {
+		return *r.matchedText, nil
+	}
+	q := r.query.Select("matchedText")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A reference to a secret value, which can be handled more safely than the value itself.
 type Secret struct {
 	query *querybuilder.Selection
@@ -7293,6 +8947,14 @@ type Secret struct {
 	plaintext *string
 	uri       *string
 }
+type WithSecretFunc func(r *Secret) *Secret
+
+// With calls the provided function with current Secret.
+//
+// This is useful for reusability and readability by not breaking the calling chain.
+func (r *Secret) With(f WithSecretFunc) *Secret {
+	return f(r)
+}
 
 func (r *Secret) WithGraphQLQuery(q *querybuilder.Selection) *Secret {
 	return &Secret{
@@ -7300,6 +8962,15 @@ func (r *Secret) WithGraphQLQuery(q *querybuilder.Selection) *Secret {
 	}
 }
 
+// Derives a new secret from the standard base64 encoding of this secret's value, without exposing it.
+func (r *Secret) Base64() *Secret {
+	q := r.query.Select("base64")
+
+	return &Secret{
+		query: q,
+	}
+}
+
 // A unique identifier for this Secret.
 func (r *Secret) ID(ctx context.Context) (SecretID, error) {
 	if r.id != nil {
@@ -7340,6 +9011,18 @@ func (r *Secret) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// Derives a new secret from a single value of this secret's value, which must be a JSON document, without exposing it.
+//
+// Strings are selected as is, and other values as JSON.
+func (r *Secret) JSONPath(path string) *Secret {
+	q := r.query.Select("jsonPath")
+	q = q.Arg("path", path)
+
+	return &Secret{
+		query: q,
+	}
+}
+
 // The name of this secret.
 func (r *Secret) Name(ctx context.Context) (string, error) {
 	if r.name != nil {
@@ -7366,6 +9049,30 @@ func (r *Secret) Plaintext(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// SecretTemplateOpts contains options for Secret.Template
+type SecretTemplateOpts struct {
+	// Other secrets available to the template, by name.
+	Vars []SecretVariable
+}
+
+// Derives a new secret by rendering a Go text/template with the value of this secret and others, without exposing them.
+//
+// The value of this secret is available as {{.secret}}, and those of the secrets in vars by their names. The template may also use {{base64 .x}} and {{json .x}} to encode values.
+func (r *Secret) Template(format string, opts ...SecretTemplateOpts) *Secret {
+	q := r.query.Select("template")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `vars` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Vars) {
+			q = q.Arg("vars", opts[i].Vars)
+		}
+	}
+	q = q.Arg("format", format)
+
+	return &Secret{
+		query: q,
+	}
+}
+
 // The URI of this secret.
 func (r *Secret) URI(ctx context.Context) (string, error) {
 	if r.uri != nil {
@@ -7526,11 +9233,25 @@ func (r *Service) Ports(ctx context.Context) ([]Port, error) {
 	return convert(response), nil
 }
 
+// ServiceStartOpts contains options for Service.Start
+type ServiceStartOpts struct {
+	// Number of seconds to wait for the service's health checks to succeed. Zero means no timeout.
+	//
+	// When the timeout expires the service is killed and the call fails with an exec error reporting that it timed out, including the output captured so far.
+	Timeout int
+}
+
 // Start the service and wait for its health checks to succeed.
 //
 // Services bound to a Container do not need to be manually started.
-func (r *Service) Start(ctx context.Context) (*Service, error) {
+func (r *Service) Start(ctx context.Context, opts ...ServiceStartOpts) (*Service, error) {
 	q := r.query.Select("start")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+	}
 
 	var id ServiceID
 	if err := q.Bind(&id).Execute(ctx); err != nil {
@@ -7574,6 +9295,10 @@ type ServiceUpOpts struct {
 	Ports []PortForward
 	// Bind each tunnel port to a random port on the host.
 	Random bool
+	// Number of seconds to wait for the service's health checks to succeed. Zero means no timeout.
+	//
+	// When the timeout expires the service is killed and the call fails with an exec error reporting that it timed out, including the output captured so far.
+	Timeout int
 }
 
 // Creates a tunnel that forwards traffic from the caller's network to this service.
@@ -7591,6 +9316,10 @@ func (r *Service) Up(ctx context.Context, opts ...ServiceUpOpts) error {
 		if !querybuilder.IsZeroValue(opts[i].Random) {
 			q = q.Arg("random", opts[i].Random)
 		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
 	}
 
 	return q.Execute(ctx)
@@ -7896,6 +9625,15 @@ func (r *TypeDef) AsList() *ListTypeDef {
 	}
 }
 
+// If kind is MAP, the map-specific type definition. If kind is not MAP, this will be null.
+func (r *TypeDef) AsMap() *MapTypeDef {
+	q := r.query.Select("asMap")
+
+	return &MapTypeDef{
+		query: q,
+	}
+}
+
 // If kind is OBJECT, the object-specific type definition. If kind is not OBJECT, this will be null.
 func (r *TypeDef) AsObject() *ObjectTypeDef {
 	q := r.query.Select("asObject")
@@ -7914,6 +9652,15 @@ func (r *TypeDef) AsScalar() *ScalarTypeDef {
 	}
 }
 
+// If kind is UNION, the union-specific type definition. If kind is not UNION, this will be null.
+func (r *TypeDef) AsUnion() *UnionTypeDef {
+	q := r.query.Select("asUnion")
+
+	return &UnionTypeDef{
+		query: q,
+	}
+}
+
 // A unique identifier for this TypeDef.
 func (r *TypeDef) ID(ctx context.Context) (TypeDefID, error) {
 	if r.id != nil {
@@ -8027,6 +9774,8 @@ type TypeDefWithEnumValueOpts struct {
 	Description string
 	// The source map for the enum value definition.
 	SourceMap *SourceMap
+	// If set, marks the enum value as deprecated with the given reason.
+	Deprecated string
 }
 
 // Adds a static value for an Enum TypeDef, failing if the type is not an enum.
@@ -8041,6 +9790,10 @@ func (r *TypeDef) WithEnumValue(value string, opts ...TypeDefWithEnumValueOpts)
 		if !querybuilder.IsZeroValue(opts[i].SourceMap) {
 			q = q.Arg("sourceMap", opts[i].SourceMap)
 		}
+		// `deprecated` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Deprecated) {
+			q = q.Arg("deprecated", opts[i].Deprecated)
+		}
 	}
 	q = q.Arg("value", value)
 
@@ -8055,6 +9808,8 @@ type TypeDefWithFieldOpts struct {
 	Description string
 	// The source map for the field definition.
 	SourceMap *SourceMap
+	// If set, marks the field as deprecated with the given reason.
+	Deprecated string
 }
 
 // Adds a static field for an Object TypeDef, failing if the type is not an object.
@@ -8070,6 +9825,10 @@ func (r *TypeDef) WithField(name string, typeDef *TypeDef, opts ...TypeDefWithFi
 		if !querybuilder.IsZeroValue(opts[i].SourceMap) {
 			q = q.Arg("sourceMap", opts[i].SourceMap)
 		}
+		// `deprecated` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Deprecated) {
+			q = q.Arg("deprecated", opts[i].Deprecated)
+		}
 	}
 	q = q.Arg("name", name)
 	q = q.Arg("typeDef", typeDef)
@@ -8138,6 +9897,19 @@ func (r *TypeDef) WithListOf(elementType *TypeDef) *TypeDef {
 	}
 }
 
+// Returns a TypeDef of kind Map with the provided type for its values.
+//
+// Map keys are always strings. Maps are represented as a JSON object in the API.
+func (r *TypeDef) WithMapOf(valueType *TypeDef) *TypeDef {
+	assertNotNil("valueType", valueType)
+	q := r.query.Select("withMapOf")
+	q = q.Arg("valueType", valueType)
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
 // TypeDefWithObjectOpts contains options for TypeDef.WithObject
 type TypeDefWithObjectOpts struct {
 	Description string
@@ -8198,6 +9970,295 @@ func (r *TypeDef) WithScalar(name string, opts ...TypeDefWithScalarOpts) *TypeDe
 	}
 }
 
+// TypeDefWithUnionOpts contains options for TypeDef.WithUnion
+type TypeDefWithUnionOpts struct {
+	// A doc string for the union, if any
+	Description string
+	// The source map for the union definition.
+	SourceMap *SourceMap
+}
+
+// Returns a TypeDef of kind Union with the provided name.
+//
+// Members are added with withUnionMember. Union values are represented as a JSON object in the API, with a single key naming the member that is set.
+func (r *TypeDef) WithUnion(name string, opts ...TypeDefWithUnionOpts) *TypeDef {
+	q := r.query.Select("withUnion")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `description` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Description) {
+			q = q.Arg("description", opts[i].Description)
+		}
+		// `sourceMap` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SourceMap) {
+			q = q.Arg("sourceMap", opts[i].SourceMap)
+		}
+	}
+	q = q.Arg("name", name)
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
+// TypeDefWithUnionMemberOpts contains options for TypeDef.WithUnionMember
+type TypeDefWithUnionMemberOpts struct {
+	// A doc string for the member, if any
+	Description string
+}
+
+// Adds a member to a Union TypeDef, failing if the type is not a union.
+func (r *TypeDef) WithUnionMember(name string, typeDef *TypeDef, opts ...TypeDefWithUnionMemberOpts) *TypeDef {
+	assertNotNil("typeDef", typeDef)
+	q := r.query.Select("withUnionMember")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `description` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Description) {
+			q = q.Arg("description", opts[i].Description)
+		}
+	}
+	q = q.Arg("name", name)
+	q = q.Arg("typeDef", typeDef)
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
+// A definition of a member of a union defined in a Module.
+type UnionMemberTypeDef struct {
+	query *querybuilder.Selection
+
+	description *string
+	id          *UnionMemberTypeDefID
+	name        *string
+}
+
+func (r *UnionMemberTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *UnionMemberTypeDef {
+	return &UnionMemberTypeDef{
+		query: q,
+	}
+}
+
+// A doc string for the member, if any.
+func (r *UnionMemberTypeDef) Description(ctx context.Context) (string, error) {
+	if r.description != nil {
+		return *r.description, nil
+	}
+	q := r.query.Select("description")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this UnionMemberTypeDef.
+func (r *UnionMemberTypeDef) ID(ctx context.Context) (UnionMemberTypeDefID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response UnionMemberTypeDefID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *UnionMemberTypeDef) XXX_GraphQLType() string {
+	return "UnionMemberTypeDef"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *UnionMemberTypeDef) XXX_GraphQLIDType() string {
+	return "UnionMemberTypeDefID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *UnionMemberTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *UnionMemberTypeDef) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The name of the member, used as its tag in union values.
+func (r *UnionMemberTypeDef) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.query.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The type of the member.
+func (r *UnionMemberTypeDef) TypeDef() *TypeDef {
+	q := r.query.Select("typeDef")
+
+	return &TypeDef{
+		query: q,
+	}
+}
+
+// A definition of a tagged union in a Module.
+//
+// A union value is a JSON object with exactly one key, naming the member that is
+// set, e.g. {"directory": "<id>"}.
+type UnionTypeDef struct {
+	query *querybuilder.Selection
+
+	description *string
+	id          *UnionTypeDefID
+	name        *string
+}
+
+func (r *UnionTypeDef) WithGraphQLQuery(q *querybuilder.Selection) *UnionTypeDef {
+	return &UnionTypeDef{
+		query: q,
+	}
+}
+
+// A doc string for the union, if any.
+func (r *UnionTypeDef) Description(ctx context.Context) (string, error) {
+	if r.description != nil {
+		return *r.description, nil
+	}
+	q := r.query.Select("description")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this UnionTypeDef.
+func (r *UnionTypeDef) ID(ctx context.Context) (UnionTypeDefID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response UnionTypeDefID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *UnionTypeDef) XXX_GraphQLType() string {
+	return "UnionTypeDef"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *UnionTypeDef) XXX_GraphQLIDType() string {
+	return "UnionTypeDefID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *UnionTypeDef) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *UnionTypeDef) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The members of the union, exactly one of which is set in a value.
+func (r *UnionTypeDef) Members(ctx context.Context) ([]UnionMemberTypeDef, error) {
+	q := r.query.Select("members")
+
+	q = q.Select("id")
+
+	type members struct {
+		Id UnionMemberTypeDefID
+	}
+
+	convert := func(fields []members) []UnionMemberTypeDef {
+		out := []UnionMemberTypeDef{}
+
+		for i := range fields {
+			val := UnionMemberTypeDef{id: &fields[i].Id}
+			val.query = q.Root().Select("loadUnionMemberTypeDefFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []members
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// The name of the union.
+func (r *UnionTypeDef) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.query.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The location of this union declaration.
+func (r *UnionTypeDef) SourceMap() *SourceMap {
+	q := r.query.Select("sourceMap")
+
+	return &SourceMap{
+		query: q,
+	}
+}
+
+// File format of an archive.
+type ArchiveFormat string
+
+func (ArchiveFormat) IsEnum() {}
+
+const (
+	// An uncompressed tar archive
+	ArchiveFormatTar ArchiveFormat = "TAR"
+
+	// A gzip-compressed tar archive
+	ArchiveFormatTarGz ArchiveFormat = "TAR_GZ"
+
+	// A zstd-compressed tar archive
+	ArchiveFormatTarZstd ArchiveFormat = "TAR_ZSTD"
+
+	// A zip archive
+	ArchiveFormatZip ArchiveFormat = "ZIP"
+)
+
 // Sharing mode of the cache volume.
 type CacheSharingMode string
 
@@ -8214,6 +10275,59 @@ const (
 	CacheSharingModeShared CacheSharingMode = "SHARED"
 )
 
+// The type of a filesystem entry.
+type FileType string
+
+func (FileType) IsEnum() {}
+
+const (
+	// A directory
+	FileTypeDirectory FileType = "DIRECTORY"
+
+	// A regular file
+	FileTypeFile FileType = "FILE"
+
+	// Anything else, such as a device or a named pipe
+	FileTypeOther FileType = "OTHER"
+
+	// A symbolic link
+	FileTypeSymlink FileType = "SYMLINK"
+)
+
+// How the results of calls to a function are cached.
+type FunctionCachePolicy string
+
+func (FunctionCachePolicy) IsEnum() {}
+
+const (
+	// Calls are cached for the duration of the session that made them, or across sessions for calls made by the engine itself.
+	FunctionCachePolicyDefault FunctionCachePolicy = "DEFAULT"
+
+	// Calls are never cached: the function runs every time it's called.
+	FunctionCachePolicyNever FunctionCachePolicy = "NEVER"
+
+	// Calls are cached for the duration of the session that made them.
+	FunctionCachePolicyPerSession FunctionCachePolicy = "PER_SESSION"
+
+	// Calls are cached across sessions, for the time to live of the function.
+	//
+	// Cached results are dropped when the current time window of that length ends, so they're never older than the time to live.
+	FunctionCachePolicyTtl FunctionCachePolicy = "TTL"
+)
+
+// An attestation to publish along with an image.
+type ImageAttestation string
+
+func (ImageAttestation) IsEnum() {}
+
+const (
+	// SLSA v0.2 provenance, listing the calls that built the image and its base images and sources.
+	ImageAttestationSlsaProvenance ImageAttestation = "SLSA_PROVENANCE"
+
+	// SPDX 2.3 SBOM, listing the Alpine and Debian packages installed in the image, and its base images and sources.
+	ImageAttestationSpdxSbom ImageAttestation = "SPDX_SBOM"
+)
+
 // Compression algorithm to use for image layers.
 type ImageLayerCompression string
 
@@ -8313,6 +10427,11 @@ const (
 	// Always paired with a ListTypeDef.
 	TypeDefKindListKind TypeDefKind = "LIST_KIND"
 
+	// A map of string keys to values all having the same type.
+	//
+	// Always paired with a MapTypeDef. Maps are represented as a JSON object in the GraphQL schema.
+	TypeDefKindMapKind TypeDefKind = "MAP_KIND"
+
 	// A named type defined in the GraphQL schema, with fields and functions.
 	//
 	// Always paired with an ObjectTypeDef.
@@ -8324,6 +10443,11 @@ const (
 	// A string value.
 	TypeDefKindStringKind TypeDefKind = "STRING_KIND"
 
+	// A tagged union of values of different types, exactly one of which is set.
+	//
+	// Always paired with a UnionTypeDef. Unions are represented as a JSON object with a single key in the GraphQL schema.
+	TypeDefKindUnionKind TypeDefKind = "UNION_KIND"
+
 	// A special kind used to signify that no value is returned.
 	//
 	// This is used for functions that have no return value. The outer TypeDef specifying this Kind is always Optional, as the Void is never actually represented.
@@ -96,12 +96,16 @@ func (s *Selection) marshalArguments(ctx context.Context) error {
 }
 
 func (s *Selection) Build(ctx context.Context) (string, error) {
+	return s.build(ctx, "query")
+}
+
+func (s *Selection) build(ctx context.Context, operation string) (string, error) {
 	if err := s.marshalArguments(ctx); err != nil {
 		return "", err
 	}
 
 	var b strings.Builder
-	b.WriteString("query")
+	b.WriteString(operation)
 
 	path := s.path()
 
@@ -197,6 +201,51 @@ func (s *Selection) Execute(ctx context.Context) error {
 	return s.unpack(response)
 }
 
+// SubscriptionClient is a graphql.Client that can execute subscriptions.
+type SubscriptionClient interface {
+	graphql.Client
+
+	// Subscribe executes a subscription, calling fn with the data of each
+	// response as soon as it's received.
+	Subscribe(ctx context.Context, req *graphql.Request, fn func(data json.RawMessage) error) error
+}
+
+// Subscribe executes the selection as a subscription: each response holds one
+// item of the selected field's result, i.e. an element of a list or a chunk
+// of a string, as soon as it's available. Bound values are set to each item
+// in turn, before calling fn.
+func (s *Selection) Subscribe(ctx context.Context, fn func() error) error {
+	if s.client == nil {
+		debug.PrintStack()
+		return fmt.Errorf("no client configured for selection")
+	}
+	client, ok := s.client.(SubscriptionClient)
+	if !ok {
+		return fmt.Errorf("client does not support subscriptions")
+	}
+
+	subscription, err := s.build(ctx, "subscription")
+	if err != nil {
+		return err
+	}
+
+	return client.Subscribe(ctx,
+		&graphql.Request{
+			Query: subscription,
+		},
+		func(data json.RawMessage) error {
+			var response any
+			if err := json.Unmarshal(data, &response); err != nil {
+				return err
+			}
+			if err := s.unpack(response); err != nil {
+				return err
+			}
+			return fn()
+		},
+	)
+}
+
 type argument struct {
 	value any
 
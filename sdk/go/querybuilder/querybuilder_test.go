@@ -3,8 +3,10 @@ package querybuilder
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 
+	"github.com/Khan/genqlient/graphql"
 	"github.com/stretchr/testify/require"
 )
 
@@ -200,3 +202,52 @@ func TestUnpackSiblings(t *testing.T) {
 	require.NoError(t, root.unpack(response))
 	require.EqualValues(t, data{"TEST", 12, true}, contents)
 }
+
+type subscriptionClient struct {
+	query     string
+	responses []string
+}
+
+func (c *subscriptionClient) MakeRequest(context.Context, *graphql.Request, *graphql.Response) error {
+	return errors.New("not implemented")
+}
+
+func (c *subscriptionClient) Subscribe(_ context.Context, req *graphql.Request, fn func(json.RawMessage) error) error {
+	c.query = req.Query
+	for _, res := range c.responses {
+		if err := fn(json.RawMessage(res)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSubscribe(t *testing.T) {
+	client := &subscriptionClient{
+		responses: []string{
+			`{"foo":{"bar":"one"}}`,
+			`{"foo":{"bar":"two"}}`,
+		},
+	}
+
+	var item string
+	var items []string
+	err := Query().Client(client).
+		Select("foo").Arg("hello", "world").
+		Select("bar").
+		Bind(&item).
+		Subscribe(context.Background(), func() error {
+			items = append(items, item)
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, `subscription{foo(hello:"world"){bar}}`, client.query)
+	require.Equal(t, []string{"one", "two"}, items)
+}
+
+func TestSubscribeUnsupported(t *testing.T) {
+	err := Query().Client(graphql.NewClient("http://localhost", nil)).
+		Select("foo").
+		Subscribe(context.Background(), func() error { return nil })
+	require.ErrorContains(t, err, "client does not support subscriptions")
+}
@@ -148,7 +148,8 @@ public class DaggerModuleAnnotationProcessor extends AbstractProcessor {
                                   ((ExecutableElement) elt).getReturnType().toString(),
                                   ((ExecutableElement) elt).getReturnType().getKind().name()),
                               parseParameters((ExecutableElement) elt)
-                                  .toArray(new ParameterInfo[0]))));
+                                  .toArray(new ParameterInfo[0]),
+                              "")));
             } else if (constructorDefs.size() > 1) {
               // There's more than one non-empty constructor, but Dagger only supports to expose a
               // single one
@@ -206,7 +207,8 @@ public class DaggerModuleAnnotationProcessor extends AbstractProcessor {
                                 fqName,
                                 parseFunctionDescription(elt),
                                 new TypeInfo(tm.toString(), tk.name()),
-                                parameterInfos.toArray(new ParameterInfo[parameterInfos.size()]));
+                                parameterInfos.toArray(new ParameterInfo[parameterInfos.size()]),
+                                moduleFunction.cache());
                         return functionInfo;
                       })
                   .toList();
@@ -623,6 +625,9 @@ public class DaggerModuleAnnotationProcessor extends AbstractProcessor {
     if (isNotBlank(fnInfo.description())) {
       code.add("\n                    .withDescription($S)", fnInfo.description());
     }
+    if (isNotBlank(fnInfo.cache())) {
+      code.add("\n                    ").add(withCachePolicy(fnInfo.cache()));
+    }
     for (var parameterInfo : fnInfo.parameters()) {
       code.add("\n                    .withArg($S, ", parameterInfo.name())
           .add(typeDef(parameterInfo.type()));
@@ -654,6 +659,19 @@ public class DaggerModuleAnnotationProcessor extends AbstractProcessor {
     return code.build();
   }
 
+  private static CodeBlock withCachePolicy(String cache) {
+    return switch (cache) {
+      case "never" -> CodeBlock.of(".withCachePolicy($T.NEVER)", FunctionCachePolicy.class);
+      case "session" -> CodeBlock.of(".withCachePolicy($T.PER_SESSION)", FunctionCachePolicy.class);
+      default ->
+          CodeBlock.of(
+              ".withCachePolicy($T.TTL, new $T.WithCachePolicyArguments().withTimeToLive($S))",
+              FunctionCachePolicy.class,
+              io.dagger.client.Function.class,
+              cache);
+    };
+  }
+
   public static TypeKind getTypeKind(String name) {
     try {
       TypeKind kind = TypeKind.valueOf(name);
@@ -7,6 +7,7 @@ import io.dagger.client.Dagger;
 import io.dagger.client.DaggerQueryException;
 import io.dagger.client.Directory;
 import io.dagger.client.Function;
+import io.dagger.client.FunctionCachePolicy;
 import io.dagger.client.FunctionCall;
 import io.dagger.client.FunctionCallArgValue;
 import io.dagger.client.JSON;
@@ -134,6 +135,10 @@ public class Entrypoint {
                         dag.typeDef().withKind(TypeDefKind.FLOAT_KIND))
                         .withArg("a", dag.typeDef().withKind(TypeDefKind.FLOAT_KIND))
                         .withArg("b", dag.typeDef().withKind(TypeDefKind.FLOAT_KIND)))
+                .withFunction(
+                    dag.function("latestVersion",
+                        dag.typeDef().withKind(TypeDefKind.STRING_KIND))
+                        .withCachePolicy(FunctionCachePolicy.TTL, new Function.WithCachePolicyArguments().withTimeToLive("10m")))
                 .withField("source", dag.typeDef().withObject("Directory"), new TypeDef.WithFieldArguments().withDescription("Project source directory"))
                 .withField("version", dag.typeDef().withKind(TypeDefKind.STRING_KIND))
                 .withConstructor(
@@ -277,6 +282,12 @@ public class Entrypoint {
           }
           float res = obj.addFloat(a, b);
           return JsonConverter.toJSON(res);
+        } else if (fnName.equals("latestVersion")) {
+          Class clazz = Class.forName("io.dagger.java.module.DaggerJava");
+          DaggerJava obj = (DaggerJava) JsonConverter.fromJSON(dag, parentJson, clazz);
+          obj.setClient(dag);
+          String res = obj.latestVersion();
+          return JsonConverter.toJSON(res);
         } if (fnName.equals("")) {
           Directory source = null;
           if (inputArgs.get("source") != null) {
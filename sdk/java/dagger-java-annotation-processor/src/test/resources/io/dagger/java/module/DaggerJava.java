@@ -134,4 +134,9 @@ public class DaggerJava extends AbstractModule {
   public float addFloat(float a, float b) {
     return a + b;
   }
+
+  @Function(cache = "10m")
+  public String latestVersion() {
+    return "1.23.2";
+  }
 }
@@ -11,4 +11,10 @@ public @interface Function {
   String value() default "";
 
   String description() default "";
+
+  /**
+   * How the results of calls to the function are cached: "never", "session" for the duration of
+   * the session, or a time to live such as "10m". Defaults to the engine's caching.
+   */
+  String cache() default "";
 }
@@ -24,6 +24,24 @@ class Function_ extends Client\AbstractObject implements Client\IdAble
         return (array)$this->queryLeaf($leafQueryBuilder, 'args');
     }
 
+    /**
+     * The policy for caching the results of calls to the function.
+     */
+    public function cachePolicy(): FunctionCachePolicy
+    {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('cachePolicy');
+        return \Dagger\FunctionCachePolicy::from((string)$this->queryLeaf($leafQueryBuilder, 'cachePolicy'));
+    }
+
+    /**
+     * Number of seconds for which the results of calls to the function are cached, if its cache policy is TTL.
+     */
+    public function cacheTTLSeconds(): int
+    {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('cacheTTLSeconds');
+        return (int)$this->queryLeaf($leafQueryBuilder, 'cacheTTLSeconds');
+    }
+
     /**
      * A doc string for the function, if any.
      */
@@ -102,6 +120,19 @@ class Function_ extends Client\AbstractObject implements Client\IdAble
         return new \Dagger\Function_($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Returns the function with the given cache policy.
+     */
+    public function withCachePolicy(FunctionCachePolicy $policy, ?string $timeToLive = ''): Function_
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('withCachePolicy');
+        $innerQueryBuilder->setArgument('policy', $policy);
+        if (null !== $timeToLive) {
+        $innerQueryBuilder->setArgument('timeToLive', $timeToLive);
+        }
+        return new \Dagger\Function_($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Returns the function with the given doc string.
      */
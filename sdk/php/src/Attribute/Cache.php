@@ -0,0 +1,19 @@
+<?php
+
+declare(strict_types=1);
+
+namespace Dagger\Attribute;
+
+#[\Attribute(\Attribute::TARGET_METHOD)]
+final readonly class Cache
+{
+    /**
+     * @param string $policy how the results of calls to the function are cached:
+     * "never", "session" for the duration of the session,
+     * or a time to live such as "10m".
+     */
+    public function __construct(
+        public string $policy,
+    ) {
+    }
+}
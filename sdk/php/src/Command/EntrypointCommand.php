@@ -5,6 +5,7 @@ declare(strict_types=1);
 namespace Dagger\Command;
 
 use Dagger;
+use Dagger\FunctionCachePolicy;
 use Dagger\Service\DecodesValue;
 use Dagger\Service\FindsDaggerObjects;
 use Dagger\Service\FindsSrcDirectory;
@@ -72,6 +73,10 @@ class EntrypointCommand extends Command
                     $func = $func->withDescription($daggerFunction->description);
                 }
 
+                if ($daggerFunction->cache !== null) {
+                    $func = $this->withCachePolicy($func, $daggerFunction->cache);
+                }
+
                 foreach ($daggerFunction->arguments as $argument) {
                     $func = $func->withArg(
                         name: $argument->name,
@@ -100,6 +105,17 @@ class EntrypointCommand extends Command
         return Command::SUCCESS;
     }
 
+    private function withCachePolicy(
+        Dagger\Function_ $func,
+        string $cache,
+    ): Dagger\Function_ {
+        return match ($cache) {
+            'never' => $func->withCachePolicy(FunctionCachePolicy::NEVER),
+            'session' => $func->withCachePolicy(FunctionCachePolicy::PER_SESSION),
+            default => $func->withCachePolicy(FunctionCachePolicy::TTL, $cache),
+        };
+    }
+
     private function callFunctionOnParent(
         OutputInterface $output,
         Dagger\FunctionCall $functionCall,
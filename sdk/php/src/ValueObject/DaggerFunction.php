@@ -16,6 +16,7 @@ final readonly class DaggerFunction
         public ?string $description,
         public array $arguments,
         public ListOfType|Type $returnType,
+        public ?string $cache = null,
     ) {
     }
 
@@ -42,12 +43,16 @@ final readonly class DaggerFunction
             ?->newInstance()
             ?->description;
 
+        $cache = (current($method
+            ->getAttributes(Attribute\Cache::class)) ?: null)
+            ?->newInstance()
+            ?->policy;
+
         $parameters = array_map(
             fn($p) => Argument::fromReflection($p),
             $method->getParameters(),
         );
 
-
         return $method->isConstructor() ?
             new self(
                 name: '',
@@ -60,6 +65,7 @@ final readonly class DaggerFunction
                 description: $description ?? $daggerFunction?->description,
                 arguments: $parameters,
                 returnType: self::getReturnType($method),
+                cache: $cache,
             );
     }
 
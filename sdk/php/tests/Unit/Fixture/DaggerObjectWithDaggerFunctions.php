@@ -4,6 +4,7 @@ declare(strict_types=1);
 
 namespace Dagger\Tests\Unit\Fixture;
 
+use Dagger\Attribute\Cache;
 use Dagger\Attribute\DaggerFunction;
 use Dagger\Attribute\DaggerObject;
 use Dagger\Attribute\DefaultPath;
@@ -113,6 +114,13 @@ final class DaggerObjectWithDaggerFunctions
     ): void {
     }
 
+    #[DaggerFunction]
+    #[Cache('10m')]
+    public function cachedString(): string
+    {
+        return 'cached for ten minutes';
+    }
+
     public function notADaggerFunction(): string {
         return 'DaggerFunctions MUST have the DaggerFunction Attribute';
     }
@@ -336,6 +344,13 @@ final class DaggerObjectWithDaggerFunctions
                     ],
                     new ValueObject\Type('void'),
                 ),
+                new ValueObject\DaggerFunction(
+                    'cachedString',
+                    null,
+                    [],
+                    new ValueObject\Type('string'),
+                    '10m',
+                ),
             ]
         );
     }
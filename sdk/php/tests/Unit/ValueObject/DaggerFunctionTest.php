@@ -188,5 +188,19 @@ class DaggerFunctionTest extends TestCase
                 'explicitlyOptionalFile',
             ),
         ];
+
+        yield 'cache policy' => [
+            new DaggerFunction(
+                'cachedString',
+                null,
+                [],
+                new Type('string'),
+                '10m',
+            ),
+            new ReflectionMethod(
+                DaggerObjectWithDaggerFunctions::class,
+                'cachedString',
+            ),
+        ];
     }
 }
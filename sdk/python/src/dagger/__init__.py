@@ -27,6 +27,7 @@ from dagger._connection import close as close
 
 # Modules.
 from dagger.mod import DefaultPath as DefaultPath
+from dagger.mod import Deprecated as Deprecated
 from dagger.mod import Doc as Doc
 from dagger.mod import Ignore as Ignore
 from dagger.mod import Enum as Enum
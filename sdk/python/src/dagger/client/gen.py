@@ -145,6 +145,11 @@ class ListTypeDefID(Scalar):
     object of type ListTypeDef."""
 
 
+class MapTypeDefID(Scalar):
+    """The `MapTypeDefID` scalar type represents an identifier for an object
+    of type MapTypeDef."""
+
+
 class ModuleID(Scalar):
     """The `ModuleID` scalar type represents an identifier for an object
     of type Module."""
@@ -211,6 +216,16 @@ class TypeDefID(Scalar):
     of type TypeDef."""
 
 
+class UnionMemberTypeDefID(Scalar):
+    """The `UnionMemberTypeDefID` scalar type represents an identifier for an
+    object of type UnionMemberTypeDef."""
+
+
+class UnionTypeDefID(Scalar):
+    """The `UnionTypeDefID` scalar type represents an identifier for an
+    object of type UnionTypeDef."""
+
+
 class Void(Scalar):
     """The absence of a value.  A Null Void is used as a placeholder for
     resolvers that do not return anything."""
@@ -229,6 +244,28 @@ class CacheSharingMode(Enum):
     """Shares the cache volume amongst many build pipelines"""
 
 
+class FunctionCachePolicy(Enum):
+    """How the results of calls to a function are cached."""
+
+    DEFAULT = "DEFAULT"
+    """Calls are cached for the duration of the session that made them, or
+    across sessions for calls made by the engine itself."""
+
+    NEVER = "NEVER"
+    """Calls are never cached: the function runs every time it's called."""
+
+    PER_SESSION = "PER_SESSION"
+    """Calls are cached for the duration of the session that made them."""
+
+    TTL = "TTL"
+    """Calls are cached across sessions, for the time to live of the
+    function.
+
+    Cached results are dropped when the current time window of that length
+    ends, so they're never older than the time to live.
+    """
+
+
 class ImageLayerCompression(Enum):
     """Compression algorithm to use for image layers."""
 
@@ -313,6 +350,12 @@ class TypeDefKind(Enum):
     Always paired with a ListTypeDef.
     """
 
+    MAP_KIND = "MAP_KIND"
+    """A map of string keys to values all having the same type.
+
+    Always paired with a MapTypeDef. Maps are represented as a JSON object in the GraphQL schema.
+    """
+
     OBJECT_KIND = "OBJECT_KIND"
     """A named type defined in the GraphQL schema, with fields and functions.
 
@@ -325,6 +368,12 @@ class TypeDefKind(Enum):
     STRING_KIND = "STRING_KIND"
     """A string value."""
 
+    UNION_KIND = "UNION_KIND"
+    """A tagged union of values of different types, exactly one of which is set.
+
+    Always paired with a UnionTypeDef. Unions are represented as a JSON object with a single key in the GraphQL schema.
+    """
+
     VOID_KIND = "VOID_KIND"
     """A special kind used to signify that no value is returned.
 
@@ -3525,6 +3574,27 @@ class EnumTypeDef(Type):
 class EnumValueTypeDef(Type):
     """A definition of a value in a custom enum defined in a Module."""
 
+    async def deprecated(self) -> str | None:
+        """The reason this enum value is deprecated, if it is.
+
+        Returns
+        -------
+        str | None
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("deprecated", _args)
+        return await _ctx.execute(str | None)
+
     async def description(self) -> str:
         """A doc string for the enum value, if any.
 
@@ -3724,6 +3794,27 @@ class FieldTypeDef(Type):
     object whose value is computed by invoking code (and can accept
     arguments)."""
 
+    async def deprecated(self) -> str | None:
+        """The reason this field is deprecated, if it is.
+
+        Returns
+        -------
+        str | None
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("deprecated", _args)
+        return await _ctx.execute(str | None)
+
     async def description(self) -> str:
         """A doc string for the field, if any.
 
@@ -4050,6 +4141,68 @@ class Function(Type):
             for v in _ids
         ]
 
+    async def cache_policy(self) -> FunctionCachePolicy:
+        """The policy for caching the results of calls to the function.
+
+        Returns
+        -------
+        FunctionCachePolicy
+            How the results of calls to a function are cached.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("cachePolicy", _args)
+        return await _ctx.execute(FunctionCachePolicy)
+
+    async def cache_ttl_seconds(self) -> int:
+        """Number of seconds for which the results of calls to the function are
+        cached, if its cache policy is TTL.
+
+        Returns
+        -------
+        int
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("cacheTTLSeconds", _args)
+        return await _ctx.execute(int)
+
+    async def deprecated(self) -> str | None:
+        """The reason this function is deprecated, if it is.
+
+        Returns
+        -------
+        str | None
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("deprecated", _args)
+        return await _ctx.execute(str | None)
+
     async def description(self) -> str:
         """A doc string for the function, if any.
 
@@ -4128,6 +4281,26 @@ class Function(Type):
         _ctx = self._select("sourceMap", _args)
         return SourceMap(_ctx)
 
+    async def streaming(self) -> bool:
+        """Whether the function returns its result incrementally, as a stream
+        of items delivered to subscribers as they are available.
+
+        Returns
+        -------
+        bool
+            The `Boolean` scalar type represents `true` or `false`.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("streaming", _args)
+        return await _ctx.execute(bool)
+
     def with_arg(
         self,
         name: str,
@@ -4138,6 +4311,12 @@ class Function(Type):
         default_path: str | None = "",
         ignore: list[str] | None = None,
         source_map: "SourceMap | None" = None,
+        deprecated: str | None = None,
+        pattern: str | None = "",
+        min: float | None = None,
+        max: float | None = None,
+        min_length: int | None = None,
+        allowed_values: list[str] | None = None,
     ) -> Self:
         """Returns the function with the provided argument
 
@@ -4158,6 +4337,24 @@ class Function(Type):
         ignore:
             Patterns to ignore when loading the contextual argument value.
         source_map:
+        deprecated:
+            If set, marks the argument as deprecated with the given reason.
+            Required arguments, without a default value, can't be deprecated.
+        pattern:
+            If the argument is a String or a list of strings, a regular
+            expression (RE2 syntax) that values must match.
+        min:
+            If the argument is an Integer or Float, or a list of those, the
+            minimum value, inclusive.
+        max:
+            If the argument is an Integer or Float, or a list of those, the
+            maximum value, inclusive.
+        min_length:
+            If the argument is a String or a List, the minimum number of
+            characters or elements.
+        allowed_values:
+            If the argument is a String or a list of strings, the only values
+            that are accepted.
         """
         _args = [
             Arg("name", name),
@@ -4167,10 +4364,56 @@ class Function(Type):
             Arg("defaultPath", default_path, ""),
             Arg("ignore", () if ignore is None else ignore, ()),
             Arg("sourceMap", source_map, None),
+            Arg("deprecated", deprecated, None),
+            Arg("pattern", pattern, ""),
+            Arg("min", min, None),
+            Arg("max", max, None),
+            Arg("minLength", min_length, None),
+            Arg(
+                "allowedValues", () if allowed_values is None else allowed_values, ()
+            ),
         ]
         _ctx = self._select("withArg", _args)
         return Function(_ctx)
 
+    def with_cache_policy(
+        self,
+        policy: FunctionCachePolicy,
+        *,
+        time_to_live: str | None = "",
+    ) -> Self:
+        """Returns the function with the given cache policy.
+
+        Parameters
+        ----------
+        policy:
+            How the results of calls to the function are cached.
+        time_to_live:
+            For the TTL policy, how long results are cached, as a duration
+            (e.g., "10m", "1h30m").
+        """
+        _args = [
+            Arg("policy", policy),
+            Arg("timeToLive", time_to_live, ""),
+        ]
+        _ctx = self._select("withCachePolicy", _args)
+        return Function(_ctx)
+
+    def with_deprecated(self, reason: str) -> Self:
+        """Returns the function marked as deprecated.
+
+        Parameters
+        ----------
+        reason:
+            The reason the function is deprecated, usually including what to
+            use instead.
+        """
+        _args = [
+            Arg("reason", reason),
+        ]
+        _ctx = self._select("withDeprecated", _args)
+        return Function(_ctx)
+
     def with_description(self, description: str) -> Self:
         """Returns the function with the given doc string.
 
@@ -4199,6 +4442,18 @@ class Function(Type):
         _ctx = self._select("withSourceMap", _args)
         return Function(_ctx)
 
+    def with_streaming(self) -> Self:
+        """Returns the function marked as streaming its result.
+
+        Subscribers to the function receive the elements of its list result, or
+        the chunks of its string result, as soon as they are returned with
+        returnItem. Queries still receive the full result once the function is
+        done.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("withStreaming", _args)
+        return Function(_ctx)
+
     def with_(self, cb: Callable[["Function"], "Function"]) -> "Function":
         """Call the provided callable with current Function.
 
@@ -4213,6 +4468,28 @@ class FunctionArg(Type):
     argument at function definition time, not an argument passed at
     function call time."""
 
+    async def allowed_values(self) -> list[str]:
+        """Only applies to arguments of type String, or lists of strings. The
+        values that are accepted, if restricted.
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("allowedValues", _args)
+        return await _ctx.execute(list[str])
+
     async def default_path(self) -> str:
         """Only applies to arguments of type File or Directory. If the argument
         is not set, load it from the given path in the context directory
@@ -4255,6 +4532,27 @@ class FunctionArg(Type):
         _ctx = self._select("defaultValue", _args)
         return await _ctx.execute(JSON)
 
+    async def deprecated(self) -> str | None:
+        """The reason this argument is deprecated, if it is.
+
+        Returns
+        -------
+        str | None
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("deprecated", _args)
+        return await _ctx.execute(str | None)
+
     async def description(self) -> str:
         """A doc string for the argument, if any.
 
@@ -4323,6 +4621,72 @@ class FunctionArg(Type):
         _ctx = self._select("ignore", _args)
         return await _ctx.execute(list[str])
 
+    async def max(self) -> float | None:
+        """Only applies to arguments of type Integer or Float, or lists of
+        those. The maximum value, inclusive, if any.
+
+        Returns
+        -------
+        float | None
+            The `Float` scalar type represents signed double-precision
+            fractional values as specified by [IEEE
+            754](https://en.wikipedia.org/wiki/IEEE_floating_point).
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("max", _args)
+        return await _ctx.execute(float | None)
+
+    async def min(self) -> float | None:
+        """Only applies to arguments of type Integer or Float, or lists of
+        those. The minimum value, inclusive, if any.
+
+        Returns
+        -------
+        float | None
+            The `Float` scalar type represents signed double-precision
+            fractional values as specified by [IEEE
+            754](https://en.wikipedia.org/wiki/IEEE_floating_point).
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("min", _args)
+        return await _ctx.execute(float | None)
+
+    async def min_length(self) -> int | None:
+        """Only applies to arguments of type String or List. The minimum number
+        of characters of a string, or of elements of a list, if any.
+
+        Returns
+        -------
+        int | None
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31 -
+            1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("minLength", _args)
+        return await _ctx.execute(int | None)
+
     async def name(self) -> str:
         """The name of the argument in lowerCamelCase format.
 
@@ -4344,6 +4708,28 @@ class FunctionArg(Type):
         _ctx = self._select("name", _args)
         return await _ctx.execute(str)
 
+    async def pattern(self) -> str:
+        """Only applies to arguments of type String, or lists of strings. A
+        regular expression (RE2 syntax) that values must match, if any.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("pattern", _args)
+        return await _ctx.execute(str)
+
     def source_map(self) -> "SourceMap":
         """The location of this arg declaration."""
         _args: list[Arg] = []
@@ -4496,6 +4882,38 @@ class FunctionCall(Type):
         _ctx = self._select("returnError", _args)
         await _ctx.execute()
 
+    async def return_item(self, value: JSON) -> Void | None:
+        """Send an item of the result of a streaming function call to its
+        caller as soon as it's available.
+
+        The function must still return its full result with returnValue once
+        done.
+
+        Parameters
+        ----------
+        value:
+            JSON serialization of the item: an element of a list, or a chunk of
+            a string.
+
+        Returns
+        -------
+        Void | None
+            The absence of a value.  A Null Void is used as a placeholder for
+            resolvers that do not return anything.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("value", value),
+        ]
+        _ctx = self._select("returnItem", _args)
+        await _ctx.execute()
+
     async def return_value(self, value: JSON) -> Void | None:
         """Set the return value of the function call to the provided value.
 
@@ -5395,6 +5813,41 @@ class ListTypeDef(Type):
         return await _ctx.execute(ListTypeDefID)
 
 
+@typecheck
+class MapTypeDef(Type):
+    """A definition of a map type in a Module, with string keys."""
+
+    async def id(self) -> MapTypeDefID:
+        """A unique identifier for this MapTypeDef.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        MapTypeDefID
+            The `MapTypeDefID` scalar type represents an identifier for an
+            object of type MapTypeDef.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(MapTypeDefID)
+
+    def value_type_def(self) -> "TypeDef":
+        """The type of the values in the map. Keys are always strings."""
+        _args: list[Arg] = []
+        _ctx = self._select("valueTypeDef", _args)
+        return TypeDef(_ctx)
+
+
 @typecheck
 class Module(Type):
     """A Dagger module."""
@@ -7037,6 +7490,14 @@ class Client(Root):
         _ctx = self._select("loadListTypeDefFromID", _args)
         return ListTypeDef(_ctx)
 
+    def load_map_type_def_from_id(self, id: MapTypeDefID) -> MapTypeDef:
+        """Load a MapTypeDef from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadMapTypeDefFromID", _args)
+        return MapTypeDef(_ctx)
+
     def load_module_from_id(self, id: ModuleID) -> Module:
         """Load a Module from its ID."""
         _args = [
@@ -7147,6 +7608,25 @@ class Client(Root):
         _ctx = self._select("loadTypeDefFromID", _args)
         return TypeDef(_ctx)
 
+    def load_union_member_type_def_from_id(
+        self,
+        id: UnionMemberTypeDefID,
+    ) -> "UnionMemberTypeDef":
+        """Load a UnionMemberTypeDef from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadUnionMemberTypeDefFromID", _args)
+        return UnionMemberTypeDef(_ctx)
+
+    def load_union_type_def_from_id(self, id: UnionTypeDefID) -> "UnionTypeDef":
+        """Load a UnionTypeDef from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadUnionTypeDefFromID", _args)
+        return UnionTypeDef(_ctx)
+
     def module(self) -> Module:
         """Create a new module."""
         _args: list[Arg] = []
@@ -7958,6 +8438,14 @@ class TypeDef(Type):
         _ctx = self._select("asList", _args)
         return ListTypeDef(_ctx)
 
+    def as_map(self) -> MapTypeDef:
+        """If kind is MAP, the map-specific type definition. If kind is not MAP,
+        this will be null.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("asMap", _args)
+        return MapTypeDef(_ctx)
+
     def as_object(self) -> ObjectTypeDef:
         """If kind is OBJECT, the object-specific type definition. If kind is not
         OBJECT, this will be null.
@@ -7974,6 +8462,14 @@ class TypeDef(Type):
         _ctx = self._select("asScalar", _args)
         return ScalarTypeDef(_ctx)
 
+    def as_union(self) -> "UnionTypeDef":
+        """If kind is UNION, the union-specific type definition. If kind is not
+        UNION, this will be null.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("asUnion", _args)
+        return UnionTypeDef(_ctx)
+
     async def id(self) -> TypeDefID:
         """A unique identifier for this TypeDef.
 
@@ -8082,6 +8578,7 @@ class TypeDef(Type):
         *,
         description: str | None = "",
         source_map: SourceMap | None = None,
+        deprecated: str | None = None,
     ) -> Self:
         """Adds a static value for an Enum TypeDef, failing if the type is not an
         enum.
@@ -8094,11 +8591,14 @@ class TypeDef(Type):
             A doc string for the value, if any
         source_map:
             The source map for the enum value definition.
+        deprecated:
+            If set, marks the enum value as deprecated with the given reason.
         """
         _args = [
             Arg("value", value),
             Arg("description", description, ""),
             Arg("sourceMap", source_map, None),
+            Arg("deprecated", deprecated, None),
         ]
         _ctx = self._select("withEnumValue", _args)
         return TypeDef(_ctx)
@@ -8110,6 +8610,7 @@ class TypeDef(Type):
         *,
         description: str | None = "",
         source_map: SourceMap | None = None,
+        deprecated: str | None = None,
     ) -> Self:
         """Adds a static field for an Object TypeDef, failing if the type is not
         an object.
@@ -8124,12 +8625,15 @@ class TypeDef(Type):
             A doc string for the field, if any
         source_map:
             The source map for the field definition.
+        deprecated:
+            If set, marks the field as deprecated with the given reason.
         """
         _args = [
             Arg("name", name),
             Arg("typeDef", type_def),
             Arg("description", description, ""),
             Arg("sourceMap", source_map, None),
+            Arg("deprecated", deprecated, None),
         ]
         _ctx = self._select("withField", _args)
         return TypeDef(_ctx)
@@ -8178,6 +8682,23 @@ class TypeDef(Type):
         _ctx = self._select("withListOf", _args)
         return TypeDef(_ctx)
 
+    def with_map_of(self, value_type: Self) -> Self:
+        """Returns a TypeDef of kind Map with the provided type for its values.
+
+        Map keys are always strings. Maps are represented as a JSON object in
+        the API.
+
+        Parameters
+        ----------
+        value_type:
+            The type of the values in the map.
+        """
+        _args = [
+            Arg("valueType", value_type),
+        ]
+        _ctx = self._select("withMapOf", _args)
+        return TypeDef(_ctx)
+
     def with_object(
         self,
         name: str,
@@ -8221,6 +8742,62 @@ class TypeDef(Type):
         _ctx = self._select("withScalar", _args)
         return TypeDef(_ctx)
 
+    def with_union(
+        self,
+        name: str,
+        *,
+        description: str | None = "",
+        source_map: SourceMap | None = None,
+    ) -> Self:
+        """Returns a TypeDef of kind Union with the provided name.
+
+        Members are added with withUnionMember. Union values are represented
+        as a JSON object in the API, with a single key naming the member that
+        is set.
+
+        Parameters
+        ----------
+        name:
+            The name of the union
+        description:
+            A doc string for the union, if any
+        source_map:
+            The source map for the union definition.
+        """
+        _args = [
+            Arg("name", name),
+            Arg("description", description, ""),
+            Arg("sourceMap", source_map, None),
+        ]
+        _ctx = self._select("withUnion", _args)
+        return TypeDef(_ctx)
+
+    def with_union_member(
+        self,
+        name: str,
+        type_def: Self,
+        *,
+        description: str | None = "",
+    ) -> Self:
+        """Adds a member to a Union TypeDef, failing if the type is not a union.
+
+        Parameters
+        ----------
+        name:
+            The name of the member, used as its tag in union values
+        type_def:
+            The type of the member
+        description:
+            A doc string for the member, if any
+        """
+        _args = [
+            Arg("name", name),
+            Arg("typeDef", type_def),
+            Arg("description", description, ""),
+        ]
+        _ctx = self._select("withUnionMember", _args)
+        return TypeDef(_ctx)
+
     def with_(self, cb: Callable[["TypeDef"], "TypeDef"]) -> "TypeDef":
         """Call the provided callable with current TypeDef.
 
@@ -8229,6 +8806,185 @@ class TypeDef(Type):
         return cb(self)
 
 
+@typecheck
+class UnionMemberTypeDef(Type):
+    """A definition of a member of a union defined in a Module."""
+
+    async def description(self) -> str:
+        """A doc string for the member, if any.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("description", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> UnionMemberTypeDefID:
+        """A unique identifier for this UnionMemberTypeDef.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        UnionMemberTypeDefID
+            The `UnionMemberTypeDefID` scalar type represents an identifier for an
+            object of type UnionMemberTypeDef.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(UnionMemberTypeDefID)
+
+    async def name(self) -> str:
+        """The name of the member, used as its tag in union values.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    def type_def(self) -> TypeDef:
+        """The type of the member."""
+        _args: list[Arg] = []
+        _ctx = self._select("typeDef", _args)
+        return TypeDef(_ctx)
+
+
+@typecheck
+class UnionTypeDef(Type):
+    """A definition of a tagged union in a Module.
+
+    A union value is a JSON object with exactly one key, naming the member
+    that is set, e.g. {"directory": "<id>"}.
+    """
+
+    async def description(self) -> str:
+        """A doc string for the union, if any.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("description", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> UnionTypeDefID:
+        """A unique identifier for this UnionTypeDef.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        UnionTypeDefID
+            The `UnionTypeDefID` scalar type represents an identifier for an
+            object of type UnionTypeDef.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(UnionTypeDefID)
+
+    async def members(self) -> list[UnionMemberTypeDef]:
+        """The members of the union, exactly one of which is set in a value."""
+        _args: list[Arg] = []
+        _ctx = self._select("members", _args)
+        _ctx = UnionMemberTypeDef(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: UnionMemberTypeDefID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            UnionMemberTypeDef(
+                Client.from_context(_ctx)._select(
+                    "loadUnionMemberTypeDefFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
+    async def name(self) -> str:
+        """The name of the union.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    def source_map(self) -> SourceMap:
+        """The location of this union declaration."""
+        _args: list[Arg] = []
+        _ctx = self._select("sourceMap", _args)
+        return SourceMap(_ctx)
+
+
 dag = Client()
 """The global client instance."""
 
@@ -8268,6 +9024,7 @@ __all__ = [
     "Function",
     "FunctionArg",
     "FunctionArgID",
+    "FunctionCachePolicy",
     "FunctionCall",
     "FunctionCallArgValue",
     "FunctionCallArgValueID",
@@ -8291,6 +9048,8 @@ __all__ = [
     "LabelID",
     "ListTypeDef",
     "ListTypeDefID",
+    "MapTypeDef",
+    "MapTypeDefID",
     "Module",
     "ModuleID",
     "ModuleSource",
@@ -8322,6 +9081,10 @@ __all__ = [
     "TypeDef",
     "TypeDefID",
     "TypeDefKind",
+    "UnionMemberTypeDef",
+    "UnionMemberTypeDefID",
+    "UnionTypeDef",
+    "UnionTypeDefID",
     "Void",
     "dag",
 ]
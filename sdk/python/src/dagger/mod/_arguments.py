@@ -75,6 +75,24 @@ class Ignore:
         return hash(tuple(self.patterns))
 
 
+@dataclasses.dataclass(slots=True, frozen=True)
+class Deprecated:
+    """Mark a function argument as deprecated.
+
+    Callers that set the argument get a warning with the given reason.
+
+    Example usage::
+
+        @function
+        def build(self, tag: Annotated[str, Deprecated("Use `version`")] = ""): ...
+    """
+
+    reason: str
+
+    def __str__(self) -> str:
+        return self.reason
+
+
 @dataclasses.dataclass(slots=True, kw_only=True)
 class Parameter:
     """Parameter from function signature in :py:class:`FunctionResolver`."""
@@ -91,6 +109,7 @@ class Parameter:
     ignore: list[str] | None = None
     default_path: ContextPath | None = None
     default_value: dagger.JSON | None = None
+    deprecated: str | None = None
 
     def __post_init__(self):
         self._validate()
@@ -57,6 +57,18 @@ FUNCTION_DEF_KEY: typing.Final[str] = "__dagger_function__"
 T = TypeVar("T", bound=type)
 
 
+def with_cache_policy(func_def: dagger.Function, cache: str) -> dagger.Function:
+    """Set the cache policy of a function from the value of its ``cache`` option."""
+    if cache == "never":
+        return func_def.with_cache_policy(dagger.FunctionCachePolicy.NEVER)
+    if cache == "session":
+        return func_def.with_cache_policy(dagger.FunctionCachePolicy.PER_SESSION)
+    return func_def.with_cache_policy(
+        dagger.FunctionCachePolicy.TTL,
+        time_to_live=cache,
+    )
+
+
 class Module:
     """Builder for a :py:class:`dagger.Module`."""
 
@@ -137,6 +149,7 @@ class Module:
                         field_name,
                         to_typedef(types[field.original_name]),
                         description=get_doc(field.return_type),
+                        deprecated=field.meta.deprecated,
                     )
 
             # Object functions
@@ -151,6 +164,12 @@ class Module:
                 if doc := func.doc:
                     func_def = func_def.with_description(doc)
 
+                if cache := func.meta.cache:
+                    func_def = with_cache_policy(func_def, cache)
+
+                if (deprecated := func.meta.deprecated) is not None:
+                    func_def = func_def.with_deprecated(deprecated)
+
                 for param in func.parameters.values():
                     arg_def = to_typedef(param.resolved_type)
 
@@ -164,6 +183,7 @@ class Module:
                         default_value=param.default_value,
                         default_path=param.default_path,
                         ignore=param.ignore,
+                        deprecated=param.deprecated,
                     )
 
                 type_def = (
@@ -182,6 +202,7 @@ class Module:
                 enum_def = enum_def.with_enum_value(
                     str(member.value),
                     description=getattr(member, "description", None),
+                    deprecated=getattr(member, "deprecated", None),
                 )
             mod = mod.with_enum(enum_def)
 
@@ -364,6 +385,7 @@ class Module:
         default: Callable[[], Any] | object = ...,
         name: APIName | None = None,
         init: bool = True,
+        deprecated: str | None = None,
     ) -> Any:
         """Exposes an attribute as a :py:class:`dagger.FieldTypeDef`.
 
@@ -388,6 +410,8 @@ class Module:
         init:
             Whether the field should be included in the constructor.
             Defaults to `True`.
+        deprecated:
+            If set, marks the field as deprecated with the given reason.
         """
         kwargs = {}
         optional = False
@@ -397,7 +421,7 @@ class Module:
             kwargs["default_factory" if callable(default) else "default"] = default
 
         return dataclasses.field(
-            metadata={FIELD_DEF_KEY: FieldDefinition(name, optional)},
+            metadata={FIELD_DEF_KEY: FieldDefinition(name, optional, deprecated)},
             kw_only=True,
             init=init,
             repr=init,  # default repr shows field as an __init__ argument
@@ -411,6 +435,8 @@ class Module:
         *,
         name: APIName | None = None,
         doc: str | None = None,
+        cache: str | None = None,
+        deprecated: str | None = None,
     ) -> Func[P, R]: ...
 
     @overload
@@ -419,6 +445,8 @@ class Module:
         *,
         name: APIName | None = None,
         doc: str | None = None,
+        cache: str | None = None,
+        deprecated: str | None = None,
     ) -> Callable[[Func[P, R]], Func[P, R]]: ...
 
     def function(
@@ -427,6 +455,8 @@ class Module:
         *,
         name: APIName | None = None,
         doc: str | None = None,
+        cache: str | None = None,
+        deprecated: str | None = None,
     ) -> Func[P, R] | Callable[[Func[P, R]], Func[P, R]]:
         """Exposes a Python function as a :py:class:`dagger.Function`.
 
@@ -451,6 +481,13 @@ class Module:
         doc:
             An alternative description for the API. Useful to use the
             docstring for other purposes.
+        cache:
+            How the results of calls to the function are cached: ``"never"``,
+            ``"session"`` for the duration of the session, or a time to live
+            such as ``"10m"``. Defaults to the engine's caching.
+        deprecated:
+            If set, marks the function as deprecated with the given reason.
+            Callers get a warning when they call it.
         """
 
         # TODO: Wrap appropriately
@@ -458,7 +495,7 @@ class Module:
             # TODO: Use beartype to validate
             assert callable(func), f"Expected a callable, got {type(func)}."
 
-            meta = FunctionDefinition(name, doc)
+            meta = FunctionDefinition(name, doc, cache, deprecated)
 
             if inspect.isclass(func):
                 return Constructor(func, meta)
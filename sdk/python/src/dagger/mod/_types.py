@@ -12,12 +12,15 @@ ContextPath: TypeAlias = str
 class FieldDefinition:
     name: APIName | None
     optional: bool = False
+    deprecated: str | None = None
 
 
 @dataclasses.dataclass(slots=True, frozen=True)
 class FunctionDefinition:
     name: APIName | None = None
     doc: str | None = None
+    cache: str | None = None
+    deprecated: str | None = None
 
 
 class Enum(base.Enum):
@@ -28,12 +31,14 @@ class Enum(base.Enum):
         class Options(dagger.Enum):
             ONE = "ONE", "The first value"
             TWO = "TWO"  # no description
+            OLD = "OLD", "The old value", "Use ONE instead"  # deprecated
     """
 
-    __slots__ = ("description",)
+    __slots__ = ("deprecated", "description")
 
-    def __new__(cls, value, description=None):
+    def __new__(cls, value, description=None, deprecated=None):
         obj = str.__new__(cls, value)
         obj._value_ = value
         obj.description = description
+        obj.deprecated = deprecated
         return obj
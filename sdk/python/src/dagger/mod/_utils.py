@@ -16,7 +16,7 @@ import typing_extensions
 from beartype.door import TypeHint, UnionTypeHint
 from graphql.pyutils import snake_to_camel
 
-from dagger.mod._arguments import DefaultPath, Ignore, Name
+from dagger.mod._arguments import DefaultPath, Deprecated, Ignore, Name
 from dagger.mod._types import ContextPath
 
 asyncify = anyio.to_thread.run_sync
@@ -93,6 +93,12 @@ def get_default_path(obj: Any) -> ContextPath | None:
     return meta.from_context if meta else None
 
 
+def get_deprecated(obj: Any) -> str | None:
+    """Get the reason in the last Deprecated() of an annotated type."""
+    meta = get_meta(obj, Deprecated)
+    return meta.reason if meta else None
+
+
 def get_alt_name(annotation: type) -> str | None:
     """Get an alternative name in last Name() of an annotated type."""
     return annotated.name if (annotated := get_meta(annotation, Name)) else None
@@ -5038,6 +5038,12 @@ pub struct FunctionWithArgOpts<'a> {
     #[builder(setter(into, strip_option), default)]
     pub source_map: Option<SourceMapId>,
 }
+#[derive(Builder, Debug, PartialEq)]
+pub struct FunctionWithCachePolicyOpts<'a> {
+    /// For the TTL policy, how long results are cached, as a duration (e.g., "10m", "1h30m").
+    #[builder(setter(into, strip_option), default)]
+    pub time_to_live: Option<&'a str>,
+}
 impl Function {
     /// Arguments accepted by the function, if any.
     pub fn args(&self) -> Vec<FunctionArg> {
@@ -5048,6 +5054,16 @@ impl Function {
             graphql_client: self.graphql_client.clone(),
         }]
     }
+    /// The policy for caching the results of calls to the function.
+    pub async fn cache_policy(&self) -> Result<FunctionCachePolicy, DaggerError> {
+        let query = self.selection.select("cachePolicy");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// Number of seconds for which the results of calls to the function are cached, if its cache policy is TTL.
+    pub async fn cache_ttl_seconds(&self) -> Result<isize, DaggerError> {
+        let query = self.selection.select("cacheTTLSeconds");
+        query.execute(self.graphql_client.clone()).await
+    }
     /// A doc string for the function, if any.
     pub async fn description(&self) -> Result<String, DaggerError> {
         let query = self.selection.select("description");
@@ -5147,6 +5163,43 @@ impl Function {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Returns the function with the given cache policy.
+    ///
+    /// # Arguments
+    ///
+    /// * `policy` - How the results of calls to the function are cached.
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn with_cache_policy(&self, policy: FunctionCachePolicy) -> Function {
+        let mut query = self.selection.select("withCachePolicy");
+        query = query.arg("policy", policy);
+        Function {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Returns the function with the given cache policy.
+    ///
+    /// # Arguments
+    ///
+    /// * `policy` - How the results of calls to the function are cached.
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn with_cache_policy_opts<'a>(
+        &self,
+        policy: FunctionCachePolicy,
+        opts: FunctionWithCachePolicyOpts<'a>,
+    ) -> Function {
+        let mut query = self.selection.select("withCachePolicy");
+        query = query.arg("policy", policy);
+        if let Some(time_to_live) = opts.time_to_live {
+            query = query.arg("timeToLive", time_to_live);
+        }
+        Function {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Returns the function with the given doc string.
     ///
     /// # Arguments
@@ -8387,6 +8440,17 @@ pub enum CacheSharingMode {
     Shared,
 }
 #[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
+pub enum FunctionCachePolicy {
+    #[serde(rename = "DEFAULT")]
+    Default,
+    #[serde(rename = "NEVER")]
+    Never,
+    #[serde(rename = "PER_SESSION")]
+    PerSession,
+    #[serde(rename = "TTL")]
+    Ttl,
+}
+#[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
 pub enum ImageLayerCompression {
     #[serde(rename = "EStarGZ")]
     EStarGz,
@@ -17,6 +17,65 @@ class BaseClient {
   constructor(protected _ctx: Context = new Context()) {}
 }
 
+/**
+ * Type of a map or union value, as described by the @jsonType directive.
+ * @hidden
+ */
+type JSONTypeRef = {
+  kind: string
+  optional?: boolean
+  name?: string
+  of?: JSONTypeRef
+  members?: { name: string; type: JSONTypeRef }[]
+}
+
+/**
+ * Decode a map or union value returned as JSON by the Dagger API, loading
+ * objects from their IDs.
+ * @hidden
+ */
+// eslint-disable-next-line @typescript-eslint/no-explicit-any, @typescript-eslint/no-unused-vars
+function loadJSONValue(ctx: Context, type: JSONTypeRef, value: any): any {
+  if (value === null || value === undefined) {
+    return value
+  }
+
+  // Maps and unions are encoded as JSON, except when nested in another one
+  if (
+    (type.kind === "MAP_KIND" || type.kind === "UNION_KIND") &&
+    typeof value === "string"
+  ) {
+    value = JSON.parse(value)
+  }
+
+  switch (type.kind) {
+    case "LIST_KIND":
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      return value.map((v: any) => loadJSONValue(ctx, type.of!, v))
+    case "MAP_KIND":
+      return Object.fromEntries(
+        Object.entries(value).map(([k, v]) => [
+          k,
+          loadJSONValue(ctx, type.of!, v),
+        ]),
+      )
+    case "UNION_KIND":
+      for (const member of type.members ?? []) {
+        const v = value[member.name]
+        if (v !== null && v !== undefined) {
+          return { [member.name]: loadJSONValue(ctx, member.type, v) }
+        }
+      }
+      return value
+    case "OBJECT_KIND":
+    case "INTERFACE_KIND":
+      // eslint-disable-next-line @typescript-eslint/no-explicit-any
+      return (new Client(ctx.copy()) as any)[`load${type.name}FromID`](value)
+    default:
+      return value
+  }
+}
+
 export type BuildArg = {
   /**
    * The build argument name.
@@ -874,6 +933,43 @@ export type FunctionWithArgOpts = {
    */
   ignore?: string[]
   sourceMap?: SourceMap
+
+  /**
+   * If set, marks the argument as deprecated with the given reason. Required arguments, without a default value, can't be deprecated.
+   */
+  deprecated?: string
+
+  /**
+   * If the argument is a String or a list of strings, a regular expression (RE2 syntax) that values must match.
+   */
+  pattern?: string
+
+  /**
+   * If the argument is an Integer or Float, or a list of those, the minimum value, inclusive.
+   */
+  min?: number
+
+  /**
+   * If the argument is an Integer or Float, or a list of those, the maximum value, inclusive.
+   */
+  max?: number
+
+  /**
+   * If the argument is a String or a List, the minimum number of characters or elements.
+   */
+  minLength?: number
+
+  /**
+   * If the argument is a String or a list of strings, the only values that are accepted.
+   */
+  allowedValues?: string[]
+}
+
+export type FunctionWithCachePolicyOpts = {
+  /**
+   * For the TTL policy, how long results are cached, as a duration (e.g., "10m", "1h30m").
+   */
+  timeToLive?: string
 }
 
 /**
@@ -881,6 +977,33 @@ export type FunctionWithArgOpts = {
  */
 export type FunctionArgID = string & { __FunctionArgID: never }
 
+/**
+ * How the results of calls to a function are cached.
+ */
+export enum FunctionCachePolicy {
+  /**
+   * Calls are cached for the duration of the session that made them, or across sessions for calls made by the engine itself.
+   */
+  Default = "DEFAULT",
+
+  /**
+   * Calls are never cached: the function runs every time it's called.
+   */
+  Never = "NEVER",
+
+  /**
+   * Calls are cached for the duration of the session that made them.
+   */
+  PerSession = "PER_SESSION",
+
+  /**
+   * Calls are cached across sessions, for the time to live of the function.
+   *
+   * Cached results are dropped when the current time window of that length ends, so they're never older than the time to live.
+   */
+  Ttl = "TTL",
+}
+
 /**
  * The `FunctionCallArgValueID` scalar type represents an identifier for an object of type FunctionCallArgValue.
  */
@@ -1021,6 +1144,11 @@ export type LabelID = string & { __LabelID: never }
  */
 export type ListTypeDefID = string & { __ListTypeDefID: never }
 
+/**
+ * The `MapTypeDefID` scalar type represents an identifier for an object of type MapTypeDef.
+ */
+export type MapTypeDefID = string & { __MapTypeDefID: never }
+
 /**
  * The `ModuleID` scalar type represents an identifier for an object of type Module.
  */
@@ -1274,6 +1402,11 @@ export type TypeDefWithEnumValueOpts = {
    * The source map for the enum value definition.
    */
   sourceMap?: SourceMap
+
+  /**
+   * If set, marks the enum value as deprecated with the given reason.
+   */
+  deprecated?: string
 }
 
 export type TypeDefWithFieldOpts = {
@@ -1286,6 +1419,11 @@ export type TypeDefWithFieldOpts = {
    * The source map for the field definition.
    */
   sourceMap?: SourceMap
+
+  /**
+   * If set, marks the field as deprecated with the given reason.
+   */
+  deprecated?: string
 }
 
 export type TypeDefWithInterfaceOpts = {
@@ -1302,6 +1440,25 @@ export type TypeDefWithScalarOpts = {
   description?: string
 }
 
+export type TypeDefWithUnionOpts = {
+  /**
+   * A doc string for the union, if any
+   */
+  description?: string
+
+  /**
+   * The source map for the union definition.
+   */
+  sourceMap?: SourceMap
+}
+
+export type TypeDefWithUnionMemberOpts = {
+  /**
+   * A doc string for the member, if any
+   */
+  description?: string
+}
+
 /**
  * The `TypeDefID` scalar type represents an identifier for an object of type TypeDef.
  */
@@ -1352,6 +1509,13 @@ export enum TypeDefKind {
    */
   ListKind = "LIST_KIND",
 
+  /**
+   * A map of string keys to values all having the same type.
+   *
+   * Always paired with a MapTypeDef. Maps are represented as a JSON object in the GraphQL schema.
+   */
+  MapKind = "MAP_KIND",
+
   /**
    * A named type defined in the GraphQL schema, with fields and functions.
    *
@@ -1369,6 +1533,13 @@ export enum TypeDefKind {
    */
   StringKind = "STRING_KIND",
 
+  /**
+   * A tagged union of values of different types, exactly one of which is set.
+   *
+   * Always paired with a UnionTypeDef. Unions are represented as a JSON object with a single key in the GraphQL schema.
+   */
+  UnionKind = "UNION_KIND",
+
   /**
    * A special kind used to signify that no value is returned.
    *
@@ -1376,6 +1547,16 @@ export enum TypeDefKind {
    */
   VoidKind = "VOID_KIND",
 }
+/**
+ * The `UnionMemberTypeDefID` scalar type represents an identifier for an object of type UnionMemberTypeDef.
+ */
+export type UnionMemberTypeDefID = string & { __UnionMemberTypeDefID: never }
+
+/**
+ * The `UnionTypeDefID` scalar type represents an identifier for an object of type UnionTypeDef.
+ */
+export type UnionTypeDefID = string & { __UnionTypeDefID: never }
+
 /**
  * The absence of a value.
  *
@@ -3506,6 +3687,7 @@ export class EnumTypeDef extends BaseClient {
  */
 export class EnumValueTypeDef extends BaseClient {
   private readonly _id?: EnumValueTypeDefID = undefined
+  private readonly _deprecated?: string = undefined
   private readonly _description?: string = undefined
   private readonly _name?: string = undefined
 
@@ -3515,12 +3697,14 @@ export class EnumValueTypeDef extends BaseClient {
   constructor(
     ctx?: Context,
     _id?: EnumValueTypeDefID,
+    _deprecated?: string,
     _description?: string,
     _name?: string,
   ) {
     super(ctx)
 
     this._id = _id
+    this._deprecated = _deprecated
     this._description = _description
     this._name = _name
   }
@@ -3540,6 +3724,21 @@ export class EnumValueTypeDef extends BaseClient {
     return response
   }
 
+  /**
+   * The reason this enum value is deprecated, if it is.
+   */
+  deprecated = async (): Promise<string> => {
+    if (this._deprecated) {
+      return this._deprecated
+    }
+
+    const ctx = this._ctx.select("deprecated")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * A doc string for the enum value, if any.
    */
@@ -3701,6 +3900,7 @@ export class Error extends BaseClient {
  */
 export class FieldTypeDef extends BaseClient {
   private readonly _id?: FieldTypeDefID = undefined
+  private readonly _deprecated?: string = undefined
   private readonly _description?: string = undefined
   private readonly _name?: string = undefined
 
@@ -3710,12 +3910,14 @@ export class FieldTypeDef extends BaseClient {
   constructor(
     ctx?: Context,
     _id?: FieldTypeDefID,
+    _deprecated?: string,
     _description?: string,
     _name?: string,
   ) {
     super(ctx)
 
     this._id = _id
+    this._deprecated = _deprecated
     this._description = _description
     this._name = _name
   }
@@ -3735,6 +3937,21 @@ export class FieldTypeDef extends BaseClient {
     return response
   }
 
+  /**
+   * The reason this field is deprecated, if it is.
+   */
+  deprecated = async (): Promise<string> => {
+    if (this._deprecated) {
+      return this._deprecated
+    }
+
+    const ctx = this._ctx.select("deprecated")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * A doc string for the field, if any.
    */
@@ -3959,8 +4176,12 @@ export class File extends BaseClient {
  */
 export class Function_ extends BaseClient {
   private readonly _id?: FunctionID = undefined
+  private readonly _cachePolicy?: FunctionCachePolicy = undefined
+  private readonly _cacheTTLSeconds?: number = undefined
+  private readonly _deprecated?: string = undefined
   private readonly _description?: string = undefined
   private readonly _name?: string = undefined
+  private readonly _streaming?: boolean = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
@@ -3968,14 +4189,22 @@ export class Function_ extends BaseClient {
   constructor(
     ctx?: Context,
     _id?: FunctionID,
+    _cachePolicy?: FunctionCachePolicy,
+    _cacheTTLSeconds?: number,
+    _deprecated?: string,
     _description?: string,
     _name?: string,
+    _streaming?: boolean,
   ) {
     super(ctx)
 
     this._id = _id
+    this._cachePolicy = _cachePolicy
+    this._cacheTTLSeconds = _cacheTTLSeconds
+    this._deprecated = _deprecated
     this._description = _description
     this._name = _name
+    this._streaming = _streaming
   }
 
   /**
@@ -4010,6 +4239,51 @@ export class Function_ extends BaseClient {
     )
   }
 
+  /**
+   * The policy for caching the results of calls to the function.
+   */
+  cachePolicy = async (): Promise<FunctionCachePolicy> => {
+    if (this._cachePolicy) {
+      return this._cachePolicy
+    }
+
+    const ctx = this._ctx.select("cachePolicy")
+
+    const response: Awaited<FunctionCachePolicy> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Number of seconds for which the results of calls to the function are cached, if its cache policy is TTL.
+   */
+  cacheTTLSeconds = async (): Promise<number> => {
+    if (this._cacheTTLSeconds) {
+      return this._cacheTTLSeconds
+    }
+
+    const ctx = this._ctx.select("cacheTTLSeconds")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The reason this function is deprecated, if it is.
+   */
+  deprecated = async (): Promise<string> => {
+    if (this._deprecated) {
+      return this._deprecated
+    }
+
+    const ctx = this._ctx.select("deprecated")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * A doc string for the function, if any.
    */
@@ -4056,6 +4330,21 @@ export class Function_ extends BaseClient {
     return new SourceMap(ctx)
   }
 
+  /**
+   * Whether the function returns its result incrementally, as a stream of items delivered to subscribers as they are available.
+   */
+  streaming = async (): Promise<boolean> => {
+    if (this._streaming) {
+      return this._streaming
+    }
+
+    const ctx = this._ctx.select("streaming")
+
+    const response: Awaited<boolean> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * Returns the function with the provided argument
    * @param name The name of the argument
@@ -4064,6 +4353,12 @@ export class Function_ extends BaseClient {
    * @param opts.defaultValue A default value to use for this argument if not explicitly set by the caller, if any
    * @param opts.defaultPath If the argument is a Directory or File type, default to load path from context directory, relative to root directory.
    * @param opts.ignore Patterns to ignore when loading the contextual argument value.
+   * @param opts.deprecated If set, marks the argument as deprecated with the given reason.
+   * @param opts.pattern If the argument is a String or a list of strings, a regular expression (RE2 syntax) that values must match.
+   * @param opts.min If the argument is an Integer or Float, or a list of those, the minimum value, inclusive.
+   * @param opts.max If the argument is an Integer or Float, or a list of those, the maximum value, inclusive.
+   * @param opts.minLength If the argument is a String or a List, the minimum number of characters or elements.
+   * @param opts.allowedValues If the argument is a String or a list of strings, the only values that are accepted.
    */
   withArg = (
     name: string,
@@ -4074,6 +4369,36 @@ export class Function_ extends BaseClient {
     return new Function_(ctx)
   }
 
+  /**
+   * Returns the function with the given cache policy.
+   * @param policy How the results of calls to the function are cached.
+   * @param opts.timeToLive For the TTL policy, how long results are cached, as a duration (e.g., "10m", "1h30m").
+   */
+  withCachePolicy = (
+    policy: FunctionCachePolicy,
+    opts?: FunctionWithCachePolicyOpts,
+  ): Function_ => {
+    const metadata = {
+      policy: { is_enum: true },
+    }
+
+    const ctx = this._ctx.select("withCachePolicy", {
+      policy,
+      ...opts,
+      __metadata: metadata,
+    })
+    return new Function_(ctx)
+  }
+
+  /**
+   * Returns the function marked as deprecated.
+   * @param reason The reason the function is deprecated, usually including what to use instead.
+   */
+  withDeprecated = (reason: string): Function_ => {
+    const ctx = this._ctx.select("withDeprecated", { reason })
+    return new Function_(ctx)
+  }
+
   /**
    * Returns the function with the given doc string.
    * @param description The doc string to set.
@@ -4092,6 +4417,16 @@ export class Function_ extends BaseClient {
     return new Function_(ctx)
   }
 
+  /**
+   * Returns the function marked as streaming its result.
+   *
+   * Subscribers to the function receive the elements of its list result, or the chunks of its string result, as soon as they are returned with returnItem. Queries still receive the full result once the function is done.
+   */
+  withStreaming = (): Function_ => {
+    const ctx = this._ctx.select("withStreaming")
+    return new Function_(ctx)
+  }
+
   /**
    * Call the provided function with current Function.
    *
@@ -4111,8 +4446,13 @@ export class FunctionArg extends BaseClient {
   private readonly _id?: FunctionArgID = undefined
   private readonly _defaultPath?: string = undefined
   private readonly _defaultValue?: JSON = undefined
+  private readonly _deprecated?: string = undefined
   private readonly _description?: string = undefined
+  private readonly _max?: number = undefined
+  private readonly _min?: number = undefined
+  private readonly _minLength?: number = undefined
   private readonly _name?: string = undefined
+  private readonly _pattern?: string = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
@@ -4122,16 +4462,26 @@ export class FunctionArg extends BaseClient {
     _id?: FunctionArgID,
     _defaultPath?: string,
     _defaultValue?: JSON,
+    _deprecated?: string,
     _description?: string,
+    _max?: number,
+    _min?: number,
+    _minLength?: number,
     _name?: string,
+    _pattern?: string,
   ) {
     super(ctx)
 
     this._id = _id
     this._defaultPath = _defaultPath
     this._defaultValue = _defaultValue
+    this._deprecated = _deprecated
     this._description = _description
+    this._max = _max
+    this._min = _min
+    this._minLength = _minLength
     this._name = _name
+    this._pattern = _pattern
   }
 
   /**
@@ -4149,6 +4499,17 @@ export class FunctionArg extends BaseClient {
     return response
   }
 
+  /**
+   * Only applies to arguments of type String, or lists of strings. The values that are accepted, if restricted.
+   */
+  allowedValues = async (): Promise<string[]> => {
+    const ctx = this._ctx.select("allowedValues")
+
+    const response: Awaited<string[]> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * Only applies to arguments of type File or Directory. If the argument is not set, load it from the given path in the context directory
    */
@@ -4179,6 +4540,21 @@ export class FunctionArg extends BaseClient {
     return response
   }
 
+  /**
+   * The reason this argument is deprecated, if it is.
+   */
+  deprecated = async (): Promise<string> => {
+    if (this._deprecated) {
+      return this._deprecated
+    }
+
+    const ctx = this._ctx.select("deprecated")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * A doc string for the argument, if any.
    */
@@ -4205,6 +4581,51 @@ export class FunctionArg extends BaseClient {
     return response
   }
 
+  /**
+   * Only applies to arguments of type Integer or Float, or lists of those. The maximum value, inclusive, if any.
+   */
+  max = async (): Promise<number> => {
+    if (this._max) {
+      return this._max
+    }
+
+    const ctx = this._ctx.select("max")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Only applies to arguments of type Integer or Float, or lists of those. The minimum value, inclusive, if any.
+   */
+  min = async (): Promise<number> => {
+    if (this._min) {
+      return this._min
+    }
+
+    const ctx = this._ctx.select("min")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Only applies to arguments of type String or List. The minimum number of characters of a string, or of elements of a list, if any.
+   */
+  minLength = async (): Promise<number> => {
+    if (this._minLength) {
+      return this._minLength
+    }
+
+    const ctx = this._ctx.select("minLength")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * The name of the argument in lowerCamelCase format.
    */
@@ -4220,6 +4641,21 @@ export class FunctionArg extends BaseClient {
     return response
   }
 
+  /**
+   * Only applies to arguments of type String, or lists of strings. A regular expression (RE2 syntax) that values must match, if any.
+   */
+  pattern = async (): Promise<string> => {
+    if (this._pattern) {
+      return this._pattern
+    }
+
+    const ctx = this._ctx.select("pattern")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * The location of this arg declaration.
    */
@@ -4246,6 +4682,7 @@ export class FunctionCall extends BaseClient {
   private readonly _parent?: JSON = undefined
   private readonly _parentName?: string = undefined
   private readonly _returnError?: Void = undefined
+  private readonly _returnItem?: Void = undefined
   private readonly _returnValue?: Void = undefined
 
   /**
@@ -4258,6 +4695,7 @@ export class FunctionCall extends BaseClient {
     _parent?: JSON,
     _parentName?: string,
     _returnError?: Void,
+    _returnItem?: Void,
     _returnValue?: Void,
   ) {
     super(ctx)
@@ -4267,6 +4705,7 @@ export class FunctionCall extends BaseClient {
     this._parent = _parent
     this._parentName = _parentName
     this._returnError = _returnError
+    this._returnItem = _returnItem
     this._returnValue = _returnValue
   }
 
@@ -4361,6 +4800,22 @@ export class FunctionCall extends BaseClient {
     await ctx.execute()
   }
 
+  /**
+   * Send an item of the result of a streaming function call to its caller as soon as it's available.
+   *
+   * The function must still return its full result with returnValue once done.
+   * @param value JSON serialization of the item: an element of a list, or a chunk of a string.
+   */
+  returnItem = async (value: JSON): Promise<void> => {
+    if (this._returnItem) {
+      return
+    }
+
+    const ctx = this._ctx.select("returnItem", { value })
+
+    await ctx.execute()
+  }
+
   /**
    * Set the return value of the function call to the provided value.
    * @param value JSON serialization of the return value.
@@ -5091,6 +5546,45 @@ export class ListTypeDef extends BaseClient {
   }
 }
 
+/**
+ * A definition of a map type in a Module, with string keys.
+ */
+export class MapTypeDef extends BaseClient {
+  private readonly _id?: MapTypeDefID = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(ctx?: Context, _id?: MapTypeDefID) {
+    super(ctx)
+
+    this._id = _id
+  }
+
+  /**
+   * A unique identifier for this MapTypeDef.
+   */
+  id = async (): Promise<MapTypeDefID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<MapTypeDefID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The type of the values in the map. Keys are always strings.
+   */
+  valueTypeDef = (): TypeDef => {
+    const ctx = this._ctx.select("valueTypeDef")
+    return new TypeDef(ctx)
+  }
+}
+
 /**
  * A Dagger module.
  */
@@ -6488,6 +6982,14 @@ export class Client extends BaseClient {
     return new ListTypeDef(ctx)
   }
 
+  /**
+   * Load a MapTypeDef from its ID.
+   */
+  loadMapTypeDefFromID = (id: MapTypeDefID): MapTypeDef => {
+    const ctx = this._ctx.select("loadMapTypeDefFromID", { id })
+    return new MapTypeDef(ctx)
+  }
+
   /**
    * Load a Module from its ID.
    */
@@ -6595,6 +7097,24 @@ export class Client extends BaseClient {
     return new TypeDef(ctx)
   }
 
+  /**
+   * Load a UnionMemberTypeDef from its ID.
+   */
+  loadUnionMemberTypeDefFromID = (
+    id: UnionMemberTypeDefID,
+  ): UnionMemberTypeDef => {
+    const ctx = this._ctx.select("loadUnionMemberTypeDefFromID", { id })
+    return new UnionMemberTypeDef(ctx)
+  }
+
+  /**
+   * Load a UnionTypeDef from its ID.
+   */
+  loadUnionTypeDefFromID = (id: UnionTypeDefID): UnionTypeDef => {
+    const ctx = this._ctx.select("loadUnionTypeDefFromID", { id })
+    return new UnionTypeDef(ctx)
+  }
+
   /**
    * Create a new module.
    */
@@ -7317,6 +7837,14 @@ export class TypeDef extends BaseClient {
     return new ListTypeDef(ctx)
   }
 
+  /**
+   * If kind is MAP, the map-specific type definition. If kind is not MAP, this will be null.
+   */
+  asMap = (): MapTypeDef => {
+    const ctx = this._ctx.select("asMap")
+    return new MapTypeDef(ctx)
+  }
+
   /**
    * If kind is OBJECT, the object-specific type definition. If kind is not OBJECT, this will be null.
    */
@@ -7333,6 +7861,14 @@ export class TypeDef extends BaseClient {
     return new ScalarTypeDef(ctx)
   }
 
+  /**
+   * If kind is UNION, the union-specific type definition. If kind is not UNION, this will be null.
+   */
+  asUnion = (): UnionTypeDef => {
+    const ctx = this._ctx.select("asUnion")
+    return new UnionTypeDef(ctx)
+  }
+
   /**
    * The kind of type this is (e.g. primitive, list, object).
    */
@@ -7391,6 +7927,7 @@ export class TypeDef extends BaseClient {
    * @param value The name of the value in the enum
    * @param opts.description A doc string for the value, if any
    * @param opts.sourceMap The source map for the enum value definition.
+   * @param opts.deprecated If set, marks the enum value as deprecated with the given reason.
    */
   withEnumValue = (value: string, opts?: TypeDefWithEnumValueOpts): TypeDef => {
     const ctx = this._ctx.select("withEnumValue", { value, ...opts })
@@ -7403,6 +7940,7 @@ export class TypeDef extends BaseClient {
    * @param typeDef The type of the field
    * @param opts.description A doc string for the field, if any
    * @param opts.sourceMap The source map for the field definition.
+   * @param opts.deprecated If set, marks the field as deprecated with the given reason.
    */
   withField = (
     name: string,
@@ -7451,6 +7989,17 @@ export class TypeDef extends BaseClient {
     return new TypeDef(ctx)
   }
 
+  /**
+   * Returns a TypeDef of kind Map with the provided type for its values.
+   *
+   * Map keys are always strings. Maps are represented as a JSON object in the API.
+   * @param valueType The type of the values in the map.
+   */
+  withMapOf = (valueType: TypeDef): TypeDef => {
+    const ctx = this._ctx.select("withMapOf", { valueType })
+    return new TypeDef(ctx)
+  }
+
   /**
    * Returns a TypeDef of kind Object with the provided name.
    *
@@ -7477,6 +8026,34 @@ export class TypeDef extends BaseClient {
     return new TypeDef(ctx)
   }
 
+  /**
+   * Returns a TypeDef of kind Union with the provided name.
+   *
+   * Members are added with withUnionMember. Union values are represented as a JSON object in the API, with a single key naming the member that is set.
+   * @param name The name of the union
+   * @param opts.description A doc string for the union, if any
+   * @param opts.sourceMap The source map for the union definition.
+   */
+  withUnion = (name: string, opts?: TypeDefWithUnionOpts): TypeDef => {
+    const ctx = this._ctx.select("withUnion", { name, ...opts })
+    return new TypeDef(ctx)
+  }
+
+  /**
+   * Adds a member to a Union TypeDef, failing if the type is not a union.
+   * @param name The name of the member, used as its tag in union values
+   * @param typeDef The type of the member
+   * @param opts.description A doc string for the member, if any
+   */
+  withUnionMember = (
+    name: string,
+    typeDef: TypeDef,
+    opts?: TypeDefWithUnionMemberOpts,
+  ): TypeDef => {
+    const ctx = this._ctx.select("withUnionMember", { name, typeDef, ...opts })
+    return new TypeDef(ctx)
+  }
+
   /**
    * Call the provided function with current TypeDef.
    *
@@ -7487,4 +8064,179 @@ export class TypeDef extends BaseClient {
   }
 }
 
+/**
+ * A definition of a member of a union defined in a Module.
+ */
+export class UnionMemberTypeDef extends BaseClient {
+  private readonly _id?: UnionMemberTypeDefID = undefined
+  private readonly _description?: string = undefined
+  private readonly _name?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: UnionMemberTypeDefID,
+    _description?: string,
+    _name?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._description = _description
+    this._name = _name
+  }
+
+  /**
+   * A unique identifier for this UnionMemberTypeDef.
+   */
+  id = async (): Promise<UnionMemberTypeDefID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<UnionMemberTypeDefID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * A doc string for the member, if any.
+   */
+  description = async (): Promise<string> => {
+    if (this._description) {
+      return this._description
+    }
+
+    const ctx = this._ctx.select("description")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The name of the member, used as its tag in union values.
+   */
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const ctx = this._ctx.select("name")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The type of the member.
+   */
+  typeDef = (): TypeDef => {
+    const ctx = this._ctx.select("typeDef")
+    return new TypeDef(ctx)
+  }
+}
+
+/**
+ * A definition of a tagged union in a Module.
+ *
+ * A union value is a JSON object with exactly one key, naming the member that is set, e.g. {"directory": "<id>"}.
+ */
+export class UnionTypeDef extends BaseClient {
+  private readonly _id?: UnionTypeDefID = undefined
+  private readonly _description?: string = undefined
+  private readonly _name?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: UnionTypeDefID,
+    _description?: string,
+    _name?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._description = _description
+    this._name = _name
+  }
+
+  /**
+   * A unique identifier for this UnionTypeDef.
+   */
+  id = async (): Promise<UnionTypeDefID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<UnionTypeDefID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * A doc string for the union, if any.
+   */
+  description = async (): Promise<string> => {
+    if (this._description) {
+      return this._description
+    }
+
+    const ctx = this._ctx.select("description")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The members of the union, exactly one of which is set in a value.
+   */
+  members = async (): Promise<UnionMemberTypeDef[]> => {
+    type members = {
+      id: UnionMemberTypeDefID
+    }
+
+    const ctx = this._ctx.select("members").select("id")
+
+    const response: Awaited<members[]> = await ctx.execute()
+
+    return response.map((r) =>
+      new Client(ctx.copy()).loadUnionMemberTypeDefFromID(r.id),
+    )
+  }
+
+  /**
+   * The name of the union.
+   */
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const ctx = this._ctx.select("name")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The location of this union declaration.
+   */
+  sourceMap = (): SourceMap => {
+    const ctx = this._ctx.select("sourceMap")
+    return new SourceMap(ctx)
+  }
+}
+
 export const dag = new Client()
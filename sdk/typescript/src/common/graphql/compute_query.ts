@@ -14,9 +14,21 @@ export type QueryTree = {
   args?: Record<string, unknown>
 }
 
+/**
+ * Type of a map or union value, as described by the @jsonType directive.
+ */
+export type JSONTypeRef = {
+  kind: string
+  optional?: boolean
+  name?: string
+  of?: JSONTypeRef
+  members?: { name: string; type: JSONTypeRef }[]
+}
+
 export type Metadata = {
   [key: string]: {
     is_enum?: boolean
+    json_type?: JSONTypeRef
   }
 }
 
@@ -100,13 +112,78 @@ async function computeNestedQuery(
     ])
   }
 
+  // Replace the objects held by a map or a union with their ids.
+  const computeJSONValue = async (
+    type: JSONTypeRef,
+    value: any,
+  ): Promise<any> => {
+    if (value === undefined || value === null) {
+      return value
+    }
+
+    switch (type.kind) {
+      case "LIST_KIND":
+        return await Promise.all(
+          value.map((v: any) => computeJSONValue(type.of!, v)),
+        )
+      case "MAP_KIND":
+      case "UNION_KIND": {
+        const entries = await Promise.all(
+          Object.entries(value).map(async ([k, v]) => {
+            const member = type.members?.find((m) => m.name === k)
+            return [k, await computeJSONValue(member?.type ?? type.of!, v)]
+          }),
+        )
+
+        return Object.fromEntries(entries)
+      }
+      case "OBJECT_KIND":
+      case "INTERFACE_KIND":
+        if (value instanceof Object && isQueryTree(value)) {
+          return await compute(await computeQueryTree(value), client)
+        }
+
+        return value
+      default:
+        return value
+    }
+  }
+
+  // Encode maps and unions into JSON, which is how they're represented in
+  // the schema.
+  const encodeJSONValue = async (
+    type: JSONTypeRef,
+    value: any,
+  ): Promise<any> => {
+    if (value === undefined || value === null || typeof value === "string") {
+      // Already encoded
+      return value
+    }
+
+    if (type.kind === "LIST_KIND") {
+      return await Promise.all(
+        value.map((v: any) => encodeJSONValue(type.of!, v)),
+      )
+    }
+
+    return JSON.stringify(await computeJSONValue(type, value))
+  }
+
   // Remove all undefined args and assert args type
   const queryToExec = query.filter((q): q is Required<QueryTree> => !!q.args)
 
   for (const q of queryToExec) {
+    const metadata: Metadata = (q.args.__metadata as Metadata) || {}
+
     await Promise.all(
       // Compute nested query for single object
       Object.entries(q.args).map(async ([key, value]: any) => {
+        const jsonType = metadata[key]?.json_type
+        if (jsonType) {
+          q.args[key] = await encodeJSONValue(jsonType, value)
+          return
+        }
+
         if (value instanceof Object && isQueryTree(value)) {
           // push an id that will be used by the container
           const getQueryTree = await computeQueryTree(value)
@@ -14,7 +14,11 @@ export const object = registry.object
  * The definition of @func decorator that should be on top of any
  * class' method that must be exposed to the Dagger API.
  *
- * @param alias The alias to use for the field when exposed on the API.
+ * Add a `@deprecated` JSDoc tag to the method to mark the function as
+ * deprecated, with the reason as the tag's comment.
+ *
+ * @param alias The alias to use for the function when exposed on the API, or
+ * its options (e.g., `@func({ cache: "10m" })`).
  */
 export const func = registry.func
 
@@ -45,6 +49,14 @@ export const enumType = registry.enumType
  * load it from the given path in the context directory.
  * @param opts.ignore Only applies to arguments of type Directory. The ignore patterns are applied to the input directory,
  * and matching entries are filtered out, in a cache-efficient manner..
+ * @param opts.deprecated Marks the argument as deprecated with the given reason.
+ * @param opts.pattern A regular expression (RE2 syntax) that string values must match.
+ * @param opts.min The minimum value of numbers, inclusive.
+ * @param opts.max The maximum value of numbers, inclusive.
+ * @param opts.minLength The minimum number of characters of a string, or of elements of a list.
+ * @param opts.allowedValues The only string values that are accepted.
+ *
+ * Constraints are checked by the engine before the function is invoked.
  *
  * Relative paths are relative to the current source files.
  * Absolute paths are rooted to the module context directory.
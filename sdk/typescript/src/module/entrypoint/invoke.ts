@@ -11,6 +11,7 @@ import {
 import { registry } from "../registry.js"
 import { InvokeCtx } from "./context.js"
 import {
+  loadJSONValueResult,
   loadResult,
   loadInvokedMethod,
   loadInvokedObject,
@@ -80,6 +81,15 @@ export async function invoke(
     }
   }
 
+  if (
+    result &&
+    !isConstructor(method) &&
+    (method.returnType!.kind === TypeDefKind.MapKind ||
+      method.returnType!.kind === TypeDefKind.UnionKind)
+  ) {
+    return await loadJSONValueResult(result)
+  }
+
   if (result) {
     let returnType: DaggerObjectBase | DaggerEnumBase
 
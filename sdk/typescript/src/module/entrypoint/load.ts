@@ -182,6 +182,32 @@ export async function loadValue(
 
       return executor.buildInterface(interfaceType, value)
     }
+    case TypeDefKind.MapKind: {
+      const valueType = (type as TypeDef<TypeDefKind.MapKind>).typeDef
+      const map: Record<string, any> = {}
+      for (const [key, v] of Object.entries(value)) {
+        map[key] = await loadValue(executor, v, valueType)
+      }
+
+      return map
+    }
+    case TypeDefKind.UnionKind: {
+      // Only one member is set in a union, keyed by its name.
+      const union = type as TypeDef<TypeDefKind.UnionKind>
+      for (const member of union.members) {
+        if (value[member.name] !== undefined && value[member.name] !== null) {
+          return {
+            [member.name]: await loadValue(
+              executor,
+              value[member.name],
+              member.typeDef,
+            ),
+          }
+        }
+      }
+
+      throw new Error(`no member of union ${union.name} is set`)
+    }
     // Cannot use `,` to specify multiple matching case so instead we use fallthrough.
     case TypeDefKind.StringKind:
     case TypeDefKind.IntegerKind:
@@ -235,6 +261,35 @@ export function loadObjectReturnType(
   }
 }
 
+/**
+ * Load the result of a function returning a map or a union.
+ *
+ * Those can only hold primitive and core types, so there's no module
+ * object to look up: core objects are replaced by their IDs.
+ *
+ * @param result The map or union to load.
+ */
+export async function loadJSONValueResult(result: any): Promise<any> {
+  if (result && typeof result?.id === "function") {
+    return await result.id()
+  }
+
+  if (Array.isArray(result)) {
+    return await Promise.all(result.map(async (r) => loadJSONValueResult(r)))
+  }
+
+  if (result && typeof result === "object") {
+    const state: any = {}
+    for (const [key, value] of Object.entries(result)) {
+      state[key] = await loadJSONValueResult(value)
+    }
+
+    return state
+  }
+
+  return result
+}
+
 export async function loadResult(
   result: any,
   module: DaggerModule,
@@ -273,6 +328,16 @@ export async function loadResult(
         throw new Error(`could not find type for result property ${key}`)
       }
 
+      // Maps and unions don't reference module objects.
+      if (
+        property.type.kind === TypeDefKind.MapKind ||
+        property.type.kind === TypeDefKind.UnionKind
+      ) {
+        state[property.alias ?? property.name] =
+          await loadJSONValueResult(value)
+        continue
+      }
+
       let referencedObject: DaggerObjectBase | undefined = undefined
 
       // Handle nested objects
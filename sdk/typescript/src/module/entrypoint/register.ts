@@ -1,6 +1,7 @@
 import {
   dag,
   Function_,
+  FunctionCachePolicy,
   FunctionWithArgOpts,
   ModuleID,
   TypeDef,
@@ -19,9 +20,11 @@ import {
   EnumTypeDef,
   InterfaceTypeDef,
   ListTypeDef,
+  MapTypeDef,
   ObjectTypeDef,
   ScalarTypeDef,
   TypeDef as ScannerTypeDef,
+  UnionTypeDef,
 } from "../introspector/typedef.js"
 
 /**
@@ -61,6 +64,7 @@ export async function register(
           {
             description: field.description,
             sourceMap: addSourceMap(field),
+            deprecated: field.deprecated,
           },
         )
       }
@@ -87,6 +91,7 @@ export async function register(
       typeDef = typeDef.withEnumValue(value.value, {
         description: value.description,
         sourceMap: addSourceMap(value),
+        deprecated: value.deprecated,
       })
     })
 
@@ -125,9 +130,44 @@ function addFunction(fct: Method | DaggerInterfaceFunction): Function_ {
     .function_(fct.alias ?? fct.name, addTypeDef(fct.returnType!))
     .withDescription(fct.description)
     .withSourceMap(addSourceMap(fct))
+    .with(addCachePolicy(fct.cache))
+    .with(addDeprecation(fct.deprecated))
     .with(addArg(fct.arguments))
 }
 
+/**
+ * Set the cache policy of the function, if any.
+ */
+function addCachePolicy(cache?: string): (fct: Function_) => Function_ {
+  return function (fct: Function_): Function_ {
+    switch (cache) {
+      case undefined:
+        return fct
+      case "never":
+        return fct.withCachePolicy(FunctionCachePolicy.Never)
+      case "session":
+        return fct.withCachePolicy(FunctionCachePolicy.PerSession)
+      default:
+        return fct.withCachePolicy(FunctionCachePolicy.Ttl, {
+          timeToLive: cache,
+        })
+    }
+  }
+}
+
+/**
+ * Mark the function as deprecated, if it is.
+ */
+function addDeprecation(reason?: string): (fct: Function_) => Function_ {
+  return function (fct: Function_): Function_ {
+    if (reason === undefined) {
+      return fct
+    }
+
+    return fct.withDeprecated(reason)
+  }
+}
+
 /**
  * Register all arguments in the function.
  */
@@ -175,6 +215,30 @@ function addArg(args: Arguments): (fct: Function_) => Function_ {
         opts.ignore = arg.ignore
       }
 
+      if (arg.deprecated !== undefined) {
+        opts.deprecated = arg.deprecated
+      }
+
+      if (arg.pattern !== undefined) {
+        opts.pattern = arg.pattern
+      }
+
+      if (arg.min !== undefined) {
+        opts.min = arg.min
+      }
+
+      if (arg.max !== undefined) {
+        opts.max = arg.max
+      }
+
+      if (arg.minLength !== undefined) {
+        opts.minLength = arg.minLength
+      }
+
+      if (arg.allowedValues) {
+        opts.allowedValues = arg.allowedValues
+      }
+
       fct = fct.withArg(arg.name, typeDef, opts)
     })
 
@@ -206,6 +270,23 @@ function addTypeDef(type: ScannerTypeDef<TypeDefKind>): TypeDef {
       return dag.typeDef().withEnum((type as EnumTypeDef).name)
     case TypeDefKind.InterfaceKind:
       return dag.typeDef().withInterface((type as InterfaceTypeDef).name)
+    case TypeDefKind.MapKind:
+      return dag.typeDef().withMapOf(addTypeDef((type as MapTypeDef).typeDef))
+    case TypeDefKind.UnionKind: {
+      const union = type as UnionTypeDef
+      let typeDef = dag
+        .typeDef()
+        .withUnion(union.name, { description: union.description })
+      for (const member of union.members) {
+        typeDef = typeDef.withUnionMember(
+          member.name,
+          addTypeDef(member.typeDef),
+          { description: member.description },
+        )
+      }
+
+      return typeDef
+    }
     default:
       return dag.typeDef().withKind(type.kind)
   }
@@ -26,6 +26,12 @@ export class DaggerArgument extends Locatable {
   public isOptional: boolean
   public defaultPath?: string
   public ignore?: string[]
+  public deprecated?: string
+  public pattern?: string
+  public min?: number
+  public max?: number
+  public minLength?: number
+  public allowedValues?: string[]
   public defaultValue?: any
 
   private symbol: ts.Symbol
@@ -58,6 +64,12 @@ export class DaggerArgument extends Locatable {
     if (decoratorArguments) {
       this.ignore = decoratorArguments.ignore
       this.defaultPath = decoratorArguments.defaultPath
+      this.deprecated = decoratorArguments.deprecated
+      this.pattern = decoratorArguments.pattern
+      this.min = decoratorArguments.min
+      this.max = decoratorArguments.max
+      this.minLength = decoratorArguments.minLength
+      this.allowedValues = decoratorArguments.allowedValues
     }
 
     this.type = this.getType()
@@ -146,6 +158,12 @@ export class DaggerArgument extends Locatable {
       defaultValue: this.defaultValue,
       defaultPath: this.defaultPath,
       ignore: this.ignore,
+      deprecated: this.deprecated,
+      pattern: this.pattern,
+      min: this.min,
+      max: this.max,
+      minLength: this.minLength,
+      allowedValues: this.allowedValues,
     }
   }
 }
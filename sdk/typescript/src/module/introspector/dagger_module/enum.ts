@@ -12,6 +12,7 @@ export class DaggerEnumValue extends Locatable implements DaggerEnumBaseValue {
   public name: string
   public value: string
   public description: string
+  public deprecated: string | undefined
 
   private symbol: ts.Symbol
 
@@ -23,6 +24,7 @@ export class DaggerEnumValue extends Locatable implements DaggerEnumBaseValue {
     this.symbol = this.ast.getSymbolOrThrow(this.node.name)
     this.name = this.node.name.getText()
     this.description = this.ast.getDocFromSymbol(this.symbol)
+    this.deprecated = this.ast.getDeprecationFromNode(this.node)
 
     const initializer = this.node.initializer
     if (!initializer) {
@@ -38,6 +40,7 @@ export class DaggerEnumValue extends Locatable implements DaggerEnumBaseValue {
     return {
       name: this.value,
       description: this.description,
+      deprecated: this.deprecated,
     }
   }
 }
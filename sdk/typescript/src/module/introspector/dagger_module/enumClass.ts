@@ -16,6 +16,7 @@ export class DaggerEnumClassValue
   public name: string
   public value: string
   public description: string
+  public deprecated: string | undefined
 
   private symbol: ts.Symbol
 
@@ -27,6 +28,7 @@ export class DaggerEnumClassValue
     this.name = this.node.name.getText()
     this.symbol = this.ast.getSymbolOrThrow(this.node.name)
     this.description = this.ast.getDocFromSymbol(this.symbol)
+    this.deprecated = this.ast.getDeprecationFromNode(this.node)
 
     const initializer = this.node.initializer
     if (!initializer) {
@@ -40,6 +42,7 @@ export class DaggerEnumClassValue
     return {
       name: this.value,
       description: this.description,
+      deprecated: this.deprecated,
     }
   }
 }
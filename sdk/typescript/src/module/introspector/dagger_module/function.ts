@@ -2,6 +2,7 @@ import ts from "typescript"
 
 import { TypeDefKind } from "../../../api/client.gen.js"
 import { IntrospectionError } from "../../../common/errors/index.js"
+import { FunctionOptions } from "../../registry.js"
 import { TypeDef } from "../typedef.js"
 import {
   AST,
@@ -21,6 +22,8 @@ export class DaggerFunction extends Locatable {
   private _returnTypeRef?: string
   public returnType?: TypeDef<TypeDefKind>
   public alias: string | undefined
+  public cache: string | undefined
+  public deprecated: string | undefined
   public arguments: DaggerArguments = {}
 
   private signature: ts.Signature
@@ -36,6 +39,7 @@ export class DaggerFunction extends Locatable {
     this.signature = this.ast.getSignatureFromFunctionOrThrow(node)
     this.name = this.node.name.getText()
     this.description = this.ast.getDocFromSymbol(this.symbol)
+    this.deprecated = this.ast.getDeprecationFromNode(this.node)
 
     for (const parameter of this.node.parameters) {
       this.arguments[parameter.name.getText()] = new DaggerArgument(
@@ -44,7 +48,10 @@ export class DaggerFunction extends Locatable {
       )
     }
     this.returnType = this.getReturnType()
-    this.alias = this.getAlias()
+
+    const options = this.getOptions()
+    this.alias = options.alias
+    this.cache = options.cache
   }
 
   private getReturnType(): TypeDef<TypeDefKind> | undefined {
@@ -58,17 +65,25 @@ export class DaggerFunction extends Locatable {
     return typedef
   }
 
-  private getAlias(): string | undefined {
-    const alias = this.ast.getDecoratorArgument<string>(
+  /**
+   * Get the options of the function decorator, which is either an alias or
+   * an options object.
+   */
+  private getOptions(): FunctionOptions {
+    const options = this.ast.getDecoratorArgument<string | FunctionOptions>(
       this.node,
       FUNCTION_DECORATOR,
-      "string",
+      "object",
     )
-    if (!alias) {
-      return
+    if (!options) {
+      return {}
+    }
+
+    if (typeof options === "string") {
+      return { alias: options }
     }
 
-    return JSON.parse(alias.replace(/'/g, '"'))
+    return options
   }
 
   public getArgsOrder(): string[] {
@@ -123,6 +138,8 @@ export class DaggerFunction extends Locatable {
       name: this.name,
       description: this.description,
       alias: this.alias,
+      cache: this.cache,
+      deprecated: this.deprecated,
       arguments: this.arguments,
       returnType: this.returnType,
     }
@@ -24,6 +24,8 @@ export class DaggerInterfaceFunction extends Locatable {
   public arguments: DaggerArguments = {}
   // Just a placeholder to be compatible with `Method` during registration
   public alias: undefined
+  public cache: undefined
+  public deprecated: undefined
   private symbol: ts.Symbol
   private signature?: ts.Signature
 
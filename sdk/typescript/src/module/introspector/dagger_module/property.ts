@@ -22,6 +22,7 @@ export class DaggerProperty
   public name: string
   public description: string
   public alias: string | undefined
+  public deprecated: string | undefined
   public isExposed: boolean
 
   private symbol: ts.Symbol
@@ -47,6 +48,7 @@ export class DaggerProperty
       this.ast.isNodeDecoratedWith(this.node, FIELD_DECORATOR)
 
     this.description = this.ast.getDocFromSymbol(this.symbol)
+    this.deprecated = this.ast.getDeprecationFromNode(this.node)
     this.alias = this.getAlias()
     this.type = this.getType()
   }
@@ -119,6 +121,7 @@ export class DaggerProperty
       name: this.name,
       description: this.description,
       alias: this.alias,
+      deprecated: this.deprecated,
       type: this.type,
       isExposed: this.isExposed,
     }
@@ -94,6 +94,14 @@ describe("scan by reference TypeScript", function () {
       name: "Should correctly scan minimal",
       directory: "minimal",
     },
+    {
+      name: "Should correctly scan cache policies",
+      directory: "cache",
+    },
+    {
+      name: "Should correctly scan deprecations",
+      directory: "deprecated",
+    },
     {
       name: "Should correctly scan interfaces",
       directory: "interface",
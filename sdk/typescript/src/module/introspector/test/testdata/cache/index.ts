@@ -0,0 +1,19 @@
+import { func, object } from "../../../../decorators.js"
+
+@object()
+export class Cache {
+  @func({ cache: "10m" })
+  ttl(): string {
+    return "ttl"
+  }
+
+  @func({ alias: "never", cache: "never" })
+  uncached(): string {
+    return "never"
+  }
+
+  @func()
+  cached(): string {
+    return "cached"
+  }
+}
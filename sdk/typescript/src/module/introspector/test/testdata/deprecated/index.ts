@@ -0,0 +1,50 @@
+import { argument, enumType, func, object } from "../../../../decorators.js"
+
+/**
+ * Build modes
+ */
+@enumType()
+export class Mode {
+  /**
+   * Fast build
+   */
+  static readonly FAST: string = "FAST"
+
+  /**
+   * Slow build
+   *
+   * @deprecated Use FAST instead
+   */
+  static readonly SLOW: string = "SLOW"
+}
+
+@object()
+export class Deprecated {
+  /**
+   * The image tag
+   *
+   * @deprecated Use version instead
+   */
+  @func()
+  tag: string = "latest"
+
+  @func()
+  version: string = "1.0.0"
+
+  /**
+   * Build the project
+   *
+   * @deprecated Use build2 instead
+   */
+  @func()
+  build(mode: Mode): string {
+    return mode
+  }
+
+  @func()
+  build2(
+    @argument({ deprecated: "Use version instead" }) tag?: string,
+  ): string {
+    return tag ?? this.version
+  }
+}
@@ -47,6 +47,33 @@ export type ListTypeDef = BaseTypeDef & {
   typeDef: TypeDef<TypeDefKind>
 }
 
+/**
+ * Extends the base if it's a map to add the type of its values.
+ */
+export type MapTypeDef = BaseTypeDef & {
+  kind: TypeDefKind.MapKind
+  typeDef: TypeDef<TypeDefKind>
+}
+
+/**
+ * A member of a union, keyed by the name of its property.
+ */
+export type UnionMemberTypeDef = {
+  name: string
+  description?: string
+  typeDef: TypeDef<TypeDefKind>
+}
+
+/**
+ * Extends the base if it's a union to add its name and members.
+ */
+export type UnionTypeDef = BaseTypeDef & {
+  kind: TypeDefKind.UnionKind
+  name: string
+  description?: string
+  members: UnionMemberTypeDef[]
+}
+
 /**
  * A generic TypeDef that will dynamically add necessary properties
  * depending on its type.
@@ -54,6 +81,8 @@ export type ListTypeDef = BaseTypeDef & {
  * If it's a type of kind scalar, it transforms the BaseTypeDef into a ScalarTypeDef.
  * If it's type of kind object, it transforms the BaseTypeDef into an ObjectTypeDef.
  * If it's a type of kind list, it transforms the BaseTypeDef into a ListTypeDef.
+ * If it's a type of kind map, it transforms the BaseTypeDef into a MapTypeDef.
+ * If it's a type of kind union, it transforms the BaseTypeDef into a UnionTypeDef.
  */
 export type TypeDef<T extends BaseTypeDef["kind"]> =
   T extends TypeDefKind.ScalarKind
@@ -66,4 +95,8 @@ export type TypeDef<T extends BaseTypeDef["kind"]> =
           ? EnumTypeDef
           : T extends TypeDefKind.InterfaceKind
             ? InterfaceTypeDef
-            : BaseTypeDef
+            : T extends TypeDefKind.MapKind
+              ? MapTypeDef
+              : T extends TypeDefKind.UnionKind
+                ? UnionTypeDef
+                : BaseTypeDef
@@ -6,10 +6,11 @@ import ts from "typescript"
 import { TypeDefKind } from "../../../api/client.gen.js"
 import { IntrospectionError } from "../../../common/errors/index.js"
 import { DaggerDecorators } from "../dagger_module/index.js"
-import { TypeDef } from "../typedef.js"
+import { TypeDef, UnionMemberTypeDef } from "../typedef.js"
 import { DeclarationsMap, isDeclarationOf } from "./declarations.js"
 import { getValueByExportedName } from "./explorer.js"
 import { Location } from "./location.js"
+import { isTypeDefResolved } from "./typedef_utils.js"
 
 export const CLIENT_GEN_FILE = "client.gen.ts"
 
@@ -151,6 +152,21 @@ export class AST {
     return ts.displayPartsToString(symbol.getDocumentationComment(this.checker))
   }
 
+  /**
+   * Get the reason of the `@deprecated` JSDoc tag of the node, if any.
+   *
+   * Returns an empty string if the tag has no reason, and undefined if the
+   * node isn't deprecated.
+   */
+  public getDeprecationFromNode(node: ts.Node): string | undefined {
+    const tag = ts.getJSDocDeprecatedTag(node)
+    if (!tag) {
+      return undefined
+    }
+
+    return ts.getTextOfJSDocComment(tag.comment)?.trim() ?? ""
+  }
+
   public getSymbolOrThrow(node: ts.Node): ts.Symbol {
     const symbol = this.getSymbol(node)
     if (!symbol) {
@@ -299,12 +315,34 @@ export class AST {
       return { kind: TypeDefKind.BooleanKind }
     if (type.flags & ts.TypeFlags.Void) return { kind: TypeDefKind.VoidKind }
 
+    // A type alias of single property objects is a tagged union, e.g.
+    // `type Source = { directory: Directory } | { gitUrl: string }`.
+    if (type.isUnion() && type.aliasSymbol) {
+      const unionTypeDef = this.tsUnionToTypeDef(node, type)
+      if (unionTypeDef) {
+        return unionTypeDef
+      }
+    }
+
     // If a type has a flag Object, is can basically be anything.
     // We firstly wants to see if it's a promise or an array so we can unwrap the
     // actual type.
     if (type.flags & ts.TypeFlags.Object) {
       const objectType = type as ts.ObjectType
 
+      // An object with only a string index signature, like `Record<string, T>`,
+      // is a map.
+      const indexInfo = this.checker.getIndexInfoOfType(
+        type,
+        ts.IndexKind.String,
+      )
+      if (indexInfo && this.checker.getPropertiesOfType(type).length === 0) {
+        return {
+          kind: TypeDefKind.MapKind,
+          typeDef: this.tsTypeToInlineTypeDef(node, indexInfo.type),
+        }
+      }
+
       // If it's a reference, that means it's a generic type like
       // `Promise<T>` or `number[]` or `Array<T>`.
       if (objectType.objectFlags & ts.ObjectFlags.Reference) {
@@ -342,6 +380,105 @@ export class AST {
     }
   }
 
+  /**
+   * Convert a union type into a union typedef if all its members are objects
+   * with a single property, named after the member.
+   *
+   * Returns undefined if the union doesn't have that shape.
+   */
+  private tsUnionToTypeDef(
+    node: ts.Node,
+    type: ts.UnionType,
+  ): TypeDef<TypeDefKind.UnionKind> | undefined {
+    const members: UnionMemberTypeDef[] = []
+
+    for (const memberType of type.types) {
+      if (!(memberType.flags & ts.TypeFlags.Object)) {
+        return undefined
+      }
+
+      const properties = this.checker.getPropertiesOfType(memberType)
+      if (properties.length !== 1) {
+        return undefined
+      }
+
+      const property = properties[0]
+      members.push({
+        name: property.getName(),
+        description: this.getDocFromSymbol(property),
+        typeDef: this.tsTypeToInlineTypeDef(
+          node,
+          this.checker.getTypeOfSymbolAtLocation(property, node),
+        ),
+      })
+    }
+
+    return {
+      kind: TypeDefKind.UnionKind,
+      name: type.aliasSymbol!.getName(),
+      description: this.getDocFromSymbol(type.aliasSymbol!),
+      members,
+    }
+  }
+
+  /**
+   * Convert the type of a map value or a union member into a typedef.
+   *
+   * Only primitive and core types can be used in maps and unions, so
+   * they are resolved directly instead of going through references.
+   */
+  private tsTypeToInlineTypeDef(
+    node: ts.Node,
+    type: ts.Type,
+  ): TypeDef<TypeDefKind> {
+    if (
+      type.flags & ts.TypeFlags.Object &&
+      (type as ts.ObjectType).objectFlags & ts.ObjectFlags.Reference &&
+      type.symbol?.getName() === "Array"
+    ) {
+      const [elementType] = this.checker.getTypeArguments(
+        type as ts.TypeReference,
+      )
+
+      return {
+        kind: TypeDefKind.ListKind,
+        typeDef: this.tsTypeToInlineTypeDef(node, elementType),
+      }
+    }
+
+    const aliasDeclaration = type.aliasSymbol?.declarations?.[0]
+    if (
+      aliasDeclaration &&
+      ts.isTypeAliasDeclaration(aliasDeclaration) &&
+      aliasDeclaration.getSourceFile().fileName.endsWith(CLIENT_GEN_FILE)
+    ) {
+      return { kind: TypeDefKind.ScalarKind, name: type.aliasSymbol!.getName() }
+    }
+
+    const declaration = type.symbol?.declarations?.[0]
+    if (
+      declaration &&
+      declaration.getSourceFile().fileName.endsWith(CLIENT_GEN_FILE)
+    ) {
+      if (ts.isClassDeclaration(declaration)) {
+        return { kind: TypeDefKind.ObjectKind, name: type.symbol.getName() }
+      }
+
+      if (ts.isEnumDeclaration(declaration)) {
+        return { kind: TypeDefKind.EnumKind, name: type.symbol.getName() }
+      }
+    }
+
+    const typeDef = this.tsTypeToTypeDef(node, type)
+    if (typeDef === undefined || !isTypeDefResolved(typeDef)) {
+      throw new IntrospectionError(
+        `could not resolve type ${this.checker.typeToString(type)} at ${AST.getNodePosition(node)}, only primitive and core types can be used in maps and unions.`,
+      )
+    }
+
+    return typeDef
+  }
+
   private resolveParameterDefaultValueTypeReference(
     expression: ts.Expression,
     value: any,
@@ -12,6 +12,21 @@ export type State = { [property: string]: any }
 
 export type Args = Record<string, unknown>
 
+export type FunctionOptions = {
+  /**
+   * The alias to use for the function when exposed on the API.
+   */
+  alias?: string
+
+  /**
+   * How the results of calls to the function are cached: "never", "session"
+   * for the duration of the session, or a time to live such as "10m".
+   *
+   * Defaults to the engine's caching.
+   */
+  cache?: string
+}
+
 /**
  * Datastructures that store the class constructor to allow invoking it
  * from the registry and store method's name.
@@ -37,6 +52,44 @@ export type ArgumentOptions = {
    * This should only be used for Directory types.
    */
   ignore?: string[]
+
+  /**
+   * If set, marks the argument as deprecated with the given reason.
+   */
+  deprecated?: string
+
+  /**
+   * A regular expression (RE2 syntax) that values must match.
+   *
+   * This should only be used for string types, or lists of strings.
+   */
+  pattern?: string
+
+  /**
+   * The minimum value, inclusive.
+   *
+   * This should only be used for number types, or lists of numbers.
+   */
+  min?: number
+
+  /**
+   * The maximum value, inclusive.
+   *
+   * This should only be used for number types, or lists of numbers.
+   */
+  max?: number
+
+  /**
+   * The minimum number of characters of a string, or of elements of a list.
+   */
+  minLength?: number
+
+  /**
+   * The only values that are accepted.
+   *
+   * This should only be used for string types, or lists of strings.
+   */
+  allowedValues?: string[]
 }
 
 /**
@@ -92,9 +145,11 @@ export class Registry {
   /**
    * The definition of @func decorator that should be on top of any
    * class' method that must be exposed to the Dagger API.
+   *
+   * @param alias The alias to use for the function when exposed on the API, or its options.
    */
   func = (
-    alias?: string,
+    alias?: string | FunctionOptions,
   ): ((
     target: object,
     propertyKey: string | symbol,